@@ -0,0 +1,93 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/mickamy/pointless/internal/config"
+	"github.com/mickamy/pointless/internal/runner"
+	"github.com/mickamy/pointless/internal/startup"
+	"github.com/mickamy/pointless/internal/survey"
+)
+
+// runCalibrate implements `pointless calibrate`, which prints the distribution
+// of struct sizes in the packages and the findings at a range of thresholds.
+func runCalibrate(args []string) int {
+	fs := flag.NewFlagSet("pointless calibrate", flag.ContinueOnError)
+	list := fs.String("thresholds", joinInts(survey.DefaultThresholds), "comma-separated `thresholds` in bytes to compare")
+	fs.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage: pointless calibrate [-thresholds list] [packages]\n\n")
+		fmt.Fprintf(os.Stderr, "Prints the distribution of struct sizes in the packages (default ./...) and\n")
+		fmt.Fprintf(os.Stderr, "the number of findings at each threshold, with the config's other settings.\n\n")
+		fs.PrintDefaults()
+	}
+
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+
+	var thresholds []int
+
+	for _, s := range strings.Split(*list, ",") {
+		n, err := strconv.Atoi(strings.TrimSpace(s))
+		if err != nil || n <= 0 {
+			fmt.Fprintf(os.Stderr, "pointless: -thresholds: invalid threshold %q\n", s)
+
+			return 2
+		}
+
+		thresholds = append(thresholds, n)
+	}
+
+	cfg := startup.LoadConfig()
+
+	// The current threshold is compared too, if it is in bytes
+	current := 0
+	if cfg.ThresholdUnit == config.UnitBytes || cfg.ThresholdUnit == "" {
+		current = cfg.Threshold
+		if !slices.Contains(thresholds, current) {
+			thresholds = append(thresholds, current)
+		}
+	}
+
+	patterns := fs.Args()
+	if len(patterns) == 0 {
+		patterns = []string{"./..."}
+	}
+
+	pkgs, err := runner.Load(patterns...)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "pointless: %v\n", err)
+
+		return 1
+	}
+
+	levels, err := survey.Calibrate(pkgs, cfg, thresholds)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "pointless: %v\n", err)
+
+		return 1
+	}
+
+	if err := survey.WriteCalibration(os.Stdout, survey.StructSizes(pkgs), levels, current); err != nil {
+		fmt.Fprintf(os.Stderr, "pointless: %v\n", err)
+
+		return 1
+	}
+
+	return 0
+}
+
+// joinInts joins ns with commas.
+func joinInts(ns []int) string {
+	s := make([]string, len(ns))
+	for i, n := range ns {
+		s[i] = strconv.Itoa(n)
+	}
+
+	return strings.Join(s, ",")
+}
@@ -0,0 +1,73 @@
+// Command pointless-suite runs pointless together with its companion
+// analyzers in one binary, all configured by the same .pointless.yaml (or
+// .pointless.yml). It starts up like pointless: -debug, -target, -tags, and
+// -strict-config apply, and at a workspace root each module gets its own
+// config and ./... matches the packages of every module.
+//
+// Each analyzer's flags are prefixed with its name, as in
+// -pointless.threshold=512, and each one can be disabled on its own, as in
+// -pointless=false.
+package main
+
+import (
+	"log/slog"
+	"os"
+	"strconv"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/analysis/multichecker"
+
+	"github.com/mickamy/pointless/internal/analyzer"
+	"github.com/mickamy/pointless/internal/startup"
+)
+
+// analyzers lists the analyzers of the suite. The companion analyzers join
+// it as they land.
+var analyzers = []*analysis.Analyzer{
+	analyzer.Analyzer,
+}
+
+func main() {
+	os.Args = append(os.Args[:1], startup.SetupLogging(os.Args[1:])...)
+
+	// Select the platform and the files of the packages before any is loaded
+	if !startup.SelectTarget(os.Args[1:]) || !startup.SelectTags(os.Args[1:]) {
+		os.Exit(2)
+	}
+
+	ws, inWorkspace := startup.LoadWorkspace()
+
+	cfg := ws.Config
+	if !inWorkspace {
+		cfg = startup.LoadConfig()
+	}
+
+	thresholdSet := startup.HasFlag(os.Args[1:], "pointless.threshold")
+
+	switch {
+	case inWorkspace && thresholdSet:
+		// The analyzer picks each module's threshold unless the flag overrides them
+		for i := range ws.Modules {
+			ws.Modules[i].Config.Threshold = 0
+		}
+
+		ws.Config.Threshold = 0
+	case !inWorkspace && cfg.Threshold > 0:
+		// The config is the default; the -pointless.threshold flag overrides it
+		if err := analyzer.Analyzer.Flags.Set("threshold", strconv.Itoa(cfg.Threshold)); err != nil {
+			slog.Warn("invalid threshold", "err", err)
+		}
+	}
+
+	analyzer.SetConfig(cfg)
+	analyzer.SetTypeDefinitions(startup.HasFlag(os.Args[1:], "json"))
+
+	if inWorkspace {
+		analyzer.SetWorkspace(ws)
+
+		// The driver cannot expand ./... at a workspace root
+		os.Args = append(os.Args[:1], startup.WorkspacePatterns(ws.Modules, os.Args[1:])...)
+	}
+
+	multichecker.Main(analyzers...)
+}
@@ -0,0 +1,121 @@
+package main_test
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestSuite(t *testing.T) {
+	t.Parallel()
+
+	bin := filepath.Join(t.TempDir(), "pointless-suite")
+
+	build := exec.Command("go", "build", "-o", bin, ".")
+	if out, err := build.CombinedOutput(); err != nil {
+		t.Fatalf("go build: %v\n%s", err, out)
+	}
+
+	tests := []struct {
+		name     string
+		dir      string
+		args     []string
+		exitCode int
+		want     []string
+		notWant  []string
+	}{
+		{
+			name:     "config threshold",
+			dir:      "mod",
+			args:     []string{"./..."},
+			exitCode: 3,
+			want:     []string{"Tiny is 1 bytes (threshold: 8 bytes)"},
+			notWant:  []string{"Point", "Flag"},
+		},
+		{
+			name:     "threshold flag overrides the config",
+			dir:      "mod",
+			args:     []string{"-pointless.threshold=64", "./..."},
+			exitCode: 3,
+			want:     []string{"Tiny is", "Point is 16 bytes (threshold: 64 bytes)"},
+		},
+		{
+			name:     "build tags",
+			dir:      "mod",
+			args:     []string{"-tags=extra", "./..."},
+			exitCode: 3,
+			want:     []string{"Flag is 1 bytes"},
+		},
+		{
+			name:     "invalid target",
+			dir:      "mod",
+			args:     []string{"-target=linux", "./..."},
+			exitCode: 2,
+			want:     []string{"-target"},
+		},
+		{
+			name:     "invalid config",
+			dir:      "invalid",
+			args:     []string{"./..."},
+			exitCode: 3,
+			want:     []string{"config problem", "Point is 16 bytes (threshold: 1024 bytes)"},
+		},
+		{
+			name:     "strict config",
+			dir:      "invalid",
+			args:     []string{"-strict-config", "./..."},
+			exitCode: 2,
+			want:     []string{"config problems are fatal with -strict-config"},
+			notWant:  []string{"Point is"},
+		},
+		{
+			name:     "workspace",
+			dir:      "work",
+			args:     []string{"./..."},
+			exitCode: 3,
+			want:     []string{"Tiny is 1 bytes (threshold: 8 bytes)"},
+			notWant:  []string{"Point"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			cmd := exec.Command(bin, tt.args...) //nolint:gosec // G204: the binary built above
+			cmd.Dir = filepath.Join("testdata", tt.dir)
+			// -mod=mod, as set in some environments, is rejected in workspace mode
+			cmd.Env = append(os.Environ(), "GOFLAGS=")
+
+			out, err := cmd.CombinedOutput()
+
+			exitCode := 0
+
+			var exitErr *exec.ExitError
+			if errors.As(err, &exitErr) {
+				exitCode = exitErr.ExitCode()
+			} else if err != nil {
+				t.Fatal(err)
+			}
+
+			if exitCode != tt.exitCode {
+				t.Errorf("exit code = %d, want %d\n%s", exitCode, tt.exitCode, out)
+			}
+
+			for _, s := range tt.want {
+				if !strings.Contains(string(out), s) {
+					t.Errorf("output does not contain %q:\n%s", s, out)
+				}
+			}
+
+			for _, s := range tt.notWant {
+				if strings.Contains(string(out), s) {
+					t.Errorf("output contains %q:\n%s", s, out)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,12 @@
+//go:build extra
+
+package mod
+
+// Flag is only analyzed with -tags=extra.
+type Flag struct {
+	on bool
+}
+
+func NewFlag() *Flag {
+	return &Flag{}
+}
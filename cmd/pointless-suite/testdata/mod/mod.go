@@ -0,0 +1,19 @@
+package mod
+
+// Tiny is below the threshold of 8 bytes in the config.
+type Tiny struct {
+	ok bool
+}
+
+func NewTiny() *Tiny {
+	return &Tiny{}
+}
+
+// Point is above it.
+type Point struct {
+	X, Y int
+}
+
+func NewPoint() *Point {
+	return &Point{}
+}
@@ -0,0 +1,71 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/mickamy/pointless/internal/runner"
+	"github.com/mickamy/pointless/internal/startup"
+)
+
+// configUsage is the usage of the config subcommands.
+const configUsage = `Usage: pointless config lint [packages]
+       pointless config schema
+`
+
+// runConfig implements `pointless config`, dispatching to its subcommands.
+func runConfig(args []string) int {
+	if len(args) > 0 {
+		switch args[0] {
+		case "lint":
+			return runConfigLint(args[1:])
+		case "schema":
+			return runConfigSchema(args[1:])
+		}
+	}
+
+	fmt.Fprint(os.Stderr, configUsage)
+
+	return 2
+}
+
+// runConfigLint implements `pointless config lint`, which reports exclude
+// patterns that have no effect on the analyzed packages.
+func runConfigLint(args []string) int {
+	fs := flag.NewFlagSet("pointless config lint", flag.ContinueOnError)
+	fs.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage: pointless config lint [packages]\n\n")
+		fmt.Fprintf(os.Stderr, "Reports exclude patterns that match no file of the packages (default ./...),\n")
+		fmt.Fprintf(os.Stderr, "tests included, or only files matched by another pattern.\n")
+	}
+
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+
+	cfg := startup.LoadConfig()
+
+	patterns := fs.Args()
+	if len(patterns) == 0 {
+		patterns = []string{"./..."}
+	}
+
+	files, err := runner.Files(patterns...)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "pointless: %v\n", err)
+
+		return 1
+	}
+
+	problems := cfg.LintExcludes(files)
+	for _, p := range problems {
+		fmt.Fprintf(os.Stderr, "pointless: warning: %s\n", p)
+	}
+
+	if len(problems) > 0 {
+		return 1
+	}
+
+	return 0
+}
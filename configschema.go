@@ -0,0 +1,48 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/mickamy/pointless/internal/config"
+)
+
+// runConfigSchema implements `pointless config schema`, which prints the JSON
+// Schema of the config file for editors to complete and validate it with.
+func runConfigSchema(args []string) int {
+	fs := flag.NewFlagSet("pointless config schema", flag.ContinueOnError)
+	fs.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage: pointless config schema\n\n")
+		fmt.Fprintf(os.Stderr, "Prints a JSON Schema for .pointless.yaml, e.g. for yaml-language-server.\n")
+	}
+
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+
+	if fs.NArg() > 0 {
+		fs.Usage()
+
+		return 2
+	}
+
+	schema, err := config.Schema()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "pointless: %v\n", err)
+
+		return 1
+	}
+
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+
+	if err := enc.Encode(schema); err != nil {
+		fmt.Fprintf(os.Stderr, "pointless: writing schema: %v\n", err)
+
+		return 1
+	}
+
+	return 0
+}
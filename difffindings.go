@@ -0,0 +1,57 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/mickamy/pointless/internal/reportdiff"
+)
+
+// runDiffFindings implements `pointless diff-findings`, which compares two
+// JSON reports and prints the findings added, removed, and moved.
+func runDiffFindings(args []string) int {
+	fs := flag.NewFlagSet("pointless diff-findings", flag.ContinueOnError)
+	fs.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage: pointless diff-findings old.json new.json\n\n")
+		fmt.Fprintf(os.Stderr, "Compares two -format=json reports and prints the findings added (+), removed (-),\n")
+		fmt.Fprintf(os.Stderr, "and moved (~). Exits with 1 if findings were added or removed.\n")
+	}
+
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+
+	if fs.NArg() != 2 {
+		fs.Usage()
+
+		return 2
+	}
+
+	older, err := reportdiff.ReadFile(fs.Arg(0))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "pointless: %v\n", err)
+
+		return 2
+	}
+
+	newer, err := reportdiff.ReadFile(fs.Arg(1))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "pointless: %v\n", err)
+
+		return 2
+	}
+
+	result := reportdiff.Compare(older, newer)
+	if err := reportdiff.Write(os.Stdout, result); err != nil {
+		fmt.Fprintf(os.Stderr, "pointless: %v\n", err)
+
+		return 2
+	}
+
+	if result.Changed() {
+		return 1
+	}
+
+	return 0
+}
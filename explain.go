@@ -0,0 +1,38 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/mickamy/pointless/internal/rules"
+)
+
+// runExplain implements `pointless explain <rule>`, which prints the built-in
+// documentation of a rule.
+func runExplain(args []string) int {
+	if len(args) != 1 {
+		fmt.Fprintf(os.Stderr, "Usage: pointless explain <rule>\n\nRules:\n")
+
+		for _, r := range rules.All() {
+			summary := r.Summary
+			if r.OptIn {
+				summary += " (opt-in)"
+			}
+
+			fmt.Fprintf(os.Stderr, "  %-18s %s\n", r.ID, summary)
+		}
+
+		return 2
+	}
+
+	doc, ok := rules.Doc(args[0])
+	if !ok {
+		fmt.Fprintf(os.Stderr, "pointless: unknown rule %q (run 'pointless explain' to list rules)\n", args[0])
+
+		return 2
+	}
+
+	fmt.Print(doc)
+
+	return 0
+}
@@ -0,0 +1,83 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"github.com/mickamy/pointless/internal/survey"
+)
+
+// runInit implements `pointless init`, which surveys the module and writes a
+// starter config file to its root.
+func runInit(args []string) int {
+	flags := flag.NewFlagSet("pointless init", flag.ContinueOnError)
+	output := flags.String("o", "", "write the config to `file` instead of .pointless.yaml at the module root (- for stdout)")
+	force := flags.Bool("force", false, "overwrite an existing config file")
+	flags.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage: pointless init [-o file] [-force] [packages]\n\n")
+		fmt.Fprintf(os.Stderr, "Surveys the packages (default ./...) for vendored dependencies, generated\n")
+		fmt.Fprintf(os.Stderr, "files, framework imports, and struct sizes, and writes a commented config.\n\n")
+		flags.PrintDefaults()
+	}
+
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+
+	patterns := flags.Args()
+	if len(patterns) == 0 {
+		patterns = []string{"./..."}
+	}
+
+	s, err := survey.Run("", patterns...)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "pointless: %v\n", err)
+
+		return 1
+	}
+
+	var buf bytes.Buffer
+	if err := survey.WriteStarter(&buf, s); err != nil {
+		fmt.Fprintf(os.Stderr, "pointless: %v\n", err)
+
+		return 1
+	}
+
+	if *output == "-" {
+		if _, err := os.Stdout.Write(buf.Bytes()); err != nil {
+			fmt.Fprintf(os.Stderr, "pointless: %v\n", err)
+
+			return 1
+		}
+
+		return 0
+	}
+
+	path := *output
+	if path == "" {
+		path = filepath.Join(s.Dir, ".pointless.yaml")
+	}
+
+	if !*force {
+		if _, err := os.Stat(path); !errors.Is(err, fs.ErrNotExist) {
+			fmt.Fprintf(os.Stderr, "pointless: %s already exists (use -force to overwrite it)\n", path)
+
+			return 1
+		}
+	}
+
+	if err := os.WriteFile(path, buf.Bytes(), 0o644); err != nil { //nolint:gosec // G306: config files are meant to be shared
+		fmt.Fprintf(os.Stderr, "pointless: %v\n", err)
+
+		return 1
+	}
+
+	fmt.Fprintf(os.Stderr, "pointless: wrote %s\n", path)
+
+	return 0
+}
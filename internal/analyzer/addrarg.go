@@ -0,0 +1,225 @@
+package analyzer
+
+import (
+	"go/ast"
+	"go/token"
+	"go/types"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/ast/inspector"
+
+	"github.com/mickamy/pointless/internal/messages"
+	"github.com/mickamy/pointless/internal/rules"
+)
+
+// addressArgs records the arguments passed to the struct pointer parameters of
+// the package's functions.
+type addressArgs struct {
+	// sites maps parameters to the &x arguments passed to them.
+	sites map[paramKey][]*ast.UnaryExpr
+	// other maps parameters to the first argument that is not an &x expression.
+	other map[paramKey]token.Pos
+	// values maps functions to their first use other than a call.
+	values map[*types.Func]token.Pos
+}
+
+// findAddressArgs scans every call of the package's functions and the values
+// passed to their struct pointer parameters.
+func findAddressArgs(pass *analysis.Pass, inspect *inspector.Inspector) addressArgs {
+	result := addressArgs{
+		sites:  make(map[paramKey][]*ast.UnaryExpr),
+		other:  make(map[paramKey]token.Pos),
+		values: make(map[*types.Func]token.Pos),
+	}
+
+	inspect.WithStack([]ast.Node{(*ast.Ident)(nil)}, func(n ast.Node, push bool, stack []ast.Node) bool {
+		if !push {
+			return true
+		}
+
+		ident, ok := n.(*ast.Ident)
+		if !ok {
+			return true
+		}
+
+		fn, ok := pass.TypesInfo.Uses[ident].(*types.Func)
+		if !ok || fn.Pkg() != pass.Pkg {
+			return true
+		}
+
+		fn = fn.Origin()
+
+		call, deferred := calledWith(stack)
+		if call == nil {
+			if !result.values[fn].IsValid() {
+				result.values[fn] = ident.Pos()
+			}
+
+			return true
+		}
+
+		sig, ok := fn.Type().(*types.Signature)
+		if !ok {
+			return true
+		}
+
+		for i, arg := range call.Args {
+			if i >= sig.Params().Len() || !isStructPointer(sig.Params().At(i).Type()) {
+				continue
+			}
+
+			key := paramKey{fn, i}
+
+			// go and defer calls dereference the pointer later, so they would see a
+			// different value than a copy taken at the call
+			if addr, ok := ast.Unparen(arg).(*ast.UnaryExpr); ok && addr.Op == token.AND && !deferred {
+				result.sites[key] = append(result.sites[key], addr)
+			} else if !result.other[key].IsValid() {
+				result.other[key] = arg.Pos()
+			}
+		}
+
+		return true
+	})
+
+	return result
+}
+
+// calledWith returns the call whose function is the identifier at the top of
+// stack, possibly parenthesized or explicitly instantiated, or nil. deferred
+// reports whether the call is run by a go or defer statement.
+func calledWith(stack []ast.Node) (call *ast.CallExpr, deferred bool) {
+	node := stack[len(stack)-1]
+
+	for i := len(stack) - 2; i >= 0; i-- {
+		switch parent := stack[i].(type) {
+		case *ast.ParenExpr:
+		case *ast.IndexExpr:
+			if parent.X != node {
+				return nil, false
+			}
+		case *ast.IndexListExpr:
+			if parent.X != node {
+				return nil, false
+			}
+		case *ast.CallExpr:
+			if parent.Fun != node {
+				return nil, false
+			}
+
+			if i > 0 {
+				switch stack[i-1].(type) {
+				case *ast.GoStmt, *ast.DeferStmt:
+					return parent, true
+				}
+			}
+
+			return parent, false
+		default:
+			return nil, false
+		}
+
+		node = stack[i]
+	}
+
+	return nil, false
+}
+
+// checkAddressArgParams checks the *T parameters of an unexported function that
+// it only reads and that every caller passes &x to, so the callers take an
+// address only to make the call.
+func checkAddressArgParams(pass *analysis.Pass, fn *ast.FuncDecl, st *state) {
+	if fn.Recv != nil || fn.Name.IsExported() || fn.Body == nil {
+		return
+	}
+
+	obj, ok := pass.TypesInfo.Defs[fn.Name].(*types.Func)
+	if !ok {
+		return
+	}
+
+	index := 0
+
+	for _, field := range fn.Type.Params.List {
+		names := field.Names
+		if len(names) == 0 {
+			index++
+
+			continue
+		}
+
+		star, isPtr := ast.Unparen(field.Type).(*ast.StarExpr)
+
+		for _, name := range names {
+			if isPtr {
+				checkAddressArgParam(pass, fn, obj, star, name, paramKey{obj, index}, st)
+			}
+
+			index++
+		}
+	}
+}
+
+func checkAddressArgParam(pass *analysis.Pass, fn *ast.FuncDecl, obj *types.Func, star *ast.StarExpr, name *ast.Ident, key paramKey, st *state) {
+	// Skip if not a named struct pointer parameter
+	if _, ok := st.params.readOnly[key]; !ok {
+		return
+	}
+
+	// Skip if the function relies on the pointer
+	if !st.params.readOnly[key] {
+		st.explain.skipped(pass, star.Pos(), "%s relies on the pointer at line %d", name.Name, lineOf(pass, st.params.blocker[key]))
+
+		return
+	}
+
+	// Skip if the signature must stay as is, e.g. to match a function type
+	if pos := st.addressArgs.values[obj]; pos.IsValid() {
+		st.explain.skipped(pass, star.Pos(), "%s is used as a value at line %d", fn.Name.Name, lineOf(pass, pos))
+
+		return
+	}
+
+	// Skip if some caller already holds a pointer
+	if pos := st.addressArgs.other[key]; pos.IsValid() {
+		st.explain.skipped(pass, star.Pos(), "a caller passes an existing pointer at line %d", lineOf(pass, pos))
+
+		return
+	}
+
+	sites := st.addressArgs.sites[key]
+	if len(sites) == 0 {
+		st.explain.skipped(pass, star.Pos(), "%s is never called in this package", fn.Name.Name)
+
+		return
+	}
+
+	t, size, ok := smallStruct(pass, st, star.Pos(), star.X)
+	if !ok {
+		return
+	}
+
+	typeName := typeString(pass, t)
+
+	msg := st.msg.Sprintf(messages.AddressArgument, name.Name, typeName, typeName, fn.Name.Name, len(sites), size, st.bounds())
+	if len(sites) == 1 {
+		msg = st.msg.Sprintf(messages.AddressArgumentOne, name.Name, typeName, typeName, fn.Name.Name, size, st.bounds())
+	}
+
+	d := analysis.Diagnostic{
+		Pos:      star.Pos(),
+		End:      star.End(),
+		Category: rules.AddressArgument,
+		Message:  msg,
+	}
+
+	for _, site := range sites {
+		d.Related = append(d.Related, analysis.RelatedInformation{
+			Pos:     site.Pos(),
+			End:     site.End(),
+			Message: st.msg.Sprintf(messages.AddressArgumentCall, types.ExprString(site)),
+		})
+	}
+
+	st.report(pass, t, d)
+}
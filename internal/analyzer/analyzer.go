@@ -3,16 +3,26 @@
 package analyzer
 
 import (
+	"fmt"
 	"go/ast"
 	"go/token"
 	"go/types"
-	"path/filepath"
+	"log/slog"
+	"maps"
+	"slices"
 	"strings"
 	"sync"
+	"time"
 
 	"golang.org/x/tools/go/analysis"
 	"golang.org/x/tools/go/analysis/passes/inspect"
 	"golang.org/x/tools/go/ast/inspector"
+
+	"github.com/mickamy/pointless/internal/config"
+	"github.com/mickamy/pointless/internal/fixsafety"
+	"github.com/mickamy/pointless/internal/messages"
+	"github.com/mickamy/pointless/internal/rules"
+	"github.com/mickamy/pointless/internal/skipexpr"
 )
 
 // DefaultThreshold is the default size threshold in bytes.
@@ -21,214 +31,962 @@ const DefaultThreshold = 1024
 
 // Analyzer is the pointless analyzer.
 var Analyzer = &analysis.Analyzer{
-	Name:     "pointless",
-	Doc:      "suggests using value types instead of pointers for small structs",
-	Run:      run,
-	Requires: []*analysis.Analyzer{inspect.Analyzer},
+	Name:       "pointless",
+	Doc:        "suggests using value types instead of pointers for small structs",
+	URL:        "https://github.com/mickamy/pointless",
+	Run:        run,
+	Requires:   []*analysis.Analyzer{inspect.Analyzer},
+	FactTypes:  []analysis.Fact{(*interfacesFact)(nil), (*paramsFact)(nil), (*typeDirectiveFact)(nil), (*mutatesReceiverFact)(nil)},
+	ResultType: metricsType,
 }
 
 // threshold can be configured via flags.
 var threshold int
 
-// excludePatterns holds file patterns to exclude from analysis.
+// Options configures an analyzer created with New.
+type Options struct {
+	// Threshold is the size threshold, in ThresholdUnit.
+	Threshold int
+	// ThresholdUnit is the unit of Threshold: config.UnitBytes (the default if
+	// empty), config.UnitWords, or config.UnitCacheLines.
+	ThresholdUnit string
+	// Config holds the settings otherwise loaded from the config file.
+	Config config.Config
+	// ShowSuppressed also reports findings suppressed by nolint comments.
+	ShowSuppressed bool
+	// Explain is an -explain target ("file.go:line"), or "" to disable.
+	Explain string
+	// GroupByType merges the findings about each type into one diagnostic per package.
+	GroupByType bool
+	// AllowBreaking keeps suggested fixes that would break the package's
+	// public API, which are withheld by default.
+	AllowBreaking bool
+	// Strict reports the cases the heuristics cannot decide as needs-review
+	// findings instead of skipping them.
+	Strict bool
+	// Verbose appends the field-by-field layout of the struct to each finding,
+	// and implies TypeDefinitions.
+	Verbose bool
+	// TypeDefinitions attaches the definition of the type, with the size
+	// breakdown of structs, to findings about it as related information. Text
+	// output prints it as one more line per finding, so it is meant for
+	// structured output, such as JSON and SARIF.
+	TypeDefinitions bool
+	// SkipIf, if set, exempts the types it returns true for, like the
+	// skip-if config. It is called with the named or pointer type a finding is
+	// about, before the finding is reported.
+	SkipIf func(t types.Type) bool
+}
+
+// New returns an analyzer with fixed options. Unlike Analyzer, it ignores flags
+// and SetConfig, so analyzers with different options can run concurrently.
+func New(opts Options) *analysis.Analyzer {
+	return &analysis.Analyzer{
+		Name:       Analyzer.Name,
+		Doc:        Analyzer.Doc,
+		URL:        Analyzer.URL,
+		Run:        func(pass *analysis.Pass) (interface{}, error) { return runWith(pass, opts) },
+		Requires:   Analyzer.Requires,
+		FactTypes:  Analyzer.FactTypes,
+		ResultType: Analyzer.ResultType,
+	}
+}
+
+// thresholdUnit can be configured via flags; "" defers to the config file.
+var thresholdUnit string
+
+// enableRules lists opt-in rules enabled via flags, comma-separated.
+var enableRules string
+
+// verbose can be set via the -verbose flag (-v on the command line).
+var verbose bool
+
+// includeSymbols can be configured via flags; "" defers to the config file.
+var includeSymbols string
+
+// typeDefinitions is set by SetTypeDefinitions.
+var typeDefinitions bool
+
+// cfg holds the settings loaded from the config file, and workspace those of
+// the modules of a workspace, if set.
 var (
-	excludePatterns []string
-	excludeMu       sync.RWMutex
+	cfg       config.Config
+	workspace *config.Workspace
+	cfgMu     sync.RWMutex
 )
 
-// SetConfig sets the exclude patterns from config file.
-func SetConfig(exclude []string) {
-	excludeMu.Lock()
-	defer excludeMu.Unlock()
-	excludePatterns = exclude
+// SetConfig sets the exclude patterns and presets from config file.
+func SetConfig(c config.Config) {
+	cfgMu.Lock()
+	defer cfgMu.Unlock()
+	cfg = c
+}
+
+// SetTypeDefinitions makes Analyzer attach the definitions of types to
+// findings, as Options.TypeDefinitions does. Like the flags, it must be set
+// before the analysis starts; commands set it for structured output.
+func SetTypeDefinitions(b bool) {
+	typeDefinitions = b
+}
+
+// SetWorkspace applies the configs of the modules of a workspace to their
+// packages instead of the config set by SetConfig, including their
+// thresholds: to let the -threshold flag win, clear them.
+func SetWorkspace(ws config.Workspace) {
+	cfgMu.Lock()
+	defer cfgMu.Unlock()
+	workspace = &ws
+}
+
+// currentConfig returns the config for the package of pass: the config of its
+// module set by SetWorkspace, or the one set by SetConfig. perModule reports
+// the former.
+func currentConfig(pass *analysis.Pass) (c config.Config, perModule bool) {
+	cfgMu.RLock()
+	defer cfgMu.RUnlock()
+
+	if workspace == nil || len(pass.Files) == 0 {
+		return cfg, false
+	}
+
+	return workspace.ConfigFor(pass.Fset.File(pass.Files[0].Pos()).Name()), true
+}
+
+// state holds the per-pass facts gathered before the checks run.
+type state struct {
+	// opts holds the options the analyzer runs with.
+	opts Options
+	// minSize is the min-size in bytes: smaller types are not reported.
+	minSize int
+	// nilReturns maps functions that return nil to their first nil return.
+	nilReturns map[*ast.FuncDecl]token.Pos
+	// receiverMutations maps methods that mutate their receiver to the first mutation.
+	receiverMutations map[*ast.FuncDecl]token.Pos
+	// receiverEscapes maps methods that store the receiver's identity to the first such use.
+	receiverEscapes map[*ast.FuncDecl]token.Pos
+	// builders maps methods returning their pointer receiver for chaining to their first return.
+	builders builderMethods
+	// pointerIfaces finds methods required by, or called through, pointer-only interfaces.
+	pointerIfaces *pointerInterfaceMethods
+	// embedders maps the types of the package to the structs embedding them by value.
+	embedders embedders
+	// nilImpls finds other implementations of interface methods that return nil.
+	nilImpls *nilImplementations
+	// presets recognizes framework-managed types.
+	presets *presetMatcher
+	// implements recognizes types implementing an interface of ignore-implements.
+	implements *implementsMatcher
+	// fieldTypes recognizes structs with a field of a type of ignore-if-contains-field-type.
+	fieldTypes *fieldTypeMatcher
+	// skipIf is the parsed skip-if expression (nil if not configured).
+	skipIf *skipexpr.Expr
+	// symbols filters findings by the symbol they are in (nil if not configured).
+	symbols *symbolFilter
+	// excludeRules drops findings matching exclude-rules entries (nil if not configured).
+	excludeRules *excludeRules
+	// indirectUses maps pointer-to-reference variables that rely on the pointer to the first such use.
+	indirectUses map[types.Object]token.Pos
+	// ignoredSymbols holds the source ranges of symbols listed in ignore-symbols.
+	ignoredSymbols []span
+	// nolint holds the findings suppressed by nolint and //pointless: comments.
+	nolint suppressions
+	// explain prints decisions for the -explain target (nil if disabled).
+	explain *explainer
+	// localPointers classifies uses of function-scoped *T variables.
+	localPointers localPointerUses
+	// nils records nil comparisons and assignments of variables and fields.
+	nils nilIndex
+	// goroutines records pointers shared with goroutines.
+	goroutines goroutineShares
+	// jobChannels records the pointer channels of the package.
+	jobChannels jobChannels
+	// unsafeTypes records types converted with unsafe.Pointer or inspected with unsafe or reflect (nil with respect-unsafe: false).
+	unsafeTypes unsafeTypes
+	// params classifies the pointer parameters of the package's functions.
+	params paramUses
+	// addressArgs records the arguments of struct pointer parameters.
+	addressArgs addressArgs
+	// derefReturns records callers dereferencing struct pointer results.
+	derefReturns derefReturns
+	// resultWrites records callers writing through returned pointers.
+	resultWrites resultWrites
+	// zeroValues records the struct types whose zero value may not be usable.
+	zeroValues zeroValues
+	// errorReturns records (*T, error) functions only returning nil with an
+	// error, if the return-pointer-error rule is enabled.
+	errorReturns errorReturns
+	// sliceMakes maps make([]*T, ...) calls to the variable or field they fill.
+	sliceMakes sliceMakes
+	// sliceConversions records the helpers converting between []T and []*T,
+	// if the slice-conversion rule is enabled.
+	sliceConversions sliceConversions
+	// sortProxies records local []*T variables filled with value addresses, if
+	// the sort-pointer-slice rule is enabled.
+	sortProxies sortProxies
+	// fieldStores records slice fields storing existing pointers.
+	fieldStores fieldStores
+	// embeddedStores records embedded pointer fields set to existing pointers.
+	embeddedStores embeddedStores
+	// pointerWrites records types whose values are written through pointers.
+	pointerWrites pointerWrites
+	// mapKeys holds the pointer-keyed map types that decide the type of a map.
+	mapKeys mapKeySites
+	// comparisons records types whose pointers are compared with each other.
+	comparisons pointerComparisons
+	// enabled holds the opt-in rules to run.
+	enabled map[string]bool
+	// msg formats diagnostic messages.
+	msg *messages.Printer
+	// groups collects diagnostics per type in -group-by-type mode (nil otherwise).
+	groups *typeGroups
+	// metrics collects the pass's Metrics.
+	metrics *Metrics
+}
+
+// ruleEnabled reports whether the rule with the given ID runs: opt-in rules
+// must be enabled explicitly.
+func (st *state) ruleEnabled(id string) bool {
+	if r, ok := rules.Lookup(id); ok && r.OptIn {
+		return st.enabled[id]
+	}
+
+	return true
 }
 
 func init() {
-	Analyzer.Flags.IntVar(&threshold, "threshold", DefaultThreshold, "size threshold in bytes")
+	Analyzer.Flags.IntVar(&threshold, "threshold", DefaultThreshold, "size threshold, in -threshold-unit")
+	Analyzer.Flags.StringVar(&thresholdUnit, "threshold-unit", "", "unit of -threshold: bytes, words, or cachelines (default from config, else bytes)")
+	Analyzer.Flags.BoolVar(&showSuppressed, "show-suppressed", false, "also report findings suppressed by nolint comments")
+	Analyzer.Flags.StringVar(&enableRules, "enable", "", "comma-separated opt-in rules to enable, in addition to the config's enable list")
+	Analyzer.Flags.BoolVar(&groupByType, "group-by-type", false, "report one diagnostic per type and package, listing every site")
+	Analyzer.Flags.StringVar(&explainTarget, "explain", "", "explain why pointers at `file.go:line` were or were not flagged")
+	Analyzer.Flags.BoolVar(&allowBreaking, "allow-breaking", false, "also suggest fixes that would break the package's public API")
+	Analyzer.Flags.BoolVar(&strict, "strict", false, "report pointers the heuristics cannot analyze as needing manual review")
+	Analyzer.Flags.BoolVar(&verbose, "verbose", false, "append the field-by-field layout (type, offset, size, padding) of the struct to each finding, and with the pointless command, the time spent per package")
+	Analyzer.Flags.StringVar(&includeSymbols, "include-symbols", "", "only report findings in functions, methods, and variables whose name matches the `regexp` (default from config)")
 }
 
 func run(pass *analysis.Pass) (interface{}, error) {
+	c, perModule := currentConfig(pass)
+
+	limit := threshold
+	if perModule && c.Threshold > 0 {
+		limit = c.Threshold
+	}
+
+	unit := thresholdUnit
+	if unit == "" {
+		unit = c.ThresholdUnit
+	}
+
+	if enableRules != "" {
+		c.Enable = append(slices.Clip(c.Enable), strings.Split(enableRules, ",")...)
+	}
+
+	if includeSymbols != "" {
+		c.IncludeSymbols = includeSymbols
+	}
+
+	return runWith(pass, Options{
+		Threshold:       limit,
+		ThresholdUnit:   unit,
+		Config:          c,
+		ShowSuppressed:  showSuppressed,
+		Explain:         explainTarget,
+		GroupByType:     groupByType,
+		AllowBreaking:   allowBreaking,
+		Strict:          strict,
+		Verbose:         verbose,
+		TypeDefinitions: typeDefinitions,
+	})
+}
+
+func runWith(pass *analysis.Pass, opts Options) (interface{}, error) {
+	metrics := new(Metrics)
+
 	ispct, ok := pass.ResultOf[inspect.Analyzer].(*inspector.Inspector)
 	if !ok {
-		return nil, nil
+		return metrics, nil
 	}
 
-	// Build set of excluded files
-	excludedFiles := make(map[string]bool)
-	excludeMu.RLock()
-	patterns := excludePatterns
-	excludeMu.RUnlock()
+	start := time.Now()
 
-	if len(patterns) > 0 {
-		for _, f := range pass.Files {
-			filename := pass.Fset.File(f.Pos()).Name()
-			if shouldExclude(filename, patterns) {
-				excludedFiles[filename] = true
-			}
+	exportInterfacesFact(pass)
+	// Types annotated with //pointless:enforce, enforced in dependents too
+	exportTypeDirectives(pass)
+	interfaces := collectInterfaces(pass)
+	start = metrics.time(PhaseUsageScan, start)
+
+	// Interface methods returning nil, exported for implementations in dependents
+	nilImpls := findNilImplementations(pass, ispct, interfaces)
+	start = metrics.time(PhaseNilScan, start)
+
+	// Classify pointer parameters and export the read-only ones for dependents
+	params := findParamUses(pass, ispct)
+	metrics.time(PhaseMutationScan, start)
+
+	// Vendored and module cache packages only contribute facts: they are neither
+	// reported on nor scanned for nil and mutation uses
+	if !opts.Config.IncludeVendor && isThirdPartyPackage(pass) {
+		return metrics, nil
+	}
+
+	metrics.Packages = 1
+
+	// Sizes are compared in bytes from here on
+	limit, err := thresholdBytes(pass, opts.Threshold, opts.ThresholdUnit)
+	if err != nil {
+		return nil, err
+	}
+
+	opts.Threshold = limit
+
+	// Test files have a threshold of their own, if set
+	testLimit := limit
+	if opts.Config.Tests.Threshold > 0 {
+		testLimit, err = thresholdBytes(pass, opts.Config.Tests.Threshold, opts.ThresholdUnit)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	minSize, err := thresholdBytes(pass, opts.Config.MinSize, opts.ThresholdUnit)
+	if err != nil {
+		return nil, err
+	}
+
+	explain, err := newExplainer(opts.Explain)
+	if err != nil {
+		return nil, err
+	}
+
+	// Nothing is reported on standard library packages, so their decisions
+	// are neither explained nor logged
+	if isStandardLibraryPackage(pass) {
+		explain = nil
+	}
+
+	msg, err := messages.NewPrinter(opts.Config.Lang)
+	if err != nil {
+		return nil, fmt.Errorf("invalid lang: %w", err)
+	}
+
+	c := opts.Config
+
+	if err := validateSeverities(c); err != nil {
+		return nil, err
+	}
+
+	var skipIf *skipexpr.Expr
+
+	if c.SkipIf != "" {
+		expr, err := skipexpr.Parse(c.SkipIf)
+		if err != nil {
+			return nil, fmt.Errorf("invalid skip-if: %w", err)
 		}
+
+		skipIf = expr
+	}
+
+	symbols, err := newSymbolFilter(c.IncludeSymbols, c.IgnoreSymbolsRegex)
+	if err != nil {
+		return nil, err
+	}
+
+	excludeRules, err := newExcludeRules(c.ExcludeRules)
+	if err != nil {
+		return nil, err
 	}
 
-	// Build nolint comment map (line number -> true if has nolint)
-	nolintLines := buildNolintMap(pass)
+	// Find the files excluded by config, including via //line directives
+	excludedFiles := newExcludedFiles(pass, c)
+
+	st := &state{
+		opts:    opts,
+		minSize: minSize,
+		// Recognize types managed by configured framework presets
+		presets: newPresetMatcher(c.Presets),
+		// Recognize types implementing the interfaces listed in ignore-implements
+		implements: newImplementsMatcher(pass.Pkg, c.IgnoreImplements),
+		// Recognize structs with fields of the types listed in ignore-if-contains-field-type
+		fieldTypes: newFieldTypeMatcher(c.IgnoreIfContainsFieldType),
+		skipIf:     skipIf,
+		// Limit findings to the symbols of include-symbols and ignore-symbols-regex
+		symbols: symbols,
+		// Drop findings matching exclude-rules entries
+		excludeRules: excludeRules,
+		// Explain decisions for the -explain target, if any
+		explain: explain,
+		// Format diagnostics in the configured language
+		msg: msg,
+		// Opt-in rules enabled by config or flags
+		enabled: make(map[string]bool, len(c.Enable)),
+		// Pointer parameters only read through, here and (via facts) in dependencies
+		params:   params,
+		nilImpls: nilImpls,
+		metrics:  metrics,
+	}
+
+	start = time.Now()
 
 	// Track nil returns per function to avoid false positives
-	nilReturns := findNilReturns(ispct)
+	st.nilReturns = findNilReturns(ispct)
+	// Index nil comparisons/assignments of variables, fields, and elements
+	st.nils = buildNilIndex(pass, ispct)
+
+	start = metrics.time(PhaseNilScan, start)
 
 	// Track receiver mutations per method
-	receiverMutations := findReceiverMutations(pass, ispct)
+	st.receiverMutations = findReceiverMutations(pass, ispct, true)
+	exportMutationFacts(pass, st.receiverMutations)
+	// Track methods that store the receiver's identity elsewhere
+	st.receiverEscapes = findReceiverEscapes(pass, ispct, params)
+	// Builder methods returning their receiver
+	st.builders = findBuilderMethods(pass, ispct)
+	// Track pointer-to-reference variables that write through or pass on the pointer
+	st.indirectUses = findIndirectUses(pass, ispct)
+	// Track how function-scoped *T variables are used
+	st.localPointers = findLocalPointerUses(pass, ispct)
+	// Slice fields holding pointers owned elsewhere
+	st.fieldStores = findFieldStores(pass, ispct)
+	// Embedded pointer fields holding pointers owned elsewhere
+	st.embeddedStores = findEmbeddedStores(pass, ispct)
+	// Types whose values are written through pointers
+	st.pointerWrites = findPointerWrites(pass, ispct)
+
+	start = metrics.time(PhaseMutationScan, start)
+
+	// Track interfaces (local and from dependencies) satisfied only via pointer method sets
+	st.pointerIfaces = newPointerInterfaceMethods(pass, ispct, interfaces)
+	// Structs the methods of embedded types are promoted to
+	st.embedders = findEmbedders(pass)
+	// Ranges of symbols listed in ignore-symbols or the suppressions file
+	st.ignoredSymbols = findIgnoredSymbols(pass, append(slices.Clip(c.IgnoreSymbols), c.Suppressed...))
+	// Declarations suppressed by nolint comments
+	st.nolint = findSuppressions(pass, ispct, excludedFiles, msg, c.SeverityOf(rules.StaleNolint), c.DocsBaseURL)
+	// Track pointers captured by or passed to go statements
+	st.goroutines = findGoroutineShares(pass, ispct)
+	// Pointer channels, which may feed worker pools
+	st.jobChannels = findJobChannels(pass, ispct, st.nils)
+	// Track types whose addresses are used through unsafe.Pointer or uintptr,
+	// or whose layout is inspected
+	if c.UnsafeRespected() {
+		st.unsafeTypes = findUnsafeConversions(pass, ispct)
+	}
+	// Arguments passed to struct pointer parameters
+	st.addressArgs = findAddressArgs(pass, ispct)
+	// Calls whose struct pointer result is dereferenced at once
+	st.derefReturns = findDerefReturns(pass, ispct)
+	// Writes through pointers returned by the package's functions
+	st.resultWrites = findResultWrites(pass, ispct)
+	// Pointer slices made for a variable or struct field
+	st.sliceMakes = findSliceMakes(pass, ispct)
+	// Pointer-keyed map types where the type of a map is decided
+	st.mapKeys = findMapKeySites(pass, ispct)
+	// Comparisons of pointers with each other
+	st.comparisons = findPointerComparisons(pass, ispct)
+	// Struct types that must be initialized before use
+	st.zeroValues = findZeroValues(pass, ispct)
+
+	start = metrics.time(PhaseUsageScan, start)
+
+	for _, id := range c.Enable {
+		st.enabled[strings.TrimSpace(id)] = true
+	}
+
+	if opts.Strict {
+		st.enabled[rules.NeedsReview] = true
+	}
+
+	if st.ruleEnabled(rules.ReturnPointerError) {
+		st.errorReturns = findErrorReturns(pass, ispct, st.nils)
+	}
 
-	// Track nil comparisons/assignments for pointer slices
-	nilUsages := findNilUsages(ispct)
+	if st.ruleEnabled(rules.SortPointerSlice) {
+		st.sortProxies = findSortProxies(pass, ispct, st.localPointers)
+	}
+
+	if st.ruleEnabled(rules.SliceConversion) {
+		st.sliceConversions = findSliceConversions(pass, ispct)
+	}
+
+	if opts.GroupByType {
+		st.groups = newTypeGroups()
+	}
 
 	nodeFilter := []ast.Node{
 		(*ast.FuncDecl)(nil),
 		(*ast.GenDecl)(nil),
+		(*ast.CallExpr)(nil),
+		(*ast.IfStmt)(nil),
+		(*ast.ForStmt)(nil),
+		(*ast.SwitchStmt)(nil),
+		(*ast.TypeSwitchStmt)(nil),
+		(*ast.MapType)(nil),
 		(*ast.AssignStmt)(nil),
+		(*ast.ValueSpec)(nil),
+		(*ast.StarExpr)(nil),
+		(*ast.ChanType)(nil),
 	}
 
+	var file *token.File
+
 	ispct.Preorder(nodeFilter, func(n ast.Node) {
 		// Skip excluded files
-		filename := pass.Fset.File(n.Pos()).Name()
-		if excludedFiles[filename] {
+		if excludedFiles.contains(pass, n.Pos()) {
+			st.explain.excluded(pass, n.Pos(), "file is excluded by config")
+
 			return
 		}
 
-		// Skip if nolint comment is present
-		line := pass.Fset.Position(n.Pos()).Line
-		if nolintLines[line] {
+		// The checks compare sizes with the threshold of the file they are in
+		if tf := pass.Fset.File(n.Pos()); tf != file {
+			file = tf
+
+			st.opts.Threshold = limit
+			if config.IsTestFile(tf.Name()) {
+				st.opts.Threshold = testLimit
+			}
+		}
+
+		// Skip symbols listed in ignore-symbols
+		if containsPos(st.ignoredSymbols, n.Pos()) {
+			st.explain.excluded(pass, n.Pos(), "symbol is listed in ignore-symbols or the suppressions file")
+
 			return
 		}
 
 		switch node := n.(type) {
 		case *ast.FuncDecl:
-			checkFuncDecl(pass, node, nilReturns, receiverMutations)
+			checkFuncDecl(pass, node, st)
 		case *ast.GenDecl:
-			checkGenDecl(pass, node, nilUsages)
+			checkGenDecl(pass, node, st)
+			checkStructFieldSlices(pass, node, st)
+			checkEmbeddedPointers(pass, node, st)
+		case *ast.CallExpr:
+			checkCallExpr(pass, node, st)
+		case *ast.IfStmt:
+			checkInitPointerVars(pass, node.Init, st)
+		case *ast.ForStmt:
+			checkInitPointerVars(pass, node.Init, st)
+		case *ast.SwitchStmt:
+			checkInitPointerVars(pass, node.Init, st)
+		case *ast.TypeSwitchStmt:
+			checkInitPointerVars(pass, node.Init, st)
+		case *ast.MapType:
+			checkMapKey(pass, node, st)
 		case *ast.AssignStmt:
-			checkAssignStmt(pass, node, nilUsages)
+			checkDefinedAddresses(pass, node, st)
+		case *ast.ValueSpec:
+			checkDeclaredAddresses(pass, node, st)
+		case *ast.StarExpr:
+			checkDereferencedAddress(pass, node, st)
+			checkEnforcedPointer(pass, node, st)
+		case *ast.ChanType:
+			checkJobChannel(pass, node, st)
 		}
 	})
 
-	return nil, nil
+	st.flushGroups(pass)
+	st.explain.finish(pass)
+	metrics.time(PhaseChecks, start)
+
+	metrics.PackagePhases = map[string]map[string]time.Duration{pass.Pkg.Path(): maps.Clone(metrics.Phases)}
+
+	// Generated packages can take far longer than the rest: point them out, so
+	// that they can be excluded
+	if budget := c.Budget(); budget > 0 {
+		if total := Total(metrics.Phases); total > budget {
+			slog.Warn("package analysis exceeded package-budget; consider excluding its generated files", "package", pass.Pkg.Path(), "time", total.Round(time.Millisecond), "budget", budget)
+		}
+	}
+
+	if debugEnabled() {
+		attrs := []any{"package", pass.Pkg.Path(), "files", len(pass.Files)}
+		for _, phase := range Phases() {
+			attrs = append(attrs, phase, metrics.Phases[phase].Round(time.Microsecond))
+		}
+
+		slog.Debug("package analyzed", attrs...)
+	}
+
+	return metrics, nil
 }
 
 // checkFuncDecl checks function return types and method receivers.
-func checkFuncDecl(pass *analysis.Pass, fn *ast.FuncDecl, nilReturns, receiverMutations map[*ast.FuncDecl]bool) {
+func checkFuncDecl(pass *analysis.Pass, fn *ast.FuncDecl, st *state) {
 	// Check method receiver
 	if fn.Recv != nil && len(fn.Recv.List) > 0 {
-		checkMethodReceiver(pass, fn, receiverMutations)
+		checkMethodReceiver(pass, fn, st)
 	}
 
 	// Check return type
 	if fn.Type.Results != nil {
-		checkReturnType(pass, fn, nilReturns)
+		checkReturnType(pass, fn, st)
 	}
+
+	// Check pointers to maps, slices, channels, and functions
+	checkRefPointerParams(pass, fn, st)
+
+	// Check helpers converting between []T and []*T
+	checkSliceConversion(pass, fn, st)
+
+	// Check constructors taking *Options
+	if isConstructor(fn) {
+		checkOptionsParams(pass, fn, st)
+	}
+
+	// Check parameters every caller passes &x to
+	checkAddressArgParams(pass, fn, st)
 }
 
 // checkMethodReceiver checks if a pointer receiver could be a value receiver.
-func checkMethodReceiver(pass *analysis.Pass, fn *ast.FuncDecl, receiverMutations map[*ast.FuncDecl]bool) {
+func checkMethodReceiver(pass *analysis.Pass, fn *ast.FuncDecl, st *state) {
 	recv := fn.Recv.List[0]
 
-	star, ok := recv.Type.(*ast.StarExpr)
+	star, ok := ast.Unparen(recv.Type).(*ast.StarExpr)
 	if !ok {
 		return // already a value receiver
 	}
 
+	// Marker types have their own rule: there is nothing to mutate or copy
+	if tv, ok := pass.TypesInfo.Types[star.X]; ok && isEmptyStruct(tv.Type) {
+		if st.presets.matches(tv.Type) {
+			st.explain.skipped(pass, star.Pos(), "type matches a configured preset")
+		} else if name := st.implements.matching(tv.Type); name != "" {
+			st.explain.skipped(pass, star.Pos(), "type implements %s, listed in ignore-implements", name)
+		} else if !st.unsafeTypes.skipped(pass, st, star.Pos(), tv.Type) {
+			checkEmptyReceiver(pass, fn, star, tv.Type, st)
+		}
+
+		return
+	}
+
+	// Builder methods have their own rule: chains share the pointer
+	if _, ok := st.builders[fn]; ok {
+		checkBuilderMethod(pass, fn, star, st)
+
+		return
+	}
+
 	// Skip if receiver is mutated
-	if receiverMutations[fn] {
+	if pos := st.receiverMutations[fn]; pos.IsValid() {
+		st.explain.skipped(pass, star.Pos(), "receiver is mutated at line %d", lineOf(pass, pos))
+
+		// Counters have an advisory rule of their own
+		checkAtomicCounter(pass, fn, star, st)
+
+		return
+	}
+
+	// Skip if receiver identity is stored (e.g. registry.Add(s), s.parent.child = s)
+	if pos := st.receiverEscapes[fn]; pos.IsValid() {
+		st.explain.skipped(pass, star.Pos(), "receiver identity is stored at line %d", lineOf(pass, pos))
+
 		return
 	}
 
+	// Skip if a goroutine shares the receiver (go s.loop(), go func() { s.x }())
+	if len(recv.Names) > 0 {
+		if pos := st.goroutines.values[pass.TypesInfo.Defs[recv.Names[0]]]; pos.IsValid() {
+			st.explain.skipped(pass, star.Pos(), "receiver is shared with a goroutine at line %d", lineOf(pass, pos))
+
+			return
+		}
+	}
+
 	// Get the underlying type
 	tv, ok := pass.TypesInfo.Types[star.X]
 	if !ok {
+		st.explain.skipped(pass, star.Pos(), "type information is unavailable")
+		st.needsReview(pass, star.Pos(), messages.ReviewNoTypeInfo)
+
+		return
+	}
+
+	// Methods declared on an alias belong to the aliased type
+	t := types.Unalias(tv.Type)
+
+	// Skip if the method is part of an interface only *T satisfies, and *T is held through it
+	if use, ok := st.pointerIfaces.lookup(fn, t); ok {
+		if use.called {
+			st.explain.skipped(pass, star.Pos(), "method is called through an interface at line %d whose dynamic type can only be the pointer type", lineOf(pass, use.pos))
+		} else {
+			st.explain.skipped(pass, star.Pos(), "method belongs to %s, which only the pointer type implements and which it is converted to at line %d", typeString(pass, use.iface), lineOf(pass, use.pos))
+		}
+
 		return
 	}
 
-	size := sizeOf(pass, tv.Type)
-	if size > int64(threshold) {
+	// Skip if the method is promoted to a struct embedding T that satisfies such an interface
+	if outer, use, ok := st.promotedPointerMethod(pass, fn, t); ok {
+		if use.called {
+			st.explain.skipped(pass, star.Pos(), "method is promoted to %s and called through an interface at line %d whose dynamic type can only be *%s", outer.Obj().Name(), lineOf(pass, use.pos), outer.Obj().Name())
+		} else {
+			st.explain.skipped(pass, star.Pos(), "method is promoted to %s and belongs to %s, which only *%s implements and which it is converted to at line %d", outer.Obj().Name(), typeString(pass, use.iface), outer.Obj().Name(), lineOf(pass, use.pos))
+		}
+
+		return
+	}
+
+	// Skip types managed by a framework preset
+	if st.presets.matches(t) {
+		st.explain.skipped(pass, star.Pos(), "type matches a configured preset")
+
+		return
+	}
+
+	// Skip types implementing an interface listed in ignore-implements
+	if name := st.implements.matching(t); name != "" {
+		st.explain.skipped(pass, star.Pos(), "type implements %s, listed in ignore-implements", name)
+
+		return
+	}
+
+	// Skip types whose pointers go through unsafe.Pointer, or whose layout is inspected
+	if st.unsafeTypes.skipped(pass, st, star.Pos(), t) {
+		return
+	}
+
+	// Skip if the size depends on the type arguments
+	if !hasFixedSize(t) {
+		st.explain.skipped(pass, star.Pos(), "the size of %s depends on its type arguments", typeString(pass, t))
+		st.needsReview(pass, star.Pos(), messages.ReviewTypeArgs, typeString(pass, t))
+
+		return
+	}
+
+	size := sizeOf(pass, t)
+	if size > int64(st.opts.Threshold) {
+		st.explain.skipped(pass, star.Pos(), "%s is %d bytes > threshold %d bytes", typeString(pass, t), size, st.opts.Threshold)
+
 		return // struct is too large
 	}
 
-	typeName := types.TypeString(tv.Type, types.RelativeTo(pass.Pkg))
-	pass.Reportf(fn.Pos(), "consider using value receiver: %s is %d bytes (threshold: %d bytes) and method doesn't mutate receiver", typeName, size, threshold)
+	if size < int64(st.minSize) {
+		st.explain.skipped(pass, star.Pos(), "%s is %d bytes < min-size %d bytes", typeString(pass, t), size, st.minSize)
+
+		return // struct is too small to bother
+	}
+
+	typeName := types.TypeString(t, types.RelativeTo(pass.Pkg))
+	diag := analysis.Diagnostic{
+		Pos:      star.Pos(),
+		End:      star.End(),
+		Category: rules.ValueReceiver,
+		Message:  st.msg.Sprintf(messages.ValueReceiver, typeName, size, st.bounds()),
+		Related:  st.typeRelated(pass, t),
+	}
+
+	if fix, ok := valueReceiverFix(pass, fn, star, st); ok {
+		diag.SuggestedFixes = []analysis.SuggestedFix{fix}
+	} else {
+		st.explain.fixWithheld(pass, star.Pos(), "a use of the receiver relies on it being a pointer")
+	}
+
+	st.report(pass, t, diag)
+}
+
+// checkEmptyReceiver reports a pointer receiver on a zero-field struct. The suggested
+// fix is only offered when the receiver is unused or only used to call methods,
+// since both keep compiling with a value receiver.
+func checkEmptyReceiver(pass *analysis.Pass, fn *ast.FuncDecl, star *ast.StarExpr, t types.Type, st *state) {
+	typeName := types.TypeString(t, types.RelativeTo(pass.Pkg))
+	diag := analysis.Diagnostic{
+		Pos:      star.Pos(),
+		End:      star.End(),
+		Category: rules.EmptyReceiver,
+		Message:  st.msg.Sprintf(messages.EmptyReceiver, typeName),
+		Related:  st.typeRelated(pass, t),
+	}
+
+	if evidence, ok := emptyReceiverFixEvidence(pass, fn, st); ok {
+		diag.SuggestedFixes = []analysis.SuggestedFix{{
+			Message: fixsafety.Tag(st.msg.Sprintf(messages.EmptyReceiverFix), evidence),
+			TextEdits: []analysis.TextEdit{{
+				Pos:     star.Pos(),
+				End:     star.X.Pos(),
+				NewText: nil,
+			}},
+		}}
+	}
+
+	st.report(pass, t, diag)
+}
+
+// emptyReceiverFixEvidence reports whether every use of the receiver is the
+// operand of a method call selector, and how safe dropping the * is: calls of
+// pointer-receiver methods get the address of a copy afterwards, which only
+// matters if they compare addresses.
+func emptyReceiverFixEvidence(pass *analysis.Pass, fn *ast.FuncDecl, st *state) (fixsafety.Evidence, bool) {
+	unused := fixsafety.Evidence{Level: fixsafety.Safe, Reasons: []string{st.msg.Sprintf(messages.FixReceiverUnused)}}
+
+	recv := fn.Recv.List[0]
+	if len(recv.Names) == 0 || fn.Body == nil {
+		return unused, true
+	}
+
+	obj := pass.TypesInfo.Defs[recv.Names[0]]
+	if obj == nil {
+		return unused, true
+	}
+
+	safe := true
+	used, pointerMethods := false, false
+	selected := make(map[*ast.Ident]bool)
+
+	ast.Inspect(fn.Body, func(n ast.Node) bool {
+		if !safe {
+			return false
+		}
+
+		switch node := n.(type) {
+		case *ast.SelectorExpr:
+			if ident, ok := node.X.(*ast.Ident); ok {
+				if sel, ok := pass.TypesInfo.Selections[node]; ok && sel.Kind() == types.MethodVal {
+					selected[ident] = true
+
+					if sig, ok := sel.Obj().Type().(*types.Signature); ok && sig.Recv() != nil && isPointer(sig.Recv().Type()) {
+						pointerMethods = pointerMethods || pass.TypesInfo.Uses[ident] == obj
+					}
+				}
+			}
+		case *ast.Ident:
+			if pass.TypesInfo.Uses[node] == obj {
+				used = true
+				safe = safe && selected[node]
+			}
+		}
+
+		return true
+	})
+
+	switch {
+	case !safe:
+		return fixsafety.Evidence{}, false
+	case pointerMethods:
+		return fixsafety.Evidence{Level: fixsafety.Likely, Reasons: []string{st.msg.Sprintf(messages.FixReceiverPointerMethods)}}, true
+	case used:
+		return fixsafety.Evidence{Level: fixsafety.Safe, Reasons: []string{st.msg.Sprintf(messages.FixReceiverValueMethods)}}, true
+	}
+
+	return unused, true
+}
+
+// isPointer reports whether t is a pointer type.
+func isPointer(t types.Type) bool {
+	_, ok := t.Underlying().(*types.Pointer)
+
+	return ok
+}
+
+// isEmptyStruct reports whether t is a struct type without fields.
+func isEmptyStruct(t types.Type) bool {
+	st, ok := t.Underlying().(*types.Struct)
+
+	return ok && st.NumFields() == 0
 }
 
 // checkReturnType checks if a pointer return type could be a value type.
-func checkReturnType(pass *analysis.Pass, fn *ast.FuncDecl, nilReturns map[*ast.FuncDecl]bool) {
+func checkReturnType(pass *analysis.Pass, fn *ast.FuncDecl, st *state) {
 	for _, result := range fn.Type.Results.List {
-		switch t := result.Type.(type) {
+		switch t := ast.Unparen(result.Type).(type) {
 		case *ast.StarExpr:
-			checkPointerReturn(pass, fn, t, nilReturns)
+			checkPointerReturn(pass, fn, t, st)
 		case *ast.ArrayType:
-			checkSliceReturn(pass, fn, t, nilReturns)
+			checkSliceReturn(pass, fn, t, st)
+		case *ast.Ident, *ast.SelectorExpr:
+			checkNamedPointerReturn(pass, fn, t, st)
 		}
 	}
 }
 
 // checkPointerReturn checks a pointer return type.
-func checkPointerReturn(pass *analysis.Pass, fn *ast.FuncDecl, star *ast.StarExpr, nilReturns map[*ast.FuncDecl]bool) {
+func checkPointerReturn(pass *analysis.Pass, fn *ast.FuncDecl, star *ast.StarExpr, st *state) {
 	// Skip if function returns nil
-	if nilReturns[fn] {
+	if pos := st.nilReturns[fn]; pos.IsValid() {
+		// The opt-in return-pointer-error rule covers nil results that always come with an error
+		if st.errorReturns.nilWithError[fn] {
+			checkErrorPointerReturn(pass, fn, star, st)
+
+			return
+		}
+
+		st.explain.skipped(pass, star.Pos(), "function returns nil at line %d", lineOf(pass, pos))
+
 		return
 	}
 
-	tv, ok := pass.TypesInfo.Types[star.X]
-	if !ok {
+	// Skip builder methods: the value-builder rule covers them
+	if pos, ok := st.builders[fn]; ok {
+		st.explain.skipped(pass, star.Pos(), "method returns its receiver at line %d for chaining (builder pattern)", lineOf(pass, pos))
+
 		return
 	}
 
-	// Only check structs
-	if _, ok := tv.Type.Underlying().(*types.Struct); !ok {
+	// Skip if other implementations of the interface method return nil
+	if st.skipNilImplementation(pass, fn, star.Pos()) {
 		return
 	}
 
-	size := sizeOf(pass, tv.Type)
-	if size > int64(threshold) {
+	// Skip if callers write through the result, which may be shared
+	if pos := st.resultWrites.first(pass, fn); pos.IsValid() {
+		st.explain.skipped(pass, star.Pos(), "a caller writes through the result at line %d, which may rely on sharing it", lineOf(pass, pos))
+
 		return
 	}
 
-	typeName := types.TypeString(tv.Type, types.RelativeTo(pass.Pkg))
-	pass.Reportf(star.Pos(), "consider returning value instead of pointer: %s is %d bytes (threshold: %d bytes)", typeName, size, threshold)
+	t, size, ok := smallStruct(pass, st, star.Pos(), star.X)
+	if !ok {
+		return
+	}
+
+	typeName := typeString(pass, t)
+
+	if sites := st.derefReturns.callers(pass, fn); len(sites) > 0 {
+		reportDereferencedReturn(pass, fn, st, star, t, typeName, size, sites)
+
+		return
+	}
+
+	st.reportf(pass, t, rules.ReturnPointer, star, messages.ReturnPointer, typeName, size, st.bounds())
 }
 
 // checkSliceReturn checks a slice return type for pointer elements.
-func checkSliceReturn(pass *analysis.Pass, fn *ast.FuncDecl, arr *ast.ArrayType, nilReturns map[*ast.FuncDecl]bool) {
+func checkSliceReturn(pass *analysis.Pass, fn *ast.FuncDecl, arr *ast.ArrayType, st *state) {
 	if arr.Len != nil {
 		return // array, not slice
 	}
 
-	star, ok := arr.Elt.(*ast.StarExpr)
+	elem, named, ok := pointerElem(pass, arr.Elt)
 	if !ok {
 		return // not a pointer slice
 	}
 
-	// Skip if function returns nil (for the slice itself)
-	if nilReturns[fn] {
-		return
-	}
+	// The opt-in slice-conversion rule reports the helper as a whole
+	if _, ok := st.sliceConversions.funcs[fn]; ok {
+		st.explain.skipped(pass, arr.Pos(), "%s converts a value slice, which the slice-conversion rule reports", fn.Name.Name)
 
-	tv, ok := pass.TypesInfo.Types[star.X]
-	if !ok {
 		return
 	}
 
-	// Only check structs
-	if _, ok := tv.Type.Underlying().(*types.Struct); !ok {
+	// Skip if function returns nil (for the slice itself)
+	if pos := st.nilReturns[fn]; pos.IsValid() {
+		st.explain.skipped(pass, arr.Pos(), "function returns nil at line %d", lineOf(pass, pos))
+
 		return
 	}
 
-	size := sizeOf(pass, tv.Type)
-	if size > int64(threshold) {
+	t, size, ok := smallStructType(pass, st, arr.Pos(), elem)
+	if !ok {
 		return
 	}
 
-	typeName := types.TypeString(tv.Type, types.RelativeTo(pass.Pkg))
-	pass.Reportf(arr.Pos(), "consider using []%s instead of []*%s: better cache locality and lower GC pressure (%d bytes, threshold: %d bytes)", typeName, typeName, size, threshold)
+	reportSlicePointer(pass, st, arr, t, named, size, typeString)
 }
 
-// checkGenDecl checks variable declarations for pointer slices.
-func checkGenDecl(pass *analysis.Pass, decl *ast.GenDecl, nilUsages map[token.Pos]bool) {
+// checkGenDecl checks variable declarations for pointer slices and pointers to reference types.
+func checkGenDecl(pass *analysis.Pass, decl *ast.GenDecl, st *state) {
 	if decl.Tok != token.VAR {
 		return
 	}
@@ -239,143 +997,157 @@ func checkGenDecl(pass *analysis.Pass, decl *ast.GenDecl, nilUsages map[token.Po
 			continue
 		}
 
-		arr, ok := vs.Type.(*ast.ArrayType)
-		if !ok || arr.Len != nil {
+		// Skip variables listed in ignore-symbols (grouped declarations)
+		if containsPos(st.ignoredSymbols, vs.Pos()) {
 			continue
 		}
 
-		star, ok := arr.Elt.(*ast.StarExpr)
-		if !ok {
-			continue
+		if vs.Type != nil {
+			checkRefPointerField(pass, vs.Type, vs.Names, st)
+			checkLocalPointerVar(pass, vs, st)
 		}
 
-		// Check if any of the declared names have nil usage
-		hasNilUsage := false
-		for _, name := range vs.Names {
-			if obj := pass.TypesInfo.Defs[name]; obj != nil {
-				if nilUsages[obj.Pos()] {
-					hasNilUsage = true
-
-					break
-				}
-			}
-		}
-
-		if hasNilUsage {
+		arr, ok := ast.Unparen(vs.Type).(*ast.ArrayType)
+		if !ok || arr.Len != nil {
 			continue
 		}
 
-		tv, ok := pass.TypesInfo.Types[star.X]
+		elem, named, ok := pointerElem(pass, arr.Elt)
 		if !ok {
 			continue
 		}
 
-		if _, ok := tv.Type.Underlying().(*types.Struct); !ok {
-			continue
-		}
-
-		size := sizeOf(pass, tv.Type)
-		if size > int64(threshold) {
-			continue
-		}
+		// The opt-in sort-pointer-slice rule covers slices only filled with
+		// value addresses to be sorted or iterated, and slice-conversion the
+		// slices of conversion helpers
+		if len(vs.Names) == 1 && named == nil {
+			if obj := pass.TypesInfo.Defs[vs.Names[0]]; obj != nil {
+				if c, ok := st.sliceConversions.results[obj]; ok {
+					st.explain.skipped(pass, arr.Pos(), "%s converts a value slice, which the slice-conversion rule reports", c.fn.Name())
 
-		typeName := types.TypeString(tv.Type, nil)
-		pass.Reportf(arr.Pos(), "consider using []%s instead of []*%s: better cache locality and lower GC pressure (%d bytes, threshold: %d bytes)", typeName, typeName, size, threshold)
-	}
-}
+					continue
+				}
 
-// checkAssignStmt checks short variable declarations for pointer slices.
-func checkAssignStmt(pass *analysis.Pass, stmt *ast.AssignStmt, nilUsages map[token.Pos]bool) {
-	if stmt.Tok != token.DEFINE {
-		return
-	}
+				if p, ok := st.sortProxies.covered(obj); ok {
+					checkSortPointerSlice(pass, arr, elem, obj, p, st)
 
-	for i, rhs := range stmt.Rhs {
-		call, ok := rhs.(*ast.CallExpr)
-		if !ok {
-			continue
+					continue
+				}
+			}
 		}
 
-		// Check for make([]*T, ...)
-		ident, ok := call.Fun.(*ast.Ident)
-		if !ok || ident.Name != "make" {
-			continue
-		}
+		// Check if any of the declared names have nil usage
+		nilUsage := token.NoPos
+		for _, name := range vs.Names {
+			if obj := pass.TypesInfo.Defs[name]; obj != nil {
+				if pos := st.nils.element(obj); pos.IsValid() {
+					nilUsage = pos
 
-		if len(call.Args) < 1 {
-			continue
+					break
+				}
+			}
 		}
 
-		arr, ok := call.Args[0].(*ast.ArrayType)
-		if !ok || arr.Len != nil {
-			continue
-		}
+		if nilUsage.IsValid() {
+			st.explain.skipped(pass, arr.Pos(), "elements are compared with or set to nil at line %d", lineOf(pass, nilUsage))
 
-		star, ok := arr.Elt.(*ast.StarExpr)
-		if !ok {
 			continue
 		}
 
-		// Check if the variable has nil usage
-		if i < len(stmt.Lhs) {
-			if ident, ok := stmt.Lhs[i].(*ast.Ident); ok {
-				if obj := pass.TypesInfo.Defs[ident]; obj != nil {
-					if nilUsages[obj.Pos()] {
-						continue
-					}
+		// Check if any of the declared names share elements with goroutines
+		shared, sharer := token.NoPos, types.Object(nil)
+		for _, name := range vs.Names {
+			if obj := pass.TypesInfo.Defs[name]; obj != nil {
+				if pos := st.goroutines.slice(obj); pos.IsValid() {
+					shared, sharer = pos, obj
+
+					break
 				}
 			}
 		}
 
-		tv, ok := pass.TypesInfo.Types[star.X]
-		if !ok {
-			continue
-		}
+		if shared.IsValid() {
+			if named == nil {
+				checkSharedSlice(pass, arr, elem, sharer, shared, st)
+			} else {
+				st.explain.skipped(pass, arr.Pos(), "elements are shared with a goroutine at line %d", lineOf(pass, shared))
+			}
 
-		if _, ok := tv.Type.Underlying().(*types.Struct); !ok {
 			continue
 		}
 
-		size := sizeOf(pass, tv.Type)
-		if size > int64(threshold) {
+		t, size, ok := smallStructType(pass, st, arr.Pos(), elem)
+		if !ok {
 			continue
 		}
 
-		typeName := types.TypeString(tv.Type, nil)
-		pass.Reportf(arr.Pos(), "consider using []%s instead of []*%s: better cache locality and lower GC pressure (%d bytes, threshold: %d bytes)", typeName, typeName, size, threshold)
+		reportSlicePointer(pass, st, arr, t, named, size, qualifiedTypeString)
 	}
 }
 
 // findNilReturns finds all functions that return nil.
-func findNilReturns(inspect *inspector.Inspector) map[*ast.FuncDecl]bool {
-	result := make(map[*ast.FuncDecl]bool)
+func findNilReturns(inspect *inspector.Inspector) map[*ast.FuncDecl]token.Pos {
+	result := make(map[*ast.FuncDecl]token.Pos)
+
+	for _, funcs := range scanFiles(inspect, fileNilReturns) {
+		maps.Copy(result, funcs)
+	}
+
+	return result
+}
+
+// fileNilReturns finds the functions of a file that return nil.
+func fileNilReturns(file inspector.Cursor) map[*ast.FuncDecl]token.Pos {
+	result := make(map[*ast.FuncDecl]token.Pos)
 	var currentFunc *ast.FuncDecl
 
-	inspect.Preorder([]ast.Node{(*ast.FuncDecl)(nil), (*ast.ReturnStmt)(nil)}, func(n ast.Node) {
-		switch node := n.(type) {
+	for c := range file.Preorder((*ast.FuncDecl)(nil), (*ast.ReturnStmt)(nil)) {
+		switch node := c.Node().(type) {
 		case *ast.FuncDecl:
 			currentFunc = node
 		case *ast.ReturnStmt:
 			if currentFunc == nil {
-				return
+				continue
+			}
+
+			if _, ok := result[currentFunc]; ok {
+				continue
 			}
 
 			for _, expr := range node.Results {
 				if isNil(expr) {
-					result[currentFunc] = true
+					result[currentFunc] = node.Pos()
 
-					return
+					break
 				}
 			}
 		}
-	})
+	}
+
+	return result
+}
+
+// findReceiverMutations finds all methods that mutate their receiver, by
+// assigning to it or selecting a mutating method on it (see
+// addMutatingCalls). With facts, methods of other packages are known to
+// mutate by their mutatesReceiverFact.
+func findReceiverMutations(pass *analysis.Pass, inspect *inspector.Inspector, facts bool) map[*ast.FuncDecl]token.Pos {
+	result := make(map[*ast.FuncDecl]token.Pos)
+
+	for _, methods := range scanFiles(inspect, func(file inspector.Cursor) map[*ast.FuncDecl]token.Pos {
+		return fileReceiverMutations(pass, file)
+	}) {
+		maps.Copy(result, methods)
+	}
+
+	addMutatingCalls(pass, inspect, result, facts)
 
 	return result
 }
 
-// findReceiverMutations finds all methods that mutate their receiver.
-func findReceiverMutations(pass *analysis.Pass, inspect *inspector.Inspector) map[*ast.FuncDecl]bool {
-	result := make(map[*ast.FuncDecl]bool)
+// fileReceiverMutations finds the methods of a file that mutate their receiver.
+func fileReceiverMutations(pass *analysis.Pass, file inspector.Cursor) map[*ast.FuncDecl]token.Pos {
+	result := make(map[*ast.FuncDecl]token.Pos)
 	var currentFunc *ast.FuncDecl
 	var receiverObj types.Object
 
@@ -385,8 +1157,8 @@ func findReceiverMutations(pass *analysis.Pass, inspect *inspector.Inspector) ma
 		(*ast.IncDecStmt)(nil),
 	}
 
-	inspect.Preorder(nodeFilter, func(n ast.Node) {
-		switch node := n.(type) {
+	for c := range file.Preorder(nodeFilter...) {
+		switch node := c.Node().(type) {
 		case *ast.FuncDecl:
 			currentFunc = node
 			receiverObj = nil
@@ -398,27 +1170,27 @@ func findReceiverMutations(pass *analysis.Pass, inspect *inspector.Inspector) ma
 				}
 			}
 		case *ast.AssignStmt:
-			if currentFunc == nil || receiverObj == nil {
-				return
+			if currentFunc == nil || receiverObj == nil || result[currentFunc].IsValid() {
+				continue
 			}
 
 			for _, lhs := range node.Lhs {
 				if refersToReceiver(pass, lhs, receiverObj) {
-					result[currentFunc] = true
+					result[currentFunc] = node.Pos()
 
-					return
+					break
 				}
 			}
 		case *ast.IncDecStmt:
-			if currentFunc == nil || receiverObj == nil {
-				return
+			if currentFunc == nil || receiverObj == nil || result[currentFunc].IsValid() {
+				continue
 			}
 
 			if refersToReceiver(pass, node.X, receiverObj) {
-				result[currentFunc] = true
+				result[currentFunc] = node.Pos()
 			}
 		}
-	})
+	}
 
 	return result
 }
@@ -441,165 +1213,203 @@ func refersToReceiver(pass *analysis.Pass, expr ast.Expr, receiverObj types.Obje
 	return false
 }
 
-// findNilUsages finds all variables that are used with nil (comparison or assignment).
-func findNilUsages(inspect *inspector.Inspector) map[token.Pos]bool {
-	result := make(map[token.Pos]bool)
+// findReceiverEscapes finds all methods that store the receiver pointer itself,
+// either by assigning it, passing it as a call argument, placing it in a composite
+// literal, or sending it on a channel. A value receiver would store a copy instead.
+// Arguments of read-only pointer parameters (see paramsFact) are not stored.
+func findReceiverEscapes(pass *analysis.Pass, inspect *inspector.Inspector, params paramUses) map[*ast.FuncDecl]token.Pos {
+	result := make(map[*ast.FuncDecl]token.Pos)
+	var currentFunc *ast.FuncDecl
+	var receiverObj types.Object
 
 	nodeFilter := []ast.Node{
-		(*ast.BinaryExpr)(nil),
+		(*ast.FuncDecl)(nil),
 		(*ast.AssignStmt)(nil),
+		(*ast.CallExpr)(nil),
+		(*ast.CompositeLit)(nil),
+		(*ast.SendStmt)(nil),
 	}
 
 	inspect.Preorder(nodeFilter, func(n ast.Node) {
+		if fn, ok := n.(*ast.FuncDecl); ok {
+			currentFunc = fn
+			receiverObj = nil
+
+			if fn.Recv != nil && len(fn.Recv.List) > 0 {
+				recv := fn.Recv.List[0]
+				if len(recv.Names) > 0 {
+					receiverObj = pass.TypesInfo.Defs[recv.Names[0]]
+				}
+			}
+
+			return
+		}
+
+		if currentFunc == nil || receiverObj == nil || result[currentFunc].IsValid() {
+			return
+		}
+
+		var values []ast.Expr
+
 		switch node := n.(type) {
-		case *ast.BinaryExpr:
-			checkBinaryExprForNil(node, result)
 		case *ast.AssignStmt:
-			checkAssignStmtForNil(node, result)
+			values = node.Rhs
+		case *ast.CallExpr:
+			for _, arg := range node.Args {
+				if callee, index, ok := paramCallee(pass, node, arg); !ok || !params.isReadOnly(pass, callee, index) {
+					values = append(values, arg)
+				}
+			}
+		case *ast.CompositeLit:
+			for _, elt := range node.Elts {
+				if kv, ok := elt.(*ast.KeyValueExpr); ok {
+					values = append(values, kv.Key, kv.Value)
+				} else {
+					values = append(values, elt)
+				}
+			}
+		case *ast.SendStmt:
+			values = []ast.Expr{node.Value}
+		}
+
+		for _, v := range values {
+			if isReceiverIdent(pass, v, receiverObj) {
+				result[currentFunc] = v.Pos()
+
+				return
+			}
 		}
 	})
 
 	return result
 }
 
-func checkBinaryExprForNil(node *ast.BinaryExpr, result map[token.Pos]bool) {
-	// Check for slice[i] == nil or slice[i] != nil
-	if node.Op != token.EQL && node.Op != token.NEQ {
-		return
-	}
+// isReceiverIdent checks if an expression is the receiver itself (not one of its fields).
+func isReceiverIdent(pass *analysis.Pass, expr ast.Expr, receiverObj types.Object) bool {
+	ident, ok := ast.Unparen(expr).(*ast.Ident)
 
-	checkIndexExprForNil(node.X, node.Y, result)
-	checkIndexExprForNil(node.Y, node.X, result)
+	return ok && pass.TypesInfo.Uses[ident] == receiverObj
 }
 
-func checkIndexExprForNil(indexSide, nilSide ast.Expr, result map[token.Pos]bool) {
-	if !isNil(nilSide) {
-		return
-	}
+// isNil checks if an expression is the nil identifier.
+func isNil(expr ast.Expr) bool {
+	ident, ok := expr.(*ast.Ident)
+
+	return ok && ident.Name == "nil"
+}
 
-	idx, ok := indexSide.(*ast.IndexExpr)
+// smallStruct resolves the type of expr and reports whether it is a candidate for
+// a value type: a struct with known type information, not managed by a preset,
+// and within the threshold. Explanations are attributed to pos.
+func smallStruct(pass *analysis.Pass, st *state, pos token.Pos, expr ast.Expr) (types.Type, int64, bool) {
+	tv, ok := pass.TypesInfo.Types[expr]
 	if !ok {
-		return
+		st.explain.skipped(pass, pos, "type information is unavailable")
+		st.needsReview(pass, pos, messages.ReviewNoTypeInfo)
+
+		return nil, 0, false
 	}
 
-	ident, ok := idx.X.(*ast.Ident)
-	if !ok {
-		return
+	return smallStructType(pass, st, pos, tv.Type)
+}
+
+// smallStructType is smallStruct for a resolved type.
+func smallStructType(pass *analysis.Pass, st *state, pos token.Pos, t types.Type) (types.Type, int64, bool) {
+	// Skip if the type argument decides what is pointed to
+	if _, ok := types.Unalias(t).(*types.TypeParam); ok {
+		st.explain.skipped(pass, pos, "%s is a type parameter", typeString(pass, t))
+		st.needsReview(pass, pos, messages.ReviewTypeParam, typeString(pass, t))
+
+		return nil, 0, false
 	}
 
-	if ident.Obj != nil {
-		result[ident.Obj.Pos()] = true
+	// Only check structs
+	if _, ok := t.Underlying().(*types.Struct); !ok {
+		st.explain.skipped(pass, pos, "%s is not a struct", typeString(pass, t))
+
+		return nil, 0, false
 	}
-}
 
-func checkAssignStmtForNil(node *ast.AssignStmt, result map[token.Pos]bool) {
-	// Check for slice[i] = nil
-	for i, lhs := range node.Lhs {
-		idx, ok := lhs.(*ast.IndexExpr)
-		if !ok {
-			continue
-		}
+	if st.presets.matches(t) {
+		st.explain.skipped(pass, pos, "type matches a configured preset")
 
-		if i >= len(node.Rhs) || !isNil(node.Rhs[i]) {
-			continue
-		}
+		return nil, 0, false
+	}
 
-		ident, ok := idx.X.(*ast.Ident)
-		if !ok {
-			continue
-		}
+	if name := st.implements.matching(t); name != "" {
+		st.explain.skipped(pass, pos, "type implements %s, listed in ignore-implements", name)
 
-		if ident.Obj != nil {
-			result[ident.Obj.Pos()] = true
-		}
+		return nil, 0, false
 	}
-}
 
-// isNil checks if an expression is the nil identifier.
-func isNil(expr ast.Expr) bool {
-	ident, ok := expr.(*ast.Ident)
+	if name := st.fieldTypes.matching(t); name != "" {
+		st.explain.skipped(pass, pos, "%s has a field of type %s, listed in ignore-if-contains-field-type", typeString(pass, t), name)
 
-	return ok && ident.Name == "nil"
-}
+		return nil, 0, false
+	}
 
-// sizeOf calculates the size of a type in bytes.
-func sizeOf(pass *analysis.Pass, t types.Type) int64 {
-	return pass.TypesSizes.Sizeof(t)
-}
+	if st.unsafeTypes.skipped(pass, st, pos, t) {
+		return nil, 0, false
+	}
 
-// shouldExclude checks if a file path matches any exclude pattern.
-func shouldExclude(path string, patterns []string) bool {
-	for _, pattern := range patterns {
-		// Try matching against full path
-		if matched, _ := filepath.Match(pattern, path); matched {
-			return true
-		}
-		// Try matching against base name
-		if matched, _ := filepath.Match(pattern, filepath.Base(path)); matched {
-			return true
-		}
+	// Skip if the size depends on the type arguments
+	if !hasFixedSize(t) {
+		st.explain.skipped(pass, pos, "the size of %s depends on its type arguments", typeString(pass, t))
+		st.needsReview(pass, pos, messages.ReviewTypeArgs, typeString(pass, t))
+
+		return nil, 0, false
 	}
 
-	return false
-}
+	size := sizeOf(pass, t)
+	if size > int64(st.opts.Threshold) {
+		st.explain.skipped(pass, pos, "%s is %d bytes > threshold %d bytes", typeString(pass, t), size, st.opts.Threshold)
 
-// buildNolintMap builds a map of line numbers that have nolint comments.
-// Supports both //nolint:pointless and //pointless:ignore formats.
-func buildNolintMap(pass *analysis.Pass) map[int]bool {
-	result := make(map[int]bool)
-
-	for _, f := range pass.Files {
-		for _, cg := range f.Comments {
-			for _, c := range cg.List {
-				text := c.Text
-				// Remove // or /* */ markers
-				if strings.HasPrefix(text, "//") {
-					text = strings.TrimPrefix(text, "//")
-				} else if strings.HasPrefix(text, "/*") {
-					text = strings.TrimPrefix(text, "/*")
-					text = strings.TrimSuffix(text, "*/")
-				}
+		return nil, 0, false
+	}
 
-				text = strings.TrimSpace(text)
+	if size < int64(st.minSize) {
+		st.explain.skipped(pass, pos, "%s is %d bytes < min-size %d bytes", typeString(pass, t), size, st.minSize)
 
-				if isNolintComment(text) {
-					line := pass.Fset.Position(c.Pos()).Line
-					result[line] = true
-					// Also mark the next line (for comments above declarations)
-					result[line+1] = true
-				}
-			}
-		}
+		return nil, 0, false
 	}
 
-	return result
+	return t, size, true
 }
 
-// isNolintComment checks if a comment text indicates nolint for pointless.
-func isNolintComment(text string) bool {
-	// Check for //nolint:pointless or //nolint (blanket)
-	if strings.HasPrefix(text, "nolint") {
-		// //nolint or //nolint:pointless or //nolint:foo,pointless,bar
-		rest := strings.TrimPrefix(text, "nolint")
-		if rest == "" || rest[0] == ' ' || rest[0] == '\t' {
-			// Blanket nolint
-			return true
-		}
+// typeString formats t relative to the current package.
+func typeString(pass *analysis.Pass, t types.Type) string {
+	return types.TypeString(t, types.RelativeTo(pass.Pkg))
+}
 
-		if rest[0] == ':' {
-			linters := strings.TrimPrefix(rest, ":")
-			for _, l := range strings.Split(linters, ",") {
-				if strings.TrimSpace(l) == "pointless" {
-					return true
-				}
-			}
+// qualifiedTypeString formats t qualified by its package path.
+func qualifiedTypeString(_ *analysis.Pass, t types.Type) string {
+	return types.TypeString(t, nil)
+}
+
+// sizeOf calculates the size of a type in bytes.
+func sizeOf(pass *analysis.Pass, t types.Type) int64 {
+	return pass.TypesSizes.Sizeof(t)
+}
+
+// cacheLineSize is the cache line size assumed for the cachelines threshold unit.
+// It is 64 bytes on all mainstream amd64 and arm64 processors.
+const cacheLineSize = 64
+
+// thresholdBytes converts a threshold in unit to bytes for the target architecture.
+func thresholdBytes(pass *analysis.Pass, threshold int, unit string) (int, error) {
+	switch unit {
+	case "", config.UnitBytes:
+		return threshold, nil
+	case config.UnitWords:
+		word := int64(8)
+		if pass.TypesSizes != nil {
+			word = pass.TypesSizes.Sizeof(types.Typ[types.Uintptr])
 		}
-	}
 
-	// Check for //pointless:ignore
-	if strings.HasPrefix(text, "pointless:ignore") {
-		return true
+		return threshold * int(word), nil
+	case config.UnitCacheLines:
+		return threshold * cacheLineSize, nil
+	default:
+		return 0, fmt.Errorf("invalid threshold unit %q: expected %s, %s, or %s", unit, config.UnitBytes, config.UnitWords, config.UnitCacheLines)
 	}
-
-	return false
 }
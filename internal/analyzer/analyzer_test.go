@@ -1,11 +1,27 @@
 package analyzer_test
 
 import (
+	"bytes"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
 	"testing"
+	"time"
 
+	"golang.org/x/tools/go/analysis"
 	"golang.org/x/tools/go/analysis/analysistest"
 
 	"github.com/mickamy/pointless/internal/analyzer"
+	"github.com/mickamy/pointless/internal/config"
+	"github.com/mickamy/pointless/internal/rules"
+	"github.com/mickamy/pointless/internal/severity"
+	"github.com/mickamy/pointless/pkg/pointless/fixtest"
 )
 
 func TestAnalyzer(t *testing.T) {
@@ -14,3 +30,775 @@ func TestAnalyzer(t *testing.T) {
 	testdata := analysistest.TestData()
 	analysistest.Run(t, testdata, analyzer.Analyzer, "a")
 }
+
+func TestAnalyzerCrossPackageInterfaces(t *testing.T) {
+	t.Parallel()
+
+	testdata := analysistest.TestData()
+	analysistest.Run(t, testdata, analyzer.Analyzer, "b")
+}
+
+func TestAnalyzerThresholdUnit(t *testing.T) {
+	t.Parallel()
+
+	a := analyzer.New(analyzer.Options{Threshold: 2, ThresholdUnit: config.UnitWords})
+
+	testdata := analysistest.TestData()
+	analysistest.Run(t, testdata, a, "units")
+}
+
+func TestAnalyzerContextValue(t *testing.T) {
+	t.Parallel()
+
+	a := analyzer.New(analyzer.Options{
+		Threshold: analyzer.DefaultThreshold,
+		Config:    config.Config{Enable: []string{rules.ContextValue}},
+	})
+
+	testdata := analysistest.TestData()
+	analysistest.Run(t, testdata, a, "ctxvalue")
+}
+
+func TestAnalyzerValueBuilder(t *testing.T) {
+	t.Parallel()
+
+	a := analyzer.New(analyzer.Options{
+		Threshold: analyzer.DefaultThreshold,
+		Config:    config.Config{Enable: []string{rules.ValueBuilder}},
+	})
+
+	testdata := analysistest.TestData()
+	analysistest.Run(t, testdata, a, "builder")
+}
+
+func TestAnalyzerLang(t *testing.T) {
+	t.Parallel()
+
+	a := analyzer.New(analyzer.Options{
+		Threshold: analyzer.DefaultThreshold,
+		Config:    config.Config{Lang: "ja"},
+	})
+
+	testdata := analysistest.TestData()
+	analysistest.Run(t, testdata, a, "ja")
+}
+
+func TestAnalyzerSeverity(t *testing.T) {
+	t.Parallel()
+
+	a := analyzer.New(analyzer.Options{
+		Threshold: analyzer.DefaultThreshold,
+		Config: config.Config{
+			DefaultSeverity: severity.Info,
+			Severity: map[string]string{
+				rules.ValueReceiver: severity.Hint,
+				rules.SlicePointer:  severity.Warning,
+			},
+		},
+	})
+
+	testdata := analysistest.TestData()
+	analysistest.Run(t, testdata, a, "severity")
+}
+
+func TestAnalyzerTestFiles(t *testing.T) {
+	t.Parallel()
+
+	a := analyzer.New(analyzer.Options{
+		Threshold: analyzer.DefaultThreshold,
+		Config: config.Config{
+			Tests: config.TestFiles{Threshold: 128, Rules: []string{rules.ReturnPointer}},
+		},
+	})
+
+	testdata := analysistest.TestData()
+	analysistest.Run(t, testdata, a, "testfiles")
+}
+
+func TestAnalyzerMinSize(t *testing.T) {
+	t.Parallel()
+
+	a := analyzer.New(analyzer.Options{
+		Threshold: analyzer.DefaultThreshold,
+		Config:    config.Config{MinSize: 16},
+	})
+
+	testdata := analysistest.TestData()
+	analysistest.Run(t, testdata, a, "minsize")
+}
+
+func TestAnalyzerStrict(t *testing.T) {
+	t.Parallel()
+
+	testdata := analysistest.TestData()
+
+	a := analyzer.New(analyzer.Options{Threshold: analyzer.DefaultThreshold, Strict: true})
+	analysistest.Run(t, testdata, a, "strict")
+}
+
+func TestAnalyzerVerbose(t *testing.T) {
+	t.Parallel()
+
+	testdata := analysistest.TestData()
+
+	a := analyzer.New(analyzer.Options{Threshold: analyzer.DefaultThreshold, Verbose: true})
+	analysistest.Run(t, testdata, a, "verbose")
+}
+
+func TestAnalyzerVendor(t *testing.T) {
+	t.Parallel()
+
+	testdata := analysistest.TestData()
+
+	a := analyzer.New(analyzer.Options{Threshold: analyzer.DefaultThreshold})
+	analysistest.Run(t, testdata, a, "vendoring/vendor/example.com/dep")
+
+	a = analyzer.New(analyzer.Options{Threshold: analyzer.DefaultThreshold, Config: config.Config{IncludeVendor: true}})
+	analysistest.Run(t, testdata, a, "vendoring/vendor/example.com/included")
+}
+
+func TestAnalyzerParamFacts(t *testing.T) {
+	t.Parallel()
+
+	a := analyzer.New(analyzer.Options{Threshold: analyzer.DefaultThreshold})
+
+	testdata := analysistest.TestData()
+	analysistest.Run(t, testdata, a, "paramfacts", "paramfactsuser")
+}
+
+func TestAnalyzerMethodCalls(t *testing.T) {
+	t.Parallel()
+
+	a := analyzer.New(analyzer.Options{Threshold: analyzer.DefaultThreshold})
+
+	testdata := analysistest.TestData()
+	analysistest.Run(t, testdata, a, "methodcalls", "methodcallsuser")
+}
+
+func TestAnalyzerGroupByType(t *testing.T) {
+	t.Parallel()
+
+	a := analyzer.New(analyzer.Options{Threshold: analyzer.DefaultThreshold, GroupByType: true})
+
+	testdata := analysistest.TestData()
+	analysistest.Run(t, testdata, a, "grouped")
+}
+
+//nolint:paralleltest // mutates the global analyzer config
+func TestAnalyzerPresets(t *testing.T) {
+	analyzer.SetConfig(config.Config{Presets: []string{"gorm", "protobuf"}})
+	t.Cleanup(func() { analyzer.SetConfig(config.DefaultConfig()) })
+
+	testdata := analysistest.TestData()
+	analysistest.Run(t, testdata, analyzer.Analyzer, "presets")
+}
+
+func TestAnalyzerGRPCPreset(t *testing.T) {
+	t.Parallel()
+
+	a := analyzer.New(analyzer.Options{Threshold: analyzer.DefaultThreshold, Config: config.Config{Presets: []string{"grpc"}}})
+
+	testdata := analysistest.TestData()
+	analysistest.Run(t, testdata, a, "grpcpreset")
+}
+
+func TestAnalyzerIgnoreImplements(t *testing.T) {
+	t.Parallel()
+
+	testdata := analysistest.TestData()
+
+	a := analyzer.New(analyzer.Options{
+		Threshold: analyzer.DefaultThreshold,
+		Config:    config.Config{IgnoreImplements: []string{"error", "driver.Valuer", "named", "example.com/missing.Iface"}},
+	})
+	analysistest.Run(t, testdata, a, "ignoreimpl")
+}
+
+func TestAnalyzerIgnoreIfContainsFieldType(t *testing.T) {
+	t.Parallel()
+
+	testdata := analysistest.TestData()
+
+	a := analyzer.New(analyzer.Options{
+		Threshold: analyzer.DefaultThreshold,
+		Config:    config.Config{IgnoreIfContainsFieldType: []string{"sync.Mutex", "*database/sql.DB"}},
+	})
+	analysistest.Run(t, testdata, a, "fieldtypes")
+}
+
+func TestAnalyzerRespectUnsafeOff(t *testing.T) {
+	t.Parallel()
+
+	respect := false
+	a := analyzer.New(analyzer.Options{
+		Threshold: analyzer.DefaultThreshold,
+		Config:    config.Config{RespectUnsafe: &respect},
+	})
+
+	testdata := analysistest.TestData()
+	analysistest.Run(t, testdata, a, "unsafeoff")
+}
+
+func TestAnalyzerSkipIf(t *testing.T) {
+	t.Parallel()
+
+	testdata := analysistest.TestData()
+
+	a := analyzer.New(analyzer.Options{
+		Threshold: analyzer.DefaultThreshold,
+		Config:    config.Config{SkipIf: `type.hasFieldOfType("time.Duration") || type.name.matches("DTO$")`},
+	})
+	analysistest.Run(t, testdata, a, "skipif")
+}
+
+func TestAnalyzerEmptyReceiverFixes(t *testing.T) {
+	t.Parallel()
+
+	testdata := analysistest.TestData()
+	fixtest.Run(t, testdata, analyzer.Analyzer, "markers")
+}
+
+func TestAnalyzerReturnPointerError(t *testing.T) {
+	t.Parallel()
+
+	testdata := analysistest.TestData()
+	a := analyzer.New(analyzer.Options{
+		Threshold: analyzer.DefaultThreshold,
+		Config:    config.Config{Enable: []string{rules.ReturnPointerError}},
+	})
+	analysistest.Run(t, testdata, a, "errorreturn")
+}
+
+func TestAnalyzerEmbeddedPointer(t *testing.T) {
+	t.Parallel()
+
+	testdata := analysistest.TestData()
+	a := analyzer.New(analyzer.Options{
+		Threshold: analyzer.DefaultThreshold,
+		Config:    config.Config{Enable: []string{rules.EmbeddedPointer}},
+	})
+	analysistest.Run(t, testdata, a, "embedptr")
+}
+
+func TestAnalyzerSliceConversion(t *testing.T) {
+	t.Parallel()
+
+	testdata := analysistest.TestData()
+	a := analyzer.New(analyzer.Options{
+		Threshold: analyzer.DefaultThreshold,
+		Config:    config.Config{Enable: []string{rules.SliceConversion}},
+	})
+	analysistest.Run(t, testdata, a, "sliceconv")
+}
+
+func TestAnalyzerAtomicCounter(t *testing.T) {
+	t.Parallel()
+
+	testdata := analysistest.TestData()
+	a := analyzer.New(analyzer.Options{
+		Threshold: analyzer.DefaultThreshold,
+		Config:    config.Config{Enable: []string{rules.AtomicCounter}},
+	})
+	analysistest.Run(t, testdata, a, "atomiccounter")
+}
+
+func TestAnalyzerSortPointerSlice(t *testing.T) {
+	t.Parallel()
+
+	testdata := analysistest.TestData()
+	a := analyzer.New(analyzer.Options{
+		Threshold: analyzer.DefaultThreshold,
+		Config:    config.Config{Enable: []string{rules.SortPointerSlice}},
+	})
+	analysistest.Run(t, testdata, a, "sortptr")
+}
+
+func TestAnalyzerZeroValue(t *testing.T) {
+	t.Parallel()
+
+	testdata := analysistest.TestData()
+	a := analyzer.New(analyzer.Options{Threshold: analyzer.DefaultThreshold})
+	analysistest.Run(t, testdata, a, "zerovalue")
+}
+
+func TestAnalyzerValueReceiverFixes(t *testing.T) {
+	t.Parallel()
+
+	testdata := analysistest.TestData()
+	fixtest.Run(t, testdata, analyzer.Analyzer, "fix")
+}
+
+func TestAnalyzerPointerRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	testdata := analysistest.TestData()
+	fixtest.Run(t, testdata, analyzer.Analyzer, "roundtrip")
+}
+
+func TestAnalyzerBreakingFixes(t *testing.T) {
+	t.Parallel()
+
+	testdata := analysistest.TestData()
+
+	a := analyzer.New(analyzer.Options{Threshold: analyzer.DefaultThreshold})
+	fixtest.Run(t, testdata, a, "apibreak")
+
+	a = analyzer.New(analyzer.Options{Threshold: analyzer.DefaultThreshold, AllowBreaking: true})
+	fixtest.Run(t, testdata, a, "allowbreaking")
+}
+
+func TestAnalyzerRanges(t *testing.T) {
+	t.Parallel()
+
+	testdata := analysistest.TestData()
+	results := analysistest.Run(t, testdata, analyzer.Analyzer, "ranges")
+
+	var got []string
+
+	for _, r := range results {
+		for _, d := range r.Diagnostics {
+			file := r.Pass.Fset.File(d.Pos)
+			src, err := os.ReadFile(file.Name())
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			got = append(got, string(src[file.Offset(d.Pos):file.Offset(d.End)]))
+		}
+	}
+
+	want := []string{"*Point", "*Point", "[]*Point"}
+	if !slices.Equal(got, want) {
+		t.Errorf("ranges = %q, want %q", got, want)
+	}
+}
+
+func TestAnalyzerDirectives(t *testing.T) {
+	t.Parallel()
+
+	testdata := analysistest.TestData()
+	analysistest.Run(t, testdata, analyzer.Analyzer, "directives")
+}
+
+func TestAnalyzerEmbedding(t *testing.T) {
+	t.Parallel()
+
+	testdata := analysistest.TestData()
+	analysistest.Run(t, testdata, analyzer.Analyzer, "embedding")
+}
+
+func TestAnalyzerSymbolFilter(t *testing.T) {
+	t.Parallel()
+
+	a := analyzer.New(analyzer.Options{
+		Threshold: analyzer.DefaultThreshold,
+		Config: config.Config{
+			IncludeSymbols:     `^(symbolfilter\.)?(\(\*Repo\)\.)?Get`,
+			IgnoreSymbolsRegex: `ForTest$`,
+		},
+	})
+
+	testdata := analysistest.TestData()
+	results := analysistest.Run(t, testdata, a, "symbolfilter")
+
+	var filtered int
+
+	for _, r := range results {
+		if m, ok := r.Result.(*analyzer.Metrics); ok {
+			filtered += m.Filtered
+		}
+	}
+
+	// NewUser, (*Repo).Len, users, and GetUserForTest
+	if filtered != 4 {
+		t.Errorf("Filtered = %d, want 4", filtered)
+	}
+}
+
+func TestAnalyzerExcludeRules(t *testing.T) {
+	t.Parallel()
+
+	a := analyzer.New(analyzer.Options{
+		Threshold: analyzer.DefaultThreshold,
+		Config: config.Config{
+			ExcludeRules: []config.ExcludeRule{
+				{Path: `excluderules/legacy\.go$`, Rules: []string{"value-receiver"}},
+				{Text: `Config is \d+ bytes`},
+				{Source: `// legacy-api$`},
+				{Linters: []string{"govet"}, Text: `DefaultUser|User is`},
+			},
+		},
+	})
+
+	testdata := analysistest.TestData()
+	results := analysistest.Run(t, testdata, a, "excluderules")
+
+	var filtered int
+
+	for _, r := range results {
+		if m, ok := r.Result.(*analyzer.Metrics); ok {
+			filtered += m.Filtered
+		}
+	}
+
+	// NewConfig, LegacyUser, and (*Config).Debug
+	if filtered != 3 {
+		t.Errorf("Filtered = %d, want 3", filtered)
+	}
+}
+
+func TestAnalyzerLineDirectives(t *testing.T) {
+	t.Parallel()
+
+	a := analyzer.New(analyzer.Options{
+		Threshold: analyzer.DefaultThreshold,
+		Config: config.Config{
+			Exclude:      []string{"*.y"},
+			ExcludeRules: []config.ExcludeRule{{Path: `linedirectives/template\.tmpl$`, Rules: []string{"value-receiver"}}},
+		},
+	})
+
+	testdata := analysistest.TestData()
+	results := analysistest.Run(t, testdata, a, "linedirectives")
+
+	var filtered int
+
+	for _, r := range results {
+		if m, ok := r.Result.(*analyzer.Metrics); ok {
+			filtered += m.Filtered
+		}
+	}
+
+	// (*Page).Len
+	if filtered != 1 {
+		t.Errorf("Filtered = %d, want 1", filtered)
+	}
+}
+
+func TestAnalyzerPackagePhases(t *testing.T) {
+	t.Parallel()
+
+	a := analyzer.New(analyzer.Options{Threshold: analyzer.DefaultThreshold, Config: config.Config{PackageBudget: "1h"}})
+
+	testdata := analysistest.TestData()
+	results := analysistest.Run(t, testdata, a, "zerovalue")
+
+	var m analyzer.Metrics
+	for _, r := range results {
+		if result, ok := r.Result.(*analyzer.Metrics); ok {
+			m.Add(result)
+		}
+	}
+
+	phases, ok := m.PackagePhases["zerovalue"]
+	if !ok || len(m.PackagePhases) != m.Packages {
+		t.Fatalf("PackagePhases = %v, want the phases of each of the %d packages checked", m.PackagePhases, m.Packages)
+	}
+
+	if total := analyzer.Total(phases); total <= 0 || total > analyzer.Total(m.Phases) {
+		t.Errorf("total time of zerovalue = %v, want at most the %v of all packages", total, analyzer.Total(m.Phases))
+	}
+}
+
+//nolint:paralleltest // mutates the global analyzer config
+func TestAnalyzerIgnoreSymbols(t *testing.T) {
+	analyzer.SetConfig(config.Config{IgnoreSymbols: []string{
+		"(*Server).Handler",
+		"symbols.NewServer",
+		"Server.Addr",
+		"defaultServers",
+	}})
+	t.Cleanup(func() { analyzer.SetConfig(config.DefaultConfig()) })
+
+	testdata := analysistest.TestData()
+	analysistest.Run(t, testdata, analyzer.Analyzer, "symbols")
+}
+
+func TestAnalyzerSuppressions(t *testing.T) {
+	t.Parallel()
+
+	// Generated entries name symbols qualified by the package path
+	var s config.Suppressions
+	for _, name := range []string{"suppressions.(*Store).Get", "suppressions.NewStore", "suppressions.stores"} {
+		s.Suppressions = append(s.Suppressions, config.Suppression{Symbol: name})
+	}
+
+	dir := t.TempDir()
+
+	f, err := os.Create(filepath.Join(dir, config.SuppressionsFile))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := config.WriteSuppressions(f, s); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := f.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := os.WriteFile(filepath.Join(dir, "go.mod"), []byte("module example.com/mod\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, err := config.LoadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	a := analyzer.New(analyzer.Options{Threshold: analyzer.DefaultThreshold, Config: cfg})
+
+	testdata := analysistest.TestData()
+	analysistest.Run(t, testdata, a, "suppressions")
+}
+
+//nolint:paralleltest // mutates the global analyzer flags
+func TestAnalyzerShowSuppressed(t *testing.T) {
+	if err := analyzer.Analyzer.Flags.Set("show-suppressed", "true"); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Cleanup(func() { _ = analyzer.Analyzer.Flags.Set("show-suppressed", "false") })
+
+	testdata := analysistest.TestData()
+	analysistest.Run(t, testdata, analyzer.Analyzer, "suppressed")
+}
+
+//nolint:paralleltest // mutates the global analyzer flags
+func TestAnalyzerExplain(t *testing.T) {
+	tests := []struct {
+		line int
+		want string
+	}{
+		{line: 11, want: "not flagged: receiver is mutated at line 12"},
+		{line: 15, want: "not flagged: function returns nil at line 17"},
+		{line: 22, want: "not flagged: Large is 2048 bytes > threshold 1024 bytes"},
+		{line: 26, want: "flagged: consider returning value instead of pointer"},
+		{line: 3, want: "no pointer types checked on this line"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.want, func(t *testing.T) {
+			var out bytes.Buffer
+			t.Cleanup(analyzer.SetExplainOutput(&out))
+
+			if err := analyzer.Analyzer.Flags.Set("explain", "explain/explain.go:"+strconv.Itoa(tt.line)); err != nil {
+				t.Fatal(err)
+			}
+
+			t.Cleanup(func() { _ = analyzer.Analyzer.Flags.Set("explain", "") })
+
+			testdata := analysistest.TestData()
+			analysistest.Run(t, testdata, analyzer.Analyzer, "explain")
+
+			if !strings.Contains(out.String(), tt.want) {
+				t.Errorf("explain output = %q, want it to contain %q", out.String(), tt.want)
+			}
+		})
+	}
+}
+
+//nolint:paralleltest // mutates the global analyzer flags
+func TestAnalyzerExplainStandardLibrary(t *testing.T) {
+	var out bytes.Buffer
+	t.Cleanup(analyzer.SetExplainOutput(&out))
+
+	// errors.go is analyzed for the facts of explainstd, but never reported on
+	if err := analyzer.Analyzer.Flags.Set("explain", "errors/errors.go:1"); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Cleanup(func() { _ = analyzer.Analyzer.Flags.Set("explain", "") })
+
+	testdata := analysistest.TestData()
+	analysistest.Run(t, testdata, analyzer.Analyzer, "explainstd")
+
+	if out.Len() > 0 {
+		t.Errorf("explain output = %q, want none", out.String())
+	}
+}
+
+//nolint:paralleltest // replaces the default logger
+func TestAnalyzerDebugStandardLibrary(t *testing.T) {
+	var out bytes.Buffer
+
+	prev := slog.Default()
+	slog.SetDefault(slog.New(slog.NewTextHandler(&out, &slog.HandlerOptions{Level: slog.LevelDebug})))
+	t.Cleanup(func() { slog.SetDefault(prev) })
+
+	testdata := analysistest.TestData()
+	analysistest.Run(t, testdata, analyzer.Analyzer, "explainstd")
+
+	if !strings.Contains(out.String(), "explainstd.go:11") {
+		t.Errorf("debug log = %q, want the decision on NewSmall", out.String())
+	}
+
+	if strings.Contains(out.String(), "errors.go") {
+		t.Errorf("debug log = %q, want no decisions on the standard library", out.String())
+	}
+}
+
+func TestAnalyzerTypeDefinitions(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		opts analyzer.Options
+		want string
+	}{
+		{name: "default", opts: analyzer.Options{}},
+		{name: "type definitions", opts: analyzer.Options{TypeDefinitions: true}, want: "Point is defined here: 16 bytes = X 8 + Y 8"},
+		{name: "verbose", opts: analyzer.Options{Verbose: true}, want: "Point is defined here: 16 bytes = X 8 + Y 8"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			tt.opts.Threshold = analyzer.DefaultThreshold
+			a := analyzer.New(tt.opts)
+
+			testdata := analysistest.TestData()
+			results := analysistest.Run(t, testdata, a, "typedefs")
+
+			var related []string
+
+			for _, r := range results {
+				for _, d := range r.Diagnostics {
+					for _, rel := range d.Related {
+						related = append(related, rel.Message)
+					}
+				}
+			}
+
+			switch {
+			case tt.want == "" && len(related) > 0:
+				t.Errorf("related = %q, want none", related)
+			case tt.want != "" && (len(related) != 1 || related[0] != tt.want):
+				t.Errorf("related = %q, want [%q]", related, tt.want)
+			}
+		})
+	}
+}
+
+func TestAnalyzerEnforce(t *testing.T) {
+	t.Parallel()
+
+	testdata := analysistest.TestData()
+	analysistest.Run(t, testdata, analyzer.Analyzer, "enforce", "enforceuser")
+}
+
+func TestAnalyzerPointerDirective(t *testing.T) {
+	t.Parallel()
+
+	testdata := analysistest.TestData()
+	analysistest.Run(t, testdata, analyzer.Analyzer, "pointertype", "pointertypeuser")
+}
+
+func TestAnalyzerWorkerPool(t *testing.T) {
+	t.Parallel()
+
+	a := analyzer.New(analyzer.Options{
+		Threshold: analyzer.DefaultThreshold,
+		Config:    config.Config{Enable: []string{rules.WorkerPool}},
+	})
+
+	testdata := analysistest.TestData()
+	analysistest.Run(t, testdata, a, "workerpool")
+}
+
+func TestAnalyzerBuildTags(t *testing.T) {
+	t.Parallel()
+
+	testdata := analysistest.TestData()
+	analysistest.Run(t, testdata, analyzer.Analyzer, "buildtags")
+}
+
+func TestEditsExportedAPI(t *testing.T) {
+	t.Parallel()
+
+	const src = `package p
+
+type T struct{ n int }
+
+type t struct{ n int }
+
+var V *T
+
+func (s *T) Get() int { return s.n }
+
+func (s *T) get() int { return s.n }
+
+func New() *T { return &T{} }
+`
+
+	fset := token.NewFileSet()
+
+	f, err := parser.ParseFile(fset, "p.go", src, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name, edit string
+		want       bool
+	}{
+		{"receiver", "*T) Get", false},
+		{"body", "return s.n }\n\nfunc (s *T) get", false},
+		{"exported result", "*T { return", true},
+		{"exported type", "struct{ n int }\n\ntype t", true},
+		{"unexported type", "struct{ n int }\n\nvar", false},
+		{"exported var", "*T\n", true},
+		{"unexported method", "int { return s.n }\n\nfunc New", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			off := strings.Index(src, tt.edit)
+			if off < 0 {
+				t.Fatalf("%q not found", tt.edit)
+			}
+
+			pos := f.FileStart + token.Pos(off)
+			fix := analysis.SuggestedFix{TextEdits: []analysis.TextEdit{{Pos: pos, End: pos + 1}}}
+
+			if got := analyzer.EditsExportedAPI([]*ast.File{f}, fix); got != tt.want {
+				t.Errorf("EditsExportedAPI = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNolintExpired(t *testing.T) {
+	t.Parallel()
+
+	until := time.Date(2026, 10, 14, 0, 0, 0, 0, time.UTC)
+	west := time.FixedZone("UTC-7", -7*60*60)
+	east := time.FixedZone("UTC+9", 9*60*60)
+
+	tests := []struct {
+		name string
+		now  time.Time
+		want bool
+	}{
+		{"start of the day", until, false},
+		{"end of the day", time.Date(2026, 10, 14, 23, 59, 59, 0, time.UTC), false},
+		{"next day", time.Date(2026, 10, 15, 0, 0, 0, 0, time.UTC), true},
+		{"next day in UTC, same day west of it", time.Date(2026, 10, 14, 20, 0, 0, 0, west), true},
+		{"same day in UTC, next day east of it", time.Date(2026, 10, 15, 1, 0, 0, 0, east), false},
+		{"day before in UTC, same day east of it", time.Date(2026, 10, 14, 1, 0, 0, 0, east), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			if got := analyzer.NolintExpired(until, tt.now); got != tt.want {
+				t.Errorf("NolintExpired(%s, %s) = %v, want %v", until.Format(time.DateOnly), tt.now, got, tt.want)
+			}
+		})
+	}
+}
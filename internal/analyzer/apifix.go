@@ -0,0 +1,206 @@
+package analyzer
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"slices"
+	"strings"
+
+	"golang.org/x/tools/go/analysis"
+
+	"github.com/mickamy/pointless/internal/apicompat"
+	"github.com/mickamy/pointless/internal/editapply"
+	"github.com/mickamy/pointless/internal/messages"
+)
+
+// allowBreaking keeps suggested fixes that would break the package's public API.
+var allowBreaking bool
+
+// guardFixes withholds the suggested fixes of d that would break the public API
+// of the package, unless AllowBreaking is set. Fixes only editing function
+// bodies, receivers, and unexported declarations are kept as they are (see
+// editsExportedAPI); the others are applied to a copy of the package, which is
+// type-checked again and compared with the original.
+func (st *state) guardFixes(pass *analysis.Pass, d *analysis.Diagnostic) {
+	if st.opts.AllowBreaking || len(d.SuggestedFixes) == 0 || !hasPublicAPI(pass.Pkg) {
+		return
+	}
+
+	var kept []analysis.SuggestedFix
+
+	for _, fix := range d.SuggestedFixes {
+		if !editsExportedAPI(pass.Files, fix) {
+			kept = append(kept, fix)
+
+			continue
+		}
+
+		changes, err := fixAPIChanges(pass, fix)
+		if err == nil && len(changes) == 0 {
+			kept = append(kept, fix)
+
+			continue
+		}
+
+		reason := strings.Join(changes, "; ")
+		if err != nil {
+			reason = err.Error()
+		}
+
+		st.explain.fixWithheld(pass, d.Pos, reason)
+		d.Related = append(d.Related, analysis.RelatedInformation{
+			Pos:     d.Pos,
+			Message: st.msg.Sprintf(messages.FixBreaksAPI, reason),
+		})
+	}
+
+	d.SuggestedFixes = kept
+}
+
+// hasPublicAPI reports whether pkg can be imported from outside its module:
+// it is neither a command nor below an internal directory.
+func hasPublicAPI(pkg *types.Package) bool {
+	if pkg.Name() == "main" || strings.HasSuffix(pkg.Path(), "_test") {
+		return false
+	}
+
+	return !slices.Contains(strings.Split(pkg.Path(), "/"), "internal")
+}
+
+// editsExportedAPI reports whether an edit of fix falls in an exported
+// declaration of files, beyond the body and the receiver of a function (moving
+// a method between the pointer and the value receiver keeps both method sets
+// intact). Type-checking the fixed package is only worth it for those fixes.
+func editsExportedAPI(files []*ast.File, fix analysis.SuggestedFix) bool {
+	for _, edit := range fix.TextEdits {
+		for _, f := range files {
+			if edit.Pos < f.FileStart || edit.Pos > f.FileEnd {
+				continue
+			}
+
+			for _, decl := range f.Decls {
+				if edit.End < decl.Pos() || edit.Pos > decl.End() {
+					continue
+				}
+
+				if declEditsExportedAPI(decl, edit) {
+					return true
+				}
+			}
+		}
+	}
+
+	return false
+}
+
+// declEditsExportedAPI reports whether edit, which overlaps decl, may change
+// the exported API (see editsExportedAPI).
+func declEditsExportedAPI(decl ast.Decl, edit analysis.TextEdit) bool {
+	within := func(pos, end token.Pos) bool {
+		return pos <= edit.Pos && edit.End <= end
+	}
+
+	switch decl := decl.(type) {
+	case *ast.FuncDecl:
+		if !decl.Name.IsExported() {
+			return false
+		}
+
+		if decl.Body != nil && within(decl.Body.Pos(), decl.Body.End()) {
+			return false
+		}
+
+		return decl.Recv == nil || !within(decl.Recv.Pos(), decl.Recv.End())
+	case *ast.GenDecl:
+		for _, spec := range decl.Specs {
+			if edit.End < spec.Pos() || edit.Pos > spec.End() {
+				continue
+			}
+
+			switch spec := spec.(type) {
+			case *ast.TypeSpec:
+				if spec.Name.IsExported() {
+					return true
+				}
+			case *ast.ValueSpec:
+				for _, name := range spec.Names {
+					if name.IsExported() {
+						return true
+					}
+				}
+			}
+		}
+	}
+
+	return false
+}
+
+// fixAPIChanges applies fix to the sources of the package and returns the
+// incompatible changes to its exported API.
+func fixAPIChanges(pass *analysis.Pass, fix analysis.SuggestedFix) ([]string, error) {
+	fset := token.NewFileSet()
+	files := make([]*ast.File, 0, len(pass.Files))
+
+	for _, f := range pass.Files {
+		tf := pass.Fset.File(f.Pos())
+
+		src, err := pass.ReadFile(tf.Name())
+		if err != nil {
+			return nil, fmt.Errorf("reading %s: %w", tf.Name(), err)
+		}
+
+		fixed, err := editapply.Apply(src, editapply.FromTextEdits(tf, fix.TextEdits))
+		if err != nil {
+			return nil, fmt.Errorf("applying the fix to %s: %w", tf.Name(), err)
+		}
+
+		file, err := parser.ParseFile(fset, tf.Name(), fixed, parser.SkipObjectResolution)
+		if err != nil {
+			return nil, fmt.Errorf("the fixed package does not parse: %w", err)
+		}
+
+		files = append(files, file)
+	}
+
+	conf := types.Config{
+		Importer:  packageImporter(pass.Pkg),
+		GoVersion: pass.Pkg.GoVersion(),
+		Sizes:     pass.TypesSizes,
+	}
+
+	fixed, err := conf.Check(pass.Pkg.Path(), fset, files, nil)
+	if err != nil {
+		return nil, fmt.Errorf("the fixed package does not type-check: %w", err)
+	}
+
+	return apicompat.Incompatible(pass.Pkg, fixed), nil
+}
+
+// importerFunc implements types.Importer.
+type importerFunc func(path string) (*types.Package, error)
+
+func (f importerFunc) Import(path string) (*types.Package, error) { return f(path) }
+
+// packageImporter imports the direct dependencies of pkg as already loaded, so
+// that the fixed package shares them with the original.
+func packageImporter(pkg *types.Package) types.Importer {
+	imports := make(map[string]*types.Package, len(pkg.Imports()))
+	for _, imp := range pkg.Imports() {
+		imports[imp.Path()] = imp
+	}
+
+	return importerFunc(func(path string) (*types.Package, error) {
+		if path == "unsafe" {
+			return types.Unsafe, nil
+		}
+
+		if imp, ok := imports[path]; ok {
+			return imp, nil
+		}
+
+		return nil, fmt.Errorf("package %s is not a dependency", path)
+	})
+}
@@ -0,0 +1,178 @@
+package analyzer
+
+import (
+	"go/ast"
+	"go/token"
+	"go/types"
+	"slices"
+	"strings"
+
+	"golang.org/x/tools/go/analysis"
+
+	"github.com/mickamy/pointless/internal/messages"
+	"github.com/mickamy/pointless/internal/rules"
+)
+
+// counterUpdates returns the fields the method fn writes if it only mutates
+// its receiver by updating integer fields of it as counters: s.n++, s.n--,
+// s.n += x, s.n -= x, or s.n = 0. Calls of sync methods (s.mu.Lock()) are
+// allowed, any other pointer-receiver method selected on the receiver, and
+// any address of it or its fields, is not. It returns nil otherwise.
+func counterUpdates(pass *analysis.Pass, fn *ast.FuncDecl) []*ast.SelectorExpr {
+	recv := fn.Recv.List[0]
+	if len(recv.Names) == 0 || fn.Body == nil {
+		return nil
+	}
+
+	receiverObj := pass.TypesInfo.Defs[recv.Names[0]]
+	if receiverObj == nil {
+		return nil
+	}
+
+	for _, c := range findReceiverCalls(pass, fn.Body, receiverObj) {
+		if c.callee.Pkg() == nil || c.callee.Pkg().Path() != "sync" {
+			return nil
+		}
+	}
+
+	var updates []*ast.SelectorExpr
+
+	ok := true
+
+	ast.Inspect(fn.Body, func(n ast.Node) bool {
+		if !ok {
+			return false
+		}
+
+		switch node := n.(type) {
+		case *ast.AssignStmt:
+			for i, lhs := range node.Lhs {
+				if !refersToReceiver(pass, lhs, receiverObj) {
+					continue
+				}
+
+				field := counterField(pass, lhs, receiverObj)
+
+				switch {
+				case field == nil:
+				case node.Tok == token.ADD_ASSIGN || node.Tok == token.SUB_ASSIGN:
+				case node.Tok == token.ASSIGN && len(node.Lhs) == len(node.Rhs) && isZero(pass, node.Rhs[i]):
+				default:
+					field = nil
+				}
+
+				if field == nil {
+					ok = false
+
+					return false
+				}
+
+				updates = append(updates, field)
+			}
+		case *ast.IncDecStmt:
+			if !refersToReceiver(pass, node.X, receiverObj) {
+				break
+			}
+
+			field := counterField(pass, node.X, receiverObj)
+			if field == nil {
+				ok = false
+
+				return false
+			}
+
+			updates = append(updates, field)
+		case *ast.UnaryExpr:
+			if node.Op == token.AND && refersToReceiver(pass, ast.Unparen(node.X), receiverObj) {
+				ok = false
+
+				return false
+			}
+		}
+
+		return true
+	})
+
+	if !ok || len(updates) == 0 {
+		return nil
+	}
+
+	return updates
+}
+
+// counterField returns e if it is a field of an integer type selected
+// directly on the receiver receiverObj, like s.hits, or nil.
+func counterField(pass *analysis.Pass, e ast.Expr, receiverObj types.Object) *ast.SelectorExpr {
+	sel, ok := ast.Unparen(e).(*ast.SelectorExpr)
+	if !ok {
+		return nil
+	}
+
+	ident, ok := ast.Unparen(sel.X).(*ast.Ident)
+	if !ok || pass.TypesInfo.Uses[ident] != receiverObj {
+		return nil
+	}
+
+	selection, ok := pass.TypesInfo.Selections[sel]
+	if !ok || selection.Kind() != types.FieldVal || len(selection.Index()) != 1 {
+		return nil
+	}
+
+	if basic, ok := selection.Type().Underlying().(*types.Basic); !ok || basic.Info()&types.IsInteger == 0 {
+		return nil
+	}
+
+	return sel
+}
+
+// isZero reports whether e is the constant 0.
+func isZero(pass *analysis.Pass, e ast.Expr) bool {
+	tv, ok := pass.TypesInfo.Types[e]
+
+	return ok && tv.Value != nil && tv.Value.String() == "0"
+}
+
+// checkAtomicCounter checks fn, a method mutating its *T receiver, for the
+// opt-in, advisory atomic-counter rule: if it only updates integer counters of
+// the receiver, sync/atomic types make the updates safe for concurrent use,
+// and kept behind a pointer they let the method take a value receiver.
+func checkAtomicCounter(pass *analysis.Pass, fn *ast.FuncDecl, star *ast.StarExpr, st *state) {
+	if !st.ruleEnabled(rules.AtomicCounter) {
+		return
+	}
+
+	updates := counterUpdates(pass, fn)
+	if updates == nil {
+		return
+	}
+
+	t, size, ok := smallStruct(pass, st, star.Pos(), star.X)
+	if !ok {
+		return
+	}
+
+	var fields []string
+
+	related := make([]analysis.RelatedInformation, 0, len(updates))
+
+	for _, u := range updates {
+		related = append(related, analysis.RelatedInformation{
+			Pos:     u.Pos(),
+			End:     u.End(),
+			Message: st.msg.Sprintf(messages.AtomicCounterUpdate, types.ExprString(u)),
+		})
+
+		if !slices.Contains(fields, u.Sel.Name) {
+			fields = append(fields, u.Sel.Name)
+		}
+	}
+
+	typeName := typeString(pass, t)
+	st.report(pass, t, analysis.Diagnostic{
+		Pos:      star.Pos(),
+		End:      star.End(),
+		Category: rules.AtomicCounter,
+		Message:  st.msg.Sprintf(messages.AtomicCounter, fn.Name.Name, strings.Join(fields, ", "), typeName, fn.Name.Name, typeName, size, st.bounds()),
+		Related:  related,
+	})
+}
@@ -0,0 +1,110 @@
+package analyzer_test
+
+import (
+	"fmt"
+	"go/types"
+	"os"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"testing"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/analysis/analysistest"
+
+	"github.com/mickamy/pointless/internal/analyzer"
+)
+
+// generatedFile is the source of each file of the benchmark package. Every
+// pointer is justified, by a mutation, a nil return or check, or a nolint
+// comment, so that the scans do all the work and nothing is reported; the
+// mutating methods export a fact.
+const generatedFile = `package gen
+
+type T%[1]d struct {
+	A, B int
+}
+
+func (t *T%[1]d) Set(a int) { // want Set:"mutatesReceiver"
+	t.A = a
+}
+
+func New%[1]d(ok bool) *T%[1]d {
+	if !ok {
+		return nil
+	}
+
+	return &T%[1]d{}
+}
+
+func Sum%[1]d(items []*T%[1]d) int {
+	n := 0
+	for _, t := range items {
+		if t != nil {
+			n += t.A
+		}
+	}
+
+	return n
+}
+
+//nolint:pointless
+func (t *T%[1]d) Get() int {
+	return t.A + t.B
+}
+`
+
+// writeGeneratedPackage writes a package of n files to a GOPATH-style tree
+// and returns its root.
+func writeGeneratedPackage(b *testing.B, n int) string {
+	b.Helper()
+
+	dir := b.TempDir()
+	pkg := filepath.Join(dir, "src", "gen")
+
+	if err := os.MkdirAll(pkg, 0o755); err != nil {
+		b.Fatal(err)
+	}
+
+	for i := range n {
+		src := fmt.Sprintf(generatedFile, i)
+		if err := os.WriteFile(filepath.Join(pkg, fmt.Sprintf("gen%d.go", i)), []byte(src), 0o600); err != nil {
+			b.Fatal(err)
+		}
+	}
+
+	return dir
+}
+
+// freshPass returns a copy of pass, whose analysis is over, that can be run
+// again: the checker disables exporting facts once a pass is done.
+func freshPass(pass *analysis.Pass) *analysis.Pass {
+	p := *pass
+	p.ExportObjectFact = func(types.Object, analysis.Fact) {}
+	p.ExportPackageFact = func(analysis.Fact) {}
+
+	return &p
+}
+
+func BenchmarkAnalyzer(b *testing.B) {
+	dir := writeGeneratedPackage(b, 300)
+
+	results := analysistest.Run(b, dir, analyzer.Analyzer, "gen")
+	if len(results) != 1 {
+		b.Fatalf("got %d packages, want 1", len(results))
+	}
+
+	pass := results[0].Pass
+
+	for _, workers := range slices.Compact([]int{1, runtime.GOMAXPROCS(0)}) {
+		b.Run(fmt.Sprintf("workers=%d", workers), func(b *testing.B) {
+			defer analyzer.SetScanWorkers(workers)()
+
+			for b.Loop() {
+				if _, err := analyzer.Analyzer.Run(freshPass(pass)); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
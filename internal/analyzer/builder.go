@@ -0,0 +1,100 @@
+package analyzer
+
+import (
+	"go/ast"
+	"go/token"
+	"go/types"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/ast/inspector"
+
+	"github.com/mickamy/pointless/internal/messages"
+	"github.com/mickamy/pointless/internal/rules"
+)
+
+// builderMethods maps the builder methods of the package, pointer-receiver
+// methods whose only result is their receiver so that calls chain
+// (b.WithX(1).WithY(2)), to their first return statement.
+type builderMethods map[*ast.FuncDecl]token.Pos
+
+// findBuilderMethods finds the methods of the package returning their pointer
+// receiver on every path.
+func findBuilderMethods(pass *analysis.Pass, inspect *inspector.Inspector) builderMethods {
+	result := make(builderMethods)
+
+	inspect.Preorder([]ast.Node{(*ast.FuncDecl)(nil)}, func(n ast.Node) {
+		fn, ok := n.(*ast.FuncDecl)
+		if !ok || fn.Body == nil || fn.Recv == nil || len(fn.Recv.List) == 0 || len(fn.Recv.List[0].Names) == 0 {
+			return
+		}
+
+		if fn.Type.Results == nil || fn.Type.Results.NumFields() != 1 {
+			return
+		}
+
+		recv := pass.TypesInfo.Defs[fn.Recv.List[0].Names[0]]
+		if recv == nil || !isPointer(recv.Type()) || !types.Identical(recv.Type(), pass.TypesInfo.TypeOf(fn.Type.Results.List[0].Type)) {
+			return
+		}
+
+		if pos := returnsReceiver(pass, fn.Body, recv); pos.IsValid() {
+			result[fn] = pos
+		}
+	})
+
+	return result
+}
+
+// returnsReceiver returns the first return statement of body if every return
+// statement of body, outside function literals, returns recv, or token.NoPos.
+func returnsReceiver(pass *analysis.Pass, body *ast.BlockStmt, recv types.Object) token.Pos {
+	first, all := token.NoPos, true
+
+	ast.Inspect(body, func(n ast.Node) bool {
+		switch node := n.(type) {
+		case *ast.FuncLit:
+			return false
+		case *ast.ReturnStmt:
+			if len(node.Results) != 1 || !isReceiverIdent(pass, node.Results[0], recv) {
+				all = false
+			} else if !first.IsValid() {
+				first = node.Pos()
+			}
+		}
+
+		return all
+	})
+
+	if !all {
+		return token.NoPos
+	}
+
+	return first
+}
+
+// checkBuilderMethod handles a builder method, which the value-receiver and
+// return-pointer rules leave alone: switching either the receiver or the
+// result to a value changes what a chain of calls modifies. The opt-in
+// value-builder rule reports it with the alternative of a value builder,
+// whose methods modify and return a copy.
+func checkBuilderMethod(pass *analysis.Pass, fn *ast.FuncDecl, star *ast.StarExpr, st *state) {
+	if !st.ruleEnabled(rules.ValueBuilder) {
+		st.explain.skipped(pass, star.Pos(), "method returns its receiver at line %d for chaining (builder pattern); value-builder reports it", lineOf(pass, st.builders[fn]))
+
+		return
+	}
+
+	t, size, ok := smallStruct(pass, st, star.Pos(), star.X)
+	if !ok {
+		return
+	}
+
+	typeName := typeString(pass, t)
+	st.report(pass, t, analysis.Diagnostic{
+		Pos:      star.Pos(),
+		End:      star.End(),
+		Category: rules.ValueBuilder,
+		Message:  st.msg.Sprintf(messages.ValueBuilder, fn.Name.Name, typeName, typeName, fn.Name.Name, typeName, size, st.bounds()),
+		Related:  st.typeRelated(pass, t),
+	})
+}
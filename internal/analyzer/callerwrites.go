@@ -0,0 +1,124 @@
+package analyzer
+
+import (
+	"go/ast"
+	"go/token"
+	"go/types"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/ast/inspector"
+	"golang.org/x/tools/go/types/typeutil"
+)
+
+// resultWrites maps the package's functions returning pointers to the first
+// write through a returned pointer by a caller in the package: Get().Count++,
+// or c.Count++ after c := Get(). Such callers may rely on the pointer being
+// shared with whoever else holds it, which returning a value would break.
+type resultWrites map[*types.Func]token.Pos
+
+// findResultWrites finds the writes through pointers returned by the package's
+// functions.
+func findResultWrites(pass *analysis.Pass, inspect *inspector.Inspector) resultWrites {
+	result := make(resultWrites)
+	origins := findPointerResultVars(pass, inspect)
+
+	forEachPointerWrite(pass, inspect, func(e ast.Expr, pos token.Pos) {
+		var fn *types.Func
+
+		switch p := ast.Unparen(writtenPointer(pass, e)).(type) {
+		case *ast.CallExpr:
+			fn = pointerResultOf(pass, p, 0)
+		case *ast.Ident:
+			fn = origins[pass.TypesInfo.Uses[p]]
+		}
+
+		if fn != nil && !result[fn].IsValid() {
+			result[fn] = pos
+		}
+	})
+
+	return result
+}
+
+// findPointerResultVars maps the variables assigned a pointer result of the
+// package's functions (c := Get(), or c, err := Load()) to the function.
+func findPointerResultVars(pass *analysis.Pass, inspect *inspector.Inspector) map[types.Object]*types.Func {
+	origins := make(map[types.Object]*types.Func)
+
+	assign := func(lhs []ast.Expr, rhs []ast.Expr) {
+		for i, l := range lhs {
+			ident, ok := ast.Unparen(l).(*ast.Ident)
+			if !ok {
+				continue
+			}
+
+			obj := pass.TypesInfo.ObjectOf(ident)
+			if obj == nil {
+				continue
+			}
+
+			var fn *types.Func
+
+			switch {
+			case len(lhs) == len(rhs):
+				fn = pointerResultOf(pass, rhs[i], 0)
+			case len(rhs) == 1:
+				fn = pointerResultOf(pass, rhs[0], i)
+			}
+
+			if fn != nil {
+				origins[obj] = fn
+			}
+		}
+	}
+
+	inspect.Preorder([]ast.Node{(*ast.AssignStmt)(nil), (*ast.ValueSpec)(nil)}, func(n ast.Node) {
+		switch node := n.(type) {
+		case *ast.AssignStmt:
+			assign(node.Lhs, node.Rhs)
+		case *ast.ValueSpec:
+			lhs := make([]ast.Expr, len(node.Names))
+			for i, name := range node.Names {
+				lhs[i] = name
+			}
+
+			assign(lhs, node.Values)
+		}
+	})
+
+	return origins
+}
+
+// pointerResultOf returns the function of the package that e calls if e is a
+// call whose i-th result is a pointer, or nil.
+func pointerResultOf(pass *analysis.Pass, e ast.Expr, i int) *types.Func {
+	call, ok := ast.Unparen(e).(*ast.CallExpr)
+	if !ok {
+		return nil
+	}
+
+	fn := typeutil.StaticCallee(pass.TypesInfo, call)
+	if fn == nil || fn.Pkg() != pass.Pkg {
+		return nil
+	}
+
+	fn = fn.Origin()
+
+	sig, ok := fn.Type().(*types.Signature)
+	if !ok || i >= sig.Results().Len() || !isPointer(sig.Results().At(i).Type()) {
+		return nil
+	}
+
+	return fn
+}
+
+// first returns the first write through a pointer returned by fn, or
+// token.NoPos.
+func (w resultWrites) first(pass *analysis.Pass, fn *ast.FuncDecl) token.Pos {
+	obj, ok := pass.TypesInfo.Defs[fn.Name].(*types.Func)
+	if !ok {
+		return token.NoPos
+	}
+
+	return w[obj]
+}
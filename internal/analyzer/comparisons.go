@@ -0,0 +1,102 @@
+package analyzer
+
+import (
+	"go/ast"
+	"go/token"
+	"go/types"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/ast/inspector"
+
+	"github.com/mickamy/pointless/internal/messages"
+	"github.com/mickamy/pointless/internal/rules"
+)
+
+// pointerComparisons maps the named types of the package to the first ==
+// or != comparison of two pointers to the type. Comparisons with nil do not
+// count: they are checked with the nil index.
+type pointerComparisons map[*types.TypeName]token.Pos
+
+// findPointerComparisons finds the comparisons of pointers to the named types
+// of the package.
+func findPointerComparisons(pass *analysis.Pass, inspect *inspector.Inspector) pointerComparisons {
+	result := make(pointerComparisons)
+
+	inspect.Preorder([]ast.Node{(*ast.BinaryExpr)(nil)}, func(n ast.Node) {
+		bin, ok := n.(*ast.BinaryExpr)
+		if !ok || bin.Op != token.EQL && bin.Op != token.NEQ {
+			return
+		}
+
+		x, y := pass.TypesInfo.Types[bin.X], pass.TypesInfo.Types[bin.Y]
+		if x.IsNil() || y.IsNil() {
+			return
+		}
+
+		ptr, ok := types.Unalias(x.Type).(*types.Pointer)
+		if !ok || !types.Identical(x.Type, y.Type) {
+			return
+		}
+
+		if obj := namedObject(ptr.Elem()); obj != nil && obj.Pkg() == pass.Pkg && !result[obj].IsValid() {
+			result[obj] = bin.Pos()
+		}
+	})
+
+	return result
+}
+
+// valueRules lists the rules whose suggestion hands out values where the code
+// has pointers, so that comparing them compares the values instead.
+var valueRules = map[string]bool{
+	rules.ReturnPointer: true,
+	rules.SlicePointer:  true,
+	rules.LocalPointer:  true,
+	rules.ContextValue:  true,
+}
+
+// checkComparisons adapts d, about subject, to the comparisons of pointers to
+// subject in the package. It reports false if d must be dropped: the code
+// compares pointers to a type whose values cannot be compared, so following
+// the suggestion would not compile. Otherwise, suggestions to use values note
+// that the comparisons would compare values instead of identities.
+//
+// Suggested fixes are withheld for types that are not comparable either way,
+// so applying them never leaves == on values that cannot be compared.
+func (st *state) checkComparisons(pass *analysis.Pass, subject types.Type, d *analysis.Diagnostic) bool {
+	if ptr, ok := types.Unalias(subject).(*types.Pointer); ok {
+		subject = ptr.Elem()
+	}
+
+	obj := namedObject(subject)
+	if obj == nil {
+		return true
+	}
+
+	pos := st.comparisons[obj]
+	if !pos.IsValid() {
+		return true
+	}
+
+	canCompare := types.Comparable(subject)
+
+	if !canCompare && len(d.SuggestedFixes) > 0 {
+		st.explain.fixWithheld(pass, d.Pos, "pointers to "+obj.Name()+" are compared, and its values are not comparable")
+
+		d.SuggestedFixes = nil
+	}
+
+	if !valueRules[d.Category] {
+		return true
+	}
+
+	if !canCompare {
+		st.explain.skipped(pass, d.Pos, "pointers to %s are compared at line %d, and %s is not comparable", obj.Name(), lineOf(pass, pos), obj.Name())
+
+		return false
+	}
+
+	d.Message = st.msg.Sprintf(messages.ComparedPointers, d.Message, obj.Name(), lineOf(pass, pos))
+
+	return true
+}
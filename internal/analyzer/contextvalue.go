@@ -0,0 +1,49 @@
+package analyzer
+
+import (
+	"go/ast"
+	"go/types"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/types/typeutil"
+
+	"github.com/mickamy/pointless/internal/messages"
+	"github.com/mickamy/pointless/internal/rules"
+)
+
+// checkCallExpr checks make([]*T, ...) calls and the opt-in call-site rules.
+func checkCallExpr(pass *analysis.Pass, call *ast.CallExpr, st *state) {
+	checkSliceMake(pass, call, st)
+
+	if st.ruleEnabled(rules.ContextValue) {
+		checkContextValue(pass, call, st)
+	}
+}
+
+// checkContextValue checks context.WithValue(ctx, key, &T{...}) calls. Context
+// values are read-only by convention, so storing a pointer only invites
+// accidental mutation shared by everyone holding the context.
+func checkContextValue(pass *analysis.Pass, call *ast.CallExpr, st *state) {
+	fn, ok := typeutil.Callee(pass.TypesInfo, call).(*types.Func)
+	if !ok || fn.Pkg() == nil || fn.Pkg().Path() != "context" || fn.Name() != "WithValue" || len(call.Args) != 3 {
+		return
+	}
+
+	val := call.Args[2]
+	if !isFreshAllocation(pass, val) {
+		return
+	}
+
+	ptr, ok := pass.TypesInfo.TypeOf(val).(*types.Pointer)
+	if !ok {
+		return
+	}
+
+	t, size, ok := smallStructType(pass, st, val.Pos(), ptr.Elem())
+	if !ok {
+		return
+	}
+
+	typeName := typeString(pass, t)
+	st.reportf(pass, t, rules.ContextValue, val, messages.ContextValue, typeName, typeName, size, st.bounds())
+}
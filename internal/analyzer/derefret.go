@@ -0,0 +1,250 @@
+package analyzer
+
+import (
+	"go/ast"
+	"go/token"
+	"go/types"
+	"strings"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/ast/inspector"
+
+	"github.com/mickamy/pointless/internal/fixsafety"
+	"github.com/mickamy/pointless/internal/messages"
+	"github.com/mickamy/pointless/internal/rules"
+)
+
+// derefReturns records how the package's functions returning a single struct
+// pointer have their result used by callers in the package.
+type derefReturns struct {
+	// sites maps functions to the *f() expressions dereferencing their result.
+	sites map[*types.Func][]*ast.StarExpr
+	// other maps functions to their first use other than a dereferenced call.
+	other map[*types.Func]token.Pos
+}
+
+// findDerefReturns scans every use of the package's functions returning a
+// single struct pointer and records whether the call is dereferenced at once.
+func findDerefReturns(pass *analysis.Pass, inspect *inspector.Inspector) derefReturns {
+	result := derefReturns{
+		sites: make(map[*types.Func][]*ast.StarExpr),
+		other: make(map[*types.Func]token.Pos),
+	}
+
+	inspect.WithStack([]ast.Node{(*ast.Ident)(nil)}, func(n ast.Node, push bool, stack []ast.Node) bool {
+		if !push {
+			return true
+		}
+
+		ident, ok := n.(*ast.Ident)
+		if !ok {
+			return true
+		}
+
+		fn, ok := pass.TypesInfo.Uses[ident].(*types.Func)
+		if !ok || fn.Pkg() != pass.Pkg {
+			return true
+		}
+
+		fn = fn.Origin()
+
+		sig, ok := fn.Type().(*types.Signature)
+		if !ok || sig.Results().Len() != 1 || !isStructPointer(sig.Results().At(0).Type()) {
+			return true
+		}
+
+		if star := dereferencedCall(stack); star != nil {
+			result.sites[fn] = append(result.sites[fn], star)
+		} else if !result.other[fn].IsValid() {
+			result.other[fn] = ident.Pos()
+		}
+
+		return true
+	})
+
+	return result
+}
+
+// callers returns the *f() expressions of fn's callers if fn has a single
+// result and every use of it in the package dereferences the result at once,
+// or nil.
+func (d derefReturns) callers(pass *analysis.Pass, fn *ast.FuncDecl) []*ast.StarExpr {
+	obj, ok := pass.TypesInfo.Defs[fn.Name].(*types.Func)
+	if !ok || d.other[obj].IsValid() {
+		return nil
+	}
+
+	return d.sites[obj]
+}
+
+// reportDereferencedReturn reports a pointer return whose callers all
+// dereference the result, listing the callers, with a fix returning the value
+// (see derefReturnFix).
+func reportDereferencedReturn(pass *analysis.Pass, fn *ast.FuncDecl, st *state, star *ast.StarExpr, t types.Type, typeName string, size int64, sites []*ast.StarExpr) {
+	msg := st.msg.Sprintf(messages.ReturnPointerDereferenced, typeName, len(sites), size, st.bounds())
+	if len(sites) == 1 {
+		msg = st.msg.Sprintf(messages.ReturnPointerDereferencedOne, typeName, size, st.bounds())
+	}
+
+	d := analysis.Diagnostic{
+		Pos:      star.Pos(),
+		End:      star.End(),
+		Category: rules.ReturnPointer,
+		Message:  msg,
+	}
+
+	for _, site := range sites {
+		d.Related = append(d.Related, analysis.RelatedInformation{
+			Pos:     site.Pos(),
+			End:     site.End(),
+			Message: st.msg.Sprintf(messages.ReturnPointerCall, types.ExprString(site)),
+		})
+	}
+
+	if fix, ok := derefReturnFix(pass, fn, star, typeName, sites, st); ok {
+		d.SuggestedFixes = []analysis.SuggestedFix{fix}
+	}
+
+	st.report(pass, t, d)
+}
+
+// derefReturnFix returns the fix changing the result of fn from *T to T: the
+// * of the result and of the callers' *f() goes, and the return statements
+// return &x as x and p as *p. Changing an exported signature is left to
+// guardFixes in public packages; in internal ones, the importers in the module
+// are not analyzed with the package, so the fix is withheld unless
+// AllowBreaking is set. Methods, whose signature may be required by an
+// interface, and named results, which bare returns use, get no fix.
+func derefReturnFix(pass *analysis.Pass, fn *ast.FuncDecl, star *ast.StarExpr, typeName string, sites []*ast.StarExpr, st *state) (analysis.SuggestedFix, bool) {
+	if fn.Recv != nil || fn.Body == nil || len(fn.Type.Results.List[0].Names) > 0 {
+		return analysis.SuggestedFix{}, false
+	}
+
+	if fn.Name.IsExported() && !st.opts.AllowBreaking && importedInModule(pass.Pkg) {
+		st.explain.fixWithheld(pass, star.Pos(), "callers in other packages of the module are not analyzed")
+
+		return analysis.SuggestedFix{}, false
+	}
+
+	edits := []analysis.TextEdit{{Pos: star.Pos(), End: star.X.Pos()}}
+	for _, site := range sites {
+		edits = append(edits, analysis.TextEdit{Pos: site.Pos(), End: site.X.Pos()})
+	}
+
+	dereferenced, deferred := false, false
+
+	ast.Inspect(fn.Body, func(n ast.Node) bool {
+		switch n := n.(type) {
+		case *ast.FuncLit:
+			return false
+		case *ast.DeferStmt:
+			deferred = true
+		case *ast.ReturnStmt:
+			if len(n.Results) != 1 {
+				return true
+			}
+
+			if addr, ok := ast.Unparen(n.Results[0]).(*ast.UnaryExpr); ok && addr.Op == token.AND {
+				edits = append(edits, analysis.TextEdit{Pos: addr.Pos(), End: addr.X.Pos()})
+			} else {
+				dereferenced = true
+				edits = append(edits, analysis.TextEdit{Pos: n.Results[0].Pos(), End: n.Results[0].Pos(), NewText: []byte("*")})
+			}
+		}
+
+		return true
+	})
+
+	evidence := fixsafety.Evidence{Level: fixsafety.Safe, Reasons: []string{st.msg.Sprintf(messages.FixReturnCopied)}}
+	if dereferenced || deferred {
+		evidence = fixsafety.Evidence{Level: fixsafety.Likely}
+	}
+
+	if dereferenced {
+		evidence.Reasons = append(evidence.Reasons, st.msg.Sprintf(messages.FixReturnDereferenced))
+	}
+
+	if deferred {
+		evidence.Reasons = append(evidence.Reasons, st.msg.Sprintf(messages.FixReturnDeferred))
+	}
+
+	return analysis.SuggestedFix{
+		Message:   fixsafety.Tag(st.msg.Sprintf(messages.ReturnPointerFix, typeName), evidence),
+		TextEdits: edits,
+	}, true
+}
+
+// importedInModule reports whether pkg can only be imported from its own
+// module: it is below an internal directory.
+func importedInModule(pkg *types.Package) bool {
+	return pkg.Name() != "main" && !strings.HasSuffix(pkg.Path(), "_test") && !hasPublicAPI(pkg)
+}
+
+// dereferencedCall returns the *f() expression if the identifier at the top of
+// stack is called and its result immediately dereferenced and copied as a
+// whole, or nil. Field accesses and assignments through *f() are left out:
+// they would not compile on a returned value once they write.
+func dereferencedCall(stack []ast.Node) *ast.StarExpr {
+	call, deferred := calledWith(stack)
+	if call == nil || deferred {
+		return nil
+	}
+
+	i := len(stack) - 1
+	for i >= 0 && stack[i] != call {
+		i--
+	}
+
+	var node ast.Node = call
+
+	for i--; i >= 0; i-- {
+		if _, ok := stack[i].(*ast.ParenExpr); !ok {
+			break
+		}
+
+		node = stack[i]
+	}
+
+	if i < 0 {
+		return nil
+	}
+
+	star, ok := stack[i].(*ast.StarExpr)
+	if !ok || star.X != node {
+		return nil
+	}
+
+	if i > 0 && !copiedWhole(stack[:i+1]) {
+		return nil
+	}
+
+	return star
+}
+
+// copiedWhole reports whether the expression at the top of stack is used as a
+// value, rather than having its address taken, a field selected, or being
+// assigned to.
+func copiedWhole(stack []ast.Node) bool {
+	node := stack[len(stack)-1]
+
+	for i := len(stack) - 2; i >= 0; i-- {
+		switch parent := stack[i].(type) {
+		case *ast.ParenExpr:
+			node = parent
+
+			continue
+		case *ast.SelectorExpr, *ast.IndexExpr, *ast.UnaryExpr, *ast.IncDecStmt:
+			return false
+		case *ast.AssignStmt:
+			for _, lhs := range parent.Lhs {
+				if lhs == node {
+					return false
+				}
+			}
+		}
+
+		return true
+	}
+
+	return true
+}
@@ -0,0 +1,113 @@
+package analyzer
+
+import (
+	"go/ast"
+	"go/types"
+
+	"golang.org/x/tools/go/analysis"
+)
+
+// embedders maps the named types of the package to the package-level structs
+// that embed them by value, directly or through other structs embedded by
+// value. Methods of *T are promoted to the pointer method set of such structs
+// only; with a value receiver they join the value method set too.
+type embedders map[*types.TypeName][]*types.Named
+
+// findEmbedders indexes the structs of the package embedding its types.
+// Embedding *T promotes the methods of *T to the value method set already, so
+// the walk does not go through embedded pointers.
+func findEmbedders(pass *analysis.Pass) embedders {
+	result := make(embedders)
+
+	scope := pass.Pkg.Scope()
+	for _, name := range scope.Names() {
+		tn, ok := scope.Lookup(name).(*types.TypeName)
+		if !ok || tn.IsAlias() {
+			continue
+		}
+
+		outer, ok := tn.Type().(*types.Named)
+		if !ok {
+			continue
+		}
+
+		seen := make(map[*types.TypeName]bool)
+
+		var walk func(s *types.Struct)
+
+		walk = func(s *types.Struct) {
+			for i := range s.NumFields() {
+				f := s.Field(i)
+				if !f.Embedded() {
+					continue
+				}
+
+				named, ok := types.Unalias(f.Type()).(*types.Named)
+				if !ok {
+					continue
+				}
+
+				obj := named.Origin().Obj()
+				if seen[obj] || obj.Pkg() != pass.Pkg {
+					continue
+				}
+
+				seen[obj] = true
+				result[obj] = append(result[obj], outer)
+
+				if inner, ok := named.Underlying().(*types.Struct); ok {
+					walk(inner)
+				}
+			}
+		}
+
+		if s, ok := outer.Underlying().(*types.Struct); ok {
+			walk(s)
+		}
+	}
+
+	return result
+}
+
+// promotedTo returns the structs of the package the method fn of t is
+// promoted to, skipping those where a shallower field or method of the
+// same name shadows it.
+func (e embedders) promotedTo(t types.Type, fn *types.Func) []*types.Named {
+	named, ok := types.Unalias(t).(*types.Named)
+	if !ok {
+		return nil
+	}
+
+	var result []*types.Named
+
+	for _, outer := range e[named.Origin().Obj()] {
+		obj, _, _ := types.LookupFieldOrMethod(types.NewPointer(outer), false, fn.Pkg(), fn.Name())
+		if m, ok := obj.(*types.Func); ok && m.Origin() == fn {
+			result = append(result, outer)
+		}
+	}
+
+	return result
+}
+
+// promotedPointerMethod reports whether the method fn of t is promoted to a
+// struct embedding t that satisfies an interface requiring it only through
+// its pointer type (see pointerInterfaceMethods), with the conversion to or
+// call through that interface showing the package relies on it. A value
+// receiver would add the method to the value method set of the struct,
+// changing which interfaces its values satisfy, and calls through the
+// interface would run on a copy of the embedded value.
+func (st *state) promotedPointerMethod(pass *analysis.Pass, fn *ast.FuncDecl, t types.Type) (*types.Named, pointerInterfaceUse, bool) {
+	obj, ok := pass.TypesInfo.Defs[fn.Name].(*types.Func)
+	if !ok {
+		return nil, pointerInterfaceUse{}, false
+	}
+
+	for _, outer := range st.embedders.promotedTo(t, obj) {
+		if use, ok := st.pointerIfaces.lookup(fn, outer); ok {
+			return outer, use, true
+		}
+	}
+
+	return nil, pointerInterfaceUse{}, false
+}
@@ -0,0 +1,250 @@
+package analyzer
+
+import (
+	"go/ast"
+	"go/token"
+	"go/types"
+	"strings"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/ast/inspector"
+
+	"github.com/mickamy/pointless/internal/messages"
+	"github.com/mickamy/pointless/internal/rules"
+)
+
+// embeddedStores maps embedded pointer fields (struct{ *T }) to the first
+// place they are set to something other than a fresh allocation, in a
+// composite literal or an assignment. Such fields share a *T owned elsewhere,
+// which embedding T would copy instead.
+type embeddedStores map[types.Object]token.Pos
+
+// findEmbeddedStores finds the embedded pointer fields of the package set to
+// existing pointers; see embeddedStores.
+func findEmbeddedStores(pass *analysis.Pass, inspect *inspector.Inspector) embeddedStores {
+	result := make(embeddedStores)
+
+	record := func(field types.Object, value ast.Expr) {
+		v, ok := field.(*types.Var)
+		if !ok || !v.Embedded() || !isPointer(v.Type()) || isFreshAllocation(pass, value) {
+			return
+		}
+
+		if !result[v].IsValid() {
+			result[v] = value.Pos()
+		}
+	}
+
+	nodeFilter := []ast.Node{
+		(*ast.CompositeLit)(nil),
+		(*ast.AssignStmt)(nil),
+	}
+
+	inspect.Preorder(nodeFilter, func(n ast.Node) {
+		switch node := n.(type) {
+		case *ast.CompositeLit:
+			t := pass.TypesInfo.TypeOf(node)
+			if t == nil {
+				return
+			}
+
+			s, ok := t.Underlying().(*types.Struct)
+			if !ok {
+				return
+			}
+
+			for i, elt := range node.Elts {
+				if kv, ok := elt.(*ast.KeyValueExpr); ok {
+					if key, ok := kv.Key.(*ast.Ident); ok {
+						record(pass.TypesInfo.Uses[key], kv.Value)
+					}
+
+					continue
+				}
+
+				if i < s.NumFields() {
+					record(s.Field(i), elt)
+				}
+			}
+		case *ast.AssignStmt:
+			if len(node.Lhs) != len(node.Rhs) {
+				return
+			}
+
+			for i, lhs := range node.Lhs {
+				if obj := objectOf(pass, lhs); obj != nil {
+					record(obj, node.Rhs[i])
+				}
+			}
+		}
+	})
+
+	return result
+}
+
+// checkEmbeddedPointers checks the embedded *T fields of the struct types
+// declared by decl, for the opt-in embedded-pointer rule. Embedding T instead
+// keeps the fields and methods of T promoted without the extra allocation and
+// indirection, but copies of the outer struct no longer share one T, and the
+// methods of T with pointer receivers leave the value method set of the outer
+// struct.
+func checkEmbeddedPointers(pass *analysis.Pass, decl *ast.GenDecl, st *state) {
+	if decl.Tok != token.TYPE || !st.ruleEnabled(rules.EmbeddedPointer) {
+		return
+	}
+
+	for _, spec := range decl.Specs {
+		ts, ok := spec.(*ast.TypeSpec)
+		if !ok {
+			continue
+		}
+
+		// Skip types listed in ignore-symbols (grouped declarations)
+		if containsPos(st.ignoredSymbols, ts.Pos()) {
+			continue
+		}
+
+		s, ok := ts.Type.(*ast.StructType)
+		if !ok {
+			continue
+		}
+
+		tn, ok := pass.TypesInfo.Defs[ts.Name].(*types.TypeName)
+		if !ok {
+			continue
+		}
+
+		outer, ok := tn.Type().(*types.Named)
+		if !ok {
+			continue
+		}
+
+		for _, field := range s.Fields.List {
+			if star, ok := ast.Unparen(field.Type).(*ast.StarExpr); ok && len(field.Names) == 0 {
+				checkEmbeddedPointer(pass, outer, star, st)
+			}
+		}
+	}
+}
+
+// checkEmbeddedPointer checks the field embedding star in the struct outer.
+func checkEmbeddedPointer(pass *analysis.Pass, outer *types.Named, star *ast.StarExpr, st *state) {
+	elem, ok := types.Unalias(pass.TypesInfo.TypeOf(star.X)).(*types.Named)
+	if !ok {
+		return
+	}
+
+	field := embeddedField(outer, elem)
+	if field == nil {
+		return
+	}
+
+	typeName := typeString(pass, elem)
+
+	// Skip if the package relies on the field being nil
+	if pos, ok := st.nils.values[field]; ok {
+		st.explain.skipped(pass, star.Pos(), "the embedded *%s is compared with or set to nil at line %d", typeName, lineOf(pass, pos))
+
+		return
+	}
+
+	// Skip if the field shares a pointer owned elsewhere
+	if pos := st.embeddedStores[field]; pos.IsValid() {
+		st.explain.skipped(pass, star.Pos(), "the embedded *%s is set to an existing pointer at line %d", typeName, lineOf(pass, pos))
+
+		return
+	}
+
+	methods := promotedPointerMethods(pass, outer, elem, field)
+
+	// Skip if methods of *T handle a nil receiver, which the zero value of the
+	// outer struct relies on
+	for _, m := range methods {
+		if pos, ok := st.nils.values[m.Signature().Recv()]; ok {
+			st.explain.skipped(pass, star.Pos(), "(*%s).%s handles a nil receiver at line %d", typeName, m.Name(), lineOf(pass, pos))
+
+			return
+		}
+	}
+
+	// Skip if the outer struct satisfies an interface through methods of *T,
+	// which embedding T would promote to the pointer method set only
+	if iface, m, ok := st.pointerIfaces.promotedThrough(outer, methods); ok {
+		st.explain.skipped(pass, star.Pos(), "%s satisfies %s through (*%s).%s, which embedding %s would promote to *%s only", outer.Obj().Name(), typeString(pass, iface.Type()), typeName, m.Name(), typeName, outer.Obj().Name())
+
+		return
+	}
+
+	t, size, ok := smallStructType(pass, st, star.Pos(), elem)
+	if !ok {
+		return
+	}
+
+	outerName := outer.Obj().Name()
+	msg := st.msg.Sprintf(messages.EmbeddedPointer, typeName, typeName, outerName, typeName, size, st.bounds())
+
+	if len(methods) > 0 {
+		names := make([]string, len(methods))
+		for i, m := range methods {
+			names[i] = m.Name()
+		}
+
+		msg = st.msg.Sprintf(messages.EmbeddedPointerMethods, msg, typeName, strings.Join(names, ", "), outerName, outerName)
+	}
+
+	st.report(pass, t, analysis.Diagnostic{Pos: star.Pos(), End: star.End(), Category: rules.EmbeddedPointer, Message: msg})
+}
+
+// embeddedField returns the field of outer embedding *elem, or nil.
+func embeddedField(outer *types.Named, elem *types.Named) *types.Var {
+	s, ok := outer.Underlying().(*types.Struct)
+	if !ok {
+		return nil
+	}
+
+	for i := range s.NumFields() {
+		f := s.Field(i)
+		if !f.Embedded() {
+			continue
+		}
+
+		if ptr, ok := types.Unalias(f.Type()).(*types.Pointer); ok && types.Identical(ptr.Elem(), elem) {
+			return f
+		}
+	}
+
+	return nil
+}
+
+// promotedPointerMethods returns the pointer-receiver methods of elem that
+// field, embedding *elem, promotes to outer: the methods that embedding elem
+// would only promote to *outer.
+func promotedPointerMethods(pass *analysis.Pass, outer, elem *types.Named, field *types.Var) []*types.Func {
+	var result []*types.Func
+
+	mset := types.NewMethodSet(types.NewPointer(elem))
+	for i := range mset.Len() {
+		m, ok := mset.At(i).Obj().(*types.Func)
+		if !ok || !hasPointerReceiver(m) || !m.Exported() && m.Pkg() != pass.Pkg {
+			continue
+		}
+
+		// Skip methods shadowed by a shallower field or method of outer
+		obj, index, _ := types.LookupFieldOrMethod(outer, false, m.Pkg(), m.Name())
+		if fn, ok := obj.(*types.Func); ok && fn.Origin() == m.Origin() && len(index) > 0 && outerField(outer, index[0]) == field {
+			result = append(result, m)
+		}
+	}
+
+	return result
+}
+
+// outerField returns the field at index i of the struct outer.
+func outerField(outer *types.Named, i int) *types.Var {
+	s, ok := outer.Underlying().(*types.Struct)
+	if !ok || i >= s.NumFields() {
+		return nil
+	}
+
+	return s.Field(i)
+}
@@ -0,0 +1,102 @@
+package analyzer
+
+import (
+	"go/ast"
+	"go/types"
+
+	"golang.org/x/tools/go/analysis"
+
+	"github.com/mickamy/pointless/internal/directives"
+	"github.com/mickamy/pointless/internal/messages"
+	"github.com/mickamy/pointless/internal/rules"
+)
+
+// typeDirectiveFact is exported for the types annotated with a directive
+// applying wherever the type is used, //pointless:enforce or
+// //pointless:pointer, so that importing packages honor it too.
+type typeDirectiveFact struct {
+	// Directive is the directive as written, as in pointless:enforce.
+	Directive string
+}
+
+// AFact implements analysis.Fact.
+func (*typeDirectiveFact) AFact() {}
+
+func (f *typeDirectiveFact) String() string {
+	return f.Directive
+}
+
+// exportTypeDirectives exports a typeDirectiveFact for every type of the
+// package annotated with //pointless:enforce or //pointless:pointer.
+func exportTypeDirectives(pass *analysis.Pass) {
+	for _, f := range pass.Files {
+		for _, cg := range f.Comments {
+			for _, c := range cg.List {
+				d, ok := directives.Parse(c.Text)
+				if !ok || !d.Kind.Exported() {
+					continue
+				}
+
+				for _, at := range annotatedTypes(pass, f, c, d.Kind) {
+					pass.ExportObjectFact(at.obj, &typeDirectiveFact{Directive: d.Kind.String()})
+				}
+			}
+		}
+	}
+}
+
+// typeDirective returns the directive annotating the named type t, or the
+// type t points to, in this package or (via facts) in its dependencies.
+func typeDirective(pass *analysis.Pass, t types.Type) string {
+	if ptr, ok := types.Unalias(t).(*types.Pointer); ok {
+		t = ptr.Elem()
+	}
+
+	named, ok := types.Unalias(t).(*types.Named)
+	if !ok {
+		return ""
+	}
+
+	var fact typeDirectiveFact
+	if !pass.ImportObjectFact(named.Origin().Obj(), &fact) {
+		return ""
+	}
+
+	return fact.Directive
+}
+
+// isEnforced reports whether t, or the type t points to, is annotated with
+// //pointless:enforce.
+func isEnforced(pass *analysis.Pass, t types.Type) bool {
+	return typeDirective(pass, t) == directives.Enforce.String()
+}
+
+// isPointerType reports whether t, or the type t points to, is annotated with
+// //pointless:pointer.
+func isPointerType(pass *analysis.Pass, t types.Type) bool {
+	return typeDirective(pass, t) == directives.Pointer.String()
+}
+
+// checkEnforcedPointer reports the *T type expressions of types annotated
+// with //pointless:enforce. Size, nil, and mutation heuristics do not apply:
+// the annotation states that the type is always used by value.
+func checkEnforcedPointer(pass *analysis.Pass, star *ast.StarExpr, st *state) {
+	tv, ok := pass.TypesInfo.Types[star]
+	if !ok || !tv.IsType() {
+		return
+	}
+
+	t := pass.TypesInfo.TypeOf(star.X)
+	if t == nil || !isEnforced(pass, t) {
+		return
+	}
+
+	typeName := typeString(pass, t)
+	st.report(pass, t, analysis.Diagnostic{
+		Pos:      star.Pos(),
+		End:      star.End(),
+		Category: rules.EnforcedValue,
+		Message:  st.msg.Sprintf(messages.EnforcedValue, typeName, typeName, typeName),
+		Related:  st.typeRelated(pass, t),
+	})
+}
@@ -0,0 +1,220 @@
+package analyzer
+
+import (
+	"go/ast"
+	"go/token"
+	"go/types"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/ast/inspector"
+	"golang.org/x/tools/go/types/typeutil"
+
+	"github.com/mickamy/pointless/internal/messages"
+	"github.com/mickamy/pointless/internal/rules"
+)
+
+// errorReturns records the functions with (*T, error) results whose nil *T
+// results are only ever returned along with a non-nil error, for the opt-in
+// return-pointer-error rule.
+type errorReturns struct {
+	// nilWithError holds the functions whose nil *T always goes with an error.
+	nilWithError map[*ast.FuncDecl]bool
+	// nilChecks maps the functions to the first nil check of their *T result
+	// by a caller in the package, which a value result would not compile with.
+	nilChecks map[*types.Func]token.Pos
+}
+
+// findErrorReturns finds the (*T, error) functions of the package that only
+// return a nil *T along with a non-nil error, and the callers nil-checking
+// their results (see nils).
+func findErrorReturns(pass *analysis.Pass, inspect *inspector.Inspector, nils nilIndex) errorReturns {
+	er := errorReturns{nilWithError: make(map[*ast.FuncDecl]bool), nilChecks: make(map[*types.Func]token.Pos)}
+
+	inspect.WithStack([]ast.Node{(*ast.ReturnStmt)(nil)}, func(n ast.Node, push bool, stack []ast.Node) bool {
+		if !push {
+			return true
+		}
+
+		// Function literals have results of their own
+		var fn *ast.FuncDecl
+
+		for i := len(stack) - 2; i >= 0 && fn == nil; i-- {
+			switch f := stack[i].(type) {
+			case *ast.FuncLit:
+				return true
+			case *ast.FuncDecl:
+				fn = f
+			}
+		}
+
+		if fn == nil || !hasPointerErrorResults(pass, fn) {
+			return true
+		}
+
+		ret, _ := n.(*ast.ReturnStmt)
+
+		ok, seen := er.nilWithError[fn]
+		if seen && !ok {
+			return true
+		}
+
+		switch {
+		case len(ret.Results) != 2:
+			// Naked returns and return f() may return nil with a nil error
+			er.nilWithError[fn] = false
+		case isNil(ast.Unparen(ret.Results[0])):
+			er.nilWithError[fn] = isNonNilError(pass, ret.Results[1], stack)
+		}
+
+		return true
+	})
+
+	for obj, fn := range findPointerResultVars(pass, inspect) {
+		pos, ok := nils.values[obj]
+		if !ok {
+			continue
+		}
+
+		if first := er.nilChecks[fn]; !first.IsValid() || pos < first {
+			er.nilChecks[fn] = pos
+		}
+	}
+
+	return er
+}
+
+// hasPointerErrorResults reports whether fn returns exactly (*T, error).
+func hasPointerErrorResults(pass *analysis.Pass, fn *ast.FuncDecl) bool {
+	obj, ok := pass.TypesInfo.Defs[fn.Name].(*types.Func)
+	if !ok {
+		return false
+	}
+
+	results := obj.Signature().Results()
+
+	return results.Len() == 2 && isPointer(results.At(0).Type()) && types.Identical(results.At(1).Type(), errorType)
+}
+
+// errorType is the predeclared error type.
+var errorType = types.Universe.Lookup("error").Type()
+
+// isNonNilError reports whether the error e, returned by the return statement
+// at the top of stack, is known not to be nil: a new error (errors.New,
+// fmt.Errorf, or a composite literal), a package-level error variable such as
+// io.EOF, or a variable the return is guarded by a != nil check of.
+func isNonNilError(pass *analysis.Pass, e ast.Expr, stack []ast.Node) bool {
+	switch e := ast.Unparen(e).(type) {
+	case *ast.CallExpr:
+		fn := typeutil.StaticCallee(pass.TypesInfo, e)
+		if fn == nil || fn.Pkg() == nil {
+			return false
+		}
+
+		path, name := fn.Pkg().Path(), fn.Name()
+
+		return path == "errors" && name == "New" || path == "fmt" && name == "Errorf"
+	case *ast.CompositeLit:
+		return true
+	case *ast.UnaryExpr:
+		_, ok := ast.Unparen(e.X).(*ast.CompositeLit)
+
+		return e.Op == token.AND && ok
+	case *ast.SelectorExpr:
+		v, ok := pass.TypesInfo.Uses[e.Sel].(*types.Var)
+
+		return ok && isPackageLevel(v)
+	case *ast.Ident:
+		v, ok := pass.TypesInfo.Uses[e].(*types.Var)
+		if !ok {
+			return false
+		}
+
+		return isPackageLevel(v) || guardedByNonNil(pass, v, stack)
+	}
+
+	return false
+}
+
+// isPackageLevel reports whether v is a package-level variable.
+func isPackageLevel(v *types.Var) bool {
+	return v.Pkg() != nil && v.Parent() == v.Pkg().Scope()
+}
+
+// guardedByNonNil reports whether the node at the top of stack is in the body
+// of an if statement whose condition checks v != nil, alone or as an operand
+// of &&, within the innermost function.
+func guardedByNonNil(pass *analysis.Pass, v *types.Var, stack []ast.Node) bool {
+	for i := len(stack) - 2; i >= 0; i-- {
+		switch node := stack[i].(type) {
+		case *ast.FuncDecl, *ast.FuncLit:
+			return false
+		case *ast.IfStmt:
+			if stack[i+1] == node.Body && checksNonNil(pass, node.Cond, v) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// checksNonNil reports whether cond implies v != nil.
+func checksNonNil(pass *analysis.Pass, cond ast.Expr, v *types.Var) bool {
+	bin, ok := ast.Unparen(cond).(*ast.BinaryExpr)
+	if !ok {
+		return false
+	}
+
+	switch bin.Op {
+	case token.LAND:
+		return checksNonNil(pass, bin.X, v) || checksNonNil(pass, bin.Y, v)
+	case token.NEQ:
+		x, y := ast.Unparen(bin.X), ast.Unparen(bin.Y)
+		if isNil(x) {
+			x, y = y, x
+		}
+
+		ident, ok := x.(*ast.Ident)
+
+		return ok && isNil(y) && pass.TypesInfo.Uses[ident] == v
+	}
+
+	return false
+}
+
+// checkErrorPointerReturn checks a *T result of a (*T, error) function that
+// only returns nil along with a non-nil error: (T, error) with the zero value
+// in place of nil would do, as callers check the error first.
+func checkErrorPointerReturn(pass *analysis.Pass, fn *ast.FuncDecl, star *ast.StarExpr, st *state) {
+	// Skip if other implementations of the interface method return nil
+	if st.skipNilImplementation(pass, fn, star.Pos()) {
+		return
+	}
+
+	obj, ok := pass.TypesInfo.Defs[fn.Name].(*types.Func)
+	if !ok {
+		return
+	}
+
+	// Skip if callers nil-check the result, which does not compile with a value
+	if pos := st.errorReturns.nilChecks[obj]; pos.IsValid() {
+		st.explain.skipped(pass, star.Pos(), "a caller compares the result with nil at line %d", lineOf(pass, pos))
+
+		return
+	}
+
+	// Skip if callers write through the result, which may be shared
+	if pos := st.resultWrites.first(pass, fn); pos.IsValid() {
+		st.explain.skipped(pass, star.Pos(), "a caller writes through the result at line %d, which may rely on sharing it", lineOf(pass, pos))
+
+		return
+	}
+
+	t, size, ok := smallStruct(pass, st, star.Pos(), star.X)
+	if !ok {
+		return
+	}
+
+	typeName := typeString(pass, t)
+	st.reportf(pass, t, rules.ReturnPointerError, star, messages.ReturnPointerError, typeName, typeName, size, st.bounds())
+}
@@ -0,0 +1,151 @@
+package analyzer
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"slices"
+
+	"golang.org/x/tools/go/analysis"
+
+	"github.com/mickamy/pointless/internal/config"
+)
+
+// linterName is the name golangci-lint knows the analyzer by, which the
+// linters condition of exclude-rules entries refers to.
+const linterName = "pointless"
+
+// excludeRule is a compiled exclude-rules entry of the config.
+type excludeRule struct {
+	// index is the 1-based position of the entry in the config.
+	index                          int
+	path, pathExcept, text, source *regexp.Regexp
+	rules                          []string
+}
+
+// excludeRules drops the findings matching an exclude-rules entry, with the
+// semantics of golangci-lint: a finding is dropped if it matches every
+// condition of an entry.
+type excludeRules struct {
+	entries []excludeRule
+	// lines caches the source lines of files, for source conditions.
+	lines map[string][][]byte
+}
+
+// newExcludeRules compiles the exclude-rules entries applying to pointless,
+// or returns nil if there are none.
+func newExcludeRules(entries []config.ExcludeRule) (*excludeRules, error) {
+	var result []excludeRule
+
+	for i, e := range entries {
+		if len(e.Linters) > 0 && !slices.Contains(e.Linters, linterName) {
+			continue
+		}
+
+		r := excludeRule{index: i + 1, rules: e.Rules}
+
+		for _, re := range []struct {
+			name, expr string
+			dst        **regexp.Regexp
+		}{
+			{"path", e.Path, &r.path},
+			{"path-except", e.PathExcept, &r.pathExcept},
+			{"text", e.Text, &r.text},
+			{"source", e.Source, &r.source},
+		} {
+			if re.expr == "" {
+				continue
+			}
+
+			compiled, err := regexp.Compile(re.expr)
+			if err != nil {
+				return nil, fmt.Errorf("invalid %s of exclude-rules entry %d: %w", re.name, r.index, err)
+			}
+
+			*re.dst = compiled
+		}
+
+		result = append(result, r)
+	}
+
+	if len(result) == 0 {
+		return nil, nil
+	}
+
+	return &excludeRules{entries: result, lines: make(map[string][][]byte)}, nil
+}
+
+// excluded reports whether d is dropped by an entry, and explains why. Path
+// conditions match the compiled file or, in code remapped by a //line
+// directive, the file it names; source conditions match the compiled line.
+func (x *excludeRules) excluded(pass *analysis.Pass, st *state, d analysis.Diagnostic) bool {
+	if x == nil {
+		return false
+	}
+
+	position := pass.Fset.PositionFor(d.Pos, false)
+
+	var names []string
+	if rel, ok := st.opts.Config.Relative(position.Filename); ok {
+		names = append(names, rel)
+	}
+
+	if remapped, ok := remappedFilename(pass, d.Pos); ok {
+		if rel, ok := st.opts.Config.Relative(remapped); ok {
+			names = append(names, rel)
+		}
+	}
+
+	matchesAny := func(re *regexp.Regexp) bool {
+		return slices.ContainsFunc(names, re.MatchString)
+	}
+
+	for _, r := range x.entries {
+		if len(r.rules) > 0 && !slices.Contains(r.rules, d.Category) {
+			continue
+		}
+
+		// Files outside the config directory match no path condition
+		if r.path != nil && !matchesAny(r.path) {
+			continue
+		}
+
+		if r.pathExcept != nil && (len(names) == 0 || matchesAny(r.pathExcept)) {
+			continue
+		}
+
+		if r.text != nil && !r.text.MatchString(d.Message) {
+			continue
+		}
+
+		if r.source != nil && !r.source.Match(x.line(pass, position.Filename, position.Line)) {
+			continue
+		}
+
+		st.explain.skipped(pass, d.Pos, "matches exclude-rules entry %d", r.index)
+
+		return true
+	}
+
+	return false
+}
+
+// line returns the given 1-based line of the file, or nil if it cannot be
+// read.
+func (x *excludeRules) line(pass *analysis.Pass, filename string, n int) []byte {
+	lines, ok := x.lines[filename]
+	if !ok {
+		src, err := pass.ReadFile(filename)
+		if err == nil {
+			lines = bytes.Split(src, []byte("\n"))
+		}
+
+		x.lines[filename] = lines
+	}
+
+	if n < 1 || n > len(lines) {
+		return nil
+	}
+
+	return bytes.TrimSuffix(lines[n-1], []byte("\r"))
+}
@@ -0,0 +1,173 @@
+package analyzer
+
+import (
+	"context"
+	"fmt"
+	"go/token"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+
+	"golang.org/x/tools/go/analysis"
+)
+
+// explainTarget is the -explain flag value ("path/to/file.go:123").
+var explainTarget string
+
+// explainOut is where -explain writes its output.
+var explainOut io.Writer = os.Stderr
+
+// explainMu serializes the writes to explainOut, shared by the passes of all
+// packages and the goroutines scanning their files.
+var explainMu sync.Mutex
+
+// explainer prints why pointers at one source line were or were not flagged,
+// and with -debug logs every decision. A nil explainer ignores all calls, so
+// checks can call it unconditionally.
+type explainer struct {
+	file string
+	line int
+	// debug logs the decisions on all lines, not only the target's.
+	debug bool
+	// seen is set once anything was explained in the current package; it is
+	// guarded by explainMu.
+	seen bool
+}
+
+// newExplainer parses an -explain target; it returns nil if target is empty
+// and -debug is not set.
+func newExplainer(target string) (*explainer, error) {
+	if target == "" {
+		if !debugEnabled() {
+			return nil, nil
+		}
+
+		return &explainer{debug: true}, nil
+	}
+
+	i := strings.LastIndexByte(target, ':')
+	if i < 0 {
+		return nil, fmt.Errorf("invalid -explain target %q: expected file.go:line", target)
+	}
+
+	line, err := strconv.Atoi(target[i+1:])
+	if err != nil || line <= 0 {
+		return nil, fmt.Errorf("invalid -explain target %q: expected file.go:line", target)
+	}
+
+	return &explainer{file: filepath.ToSlash(filepath.Clean(target[:i])), line: line, debug: debugEnabled()}, nil
+}
+
+// matches reports whether pos is on the target line.
+func (e *explainer) matches(pass *analysis.Pass, pos token.Pos) bool {
+	if e == nil || e.file == "" || !pos.IsValid() {
+		return false
+	}
+
+	// Targets may name the compiled file or the one a //line directive maps
+	// pos to
+	for _, p := range []token.Position{pass.Fset.Position(pos), pass.Fset.PositionFor(pos, false)} {
+		name := filepath.ToSlash(p.Filename)
+		if p.Line == e.line && (name == e.file || strings.HasSuffix(name, "/"+e.file)) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// skipped records why the pointer at pos was not flagged.
+func (e *explainer) skipped(pass *analysis.Pass, pos token.Pos, format string, args ...any) {
+	if e != nil && e.debug {
+		slog.Debug("not flagged", "pos", pass.Fset.Position(pos), "reason", fmt.Sprintf(format, args...))
+	}
+
+	e.excluded(pass, pos, format, args...)
+}
+
+// excluded is like skipped for nodes not checked at all, in excluded files or
+// ignored symbols. They are left out of the debug log, which lists each
+// excluded file and ignored symbol once instead.
+func (e *explainer) excluded(pass *analysis.Pass, pos token.Pos, format string, args ...any) {
+	if !e.matches(pass, pos) {
+		return
+	}
+
+	e.printf("%s: not flagged: %s\n", pass.Fset.Position(pos), fmt.Sprintf(format, args...))
+}
+
+// flagged records that a diagnostic was reported at pos.
+func (e *explainer) flagged(pass *analysis.Pass, pos token.Pos, msg string) {
+	if e != nil && e.debug {
+		slog.Debug("flagged", "pos", pass.Fset.Position(pos), "message", msg)
+	}
+
+	if !e.matches(pass, pos) {
+		return
+	}
+
+	e.printf("%s: flagged: %s\n", pass.Fset.Position(pos), msg)
+}
+
+// fixWithheld records that the suggested fix of the diagnostic at pos was
+// dropped, and why.
+func (e *explainer) fixWithheld(pass *analysis.Pass, pos token.Pos, reason string) {
+	if e != nil && e.debug {
+		slog.Debug("fix withheld", "pos", pass.Fset.Position(pos), "reason", reason)
+	}
+
+	if !e.matches(pass, pos) {
+		return
+	}
+
+	e.printf("%s: fix withheld: %s\n", pass.Fset.Position(pos), reason)
+}
+
+// finish reports when the target file belongs to the package but nothing on
+// the target line was examined.
+func (e *explainer) finish(pass *analysis.Pass) {
+	if e == nil || e.file == "" {
+		return
+	}
+
+	explainMu.Lock()
+	seen := e.seen
+	explainMu.Unlock()
+
+	if seen {
+		return
+	}
+
+	for _, f := range pass.Files {
+		name := filepath.ToSlash(pass.Fset.File(f.Pos()).Name())
+		if name == e.file || strings.HasSuffix(name, "/"+e.file) {
+			e.printf("%s:%d: no pointer types checked on this line\n", pass.Fset.File(f.Pos()).Name(), e.line)
+
+			return
+		}
+	}
+}
+
+// printf writes an explanation to explainOut and marks the package as seen.
+func (e *explainer) printf(format string, args ...any) {
+	explainMu.Lock()
+	defer explainMu.Unlock()
+
+	e.seen = true
+	fmt.Fprintf(explainOut, format, args...)
+}
+
+// debugEnabled reports whether the default logger writes debug records, as
+// with -debug, so that checks only format them when needed.
+func debugEnabled() bool {
+	return slog.Default().Enabled(context.Background(), slog.LevelDebug)
+}
+
+// lineOf returns the line number of pos for use in explanations.
+func lineOf(pass *analysis.Pass, pos token.Pos) int {
+	return pass.Fset.Position(pos).Line
+}
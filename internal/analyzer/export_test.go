@@ -0,0 +1,26 @@
+package analyzer
+
+import "io"
+
+// SetExplainOutput redirects -explain output and returns a function restoring it.
+func SetExplainOutput(w io.Writer) func() {
+	prev := explainOut
+	explainOut = w
+
+	return func() { explainOut = prev }
+}
+
+// SetScanWorkers bounds the goroutines scanning files and returns a function
+// restoring the previous bound.
+func SetScanWorkers(n int) func() {
+	prev := scanWorkers
+	scanWorkers = n
+
+	return func() { scanWorkers = prev }
+}
+
+// EditsExportedAPI exposes editsExportedAPI.
+var EditsExportedAPI = editsExportedAPI
+
+// NolintExpired exposes nolintExpired.
+var NolintExpired = nolintExpired
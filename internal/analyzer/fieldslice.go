@@ -0,0 +1,148 @@
+package analyzer
+
+import (
+	"go/ast"
+	"go/token"
+	"go/types"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/ast/inspector"
+)
+
+// fieldStores maps slice fields to the first place an existing pointer is
+// stored as an element (append(s.items, p), s.items[i] = p). Such fields hold
+// the identity of values owned elsewhere, like a registry, so []T would store
+// copies instead.
+type fieldStores map[types.Object]token.Pos
+
+// findFieldStores finds the slice fields of the package storing existing
+// pointers; see fieldStores.
+func findFieldStores(pass *analysis.Pass, inspect *inspector.Inspector) fieldStores {
+	result := make(fieldStores)
+
+	record := func(target ast.Expr, pos token.Pos) {
+		if v, ok := objectOf(pass, target).(*types.Var); ok && v.IsField() && !result[v].IsValid() {
+			result[v] = pos
+		}
+	}
+
+	nodeFilter := []ast.Node{
+		(*ast.CallExpr)(nil),
+		(*ast.AssignStmt)(nil),
+	}
+
+	inspect.Preorder(nodeFilter, func(n ast.Node) {
+		switch node := n.(type) {
+		case *ast.CallExpr:
+			ident, ok := ast.Unparen(node.Fun).(*ast.Ident)
+			if !ok || ident.Name != "append" || len(node.Args) < 2 {
+				return
+			}
+
+			if _, ok := pass.TypesInfo.Uses[ident].(*types.Builtin); !ok {
+				return
+			}
+
+			for _, arg := range node.Args[1:] {
+				// append(s.items, other...) shares the elements of other
+				if node.Ellipsis.IsValid() || !isFreshAllocation(pass, arg) {
+					record(node.Args[0], arg.Pos())
+
+					return
+				}
+			}
+		case *ast.AssignStmt:
+			if len(node.Lhs) != len(node.Rhs) {
+				return
+			}
+
+			for i, lhs := range node.Lhs {
+				idx, ok := ast.Unparen(lhs).(*ast.IndexExpr)
+				if ok && !isNil(node.Rhs[i]) && !isFreshAllocation(pass, node.Rhs[i]) {
+					record(idx.X, node.Rhs[i].Pos())
+				}
+			}
+		}
+	})
+
+	return result
+}
+
+// checkStructFieldSlices checks the []*T fields of the struct types declared by
+// decl, including nested anonymous structs. Fields are keyed by object in the
+// nil index, so nil uses anywhere in the package (s.items[i] = nil, a nil
+// check of a range value) keep a field from being reported.
+func checkStructFieldSlices(pass *analysis.Pass, decl *ast.GenDecl, st *state) {
+	if decl.Tok != token.TYPE {
+		return
+	}
+
+	for _, spec := range decl.Specs {
+		ts, ok := spec.(*ast.TypeSpec)
+		if !ok {
+			continue
+		}
+
+		// Skip types listed in ignore-symbols (grouped declarations)
+		if containsPos(st.ignoredSymbols, ts.Pos()) {
+			continue
+		}
+
+		ast.Inspect(ts.Type, func(n ast.Node) bool {
+			if s, ok := n.(*ast.StructType); ok {
+				for _, field := range s.Fields.List {
+					checkFieldSlice(pass, field, st)
+				}
+			}
+
+			return true
+		})
+	}
+}
+
+func checkFieldSlice(pass *analysis.Pass, field *ast.Field, st *state) {
+	arr, ok := ast.Unparen(field.Type).(*ast.ArrayType)
+	if !ok || arr.Len != nil {
+		return
+	}
+
+	elem, named, ok := pointerElem(pass, arr.Elt)
+	if !ok {
+		return
+	}
+
+	for _, name := range field.Names {
+		obj := pass.TypesInfo.Defs[name]
+		if obj == nil {
+			continue
+		}
+
+		// Skip if the package relies on nil elements
+		if pos := st.nils.element(obj); pos.IsValid() {
+			st.explain.skipped(pass, arr.Pos(), "elements of %s are compared with or set to nil at line %d", name.Name, lineOf(pass, pos))
+
+			return
+		}
+
+		// Skip if the elements are shared with goroutines
+		if pos := st.goroutines.slice(obj); pos.IsValid() {
+			st.explain.skipped(pass, arr.Pos(), "elements of %s are shared with a goroutine at line %d", name.Name, lineOf(pass, pos))
+
+			return
+		}
+
+		// Skip if the field holds pointers owned elsewhere
+		if pos := st.fieldStores[obj]; pos.IsValid() {
+			st.explain.skipped(pass, arr.Pos(), "%s stores an existing pointer at line %d", name.Name, lineOf(pass, pos))
+
+			return
+		}
+	}
+
+	t, size, ok := smallStructType(pass, st, arr.Pos(), elem)
+	if !ok {
+		return
+	}
+
+	reportSlicePointer(pass, st, arr, t, named, size, qualifiedTypeString)
+}
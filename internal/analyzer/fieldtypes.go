@@ -0,0 +1,73 @@
+package analyzer
+
+import (
+	"go/types"
+	"strings"
+)
+
+// fieldTypeMatcher reports whether a struct has a field of one of the types
+// listed in ignore-if-contains-field-type, directly or in fields of struct or
+// array type. Results are cached per type.
+type fieldTypeMatcher struct {
+	names []string
+	cache map[types.Type]string
+}
+
+// newFieldTypeMatcher matches the field types called names, spelled with the
+// package name ("sync.Mutex", "*sql.DB") or path ("database/sql.DB").
+func newFieldTypeMatcher(names []string) *fieldTypeMatcher {
+	m := &fieldTypeMatcher{cache: make(map[types.Type]string)}
+
+	for _, name := range names {
+		if name = strings.TrimSpace(name); name != "" {
+			m.names = append(m.names, name)
+		}
+	}
+
+	return m
+}
+
+// matching returns the first listed type with which t has a field, or "".
+func (m *fieldTypeMatcher) matching(t types.Type) string {
+	if len(m.names) == 0 {
+		return ""
+	}
+
+	if name, ok := m.cache[t]; ok {
+		return name
+	}
+
+	name := ""
+
+	if s, ok := t.Underlying().(*types.Struct); ok {
+		for i := range s.NumFields() {
+			if name = m.field(s.Field(i).Type()); name != "" {
+				break
+			}
+		}
+	}
+
+	m.cache[t] = name
+
+	return name
+}
+
+// field returns the listed type that a field of type t is, or contains.
+func (m *fieldTypeMatcher) field(t types.Type) string {
+	byName := func(p *types.Package) string { return p.Name() }
+
+	for _, name := range m.names {
+		if types.TypeString(t, byName) == name || types.TypeString(t, nil) == name {
+			return name
+		}
+	}
+
+	switch u := t.Underlying().(type) {
+	case *types.Struct:
+		return m.matching(t)
+	case *types.Array:
+		return m.field(u.Elem())
+	}
+
+	return ""
+}
@@ -0,0 +1,156 @@
+package analyzer
+
+import (
+	"go/ast"
+	"go/token"
+	"go/types"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/ast/inspector"
+)
+
+// goroutineShares records pointers handed to goroutines. Turning such a pointer
+// into a value gives the goroutine its own copy, so it would no longer observe
+// (or publish) changes made on the other side.
+type goroutineShares struct {
+	// values maps variables captured by a go statement's closure (or that of
+	// a Go method, see goMethodFunc), passed to the goroutine, or used as the
+	// receiver of its method call to the first such statement.
+	values map[types.Object]token.Pos
+	// elements maps slices whose element pointers reach a goroutine (go f(items[i]),
+	// or a captured range value) to the first such go statement.
+	elements map[types.Object]token.Pos
+}
+
+// spawn is a function started on a goroutine: by a go statement, or by a Go
+// method such as that of errgroup.Group.
+type spawn struct {
+	pos  token.Pos
+	fun  ast.Expr
+	args []ast.Expr
+}
+
+// findGoroutineShares scans go statements, and function literals passed to Go
+// methods, for the variables they share.
+func findGoroutineShares(pass *analysis.Pass, inspect *inspector.Inspector) goroutineShares {
+	shares := goroutineShares{
+		values:   make(map[types.Object]token.Pos),
+		elements: make(map[types.Object]token.Pos),
+	}
+
+	// rangeValues maps range value variables to the container they iterate over
+	rangeValues := make(map[types.Object]types.Object)
+
+	var spawns []spawn
+
+	nodeFilter := []ast.Node{
+		(*ast.RangeStmt)(nil),
+		(*ast.GoStmt)(nil),
+		(*ast.CallExpr)(nil),
+	}
+
+	inspect.Preorder(nodeFilter, func(n ast.Node) {
+		switch node := n.(type) {
+		case *ast.RangeStmt:
+			value, ok := node.Value.(*ast.Ident)
+			if !ok {
+				return
+			}
+
+			if obj, container := pass.TypesInfo.Defs[value], objectOf(pass, node.X); obj != nil && container != nil {
+				rangeValues[obj] = container
+			}
+		case *ast.GoStmt:
+			spawns = append(spawns, spawn{pos: node.Pos(), fun: node.Call.Fun, args: node.Call.Args})
+		case *ast.CallExpr:
+			if lit := goMethodFunc(pass, node); lit != nil {
+				spawns = append(spawns, spawn{pos: node.Pos(), fun: lit})
+			}
+		}
+	})
+
+	share := func(expr ast.Expr, pos token.Pos) {
+		if idx, ok := ast.Unparen(expr).(*ast.IndexExpr); ok {
+			if obj := objectOf(pass, idx.X); obj != nil {
+				markFirst(shares.elements, obj, pos)
+			}
+
+			return
+		}
+
+		obj := objectOf(pass, expr)
+		if obj == nil {
+			return
+		}
+
+		markFirst(shares.values, obj, pos)
+
+		if container, ok := rangeValues[obj]; ok {
+			markFirst(shares.elements, container, pos)
+		}
+	}
+
+	for _, sp := range spawns {
+		switch fun := ast.Unparen(sp.fun).(type) {
+		case *ast.FuncLit:
+			// Variables declared outside the closure are shared with it
+			ast.Inspect(fun.Body, func(n ast.Node) bool {
+				ident, ok := n.(*ast.Ident)
+				if !ok {
+					return true
+				}
+
+				if v, ok := pass.TypesInfo.Uses[ident].(*types.Var); ok && !v.IsField() && (v.Pos() < fun.Pos() || v.Pos() >= fun.End()) {
+					share(ident, sp.pos)
+				}
+
+				return true
+			})
+		case *ast.SelectorExpr:
+			// go x.Method() runs with x as the receiver
+			if sel, ok := pass.TypesInfo.Selections[fun]; ok && sel.Kind() == types.MethodVal {
+				share(fun.X, sp.pos)
+			}
+		}
+
+		for _, arg := range sp.args {
+			share(arg, sp.pos)
+		}
+	}
+
+	return shares
+}
+
+// goMethodFunc returns the function literal passed to a Go method starting it
+// on a goroutine, as in g.Go(func() error { ... }) with an errgroup.Group or
+// wg.Go(func() { ... }) with a sync.WaitGroup, or nil for other calls.
+func goMethodFunc(pass *analysis.Pass, call *ast.CallExpr) *ast.FuncLit {
+	sel, ok := ast.Unparen(call.Fun).(*ast.SelectorExpr)
+	if !ok || sel.Sel.Name != "Go" || len(call.Args) != 1 {
+		return nil
+	}
+
+	if s, ok := pass.TypesInfo.Selections[sel]; !ok || s.Kind() != types.MethodVal {
+		return nil
+	}
+
+	lit, _ := ast.Unparen(call.Args[0]).(*ast.FuncLit)
+
+	return lit
+}
+
+// markFirst records pos for obj unless an earlier position is already recorded.
+func markFirst(m map[types.Object]token.Pos, obj types.Object, pos token.Pos) {
+	if _, ok := m[obj]; !ok {
+		m[obj] = pos
+	}
+}
+
+// slice returns the first go statement sharing the slice obj or its elements, or token.NoPos.
+func (g goroutineShares) slice(obj types.Object) token.Pos {
+	if pos := g.values[obj]; pos.IsValid() {
+		return pos
+	}
+
+	return g.elements[obj]
+}
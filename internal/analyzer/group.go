@@ -0,0 +1,105 @@
+package analyzer
+
+import (
+	"fmt"
+	"go/types"
+	"sort"
+	"strings"
+
+	"golang.org/x/tools/go/analysis"
+
+	"github.com/mickamy/pointless/internal/messages"
+	"github.com/mickamy/pointless/internal/severity"
+)
+
+// groupByType merges the diagnostics about each type into one.
+var groupByType bool
+
+// typeGroups collects the diagnostics of a package per type in -group-by-type mode.
+type typeGroups struct {
+	order []*typeGroup
+	byKey map[string]*typeGroup
+}
+
+// typeGroup holds the diagnostics about one type.
+type typeGroup struct {
+	name  string
+	diags []analysis.Diagnostic
+}
+
+func newTypeGroups() *typeGroups {
+	return &typeGroups{byKey: make(map[string]*typeGroup)}
+}
+
+func (g *typeGroups) add(pass *analysis.Pass, t types.Type, d analysis.Diagnostic) {
+	key := types.TypeString(t, nil)
+
+	group, ok := g.byKey[key]
+	if !ok {
+		group = &typeGroup{name: typeString(pass, t)}
+		g.byKey[key] = group
+		g.order = append(g.order, group)
+	}
+
+	group.diags = append(group.diags, d)
+}
+
+// flushGroups reports one diagnostic per type, at its first site, listing every
+// site as related information. Types flagged once are reported unchanged.
+// Suggested fixes are dropped from merged diagnostics.
+func (st *state) flushGroups(pass *analysis.Pass) {
+	if st.groups == nil {
+		return
+	}
+
+	for _, group := range st.groups.order {
+		diags := group.diags
+		if len(diags) == 1 {
+			reportRule(pass, st.severityOf(pass, diags[0].Category, diags[0].Pos), st.opts.Config.DocsBaseURL, diags[0])
+
+			continue
+		}
+
+		sort.SliceStable(diags, func(i, j int) bool { return diags[i].Pos < diags[j].Pos })
+
+		counts := make(map[string]int)
+		for _, d := range diags {
+			counts[d.Category]++
+		}
+
+		rulesList := make([]string, 0, len(counts))
+		for rule := range counts {
+			rulesList = append(rulesList, rule)
+		}
+
+		sort.Strings(rulesList)
+
+		parts := make([]string, 0, len(rulesList))
+		for _, rule := range rulesList {
+			parts = append(parts, fmt.Sprintf("%s %d", rule, counts[rule]))
+		}
+
+		merged := analysis.Diagnostic{
+			Pos:     diags[0].Pos,
+			End:     diags[0].End,
+			Message: st.msg.Sprintf(messages.GroupedType, group.name, len(diags), strings.Join(parts, ", ")),
+		}
+
+		// A single rule keeps its category and documentation link
+		if len(rulesList) == 1 {
+			merged.Category = rulesList[0]
+		}
+
+		for _, d := range diags {
+			merged.Related = append(merged.Related, analysis.RelatedInformation{Pos: d.Pos, End: d.End, Message: d.Message})
+		}
+
+		// Merged findings are as severe as the most severe of their rules
+		sevs := make([]string, 0, len(rulesList))
+		for _, rule := range rulesList {
+			sevs = append(sevs, st.severityOf(pass, rule, merged.Pos))
+		}
+
+		reportRule(pass, severity.MostSevere(sevs...), st.opts.Config.DocsBaseURL, merged)
+	}
+}
@@ -0,0 +1,258 @@
+package analyzer
+
+import (
+	"go/ast"
+	"go/token"
+	"go/types"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/ast/inspector"
+)
+
+// interfaceConversion is a site where a pointer value is converted, assigned,
+// passed, returned, or sent to a non-empty interface, or asserted back from
+// one, which tells that the pointer is held through the interface.
+type interfaceConversion struct {
+	from  types.Type
+	to    types.Type
+	iface *types.Interface
+	pos   token.Pos
+}
+
+// findInterfaceConversions finds the conversions of pointer values to
+// interfaces in the package (see interfaceConversion).
+func findInterfaceConversions(pass *analysis.Pass, inspect *inspector.Inspector) []interfaceConversion {
+	var result []interfaceConversion
+
+	add := func(to types.Type, from ast.Expr) {
+		result = appendConversion(result, to, pass.TypesInfo.TypeOf(from), from.Pos())
+	}
+
+	nodeFilter := []ast.Node{
+		(*ast.AssignStmt)(nil),
+		(*ast.ValueSpec)(nil),
+		(*ast.CallExpr)(nil),
+		(*ast.ReturnStmt)(nil),
+		(*ast.CompositeLit)(nil),
+		(*ast.SendStmt)(nil),
+		(*ast.TypeAssertExpr)(nil),
+		(*ast.TypeSwitchStmt)(nil),
+	}
+
+	for c := range inspect.Root().Preorder(nodeFilter...) {
+		switch node := c.Node().(type) {
+		case *ast.AssignStmt:
+			if node.Tok != token.ASSIGN || len(node.Lhs) != len(node.Rhs) {
+				continue
+			}
+
+			for i, lhs := range node.Lhs {
+				add(pass.TypesInfo.TypeOf(lhs), node.Rhs[i])
+			}
+		case *ast.ValueSpec:
+			if node.Type == nil {
+				continue
+			}
+
+			for _, value := range node.Values {
+				add(pass.TypesInfo.TypeOf(node.Type), value)
+			}
+		case *ast.CallExpr:
+			if tv, ok := pass.TypesInfo.Types[node.Fun]; ok && tv.IsType() {
+				if len(node.Args) == 1 {
+					add(tv.Type, node.Args[0])
+				}
+
+				continue
+			}
+
+			fun := pass.TypesInfo.TypeOf(node.Fun)
+			if fun == nil {
+				continue
+			}
+
+			sig, ok := fun.Underlying().(*types.Signature)
+			if !ok {
+				continue
+			}
+
+			for i, arg := range node.Args {
+				if param := paramTypeAt(sig, i, node.Ellipsis.IsValid()); param != nil {
+					add(param, arg)
+				}
+			}
+		case *ast.ReturnStmt:
+			sig := enclosingSignature(pass, c)
+			if sig == nil || sig.Results().Len() != len(node.Results) {
+				continue
+			}
+
+			for i, res := range node.Results {
+				add(sig.Results().At(i).Type(), res)
+			}
+		case *ast.CompositeLit:
+			addCompositeConversions(pass, node, add)
+		case *ast.SendStmt:
+			if t := pass.TypesInfo.TypeOf(node.Chan); t != nil {
+				if ch, ok := t.Underlying().(*types.Chan); ok {
+					add(ch.Elem(), node.Value)
+				}
+			}
+		case *ast.TypeAssertExpr:
+			if node.Type != nil {
+				result = appendConversion(result, pass.TypesInfo.TypeOf(node.X), pass.TypesInfo.TypeOf(node.Type), node.Pos())
+			}
+		case *ast.TypeSwitchStmt:
+			result = appendTypeSwitchConversions(result, pass, node)
+		}
+	}
+
+	return result
+}
+
+// appendConversion appends the conversion of from to to at pos to result if
+// from is a pointer and to is a non-empty interface.
+func appendConversion(result []interfaceConversion, to, from types.Type, pos token.Pos) []interfaceConversion {
+	if to == nil || from == nil {
+		return result
+	}
+
+	if _, ok := from.Underlying().(*types.Pointer); !ok {
+		return result
+	}
+
+	iface, ok := to.Underlying().(*types.Interface)
+	if !ok || iface.NumMethods() == 0 {
+		return result
+	}
+
+	return append(result, interfaceConversion{from: from, to: to, iface: iface, pos: pos})
+}
+
+// appendTypeSwitchConversions appends the pointer types of the cases of sw,
+// asserted from the interface it switches on, to result.
+func appendTypeSwitchConversions(result []interfaceConversion, pass *analysis.Pass, sw *ast.TypeSwitchStmt) []interfaceConversion {
+	var x ast.Expr
+
+	switch assign := sw.Assign.(type) {
+	case *ast.ExprStmt:
+		x = assign.X
+	case *ast.AssignStmt:
+		if len(assign.Rhs) == 1 {
+			x = assign.Rhs[0]
+		}
+	}
+
+	assert, ok := ast.Unparen(x).(*ast.TypeAssertExpr)
+	if !ok {
+		return result
+	}
+
+	iface := pass.TypesInfo.TypeOf(assert.X)
+
+	for _, stmt := range sw.Body.List {
+		clause, ok := stmt.(*ast.CaseClause)
+		if !ok {
+			continue
+		}
+
+		for _, e := range clause.List {
+			result = appendConversion(result, iface, pass.TypesInfo.TypeOf(e), e.Pos())
+		}
+	}
+
+	return result
+}
+
+// paramTypeAt returns the type of the parameter of sig receiving the i-th
+// argument, the element type for variadic arguments, or nil.
+func paramTypeAt(sig *types.Signature, i int, ellipsis bool) types.Type {
+	params := sig.Params()
+
+	if sig.Variadic() && i >= params.Len()-1 {
+		last := params.At(params.Len() - 1).Type()
+		if ellipsis {
+			return last
+		}
+
+		if s, ok := last.Underlying().(*types.Slice); ok {
+			return s.Elem()
+		}
+
+		return nil
+	}
+
+	if i >= params.Len() {
+		return nil
+	}
+
+	return params.At(i).Type()
+}
+
+// enclosingSignature returns the signature of the function or function
+// literal enclosing c, or nil.
+func enclosingSignature(pass *analysis.Pass, c inspector.Cursor) *types.Signature {
+	for fn := range c.Enclosing((*ast.FuncDecl)(nil), (*ast.FuncLit)(nil)) {
+		var t types.Type
+
+		switch fn := fn.Node().(type) {
+		case *ast.FuncDecl:
+			if obj := pass.TypesInfo.Defs[fn.Name]; obj != nil {
+				t = obj.Type()
+			}
+		case *ast.FuncLit:
+			t = pass.TypesInfo.TypeOf(fn)
+		}
+
+		sig, _ := t.(*types.Signature)
+
+		return sig
+	}
+
+	return nil
+}
+
+// addCompositeConversions calls add for the elements of lit stored in fields
+// or elements of interface type.
+func addCompositeConversions(pass *analysis.Pass, lit *ast.CompositeLit, add func(types.Type, ast.Expr)) {
+	t := pass.TypesInfo.TypeOf(lit)
+	if t == nil {
+		return
+	}
+
+	for i, elt := range lit.Elts {
+		kv, keyed := elt.(*ast.KeyValueExpr)
+
+		switch u := t.Underlying().(type) {
+		case *types.Struct:
+			switch {
+			case keyed:
+				if key, ok := kv.Key.(*ast.Ident); ok {
+					if field, ok := pass.TypesInfo.Uses[key].(*types.Var); ok {
+						add(field.Type(), kv.Value)
+					}
+				}
+			case i < u.NumFields():
+				add(u.Field(i).Type(), elt)
+			}
+		case *types.Slice:
+			add(u.Elem(), valueOf(elt))
+		case *types.Array:
+			add(u.Elem(), valueOf(elt))
+		case *types.Map:
+			if keyed {
+				add(u.Key(), kv.Key)
+				add(u.Elem(), kv.Value)
+			}
+		}
+	}
+}
+
+// valueOf returns the value of a keyed element, or the element itself.
+func valueOf(elt ast.Expr) ast.Expr {
+	if kv, ok := elt.(*ast.KeyValueExpr); ok {
+		return kv.Value
+	}
+
+	return elt
+}
@@ -0,0 +1,140 @@
+package analyzer
+
+import (
+	"go/types"
+	"strings"
+)
+
+// implementsMatcher reports whether a type implements one of the interfaces
+// listed in ignore-implements. Results are cached per type.
+type implementsMatcher struct {
+	ifaces []namedInterface
+	cache  map[types.Type]string
+}
+
+type namedInterface struct {
+	name  string
+	iface *types.Interface
+}
+
+// newImplementsMatcher resolves names ("error", "driver.Valuer", or
+// "database/sql/driver.Valuer") among the packages pkg imports, directly or
+// indirectly. A type cannot implement an interface mentioning a package it
+// does not depend on, so names that do not resolve are ignored.
+func newImplementsMatcher(pkg *types.Package, names []string) *implementsMatcher {
+	m := &implementsMatcher{cache: make(map[types.Type]string)}
+	if len(names) == 0 {
+		return m
+	}
+
+	deps := dependencies(pkg)
+
+	for _, name := range names {
+		name = strings.TrimSpace(name)
+
+		for _, iface := range lookupInterfaces(pkg, deps, name) {
+			m.ifaces = append(m.ifaces, namedInterface{name: name, iface: iface})
+		}
+	}
+
+	return m
+}
+
+// dependencies returns pkg and the packages it imports, directly or indirectly.
+func dependencies(pkg *types.Package) []*types.Package {
+	seen := map[*types.Package]bool{pkg: true}
+	deps := []*types.Package{pkg}
+
+	for i := 0; i < len(deps); i++ {
+		for _, imp := range deps[i].Imports() {
+			if !seen[imp] {
+				seen[imp] = true
+				deps = append(deps, imp)
+			}
+		}
+	}
+
+	return deps
+}
+
+// lookupInterfaces returns the interfaces called name: a predeclared one, a
+// local one, or qualified by the name or path of its package.
+func lookupInterfaces(pkg *types.Package, deps []*types.Package, name string) []*types.Interface {
+	qualifier, typeName, qualified := cutLast(name, ".")
+	if !qualified {
+		if iface := interfaceNamed(types.Universe, name); iface != nil {
+			return []*types.Interface{iface}
+		}
+
+		if iface := interfaceNamed(pkg.Scope(), name); iface != nil {
+			return []*types.Interface{iface}
+		}
+
+		return nil
+	}
+
+	var result []*types.Interface
+
+	for _, dep := range deps {
+		if dep.Path() != qualifier && dep.Name() != qualifier {
+			continue
+		}
+
+		if iface := interfaceNamed(dep.Scope(), typeName); iface != nil {
+			result = append(result, iface)
+		}
+	}
+
+	return result
+}
+
+// cutLast slices s around the last instance of sep.
+func cutLast(s, sep string) (before, after string, found bool) {
+	if i := strings.LastIndex(s, sep); i >= 0 {
+		return s[:i], s[i+len(sep):], true
+	}
+
+	return s, "", false
+}
+
+// interfaceNamed returns the non-generic interface type called name in scope, or nil.
+func interfaceNamed(scope *types.Scope, name string) *types.Interface {
+	obj, ok := scope.Lookup(name).(*types.TypeName)
+	if !ok {
+		return nil
+	}
+
+	if named, ok := types.Unalias(obj.Type()).(*types.Named); ok && named.TypeParams().Len() > 0 {
+		return nil
+	}
+
+	iface, _ := obj.Type().Underlying().(*types.Interface)
+
+	return iface
+}
+
+// matching returns the name of the first listed interface that t or *t
+// implements, or "".
+func (m *implementsMatcher) matching(t types.Type) string {
+	if len(m.ifaces) == 0 {
+		return ""
+	}
+
+	if name, ok := m.cache[t]; ok {
+		return name
+	}
+
+	name := ""
+
+	for _, ni := range m.ifaces {
+		if types.Implements(t, ni.iface) || types.Implements(types.NewPointer(t), ni.iface) {
+			name = ni.name
+
+			break
+		}
+	}
+
+	m.cache[t] = name
+
+	return name
+}
@@ -0,0 +1,250 @@
+package analyzer
+
+import (
+	"go/ast"
+	"go/token"
+	"go/types"
+	"sort"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/ast/inspector"
+)
+
+// interfacesFact is exported for every package and lists the names of the
+// package-level interfaces (with at least one method) it declares. Dependents
+// use it to find interfaces their types satisfy without referencing them.
+type interfacesFact struct {
+	Names []string
+}
+
+// AFact implements analysis.Fact.
+func (*interfacesFact) AFact() {}
+
+func (f *interfacesFact) String() string {
+	return "interfaces"
+}
+
+// exportInterfacesFact records the interfaces declared by the current package.
+func exportInterfacesFact(pass *analysis.Pass) {
+	var names []string
+
+	scope := pass.Pkg.Scope()
+	for _, name := range scope.Names() {
+		if _, ok := interfaceOf(scope.Lookup(name)); ok {
+			names = append(names, name)
+		}
+	}
+
+	if len(names) == 0 {
+		return
+	}
+
+	sort.Strings(names)
+	pass.ExportPackageFact(&interfacesFact{Names: names})
+}
+
+// declaredInterface is a package-level interface with its type name.
+type declaredInterface struct {
+	obj   *types.TypeName
+	iface *types.Interface
+}
+
+// collectInterfaces returns the interfaces declared by the current package and,
+// via facts, by every package in its dependency graph.
+func collectInterfaces(pass *analysis.Pass) []declaredInterface {
+	var result []declaredInterface
+
+	addFrom := func(pkg *types.Package, names []string) {
+		for _, name := range names {
+			if ni, ok := interfaceOf(pkg.Scope().Lookup(name)); ok {
+				result = append(result, ni)
+			}
+		}
+	}
+
+	addFrom(pass.Pkg, pass.Pkg.Scope().Names())
+
+	for _, pf := range pass.AllPackageFacts() {
+		fact, ok := pf.Fact.(*interfacesFact)
+		if !ok || pf.Package == pass.Pkg {
+			continue
+		}
+
+		addFrom(pf.Package, fact.Names)
+	}
+
+	return result
+}
+
+// interfaceOf returns the interface declared by obj, if obj is a type name for
+// a non-empty interface.
+func interfaceOf(obj types.Object) (declaredInterface, bool) {
+	tn, ok := obj.(*types.TypeName)
+	if !ok {
+		return declaredInterface{}, false
+	}
+
+	iface, ok := tn.Type().Underlying().(*types.Interface)
+	if !ok || iface.NumMethods() == 0 {
+		return declaredInterface{}, false
+	}
+
+	return declaredInterface{obj: tn, iface: iface}, true
+}
+
+// pointerInterfaceMethods lazily computes, per receiver type, the set of method
+// names belonging to interfaces that the type satisfies only through its pointer
+// method set, and that the package holds a pointer to the type through.
+// Changing one of these methods to a value receiver would leave the interface
+// implemented by a mix of receivers, and callers holding the interface value
+// keep a *T anyway.
+type pointerInterfaceMethods struct {
+	interfaces  []declaredInterface
+	calls       []interfaceCall
+	conversions []interfaceConversion
+	cache       map[types.Type]map[string]pointerInterfaceUse
+}
+
+// interfaceCall is a method call (or method value) through an anonymous
+// interface, such as x.(interface{ M() }).M(). Calls through named interfaces
+// tell nothing about T: every String method can be called through
+// fmt.Stringer, so those need a conversion (see interfaceConversion).
+type interfaceCall struct {
+	iface  *types.Interface
+	method string
+	pos    token.Pos
+}
+
+// pointerInterfaceUse is the evidence that a method is required through the
+// pointer type: a call through an interface whose dynamic type can only be
+// the pointer, or a conversion of the pointer to the interface iface.
+type pointerInterfaceUse struct {
+	pos    token.Pos
+	iface  types.Type
+	called bool
+}
+
+func newPointerInterfaceMethods(pass *analysis.Pass, inspect *inspector.Inspector, interfaces []declaredInterface) *pointerInterfaceMethods {
+	return &pointerInterfaceMethods{
+		interfaces:  interfaces,
+		calls:       findInterfaceCalls(pass, inspect),
+		conversions: findInterfaceConversions(pass, inspect),
+		cache:       make(map[types.Type]map[string]pointerInterfaceUse),
+	}
+}
+
+// findInterfaceCalls finds all method selections through anonymous interface
+// values.
+func findInterfaceCalls(pass *analysis.Pass, inspect *inspector.Inspector) []interfaceCall {
+	var result []interfaceCall
+
+	inspect.Preorder([]ast.Node{(*ast.SelectorExpr)(nil)}, func(n ast.Node) {
+		sel, ok := n.(*ast.SelectorExpr)
+		if !ok {
+			return
+		}
+
+		selection, ok := pass.TypesInfo.Selections[sel]
+		if !ok || selection.Kind() != types.MethodVal {
+			return
+		}
+
+		iface, ok := types.Unalias(selection.Recv()).(*types.Interface)
+		if !ok {
+			return
+		}
+
+		result = append(result, interfaceCall{iface: iface, method: sel.Sel.Name, pos: sel.Pos()})
+	})
+
+	return result
+}
+
+// lookup reports whether fn is required by an interface that its receiver type
+// only satisfies via the pointer method set, with the evidence that the
+// package relies on it. Implementing the interface is not enough, as every
+// Close or String method implements one: the package has to convert, assign,
+// pass, or assert the pointer to the interface, and the first such site is
+// returned. A call of the method through an anonymous interface the value type
+// does not implement is evidence too: the dynamic type at that call can only
+// be *T, so a value receiver would run on a copy of the value other (mutating)
+// methods update.
+func (p *pointerInterfaceMethods) lookup(fn *ast.FuncDecl, recv types.Type) (pointerInterfaceUse, bool) {
+	methods, ok := p.cache[recv]
+	if !ok {
+		methods = make(map[string]pointerInterfaceUse)
+		ptr := types.NewPointer(recv)
+
+		pointerOnly := func(iface *types.Interface) bool {
+			return !types.Implements(recv, iface) && types.Implements(ptr, iface)
+		}
+
+		for _, conv := range p.conversions {
+			if !samePointer(conv.from, recv) || !pointerOnly(conv.iface) {
+				continue
+			}
+
+			for i := range conv.iface.NumMethods() {
+				if _, ok := methods[conv.iface.Method(i).Name()]; !ok {
+					methods[conv.iface.Method(i).Name()] = pointerInterfaceUse{pos: conv.pos, iface: conv.to}
+				}
+			}
+		}
+
+		for _, call := range p.calls {
+			if _, ok := methods[call.method]; ok || !pointerOnly(call.iface) {
+				continue
+			}
+
+			methods[call.method] = pointerInterfaceUse{pos: call.pos, called: true}
+		}
+
+		p.cache[recv] = methods
+	}
+
+	use, ok := methods[fn.Name.Name]
+
+	return use, ok
+}
+
+// samePointer reports whether from is a pointer to recv, or to an
+// instantiation of it when recv is generic.
+func samePointer(from, recv types.Type) bool {
+	ptr, ok := types.Unalias(from).(*types.Pointer)
+	if !ok {
+		return false
+	}
+
+	elem := types.Unalias(ptr.Elem())
+
+	if named, ok := elem.(*types.Named); ok {
+		if r, ok := recv.(*types.Named); ok {
+			return named.Origin() == r.Origin()
+		}
+	}
+
+	return types.Identical(elem, recv)
+}
+
+// promotedThrough returns an interface that the value type outer satisfies
+// and that requires one of methods, promoted to outer from an embedded
+// pointer, along with that method.
+func (p *pointerInterfaceMethods) promotedThrough(outer types.Type, methods []*types.Func) (*types.TypeName, *types.Func, bool) {
+	if len(methods) == 0 {
+		return nil, nil, false
+	}
+
+	for _, ni := range p.interfaces {
+		if !types.Implements(outer, ni.iface) {
+			continue
+		}
+
+		for _, m := range methods {
+			if obj, _, _ := types.LookupFieldOrMethod(ni.iface, false, m.Pkg(), m.Name()); obj != nil {
+				return ni.obj, m, true
+			}
+		}
+	}
+
+	return nil, nil, false
+}
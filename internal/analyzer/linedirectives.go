@@ -0,0 +1,98 @@
+package analyzer
+
+import (
+	"go/token"
+	"log/slog"
+
+	"golang.org/x/tools/go/analysis"
+
+	"github.com/mickamy/pointless/internal/config"
+)
+
+// excludedFiles holds the files excluded by the config. Generated code often
+// maps its positions to the source it was generated from (a .y grammar, a
+// .tmpl template) with //line directives, so a position is excluded if either
+// the compiled file or the file its //line directive names is.
+type excludedFiles struct {
+	config config.Config
+	// compiled holds the excluded compiled files.
+	compiled map[string]bool
+	// remapped caches, per file named by a //line directive, whether the
+	// config excludes it.
+	remapped map[string]bool
+}
+
+// newExcludedFiles finds the files of the package excluded by the config c:
+// those matching an exclude pattern or none of the include patterns and,
+// unless include-vendor is set, third-party code.
+func newExcludedFiles(pass *analysis.Pass, c config.Config) *excludedFiles {
+	x := &excludedFiles{config: c, compiled: make(map[string]bool), remapped: make(map[string]bool)}
+
+	// Include patterns select the compiled files: the files //line directives
+	// name are generator inputs, which they are not written for
+	x.config.Include = nil
+
+	for _, f := range pass.Files {
+		filename := pass.Fset.File(f.Pos()).Name()
+		switch {
+		case c.ShouldExclude(filename):
+			slog.Debug("file excluded by config", "file", filename)
+		case !c.IncludeVendor && isThirdParty(filename):
+			slog.Debug("file excluded as third-party code", "file", filename)
+		default:
+			continue
+		}
+
+		x.compiled[filename] = true
+	}
+
+	return x
+}
+
+// contains reports whether pos is in an excluded file, either the compiled
+// file or the one a //line directive attributes pos to.
+func (x *excludedFiles) contains(pass *analysis.Pass, pos token.Pos) bool {
+	filename := pass.Fset.File(pos).Name()
+	if x.compiled[filename] {
+		return true
+	}
+
+	remapped, ok := remappedFilename(pass, pos)
+	if !ok {
+		return false
+	}
+
+	excluded, ok := x.remapped[remapped]
+	if !ok {
+		excluded = x.config.ShouldExclude(remapped)
+		if excluded {
+			slog.Debug("file excluded by config", "file", remapped, "via", filename)
+		}
+
+		x.remapped[remapped] = excluded
+	}
+
+	return excluded
+}
+
+// remappedFilename returns the file a //line directive attributes pos to, if
+// it differs from the compiled file.
+func remappedFilename(pass *analysis.Pass, pos token.Pos) (string, bool) {
+	tf := pass.Fset.File(pos)
+	if tf == nil {
+		return "", false
+	}
+
+	adjusted := tf.PositionFor(pos, true).Filename
+	if adjusted == "" || adjusted == tf.Name() {
+		return "", false
+	}
+
+	return adjusted, true
+}
+
+// compiledLine returns the line of pos in the compiled file tf, ignoring
+// //line directives, as token.File.LineStart expects.
+func compiledLine(tf *token.File, pos token.Pos) int {
+	return tf.PositionFor(pos, false).Line
+}
@@ -0,0 +1,251 @@
+package analyzer
+
+import (
+	"go/ast"
+	"go/token"
+	"go/types"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/ast/inspector"
+
+	"github.com/mickamy/pointless/internal/messages"
+	"github.com/mickamy/pointless/internal/rules"
+)
+
+// localPointerUses classifies the uses of function-scoped *T variables (T a struct).
+type localPointerUses struct {
+	// disqualified maps variables used in a way that relies on the pointer
+	// (nil checks, passing it on, taking its address) to the first such use.
+	disqualified map[types.Object]token.Pos
+	// initialized marks variables assigned &T{...} or new(T) at least once.
+	initialized map[types.Object]bool
+}
+
+// findLocalPointerUses scans every use of local pointer-to-struct variables.
+// Uses that keep working with a value variable are field selections (p.F, p.M()),
+// dereferences (*p), and assignments of a fresh &T{...} or new(T).
+func findLocalPointerUses(pass *analysis.Pass, inspect *inspector.Inspector) localPointerUses {
+	result := localPointerUses{
+		disqualified: make(map[types.Object]token.Pos),
+		initialized:  make(map[types.Object]bool),
+	}
+
+	inspect.WithStack([]ast.Node{(*ast.Ident)(nil)}, func(n ast.Node, push bool, stack []ast.Node) bool {
+		if !push {
+			return true
+		}
+
+		ident, ok := n.(*ast.Ident)
+		if !ok {
+			return true
+		}
+
+		obj, ok := pass.TypesInfo.Uses[ident].(*types.Var)
+		if !ok || !isLocalStructPointer(pass, obj) {
+			return true
+		}
+
+		switch allocated, ok := classifyLocalPointerUse(pass, stack); {
+		case !ok:
+			if !result.disqualified[obj].IsValid() {
+				result.disqualified[obj] = ident.Pos()
+			}
+		case allocated:
+			result.initialized[obj] = true
+		}
+
+		return true
+	})
+
+	return result
+}
+
+// isLocalStructPointer reports whether v is a function-scoped variable of type *T
+// where T is a struct.
+func isLocalStructPointer(pass *analysis.Pass, v *types.Var) bool {
+	if v.IsField() || v.Parent() == nil || v.Parent() == pass.Pkg.Scope() {
+		return false
+	}
+
+	ptr, ok := v.Type().(*types.Pointer)
+	if !ok {
+		return false
+	}
+
+	_, ok = ptr.Elem().Underlying().(*types.Struct)
+
+	return ok
+}
+
+// classifyLocalPointerUse classifies the identifier at the top of stack. It reports
+// ok=false when the use relies on pointer semantics, and allocated=true when the
+// use is the left-hand side of an assignment of a fresh allocation.
+func classifyLocalPointerUse(pass *analysis.Pass, stack []ast.Node) (allocated, ok bool) {
+	node := stack[len(stack)-1]
+
+	i := len(stack) - 2
+	for i >= 0 {
+		if _, isParen := stack[i].(*ast.ParenExpr); !isParen {
+			break
+		}
+
+		node = stack[i]
+		i--
+	}
+
+	if i < 0 {
+		return false, false
+	}
+
+	switch parent := stack[i].(type) {
+	case *ast.SelectorExpr:
+		return false, parent.X == node
+	case *ast.StarExpr:
+		return false, true
+	case *ast.AssignStmt:
+		for j, lhs := range parent.Lhs {
+			if lhs != node {
+				continue
+			}
+
+			if len(parent.Lhs) == len(parent.Rhs) && isFreshAllocation(pass, parent.Rhs[j]) {
+				return true, true
+			}
+		}
+	}
+
+	return false, false
+}
+
+// isFreshAllocation reports whether expr is &T{...} or new(T).
+func isFreshAllocation(pass *analysis.Pass, expr ast.Expr) bool {
+	switch e := ast.Unparen(expr).(type) {
+	case *ast.UnaryExpr:
+		if e.Op != token.AND {
+			return false
+		}
+
+		_, ok := ast.Unparen(e.X).(*ast.CompositeLit)
+
+		return ok
+	case *ast.CallExpr:
+		ident, ok := ast.Unparen(e.Fun).(*ast.Ident)
+		if !ok || len(e.Args) != 1 {
+			return false
+		}
+
+		_, isBuiltin := pass.TypesInfo.Uses[ident].(*types.Builtin)
+
+		return isBuiltin && ident.Name == "new"
+	}
+
+	return false
+}
+
+// checkLocalPointerVar checks a function-scoped `var p *T` declaration whose
+// pointer is only ever initialized with a fresh allocation and dereferenced.
+func checkLocalPointerVar(pass *analysis.Pass, vs *ast.ValueSpec, st *state) {
+	star, ok := ast.Unparen(vs.Type).(*ast.StarExpr)
+	if !ok || len(vs.Names) != 1 {
+		return
+	}
+
+	name := vs.Names[0]
+
+	obj, ok := pass.TypesInfo.Defs[name].(*types.Var)
+	if !ok || !isLocalStructPointer(pass, obj) {
+		return
+	}
+
+	initialized := st.localPointers.initialized[obj]
+
+	if len(vs.Values) > 0 {
+		if !isFreshAllocation(pass, vs.Values[0]) {
+			st.explain.skipped(pass, star.Pos(), "%s is initialized with an existing pointer", name.Name)
+
+			return
+		}
+
+		initialized = true
+	}
+
+	if !initialized {
+		st.explain.skipped(pass, star.Pos(), "%s is never assigned a new &T{...} value", name.Name)
+
+		return
+	}
+
+	if pos := st.localPointers.disqualified[obj]; pos.IsValid() {
+		st.explain.skipped(pass, star.Pos(), "%s relies on the pointer at line %d", name.Name, lineOf(pass, pos))
+
+		return
+	}
+
+	if pos := st.goroutines.values[obj]; pos.IsValid() {
+		st.explain.skipped(pass, star.Pos(), "%s is shared with a goroutine at line %d", name.Name, lineOf(pass, pos))
+
+		return
+	}
+
+	t, size, ok := smallStruct(pass, st, star.Pos(), star.X)
+	if !ok {
+		return
+	}
+
+	typeName := typeString(pass, t)
+	st.reportf(pass, t, rules.LocalPointer, star, messages.LocalPointer, name.Name, typeName, typeName, typeName, size, st.bounds())
+}
+
+// checkInitPointerVars checks the variables declared by the init statement of
+// an if, for, or switch statement, such as `if p := &T{...}; p.OK`. Their scope
+// ends with the statement, so like `var p *T` declarations they are reported
+// when the pointer is only initialized with fresh allocations and dereferenced.
+func checkInitPointerVars(pass *analysis.Pass, init ast.Stmt, st *state) {
+	assign, ok := init.(*ast.AssignStmt)
+	if !ok || assign.Tok != token.DEFINE || len(assign.Lhs) != len(assign.Rhs) {
+		return
+	}
+
+	for i, lhs := range assign.Lhs {
+		name, ok := lhs.(*ast.Ident)
+		if !ok {
+			continue
+		}
+
+		obj, ok := pass.TypesInfo.Defs[name].(*types.Var)
+		if !ok || !isLocalStructPointer(pass, obj) {
+			continue
+		}
+
+		if !isFreshAllocation(pass, assign.Rhs[i]) {
+			st.explain.skipped(pass, name.Pos(), "%s is initialized with an existing pointer", name.Name)
+
+			continue
+		}
+
+		if pos := st.localPointers.disqualified[obj]; pos.IsValid() {
+			st.explain.skipped(pass, name.Pos(), "%s relies on the pointer at line %d", name.Name, lineOf(pass, pos))
+
+			continue
+		}
+
+		if pos := st.goroutines.values[obj]; pos.IsValid() {
+			st.explain.skipped(pass, name.Pos(), "%s is shared with a goroutine at line %d", name.Name, lineOf(pass, pos))
+
+			continue
+		}
+
+		ptr, ok := obj.Type().(*types.Pointer)
+		if !ok {
+			continue
+		}
+
+		t, size, ok := smallStructType(pass, st, name.Pos(), ptr.Elem())
+		if !ok {
+			continue
+		}
+
+		typeName := typeString(pass, t)
+		st.reportf(pass, t, rules.LocalPointer, name, messages.LocalPointerDefine, name.Name, typeName, typeName, size, st.bounds())
+	}
+}
@@ -0,0 +1,298 @@
+package analyzer
+
+import (
+	"go/ast"
+	"go/token"
+	"go/types"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/ast/inspector"
+
+	"github.com/mickamy/pointless/internal/messages"
+	"github.com/mickamy/pointless/internal/rules"
+)
+
+// mapKeySites holds the map[*T]V type expressions where the type of a map is
+// decided: struct fields, parameters, results, declared variables and types,
+// and make(map[*T]V) calls or literals stored in new variables. Other
+// occurrences, like a make call assigned to a declared field, repeat a type
+// checked elsewhere.
+type mapKeySites map[*ast.MapType]bool
+
+// findMapKeySites finds the pointer-keyed map types of the package that decide
+// the type of a map.
+func findMapKeySites(pass *analysis.Pass, inspect *inspector.Inspector) mapKeySites {
+	result := make(mapKeySites)
+
+	inspect.WithStack([]ast.Node{(*ast.MapType)(nil)}, func(n ast.Node, push bool, stack []ast.Node) bool {
+		if !push {
+			return true
+		}
+
+		mt, ok := n.(*ast.MapType)
+		if !ok {
+			return true
+		}
+
+		if _, isPtr := ast.Unparen(mt.Key).(*ast.StarExpr); isPtr && decidesMapType(pass, stack) {
+			result[mt] = true
+		}
+
+		return true
+	})
+
+	return result
+}
+
+// decidesMapType reports whether the map type at the top of stack, possibly
+// nested in other composite types, is where the type of a map is decided (see
+// mapKeySites).
+func decidesMapType(pass *analysis.Pass, stack []ast.Node) bool {
+	node := stack[len(stack)-1]
+
+	for i := len(stack) - 2; i >= 0; i-- {
+		switch parent := stack[i].(type) {
+		case *ast.ParenExpr, *ast.ArrayType, *ast.MapType, *ast.StarExpr, *ast.ChanType, *ast.Ellipsis:
+		case *ast.Field, *ast.TypeSpec:
+			return true
+		case *ast.ValueSpec:
+			return parent.Type == node
+		case *ast.CompositeLit:
+			return parent.Type == node && newVariable(pass, stack[:i+1])
+		case *ast.CallExpr:
+			return len(parent.Args) > 0 && parent.Args[0] == node && newVariable(pass, stack[:i+1])
+		default:
+			return false
+		}
+
+		node = stack[i]
+	}
+
+	return false
+}
+
+// newVariable reports whether the expression at the top of stack initializes a
+// variable declared without a type.
+func newVariable(pass *analysis.Pass, stack []ast.Node) bool {
+	v, ok := sliceDestination(pass, stack).(*types.Var)
+
+	return ok && !v.IsField()
+}
+
+// pointerWrites maps the package's named types to the first write to a value
+// of the type through a pointer: a field assignment (p.X = 1), an increment, a
+// dereference assignment (*p = T{}), taking the address of a field, or calling
+// a pointer method on a field.
+type pointerWrites map[*types.TypeName]token.Pos
+
+// findPointerWrites finds the types of the package whose values are written
+// through pointers.
+func findPointerWrites(pass *analysis.Pass, inspect *inspector.Inspector) pointerWrites {
+	result := make(pointerWrites)
+
+	forEachPointerWrite(pass, inspect, func(e ast.Expr, pos token.Pos) {
+		if obj := writtenType(pass, e); obj != nil && obj.Pkg() == pass.Pkg && !result[obj].IsValid() {
+			result[obj] = pos
+		}
+	})
+
+	return result
+}
+
+// forEachPointerWrite calls record, in source order, with the expressions the
+// package writes to or takes the address of, and the position of the write:
+// left-hand sides of assignments, increments, operands of &, and operands of
+// pointer method calls on addressable values.
+func forEachPointerWrite(pass *analysis.Pass, inspect *inspector.Inspector, record func(e ast.Expr, pos token.Pos)) {
+	nodeFilter := []ast.Node{
+		(*ast.AssignStmt)(nil),
+		(*ast.IncDecStmt)(nil),
+		(*ast.UnaryExpr)(nil),
+		(*ast.CallExpr)(nil),
+	}
+
+	inspect.Preorder(nodeFilter, func(n ast.Node) {
+		switch node := n.(type) {
+		case *ast.AssignStmt:
+			if node.Tok == token.DEFINE {
+				return
+			}
+
+			for _, lhs := range node.Lhs {
+				record(lhs, node.Pos())
+			}
+		case *ast.IncDecStmt:
+			record(node.X, node.Pos())
+		case *ast.UnaryExpr:
+			if node.Op == token.AND {
+				record(node.X, node.Pos())
+			}
+		case *ast.CallExpr:
+			// p.mu.Lock() takes the address of the field
+			sel, ok := ast.Unparen(node.Fun).(*ast.SelectorExpr)
+			if !ok {
+				return
+			}
+
+			selection, ok := pass.TypesInfo.Selections[sel]
+			if !ok || selection.Kind() != types.MethodVal {
+				return
+			}
+
+			sig, ok := selection.Obj().Type().(*types.Signature)
+			if !ok || sig.Recv() == nil {
+				return
+			}
+
+			if _, isPtr := sig.Recv().Type().(*types.Pointer); !isPtr {
+				return
+			}
+
+			if _, isPtr := pass.TypesInfo.TypeOf(sel.X).(*types.Pointer); !isPtr {
+				record(sel.X, node.Pos())
+			}
+		}
+	})
+}
+
+// writtenType returns the named type whose value a write to e modifies through
+// a pointer: T for p.X, p.A.B, p.Arr[i], or *p with p of type *T. It returns
+// nil for writes to variables and to the elements of slices and maps.
+func writtenType(pass *analysis.Pass, e ast.Expr) *types.TypeName {
+	p := writtenPointer(pass, e)
+	if p == nil {
+		return nil
+	}
+
+	ptr, ok := pass.TypesInfo.TypeOf(p).Underlying().(*types.Pointer)
+	if !ok {
+		return nil
+	}
+
+	return namedObject(ptr.Elem())
+}
+
+// writtenPointer returns the pointer expression a write to e goes through: p
+// for p.X, p.A.B, p.Arr[i], or *p. It returns nil for writes to variables and
+// to the elements of slices and maps.
+func writtenPointer(pass *analysis.Pass, e ast.Expr) ast.Expr {
+	for {
+		switch x := ast.Unparen(e).(type) {
+		case *ast.SelectorExpr:
+			sel, ok := pass.TypesInfo.Selections[x]
+			if !ok || sel.Kind() != types.FieldVal {
+				return nil
+			}
+
+			if _, ok := pass.TypesInfo.TypeOf(x.X).Underlying().(*types.Pointer); ok {
+				return x.X
+			}
+
+			e = x.X
+		case *ast.IndexExpr:
+			if _, isArray := pass.TypesInfo.TypeOf(x.X).Underlying().(*types.Array); !isArray {
+				return nil
+			}
+
+			e = x.X
+		case *ast.StarExpr:
+			if _, ok := pass.TypesInfo.TypeOf(x.X).Underlying().(*types.Pointer); ok {
+				return x.X
+			}
+
+			return nil
+		default:
+			return nil
+		}
+	}
+}
+
+// holdsReferences reports whether comparing values of the comparable type t
+// compares pointers, interfaces, or channels, directly or in nested structs and
+// arrays.
+func holdsReferences(t types.Type) bool {
+	switch u := t.Underlying().(type) {
+	case *types.Pointer, *types.Interface, *types.Chan:
+		return true
+	case *types.Basic:
+		return u.Kind() == types.UnsafePointer
+	case *types.Array:
+		return holdsReferences(u.Elem())
+	case *types.Struct:
+		for i := range u.NumFields() {
+			if holdsReferences(u.Field(i).Type()) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// namedObject returns the type name of a named type, or nil.
+func namedObject(t types.Type) *types.TypeName {
+	named, ok := types.Unalias(t).(*types.Named)
+	if !ok {
+		return nil
+	}
+
+	return named.Origin().Obj()
+}
+
+// checkMapKey checks a map[*T]V type that decides the type of a map (see
+// mapKeySites) whose key struct is small, comparable, and never written
+// through a pointer, so the pointer keys most likely compare by identity by
+// accident.
+func checkMapKey(pass *analysis.Pass, mt *ast.MapType, st *state) {
+	if !st.mapKeys[mt] {
+		return
+	}
+
+	star, ok := ast.Unparen(mt.Key).(*ast.StarExpr)
+	if !ok {
+		return
+	}
+
+	tv, ok := pass.TypesInfo.Types[star.X]
+	if !ok {
+		return
+	}
+
+	obj := namedObject(tv.Type)
+
+	// Skip if writes through pointers elsewhere cannot be ruled out
+	if obj == nil || obj.Pkg() != pass.Pkg {
+		st.explain.skipped(pass, star.Pos(), "%s is not a named type of this package", typeString(pass, tv.Type))
+
+		return
+	}
+
+	// Skip if the value type cannot be a key
+	if !types.Comparable(tv.Type) {
+		st.explain.skipped(pass, star.Pos(), "%s is not comparable", typeString(pass, tv.Type))
+
+		return
+	}
+
+	// Skip if comparing values would still compare pointers
+	if holdsReferences(tv.Type) {
+		st.explain.skipped(pass, star.Pos(), "%s holds pointers, interfaces, or channels, which compare by identity too", typeString(pass, tv.Type))
+
+		return
+	}
+
+	// Skip if values change behind the pointers, which then serve as identities
+	if pos := st.pointerWrites[obj]; pos.IsValid() {
+		st.explain.skipped(pass, star.Pos(), "%s values are written through a pointer at line %d", obj.Name(), lineOf(pass, pos))
+
+		return
+	}
+
+	t, size, ok := smallStructType(pass, st, star.Pos(), tv.Type)
+	if !ok {
+		return
+	}
+
+	typeName := typeString(pass, t)
+	st.reportf(pass, t, rules.MapPointerKey, star, messages.MapPointerKey, typeName, typeName, size, st.bounds())
+}
@@ -0,0 +1,99 @@
+package analyzer
+
+import (
+	"maps"
+	"reflect"
+	"time"
+)
+
+// Phases of a pass, as reported in Metrics.
+const (
+	// PhaseNilScan finds nil returns, comparisons, and assignments.
+	PhaseNilScan = "nil-scan"
+	// PhaseMutationScan finds writes through receivers and parameters, and
+	// where their identity is stored.
+	PhaseMutationScan = "mutation-scan"
+	// PhaseUsageScan finds the other uses the checks depend on: interfaces,
+	// goroutines, unsafe conversions, call sites, and nolint comments.
+	PhaseUsageScan = "usage-scan"
+	// PhaseChecks runs the checks and reports their findings.
+	PhaseChecks = "checks"
+)
+
+// Phases returns the phases of a pass, in the order they are reported.
+func Phases() []string {
+	return []string{PhaseNilScan, PhaseMutationScan, PhaseUsageScan, PhaseChecks}
+}
+
+// Metrics describes the work done by the analyzer. It is the result of a
+// pass; merge the results of several passes with Add.
+type Metrics struct {
+	// Packages counts the packages checked, as opposed to only scanned for
+	// facts (see Config.IncludeVendor).
+	Packages int
+	// Phases maps phases (see PhaseNilScan and the other Phase constants) to
+	// the time spent in them.
+	Phases map[string]time.Duration
+	// PackagePhases maps the paths of the packages checked to the time spent
+	// in each phase for them.
+	PackagePhases map[string]map[string]time.Duration
+	// Suppressed counts the findings suppressed by nolint comments.
+	Suppressed int
+	// Filtered counts the findings dropped by include-symbols,
+	// ignore-symbols-regex, exclude-rules, or tests.rules.
+	Filtered int
+}
+
+// metricsType is the analyzer's ResultType.
+var metricsType = reflect.TypeFor[*Metrics]()
+
+// Add adds the metrics of another pass to m.
+func (m *Metrics) Add(other *Metrics) {
+	if other == nil {
+		return
+	}
+
+	if m.Phases == nil {
+		m.Phases = make(map[string]time.Duration, len(other.Phases))
+	}
+
+	for phase, d := range other.Phases {
+		m.Phases[phase] += d
+	}
+
+	if len(other.PackagePhases) > 0 && m.PackagePhases == nil {
+		m.PackagePhases = make(map[string]map[string]time.Duration, len(other.PackagePhases))
+	}
+
+	for path, phases := range other.PackagePhases {
+		m.PackagePhases[path] = maps.Clone(phases)
+	}
+
+	m.Packages += other.Packages
+	m.Suppressed += other.Suppressed
+	m.Filtered += other.Filtered
+}
+
+// time adds the time since start to phase and restarts the clock, returning
+// the new start.
+func (m *Metrics) time(phase string, start time.Time) time.Time {
+	now := time.Now()
+
+	if m.Phases == nil {
+		m.Phases = make(map[string]time.Duration)
+	}
+
+	m.Phases[phase] += now.Sub(start)
+
+	return now
+}
+
+// Total returns the time spent in all phases of phases.
+func Total(phases map[string]time.Duration) time.Duration {
+	var total time.Duration
+	for _, d := range phases {
+		total += d
+	}
+
+	return total
+}
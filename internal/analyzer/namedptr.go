@@ -0,0 +1,111 @@
+package analyzer
+
+import (
+	"fmt"
+	"go/ast"
+	"go/types"
+
+	"golang.org/x/tools/go/analysis"
+
+	"github.com/mickamy/pointless/internal/messages"
+	"github.com/mickamy/pointless/internal/rules"
+)
+
+// pointerElem returns the element type of a pointer type expression: *T, an
+// alias (type Ref = *T), or a defined pointer type (type Handle *T). named is
+// the alias or defined type, or nil for *T.
+func pointerElem(pass *analysis.Pass, expr ast.Expr) (elem, named types.Type, ok bool) {
+	if star, ok := ast.Unparen(expr).(*ast.StarExpr); ok {
+		tv, ok := pass.TypesInfo.Types[star.X]
+		if !ok {
+			return nil, nil, false
+		}
+
+		return tv.Type, nil, true
+	}
+
+	return namedPointer(pass, expr)
+}
+
+// namedPointer returns the element type of expr if it names a pointer type
+// without a * (an alias or defined pointer type), along with that type.
+func namedPointer(pass *analysis.Pass, expr ast.Expr) (elem, named types.Type, ok bool) {
+	switch ast.Unparen(expr).(type) {
+	case *ast.Ident, *ast.SelectorExpr:
+	default:
+		return nil, nil, false
+	}
+
+	tv, ok := pass.TypesInfo.Types[expr]
+	if !ok || !tv.IsType() {
+		return nil, nil, false
+	}
+
+	switch t := tv.Type.(type) {
+	case *types.Alias, *types.Named:
+		ptr, ok := t.Underlying().(*types.Pointer)
+		if !ok {
+			return nil, nil, false
+		}
+
+		return ptr.Elem(), t, true
+	}
+
+	return nil, nil, false
+}
+
+// checkNamedPointerReturn checks a result whose type is an alias or defined
+// pointer type, like checkPointerReturn does for *T.
+func checkNamedPointerReturn(pass *analysis.Pass, fn *ast.FuncDecl, expr ast.Expr, st *state) {
+	elem, named, ok := namedPointer(pass, expr)
+	if !ok {
+		return
+	}
+
+	// Skip if function returns nil
+	if pos := st.nilReturns[fn]; pos.IsValid() {
+		st.explain.skipped(pass, expr.Pos(), "function returns nil at line %d", lineOf(pass, pos))
+
+		return
+	}
+
+	// Skip builder methods: the value-builder rule covers them
+	if pos, ok := st.builders[fn]; ok {
+		st.explain.skipped(pass, expr.Pos(), "method returns its receiver at line %d for chaining (builder pattern)", lineOf(pass, pos))
+
+		return
+	}
+
+	// Skip if other implementations of the interface method return nil
+	if st.skipNilImplementation(pass, fn, expr.Pos()) {
+		return
+	}
+
+	// Skip if callers write through the result, which may be shared
+	if pos := st.resultWrites.first(pass, fn); pos.IsValid() {
+		st.explain.skipped(pass, expr.Pos(), "a caller writes through the result at line %d, which may rely on sharing it", lineOf(pass, pos))
+
+		return
+	}
+
+	t, size, ok := smallStructType(pass, st, expr.Pos(), elem)
+	if !ok {
+		return
+	}
+
+	typeName := typeString(pass, t)
+	st.reportf(pass, t, rules.ReturnPointer, expr, messages.NamedPointerReturn, typeString(pass, named), typeName, typeName, size, st.bounds())
+}
+
+// reportSlicePointer reports a []*T slice type, naming the alias or defined
+// pointer type used for the elements, if any.
+func reportSlicePointer(pass *analysis.Pass, st *state, arr *ast.ArrayType, t, named types.Type, size int64, format func(*analysis.Pass, types.Type) string) {
+	typeName := format(pass, t)
+
+	elemName := "*" + typeName
+	if named != nil {
+		elemName = fmt.Sprintf("%s (*%s)", format(pass, named), typeName)
+	}
+
+	st.reportf(pass, t, rules.SlicePointer, arr, messages.SlicePointer, typeName, elemName, size, st.bounds())
+}
@@ -0,0 +1,208 @@
+package analyzer
+
+import (
+	"go/ast"
+	"go/token"
+	"go/types"
+	"sort"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/ast/inspector"
+)
+
+// nilResultsFact is exported for methods with a pointer result that return nil
+// on some path and implement a method of an interface. Other implementations of
+// the interface method, in this package or importing ones, use it to tell that
+// the pointer result can encode "absent" for callers holding the interface.
+type nilResultsFact struct{}
+
+// AFact implements analysis.Fact.
+func (*nilResultsFact) AFact() {}
+
+func (*nilResultsFact) String() string {
+	return "returnsNil"
+}
+
+// nilImplementation is a method implementing an interface method that returns
+// nil.
+type nilImplementation struct {
+	fn *types.Func
+	// pos is the first nil return, or token.NoPos for methods of dependencies.
+	pos token.Pos
+}
+
+// nilImplementations finds, for pointer-returning methods, other
+// implementations of the same interface method that return nil.
+type nilImplementations struct {
+	interfaces []declaredInterface
+	// methods holds the methods returning nil of this package, in source
+	// order, followed by those of dependencies (via facts).
+	methods []nilImplementation
+}
+
+// findNilImplementations collects the methods returning nil that implement an
+// interface method, and exports a nilResultsFact for those of this package.
+func findNilImplementations(pass *analysis.Pass, inspect *inspector.Inspector, interfaces []declaredInterface) *nilImplementations {
+	impls := &nilImplementations{interfaces: interfaces}
+
+	nilReturns := findNilReturns(inspect)
+
+	inspect.Preorder([]ast.Node{(*ast.FuncDecl)(nil)}, func(n ast.Node) {
+		decl, ok := n.(*ast.FuncDecl)
+		if !ok || decl.Recv == nil || !nilReturns[decl].IsValid() {
+			return
+		}
+
+		fn, ok := pass.TypesInfo.Defs[decl.Name].(*types.Func)
+		if !ok || !hasPointerResult(fn) || len(impls.implemented(fn)) == 0 {
+			return
+		}
+
+		pass.ExportObjectFact(fn, &nilResultsFact{})
+		impls.methods = append(impls.methods, nilImplementation{fn: fn, pos: nilReturns[decl]})
+	})
+
+	var imported []nilImplementation
+
+	for _, of := range pass.AllObjectFacts() {
+		fn, ok := of.Object.(*types.Func)
+		if _, isNil := of.Fact.(*nilResultsFact); !isNil || !ok || fn.Pkg() == pass.Pkg {
+			continue
+		}
+
+		imported = append(imported, nilImplementation{fn: fn})
+	}
+
+	// Facts come in no particular order
+	sort.Slice(imported, func(i, j int) bool {
+		a, b := imported[i].fn, imported[j].fn
+		if a.Pkg().Path() != b.Pkg().Path() {
+			return a.Pkg().Path() < b.Pkg().Path()
+		}
+
+		return a.FullName() < b.FullName()
+	})
+
+	impls.methods = append(impls.methods, imported...)
+
+	return impls
+}
+
+// implemented returns the interfaces with a method named like fn that the
+// receiver type of fn implements, with either method set.
+func (n *nilImplementations) implemented(fn *types.Func) []declaredInterface {
+	sig, ok := fn.Type().(*types.Signature)
+	if !ok || sig.Recv() == nil {
+		return nil
+	}
+
+	recv := sig.Recv().Type()
+	if ptr, ok := recv.(*types.Pointer); ok {
+		recv = ptr.Elem()
+	}
+
+	var result []declaredInterface
+
+	for _, ni := range n.interfaces {
+		if obj, _, _ := types.LookupFieldOrMethod(ni.iface, false, nil, fn.Name()); obj == nil {
+			continue
+		}
+
+		if types.Implements(recv, ni.iface) || types.Implements(types.NewPointer(recv), ni.iface) {
+			result = append(result, ni)
+		}
+	}
+
+	return result
+}
+
+// lookup returns an interface implemented by the method fn and another
+// implementation of the interface method that returns nil, if any.
+func (n *nilImplementations) lookup(fn *types.Func) (declaredInterface, nilImplementation, bool) {
+	if len(n.methods) == 0 {
+		return declaredInterface{}, nilImplementation{}, false
+	}
+
+	for _, ni := range n.implemented(fn) {
+		for _, m := range n.methods {
+			if m.fn == fn || m.fn.Name() != fn.Name() {
+				continue
+			}
+
+			for _, other := range n.implemented(m.fn) {
+				if other.obj == ni.obj {
+					return ni, m, true
+				}
+			}
+		}
+	}
+
+	return declaredInterface{}, nilImplementation{}, false
+}
+
+// skipNilImplementation reports whether the method decl implements an interface
+// method that another implementation returns nil from. Callers holding the
+// interface already handle nil, so the pointer result of fn is part of the
+// interface's contract rather than a choice of fn.
+func (st *state) skipNilImplementation(pass *analysis.Pass, decl *ast.FuncDecl, pos token.Pos) bool {
+	if decl.Recv == nil || st.nilImpls == nil {
+		return false
+	}
+
+	fn, ok := pass.TypesInfo.Defs[decl.Name].(*types.Func)
+	if !ok {
+		return false
+	}
+
+	ni, m, ok := st.nilImpls.lookup(fn)
+	if !ok {
+		return false
+	}
+
+	ifaceName := ni.obj.Name()
+	if ni.obj.Pkg() != pass.Pkg {
+		ifaceName = ni.obj.Pkg().Name() + "." + ifaceName
+	}
+
+	implName := methodString(pass, m.fn)
+
+	if m.pos.IsValid() {
+		st.explain.skipped(pass, pos, "method implements %s, and %s returns nil at line %d", ifaceName, implName, lineOf(pass, m.pos))
+	} else {
+		st.explain.skipped(pass, pos, "method implements %s, and %s returns nil", ifaceName, implName)
+	}
+
+	return true
+}
+
+// methodString formats a method as (*T).M or T.M, qualifying types of other
+// packages.
+func methodString(pass *analysis.Pass, fn *types.Func) string {
+	sig, ok := fn.Type().(*types.Signature)
+	if !ok || sig.Recv() == nil {
+		return fn.Name()
+	}
+
+	recv := types.TypeString(sig.Recv().Type(), types.RelativeTo(pass.Pkg))
+	if isPointer(sig.Recv().Type()) {
+		recv = "(" + recv + ")"
+	}
+
+	return recv + "." + fn.Name()
+}
+
+// hasPointerResult reports whether the function has a result of pointer type.
+func hasPointerResult(fn *types.Func) bool {
+	sig, ok := fn.Type().(*types.Signature)
+	if !ok {
+		return false
+	}
+
+	for i := range sig.Results().Len() {
+		if isPointer(sig.Results().At(i).Type()) {
+			return true
+		}
+	}
+
+	return false
+}
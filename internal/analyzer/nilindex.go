@@ -0,0 +1,256 @@
+package analyzer
+
+import (
+	"go/ast"
+	"go/token"
+	"go/types"
+	"maps"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/ast/inspector"
+)
+
+// nilIndex records, per variable or struct field, where the package relies on
+// nil. It is keyed by types.Object, so fields (s.item = nil, cfg.cache == nil)
+// are tracked like variables, and uses in any file of the package are merged.
+type nilIndex struct {
+	// values maps objects compared with or assigned nil to the first such use.
+	values map[types.Object]token.Pos
+	// elements maps slices, arrays, and maps whose elements are compared with
+	// or assigned nil (items[i] == nil, m[k] = nil, or a nil check of a range
+	// value) to the first such use.
+	elements map[types.Object]token.Pos
+}
+
+// buildNilIndex scans the package for nil comparisons and assignments.
+func buildNilIndex(pass *analysis.Pass, inspect *inspector.Inspector) nilIndex {
+	ix := newNilIndex()
+
+	// rangeValues maps range value variables to the container they iterate over
+	rangeValues := make(map[types.Object]types.Object)
+
+	// calls holds arguments passed to parameters of functions in this package
+	var calls []argumentEdge
+
+	// Files are merged in order, so the first use is that of a serial scan
+	for _, f := range scanFiles(inspect, func(file inspector.Cursor) nilUses {
+		return fileNilUses(pass, file)
+	}) {
+		for obj, pos := range f.ix.values {
+			if _, ok := ix.values[obj]; !ok {
+				ix.values[obj] = pos
+			}
+		}
+
+		for obj, pos := range f.ix.elements {
+			ix.markElement(obj, pos)
+		}
+
+		maps.Copy(rangeValues, f.rangeValues)
+		calls = append(calls, f.calls...)
+	}
+
+	// A nil check of a range value is a nil check of the container's elements
+	for value, container := range rangeValues {
+		if pos, ok := ix.values[value]; ok {
+			ix.markElement(container, pos)
+		}
+	}
+
+	// A helper setting its parameter's elements to nil does so for the caller's
+	// argument too; iterate to follow chains of helpers.
+	for changed := true; changed; {
+		changed = false
+
+		for _, call := range calls {
+			if _, ok := ix.elements[call.arg]; ok {
+				continue
+			}
+
+			if _, ok := ix.elements[call.param]; ok {
+				ix.elements[call.arg] = call.pos
+				changed = true
+			}
+		}
+	}
+
+	return ix
+}
+
+// newNilIndex returns an empty nilIndex.
+func newNilIndex() nilIndex {
+	return nilIndex{
+		values:   make(map[types.Object]token.Pos),
+		elements: make(map[types.Object]token.Pos),
+	}
+}
+
+// nilUses holds the nil uses found in a file, before the range values and
+// calls of the package are followed.
+type nilUses struct {
+	ix          nilIndex
+	rangeValues map[types.Object]types.Object
+	calls       []argumentEdge
+}
+
+// fileNilUses scans a file for nil comparisons and assignments.
+func fileNilUses(pass *analysis.Pass, file inspector.Cursor) nilUses {
+	f := nilUses{ix: newNilIndex(), rangeValues: make(map[types.Object]types.Object)}
+
+	nodeFilter := []ast.Node{
+		(*ast.BinaryExpr)(nil),
+		(*ast.AssignStmt)(nil),
+		(*ast.RangeStmt)(nil),
+		(*ast.CallExpr)(nil),
+	}
+
+	for c := range file.Preorder(nodeFilter...) {
+		switch node := c.Node().(type) {
+		case *ast.BinaryExpr:
+			if node.Op != token.EQL && node.Op != token.NEQ {
+				continue
+			}
+
+			if isNil(node.Y) {
+				f.ix.record(pass, node.X)
+			} else if isNil(node.X) {
+				f.ix.record(pass, node.Y)
+			}
+		case *ast.AssignStmt:
+			if len(node.Lhs) != len(node.Rhs) {
+				continue
+			}
+
+			for i, rhs := range node.Rhs {
+				if isNil(rhs) {
+					f.ix.record(pass, node.Lhs[i])
+				}
+			}
+		case *ast.RangeStmt:
+			value, ok := node.Value.(*ast.Ident)
+			if !ok {
+				continue
+			}
+
+			if obj, container := pass.TypesInfo.Defs[value], objectOf(pass, node.X); obj != nil && container != nil {
+				f.rangeValues[obj] = container
+			}
+		case *ast.CallExpr:
+			f.calls = append(f.calls, argumentEdges(pass, node)...)
+		}
+	}
+
+	return f
+}
+
+// argumentEdge is a variable or field passed to a parameter of a function
+// declared in the current package.
+type argumentEdge struct {
+	arg   types.Object
+	param types.Object
+	pos   token.Pos
+}
+
+// argumentEdges returns the edges of a call to a function or method of the
+// current package.
+func argumentEdges(pass *analysis.Pass, call *ast.CallExpr) []argumentEdge {
+	var fn *types.Func
+
+	switch f := ast.Unparen(call.Fun).(type) {
+	case *ast.Ident:
+		fn, _ = pass.TypesInfo.Uses[f].(*types.Func)
+	case *ast.SelectorExpr:
+		fn, _ = pass.TypesInfo.Uses[f.Sel].(*types.Func)
+	case *ast.IndexExpr: // explicit instantiation
+		if ident, ok := ast.Unparen(f.X).(*ast.Ident); ok {
+			fn, _ = pass.TypesInfo.Uses[ident].(*types.Func)
+		}
+	}
+
+	if fn == nil || fn.Pkg() != pass.Pkg {
+		return nil
+	}
+
+	fn = fn.Origin()
+
+	sig, ok := fn.Type().(*types.Signature)
+	if !ok {
+		return nil
+	}
+
+	params := sig.Params()
+
+	var edges []argumentEdge
+
+	for i, arg := range call.Args {
+		if i >= params.Len() {
+			break
+		}
+
+		// Only x... can alias the variadic parameter's backing array
+		if sig.Variadic() && i == params.Len()-1 && !call.Ellipsis.IsValid() {
+			break
+		}
+
+		if obj := objectOf(pass, arg); obj != nil {
+			edges = append(edges, argumentEdge{arg: obj, param: params.At(i), pos: call.Pos()})
+		}
+	}
+
+	return edges
+}
+
+// record records a nil comparison or assignment of expr.
+func (ix nilIndex) record(pass *analysis.Pass, expr ast.Expr) {
+	expr = ast.Unparen(expr)
+
+	if idx, ok := expr.(*ast.IndexExpr); ok {
+		if obj := objectOf(pass, idx.X); obj != nil {
+			ix.markElement(obj, expr.Pos())
+		}
+
+		return
+	}
+
+	if obj := objectOf(pass, expr); obj != nil {
+		if _, ok := ix.values[obj]; !ok {
+			ix.values[obj] = expr.Pos()
+		}
+	}
+}
+
+func (ix nilIndex) markElement(obj types.Object, pos token.Pos) {
+	if _, ok := ix.elements[obj]; !ok {
+		ix.elements[obj] = pos
+	}
+}
+
+// element returns the first nil use of obj's elements, or token.NoPos.
+func (ix nilIndex) element(obj types.Object) token.Pos {
+	return ix.elements[obj]
+}
+
+// objectOf returns the variable or field an expression refers to: x, pkg.x, or s.f.
+func objectOf(pass *analysis.Pass, expr ast.Expr) types.Object {
+	switch e := ast.Unparen(expr).(type) {
+	case *ast.Ident:
+		if obj := pass.TypesInfo.Uses[e]; obj != nil {
+			return obj
+		}
+
+		return pass.TypesInfo.Defs[e]
+	case *ast.SelectorExpr:
+		if sel, ok := pass.TypesInfo.Selections[e]; ok {
+			if sel.Kind() == types.FieldVal {
+				return sel.Obj()
+			}
+
+			return nil
+		}
+
+		// Qualified identifier (pkg.Var)
+		return pass.TypesInfo.Uses[e.Sel]
+	}
+
+	return nil
+}
@@ -0,0 +1,359 @@
+package analyzer
+
+import (
+	"go/ast"
+	"go/token"
+	"go/types"
+	"log/slog"
+	"maps"
+	"time"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/ast/inspector"
+
+	"github.com/mickamy/pointless/internal/directives"
+	"github.com/mickamy/pointless/internal/messages"
+	"github.com/mickamy/pointless/internal/rules"
+)
+
+// nolintSpan is a source range suppressed by a nolint comment.
+type nolintSpan struct {
+	span
+	comment *ast.Comment
+}
+
+// suppressions holds the findings suppressed by directive comments (see
+// package directives).
+type suppressions struct {
+	// spans holds the ranges suppressed by nolint, //pointless:ignore, and
+	// //pointless:ignore-file comments, and the declarations of the types
+	// annotated with //pointless:ignore-type.
+	spans []nolintSpan
+	// types maps the types annotated with //pointless:ignore-type to the
+	// comment.
+	types map[*types.TypeName]*ast.Comment
+}
+
+// suppressing returns the comment suppressing a finding about subject (if not
+// nil) at pos: that of the innermost range containing pos, or that of the
+// type of the finding. It returns nil if the finding is not suppressed.
+func (s suppressions) suppressing(subject types.Type, pos token.Pos) *ast.Comment {
+	var best *nolintSpan
+
+	for i, sp := range s.spans {
+		if sp.pos <= pos && pos < sp.end && (best == nil || sp.end-sp.pos < best.end-best.pos) {
+			best = &s.spans[i]
+		}
+	}
+
+	if best != nil {
+		return best.comment
+	}
+
+	if named, ok := types.Unalias(subject).(*types.Named); ok {
+		return s.types[named.Origin().Obj()]
+	}
+
+	return nil
+}
+
+// findSuppressions returns the findings suppressed by directive comments.
+// Supports both //nolint:pointless and //pointless:ignore formats.
+//
+// Lines are those of the compiled file, ignoring //line directives, which
+// may attribute a comment and the code below it to different sources.
+//
+// A comment suppresses the whole of the innermost declaration or assignment it
+// annotates: one on the line directly above it, or on any line of its header
+// (the signature of a function, the first line of a grouped declaration).
+// Comments not annotating any declaration cover their own line and the next one.
+// //pointless:ignore-file covers its whole file, and //pointless:ignore-type
+// the findings about the type it annotates, in any file.
+// In type declarations, a comment on a struct field covers that field only.
+// Comments with an expired until=YYYY-MM-DD date no longer suppress and are reported as stale,
+// with severity sev and linked to the documentation under docs.
+func findSuppressions(pass *analysis.Pass, inspect *inspector.Inspector, excludedFiles *excludedFiles, msg *messages.Printer, sev, docs string) suppressions {
+	var s suppressions
+
+	// comments maps each file to the lines holding nolint comments
+	comments := make(map[*token.File]map[int]*ast.Comment)
+
+	for _, f := range pass.Files {
+		for _, cg := range f.Comments {
+			for _, c := range cg.List {
+				d, ok := directives.Parse(c.Text)
+				if !ok || !d.Kind.Suppresses() || !checkNolintExpiry(pass, c, d.Until, excludedFiles, msg, sev, docs) {
+					continue
+				}
+
+				switch d.Kind {
+				case directives.IgnoreFile:
+					s.spans = append(s.spans, nolintSpan{span{f.FileStart, f.FileEnd}, c})
+
+					continue
+				case directives.IgnoreType:
+					s.ignoreTypes(pass, f, c)
+
+					continue
+				}
+
+				tf := pass.Fset.File(c.Pos())
+				if comments[tf] == nil {
+					comments[tf] = make(map[int]*ast.Comment)
+				}
+
+				comments[tf][compiledLine(tf, c.Pos())] = c
+			}
+		}
+	}
+
+	if len(comments) == 0 {
+		return s
+	}
+
+	// onHeader holds the innermost node with a comment on its header;
+	// above holds the outermost node starting on the line after a comment.
+	onHeader := make(map[*ast.Comment]ast.Node)
+	above := make(map[*ast.Comment]ast.Node)
+
+	for _, nodes := range scanFiles(inspect, func(file inspector.Cursor) nolintNodes {
+		return fileNolintNodes(pass, file, comments)
+	}) {
+		maps.Copy(onHeader, nodes.onHeader)
+		maps.Copy(above, nodes.above)
+	}
+
+	for tf, lines := range comments {
+		for line, c := range lines {
+			n := onHeader[c]
+			if n == nil {
+				n = above[c]
+			}
+
+			if n != nil {
+				s.spans = append(s.spans, nolintSpan{span{n.Pos(), n.End()}, c})
+
+				continue
+			}
+
+			// Also cover the next line (for comments above statements)
+			end := token.Pos(tf.Base() + tf.Size())
+			if line+2 <= tf.LineCount() {
+				end = tf.LineStart(line + 2)
+			}
+
+			s.spans = append(s.spans, nolintSpan{span{tf.LineStart(line), end}, c})
+		}
+	}
+
+	return s
+}
+
+// ignoreTypes records the types annotated by the //pointless:ignore-type
+// comment c of file f, along with their declarations.
+func (s *suppressions) ignoreTypes(pass *analysis.Pass, f *ast.File, c *ast.Comment) {
+	for _, at := range annotatedTypes(pass, f, c, directives.IgnoreType) {
+		if s.types == nil {
+			s.types = make(map[*types.TypeName]*ast.Comment)
+		}
+
+		// The declaration is covered too, for the findings on its fields
+		s.types[at.obj] = c
+		s.spans = append(s.spans, nolintSpan{span{at.decl.Pos(), at.decl.End()}, c})
+	}
+}
+
+// annotatedType is a type declaration annotated with a directive.
+type annotatedType struct {
+	obj *types.TypeName
+	// decl is the declaration of the type: its spec in a grouped declaration,
+	// or the whole of an ungrouped one.
+	decl ast.Node
+}
+
+// annotatedTypes returns the types of file f annotated by the comment c, a
+// directive of the given kind: those of the type declaration whose doc
+// comment holds c, or whose name is on the line of c.
+func annotatedTypes(pass *analysis.Pass, f *ast.File, c *ast.Comment, kind directives.Kind) []annotatedType {
+	has := func(doc *ast.CommentGroup) bool {
+		return doc != nil && doc.Pos() <= c.Pos() && c.Pos() < doc.End()
+	}
+
+	tf := pass.Fset.File(c.Pos())
+	line := compiledLine(tf, c.Pos())
+
+	var result []annotatedType
+
+	for _, decl := range f.Decls {
+		gen, ok := decl.(*ast.GenDecl)
+		if !ok || gen.Tok != token.TYPE {
+			continue
+		}
+
+		for _, spec := range gen.Specs {
+			ts, ok := spec.(*ast.TypeSpec)
+			if !ok || !has(gen.Doc) && !has(ts.Doc) && compiledLine(tf, ts.Name.Pos()) != line {
+				continue
+			}
+
+			obj, ok := pass.TypesInfo.Defs[ts.Name].(*types.TypeName)
+			if !ok {
+				continue
+			}
+
+			at := annotatedType{obj: obj, decl: ts}
+			if !gen.Lparen.IsValid() {
+				at.decl = gen
+			}
+
+			result = append(result, at)
+		}
+	}
+
+	if len(result) == 0 {
+		slog.Debug("directive annotates no type declaration", "directive", kind, "pos", pass.Fset.Position(c.Pos()))
+	}
+
+	return result
+}
+
+// nolintNodes holds the declarations annotated by the nolint comments of a
+// file (see findSuppressions).
+type nolintNodes struct {
+	onHeader map[*ast.Comment]ast.Node
+	above    map[*ast.Comment]ast.Node
+}
+
+// fileNolintNodes finds the declarations annotated by the nolint comments of
+// a file, given the lines holding them per file.
+func fileNolintNodes(pass *analysis.Pass, file inspector.Cursor, comments map[*token.File]map[int]*ast.Comment) nolintNodes {
+	nodes := nolintNodes{onHeader: make(map[*ast.Comment]ast.Node), above: make(map[*ast.Comment]ast.Node)}
+
+	tf := pass.Fset.File(file.Node().Pos())
+
+	lines := comments[tf]
+	if lines == nil {
+		return nodes
+	}
+
+	nodeFilter := []ast.Node{
+		(*ast.FuncDecl)(nil),
+		(*ast.GenDecl)(nil),
+		(*ast.ValueSpec)(nil),
+		(*ast.TypeSpec)(nil),
+		(*ast.Field)(nil),
+		(*ast.AssignStmt)(nil),
+	}
+
+	// Preorder visits enclosing nodes before the nodes inside them
+	for cur := range file.Preorder(nodeFilter...) {
+		n := cur.Node()
+		if _, ok := n.(*ast.Field); ok && !isTypeDeclField(cur) {
+			continue
+		}
+
+		first := compiledLine(tf, n.Pos())
+
+		if c := lines[first-1]; c != nil && nodes.above[c] == nil {
+			nodes.above[c] = n
+		}
+
+		for line := first; line <= compiledLine(tf, headerEnd(n)); line++ {
+			if c := lines[line]; c != nil {
+				nodes.onHeader[c] = n
+			}
+		}
+	}
+
+	return nodes
+}
+
+// headerEnd returns the end of the part of n a nolint comment can annotate,
+// so comments inside a function body or declaration group do not cover it whole.
+func headerEnd(n ast.Node) token.Pos {
+	switch n := n.(type) {
+	case *ast.FuncDecl:
+		return n.Type.End()
+	case *ast.GenDecl:
+		if n.Lparen.IsValid() {
+			return n.Lparen
+		}
+
+		if n.Tok == token.TYPE && len(n.Specs) == 1 {
+			return headerEnd(n.Specs[0])
+		}
+	case *ast.TypeSpec:
+		if st, ok := n.Type.(*ast.StructType); ok {
+			return st.Fields.Opening
+		}
+	case *ast.Field:
+		if st, ok := n.Type.(*ast.StructType); ok {
+			return st.Fields.Opening
+		}
+	}
+
+	return n.End()
+}
+
+// isTypeDeclField reports whether the field at cur is a struct field in a type
+// declaration, which nolint comments annotate on their own. Parameters and
+// results are covered by the comments on the function.
+func isTypeDeclField(cur inspector.Cursor) bool {
+	if _, ok := cur.Parent().Parent().Node().(*ast.StructType); !ok {
+		return false
+	}
+
+	for range cur.Enclosing((*ast.TypeSpec)(nil)) {
+		return true
+	}
+
+	return false
+}
+
+// nolintExpired reports whether a suppression until the date until, parsed in
+// UTC, has expired at now. It is valid through the whole day of its date,
+// which ends at midnight UTC whatever the local timezone.
+func nolintExpired(until, now time.Time) bool {
+	y, m, d := now.UTC().Date()
+
+	return time.Date(y, m, d, 0, 0, 0, 0, time.UTC).After(until)
+}
+
+// checkNolintExpiry reports whether a nolint comment with the until= date value
+// (empty if it does not expire) is still in effect. Comments whose date has
+// passed are reported as stale; malformed dates are reported but keep
+// suppressing.
+func checkNolintExpiry(pass *analysis.Pass, c *ast.Comment, value string, excludedFiles *excludedFiles, msg *messages.Printer, sev, docs string) bool {
+	if value == "" {
+		return true
+	}
+
+	report := !excludedFiles.contains(pass, c.Pos())
+
+	until, err := time.Parse(time.DateOnly, value)
+	if err != nil {
+		if report {
+			reportRule(pass, sev, docs, analysis.Diagnostic{
+				Pos:      c.Pos(),
+				Category: rules.StaleNolint,
+				Message:  msg.Sprintf(messages.InvalidNolintExpiration, value),
+			})
+		}
+
+		return true
+	}
+
+	if !nolintExpired(until, time.Now()) {
+		return true
+	}
+
+	if report {
+		reportRule(pass, sev, docs, analysis.Diagnostic{
+			Pos:      c.Pos(),
+			Category: rules.StaleNolint,
+			Message:  msg.Sprintf(messages.StaleNolint, value),
+		})
+	}
+
+	return false
+}
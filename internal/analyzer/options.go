@@ -0,0 +1,139 @@
+package analyzer
+
+import (
+	"go/ast"
+	"go/token"
+	"go/types"
+	"strings"
+
+	"golang.org/x/tools/go/analysis"
+
+	"github.com/mickamy/pointless/internal/messages"
+	"github.com/mickamy/pointless/internal/rules"
+)
+
+// optionsTypeSuffixes are the type name suffixes of option structs passed to
+// constructors (Options, ClientOpts, ServerConfig, ...).
+var optionsTypeSuffixes = []string{"Options", "Opts", "Config"}
+
+// isConstructor reports whether fn is an exported top-level New... function.
+func isConstructor(fn *ast.FuncDecl) bool {
+	return fn.Recv == nil && fn.Body != nil && fn.Name.IsExported() && strings.HasPrefix(fn.Name.Name, "New")
+}
+
+// isOptionsType reports whether t is a named struct that looks like an options struct.
+func isOptionsType(t types.Type) bool {
+	named, ok := types.Unalias(t).(*types.Named)
+	if !ok {
+		return false
+	}
+
+	if _, ok := named.Underlying().(*types.Struct); !ok {
+		return false
+	}
+
+	for _, suffix := range optionsTypeSuffixes {
+		if strings.HasSuffix(named.Obj().Name(), suffix) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// checkOptionsParams checks constructor parameters of type *Options that are only read.
+// Such parameters force callers to allocate and invite nil, while the constructor
+// works just as well with a copy.
+func checkOptionsParams(pass *analysis.Pass, fn *ast.FuncDecl, st *state) {
+	for _, field := range fn.Type.Params.List {
+		star, ok := ast.Unparen(field.Type).(*ast.StarExpr)
+		if !ok {
+			continue
+		}
+
+		tv, ok := pass.TypesInfo.Types[star.X]
+		if !ok || !isOptionsType(tv.Type) {
+			continue
+		}
+
+		for _, name := range field.Names {
+			checkOptionsParam(pass, fn, star, name, st)
+		}
+	}
+}
+
+func checkOptionsParam(pass *analysis.Pass, fn *ast.FuncDecl, star *ast.StarExpr, name *ast.Ident, st *state) {
+	obj := pass.TypesInfo.Defs[name]
+	if obj == nil || name.Name == "_" {
+		return
+	}
+
+	// Skip if the pointer is nil-checked, passed on, or reassigned
+	if pos := st.localPointers.disqualified[obj]; pos.IsValid() {
+		st.explain.skipped(pass, star.Pos(), "%s relies on the pointer at line %d", name.Name, lineOf(pass, pos))
+
+		return
+	}
+
+	// Skip if a goroutine keeps reading the caller's options
+	if pos := st.goroutines.values[obj]; pos.IsValid() {
+		st.explain.skipped(pass, star.Pos(), "%s is shared with a goroutine at line %d", name.Name, lineOf(pass, pos))
+
+		return
+	}
+
+	// Skip if the constructor writes through the pointer
+	if pos := findWritesThrough(pass, fn.Body, obj); pos.IsValid() {
+		st.explain.skipped(pass, star.Pos(), "%s is mutated at line %d", name.Name, lineOf(pass, pos))
+
+		return
+	}
+
+	t, size, ok := smallStruct(pass, st, star.Pos(), star.X)
+	if !ok {
+		return
+	}
+
+	typeName := typeString(pass, t)
+	st.reportf(pass, t, rules.OptionsPointer, star, messages.OptionsPointer, name.Name, typeName, typeName, fn.Name.Name, size, st.bounds())
+}
+
+// findWritesThrough returns the first position in body that assigns to obj or
+// anything reached through it, or calls a pointer-receiver method on it.
+func findWritesThrough(pass *analysis.Pass, body *ast.BlockStmt, obj types.Object) token.Pos {
+	var pos token.Pos
+
+	ast.Inspect(body, func(n ast.Node) bool {
+		if pos.IsValid() {
+			return false
+		}
+
+		switch node := n.(type) {
+		case *ast.AssignStmt:
+			for _, lhs := range node.Lhs {
+				if refersToReceiver(pass, lhs, obj) {
+					pos = node.Pos()
+				}
+			}
+		case *ast.IncDecStmt:
+			if refersToReceiver(pass, node.X, obj) {
+				pos = node.Pos()
+			}
+		case *ast.SelectorExpr:
+			sel, ok := pass.TypesInfo.Selections[node]
+			if !ok || sel.Kind() != types.MethodVal || !refersToReceiver(pass, node.X, obj) {
+				return true
+			}
+
+			if sig, ok := sel.Obj().Type().(*types.Signature); ok && sig.Recv() != nil {
+				if _, isPtr := sig.Recv().Type().(*types.Pointer); isPtr {
+					pos = node.Pos()
+				}
+			}
+		}
+
+		return true
+	})
+
+	return pos
+}
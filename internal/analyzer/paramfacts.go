@@ -0,0 +1,303 @@
+package analyzer
+
+import (
+	"go/ast"
+	"go/token"
+	"go/types"
+	"slices"
+	"strconv"
+	"strings"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/ast/inspector"
+	"golang.org/x/tools/go/types/typeutil"
+)
+
+// paramsFact is exported for functions and methods with pointer-to-struct
+// parameters that are only read through: never written through, stored, returned, compared with
+// nil, or passed on other than to parameters that are read-only themselves.
+// Callers, in this package or importing ones, use it to tell whether passing &x
+// to such a parameter was necessary.
+type paramsFact struct {
+	// ReadOnly holds the indexes of the read-only pointer parameters, ascending.
+	ReadOnly []int
+}
+
+// AFact implements analysis.Fact.
+func (*paramsFact) AFact() {}
+
+func (f *paramsFact) String() string {
+	indexes := make([]string, len(f.ReadOnly))
+	for i, idx := range f.ReadOnly {
+		indexes[i] = strconv.Itoa(idx)
+	}
+
+	return "readOnlyParams(" + strings.Join(indexes, ",") + ")"
+}
+
+// paramKey identifies a parameter by function and index.
+type paramKey struct {
+	fn    *types.Func
+	index int
+}
+
+// paramUses records how the pointer parameters of the package's functions use
+// their pointer.
+type paramUses struct {
+	// readOnly marks the read-only pointer parameters (see paramsFact).
+	readOnly map[paramKey]bool
+	// blocker maps the other pointer parameters to the first use relying on the pointer.
+	blocker map[paramKey]token.Pos
+}
+
+// isReadOnly reports whether the index-th parameter of fn is a read-only pointer
+// parameter, consulting facts for functions of other packages.
+func (u paramUses) isReadOnly(pass *analysis.Pass, fn *types.Func, index int) bool {
+	fn = fn.Origin()
+	if fn.Pkg() == pass.Pkg {
+		return u.readOnly[paramKey{fn, index}]
+	}
+
+	var fact paramsFact
+	if !pass.ImportObjectFact(fn, &fact) {
+		return false
+	}
+
+	for _, idx := range fact.ReadOnly {
+		if idx == index {
+			return true
+		}
+	}
+
+	return false
+}
+
+// findParamUses classifies the pointer parameters of the package's functions
+// and exports a paramsFact for each function with read-only ones.
+//
+// Parameters passed on to another function's parameter are read-only if that
+// parameter is. Within the package this is resolved optimistically to a
+// fixpoint, so mutually recursive readers stay read-only.
+func findParamUses(pass *analysis.Pass, inspect *inspector.Inspector) paramUses {
+	uses := paramUses{readOnly: make(map[paramKey]bool), blocker: make(map[paramKey]token.Pos)}
+
+	// params maps parameter variables to their key
+	params := make(map[*types.Var]paramKey)
+
+	inspect.Preorder([]ast.Node{(*ast.FuncDecl)(nil)}, func(n ast.Node) {
+		decl, ok := n.(*ast.FuncDecl)
+		if !ok || decl.Body == nil {
+			return
+		}
+
+		fn, ok := pass.TypesInfo.Defs[decl.Name].(*types.Func)
+		if !ok {
+			return
+		}
+
+		sig, ok := fn.Type().(*types.Signature)
+		if !ok {
+			return
+		}
+
+		for i := range sig.Params().Len() {
+			v := sig.Params().At(i)
+			if !isStructPointer(v.Type()) || v.Name() == "" || v.Name() == "_" {
+				continue
+			}
+
+			key := paramKey{fn, i}
+			params[v] = key
+			uses.readOnly[key] = true
+
+			if pos := findWritesThrough(pass, decl.Body, v); pos.IsValid() {
+				uses.block(key, pos)
+			}
+		}
+	})
+
+	if len(params) == 0 {
+		return uses
+	}
+
+	// edges maps parameters to the same-package parameters they are passed to
+	edges := make(map[paramKey][]paramKey)
+
+	inspect.WithStack([]ast.Node{(*ast.Ident)(nil)}, func(n ast.Node, push bool, stack []ast.Node) bool {
+		if !push {
+			return true
+		}
+
+		ident, ok := n.(*ast.Ident)
+		if !ok {
+			return true
+		}
+
+		v, ok := pass.TypesInfo.Uses[ident].(*types.Var)
+		if !ok {
+			return true
+		}
+
+		key, ok := params[v]
+		if !ok {
+			return true
+		}
+
+		callee, index, ok := classifyParamUse(pass, stack)
+		switch {
+		case !ok:
+			uses.block(key, ident.Pos())
+		case callee == nil:
+			// Read through the pointer
+		case callee.Pkg() == pass.Pkg:
+			edges[key] = append(edges[key], paramKey{callee, index})
+		case !uses.isReadOnly(pass, callee, index):
+			uses.block(key, ident.Pos())
+		}
+
+		return true
+	})
+
+	// Propagate until no parameter passed to a non-read-only one is left
+	for changed := true; changed; {
+		changed = false
+
+		for from, targets := range edges {
+			if !uses.readOnly[from] {
+				continue
+			}
+
+			for _, to := range targets {
+				if !uses.readOnly[to] {
+					uses.block(from, uses.blocker[to])
+					changed = true
+
+					break
+				}
+			}
+		}
+	}
+
+	exportParamsFacts(pass, uses)
+
+	return uses
+}
+
+// isStructPointer reports whether t is a pointer to a struct.
+func isStructPointer(t types.Type) bool {
+	ptr, ok := t.(*types.Pointer)
+	if !ok {
+		return false
+	}
+
+	_, ok = ptr.Elem().Underlying().(*types.Struct)
+
+	return ok
+}
+
+// block marks key as relying on the pointer, first at pos.
+func (u paramUses) block(key paramKey, pos token.Pos) {
+	if !u.readOnly[key] && u.blocker[key].IsValid() {
+		return
+	}
+
+	u.readOnly[key] = false
+	u.blocker[key] = pos
+}
+
+// exportParamsFacts exports a paramsFact for every function of the package with
+// read-only pointer parameters.
+func exportParamsFacts(pass *analysis.Pass, uses paramUses) {
+	byFunc := make(map[*types.Func][]int)
+
+	for key, ok := range uses.readOnly {
+		if ok {
+			byFunc[key.fn] = append(byFunc[key.fn], key.index)
+		}
+	}
+
+	for fn, indexes := range byFunc {
+		slices.Sort(indexes)
+		pass.ExportObjectFact(fn, &paramsFact{ReadOnly: indexes})
+	}
+}
+
+// classifyParamUse classifies the use of a pointer parameter at the top of stack.
+// It reports ok=false when the use relies on the pointer itself, and the callee
+// and parameter index when the pointer is passed to a statically known function.
+// Reads through the pointer (p.F, *p, p.M()) return a nil callee.
+func classifyParamUse(pass *analysis.Pass, stack []ast.Node) (callee *types.Func, index int, ok bool) {
+	node := stack[len(stack)-1]
+
+	i := len(stack) - 2
+	for i >= 0 {
+		if _, isParen := stack[i].(*ast.ParenExpr); !isParen {
+			break
+		}
+
+		node = stack[i]
+		i--
+	}
+
+	if i < 0 {
+		return nil, 0, false
+	}
+
+	switch parent := stack[i].(type) {
+	case *ast.SelectorExpr:
+		// &p.F aliases the caller's value
+		return nil, 0, parent.X == node && !addressTaken(stack[:i+1])
+	case *ast.StarExpr:
+		return nil, 0, !addressTaken(stack[:i+1])
+	case *ast.CallExpr:
+		return paramCallee(pass, parent, node)
+	}
+
+	return nil, 0, false
+}
+
+// addressTaken reports whether the expression at the top of stack, or a field,
+// index, or dereference chain containing it, is the operand of &.
+func addressTaken(stack []ast.Node) bool {
+	for i := len(stack) - 2; i >= 0; i-- {
+		switch parent := stack[i].(type) {
+		case *ast.ParenExpr, *ast.SelectorExpr, *ast.IndexExpr, *ast.StarExpr:
+			continue
+		case *ast.UnaryExpr:
+			return parent.Op == token.AND
+		}
+
+		return false
+	}
+
+	return false
+}
+
+// paramCallee returns the statically known function call passes arg to, and the
+// index of the parameter receiving it.
+func paramCallee(pass *analysis.Pass, call *ast.CallExpr, arg ast.Node) (*types.Func, int, bool) {
+	fn := typeutil.StaticCallee(pass.TypesInfo, call)
+	if fn == nil {
+		return nil, 0, false
+	}
+
+	sig, ok := fn.Type().(*types.Signature)
+	if !ok {
+		return nil, 0, false
+	}
+
+	for i, a := range call.Args {
+		if a != arg {
+			continue
+		}
+
+		// Variadic arguments end up in a slice
+		if i >= sig.Params().Len() || sig.Variadic() && i >= sig.Params().Len()-1 {
+			return nil, 0, false
+		}
+
+		return fn.Origin(), i, true
+	}
+
+	return nil, 0, false
+}
@@ -0,0 +1,158 @@
+package analyzer
+
+import (
+	"go/types"
+	"path"
+	"reflect"
+	"slices"
+
+	"github.com/mickamy/pointless/internal/preset"
+)
+
+// presetMatcher reports whether a type is managed by one of the configured
+// framework presets. Results are cached per type.
+type presetMatcher struct {
+	presets []preset.Preset
+	cache   map[types.Type]bool
+}
+
+func newPresetMatcher(names []string) *presetMatcher {
+	m := &presetMatcher{cache: make(map[types.Type]bool)}
+
+	for _, name := range names {
+		if p, ok := preset.Lookup(name); ok {
+			m.presets = append(m.presets, p)
+		}
+	}
+
+	return m
+}
+
+// matches reports whether t (a named struct type) matches any active preset.
+func (m *presetMatcher) matches(t types.Type) bool {
+	if len(m.presets) == 0 {
+		return false
+	}
+
+	if result, ok := m.cache[t]; ok {
+		return result
+	}
+
+	result := false
+
+	for _, p := range m.presets {
+		if matchesPreset(t, p) {
+			result = true
+
+			break
+		}
+	}
+
+	m.cache[t] = result
+
+	return result
+}
+
+func matchesPreset(t types.Type, p preset.Preset) bool {
+	named, ok := types.Unalias(t).(*types.Named)
+	if !ok {
+		return false
+	}
+
+	obj := named.Obj()
+	qualified := obj.Name()
+
+	if obj.Pkg() != nil {
+		qualified = obj.Pkg().Path() + "." + obj.Name()
+	}
+
+	for _, pattern := range p.TypePatterns {
+		if matched, _ := path.Match(pattern, obj.Name()); matched {
+			return true
+		}
+
+		if matched, _ := path.Match(pattern, qualified); matched {
+			return true
+		}
+	}
+
+	if st, ok := named.Underlying().(*types.Struct); ok && matchesStructMarkers(st, p) {
+		return true
+	}
+
+	if len(p.Interfaces) > 0 {
+		methods := make(map[string]bool)
+
+		mset := types.NewMethodSet(types.NewPointer(named))
+		for i := range mset.Len() {
+			methods[mset.At(i).Obj().Name()] = true
+		}
+
+		for _, iface := range p.Interfaces {
+			if hasAllMethods(methods, iface.Methods) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// matchesStructMarkers checks embedded marker types, field marker types, and
+// struct tag keys.
+func matchesStructMarkers(st *types.Struct, p preset.Preset) bool {
+	for i := range st.NumFields() {
+		field := st.Field(i)
+		name := qualifiedTypeName(field.Type())
+
+		if field.Embedded() && slices.Contains(p.EmbeddedTypes, name) {
+			return true
+		}
+
+		if name != "" && slices.Contains(p.FieldTypes, name) {
+			return true
+		}
+
+		tag := reflect.StructTag(st.Tag(i))
+		for _, key := range p.TagKeys {
+			if _, ok := tag.Lookup(key); ok {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// qualifiedTypeName returns "pkgpath.Name" for a named type or pointer to one.
+func qualifiedTypeName(t types.Type) string {
+	if ptr, ok := t.(*types.Pointer); ok {
+		t = ptr.Elem()
+	}
+
+	named, ok := types.Unalias(t).(*types.Named)
+	if !ok {
+		return ""
+	}
+
+	obj := named.Obj()
+	if obj.Pkg() == nil {
+		return obj.Name()
+	}
+
+	return obj.Pkg().Path() + "." + obj.Name()
+}
+
+func hasAllMethods(methods map[string]bool, required []string) bool {
+	if len(required) == 0 {
+		return false
+	}
+
+	for _, name := range required {
+		if !methods[name] {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,155 @@
+package analyzer
+
+import (
+	"go/ast"
+	"go/token"
+	"go/types"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/ast/inspector"
+)
+
+// mutatesReceiverFact is exported for methods with a pointer receiver that
+// mutate it (see findReceiverMutations). Methods of importing packages calling
+// them on their receiver, or a field of it, mutate their receiver too.
+type mutatesReceiverFact struct{}
+
+// AFact implements analysis.Fact.
+func (*mutatesReceiverFact) AFact() {}
+
+func (*mutatesReceiverFact) String() string {
+	return "mutatesReceiver"
+}
+
+// exportMutationFacts exports a mutatesReceiverFact for the exported methods
+// of mutations with a pointer receiver, the only ones other packages can
+// select.
+func exportMutationFacts(pass *analysis.Pass, mutations map[*ast.FuncDecl]token.Pos) {
+	for decl, pos := range mutations {
+		if !pos.IsValid() {
+			continue
+		}
+
+		if fn, ok := pass.TypesInfo.Defs[decl.Name].(*types.Func); ok && fn.Exported() && hasPointerReceiver(fn) {
+			pass.ExportObjectFact(fn, &mutatesReceiverFact{})
+		}
+	}
+}
+
+// receiverCall is a pointer-receiver method selected on the receiver of a
+// method, or on a field of it: called (s.update()), bound as a method value
+// (f := s.update), or called as a method expression ((*T).update(s)).
+type receiverCall struct {
+	callee *types.Func
+	pos    token.Pos
+}
+
+// addMutatingCalls adds to mutations, holding the methods that mutate their
+// receiver directly, the methods that select a mutating method on their
+// receiver: the method value may be called later, in a goroutine, or
+// deferred, and mutates the receiver all the same. Within the package this is
+// resolved to a fixpoint, so chains of such methods mutate too; methods of
+// other packages are looked up in facts, if facts is set.
+func addMutatingCalls(pass *analysis.Pass, inspect *inspector.Inspector, mutations map[*ast.FuncDecl]token.Pos, facts bool) {
+	decls := make(map[*types.Func]*ast.FuncDecl)
+	calls := make(map[*ast.FuncDecl][]receiverCall)
+
+	// methods holds the methods with receiver calls, in source order
+	var methods []*ast.FuncDecl
+
+	inspect.Preorder([]ast.Node{(*ast.FuncDecl)(nil)}, func(n ast.Node) {
+		decl, ok := n.(*ast.FuncDecl)
+		if !ok || decl.Recv == nil || len(decl.Recv.List) == 0 || decl.Body == nil {
+			return
+		}
+
+		if fn, ok := pass.TypesInfo.Defs[decl.Name].(*types.Func); ok {
+			decls[fn] = decl
+		}
+
+		recv := decl.Recv.List[0]
+		if len(recv.Names) == 0 || mutations[decl].IsValid() {
+			return
+		}
+
+		if cs := findReceiverCalls(pass, decl.Body, pass.TypesInfo.Defs[recv.Names[0]]); len(cs) > 0 {
+			calls[decl] = cs
+			methods = append(methods, decl)
+		}
+	})
+
+	mutates := func(fn *types.Func) bool {
+		fn = fn.Origin()
+		if decl, ok := decls[fn]; ok {
+			return mutations[decl].IsValid()
+		}
+
+		return facts && fn.Pkg() != nil && fn.Pkg() != pass.Pkg && pass.ImportObjectFact(fn, new(mutatesReceiverFact))
+	}
+
+	for changed := true; changed; {
+		changed = false
+
+		for _, decl := range methods {
+			if mutations[decl].IsValid() {
+				continue
+			}
+
+			for _, c := range calls[decl] {
+				if mutates(c.callee) {
+					mutations[decl] = c.pos
+					changed = true
+
+					break
+				}
+			}
+		}
+	}
+}
+
+// findReceiverCalls finds the pointer-receiver methods selected on the
+// receiver receiverObj, or a field of it, in body.
+func findReceiverCalls(pass *analysis.Pass, body *ast.BlockStmt, receiverObj types.Object) []receiverCall {
+	if receiverObj == nil {
+		return nil
+	}
+
+	var result []receiverCall
+
+	ast.Inspect(body, func(n ast.Node) bool {
+		switch node := n.(type) {
+		case *ast.SelectorExpr:
+			sel, ok := pass.TypesInfo.Selections[node]
+			if !ok || sel.Kind() != types.MethodVal || !hasPointerReceiver(sel.Obj()) {
+				return true
+			}
+
+			if fn, ok := sel.Obj().(*types.Func); ok && refersToReceiver(pass, node.X, receiverObj) {
+				result = append(result, receiverCall{callee: fn, pos: node.Pos()})
+			}
+		case *ast.CallExpr:
+			fun, ok := ast.Unparen(node.Fun).(*ast.SelectorExpr)
+			if !ok || len(node.Args) == 0 {
+				return true
+			}
+
+			sel, ok := pass.TypesInfo.Selections[fun]
+			if !ok || sel.Kind() != types.MethodExpr || !hasPointerReceiver(sel.Obj()) {
+				return true
+			}
+
+			arg := ast.Unparen(node.Args[0])
+			if addr, ok := arg.(*ast.UnaryExpr); ok && addr.Op == token.AND {
+				arg = addr.X
+			}
+
+			if fn, ok := sel.Obj().(*types.Func); ok && refersToReceiver(pass, arg, receiverObj) {
+				result = append(result, receiverCall{callee: fn, pos: node.Pos()})
+			}
+		}
+
+		return true
+	})
+
+	return result
+}
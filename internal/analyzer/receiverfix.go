@@ -0,0 +1,224 @@
+package analyzer
+
+import (
+	"go/ast"
+	"go/token"
+	"go/types"
+
+	"golang.org/x/tools/go/analysis"
+
+	"github.com/mickamy/pointless/internal/fixsafety"
+	"github.com/mickamy/pointless/internal/messages"
+)
+
+// receiverRewrite plans the edits of the body of a method whose pointer
+// receiver becomes a value receiver, so that the body keeps compiling.
+type receiverRewrite struct {
+	edits []analysis.TextEdit
+	// used reports whether the body uses the receiver at all.
+	used bool
+	// addressed reports whether &recv is passed to read-only parameters.
+	addressed bool
+	// pointerMethods reports whether pointer-receiver methods are called on the receiver.
+	pointerMethods bool
+	// fieldAddress reports whether addresses of the receiver's fields are taken.
+	fieldAddress bool
+}
+
+// valueReceiverFix returns the fix turning the pointer receiver of fn into a
+// value receiver, along with the edits its body needs: *recv becomes recv, and
+// recv passed to read-only pointer parameters becomes &recv. It reports false
+// if another use of the receiver relies on it being a pointer, such as a nil
+// check or a comparison, since no rewrite keeps those both compiling and
+// meaning the same.
+func valueReceiverFix(pass *analysis.Pass, fn *ast.FuncDecl, star *ast.StarExpr, st *state) (analysis.SuggestedFix, bool) {
+	rw := receiverRewrite{edits: []analysis.TextEdit{{Pos: star.Pos(), End: star.X.Pos()}}}
+
+	recv := fn.Recv.List[0]
+	if len(recv.Names) > 0 && fn.Body != nil {
+		if obj := pass.TypesInfo.Defs[recv.Names[0]]; obj != nil && !rw.plan(pass, fn.Body, obj, st) {
+			return analysis.SuggestedFix{}, false
+		}
+	}
+
+	return analysis.SuggestedFix{
+		Message:   fixsafety.Tag(st.msg.Sprintf(messages.ValueReceiverFix), rw.evidence(recv, st)),
+		TextEdits: rw.edits,
+	}, true
+}
+
+// plan records the edits of every use of the receiver obj in body, and
+// reports false at the first use it cannot rewrite.
+func (rw *receiverRewrite) plan(pass *analysis.Pass, body *ast.BlockStmt, obj types.Object, st *state) bool {
+	ok := true
+
+	var stack []ast.Node
+
+	ast.Inspect(body, func(n ast.Node) bool {
+		if n == nil {
+			stack = stack[:len(stack)-1]
+
+			return true
+		}
+
+		// Nodes not descended into get no nil call, so they are not pushed
+		if !ok {
+			return false
+		}
+
+		stack = append(stack, n)
+
+		if ident, isIdent := n.(*ast.Ident); isIdent && pass.TypesInfo.Uses[ident] == obj {
+			rw.used = true
+			ok = rw.planUse(pass, stack, st)
+		}
+
+		return true
+	})
+
+	return ok
+}
+
+// planUse plans the use of the receiver at the top of stack.
+func (rw *receiverRewrite) planUse(pass *analysis.Pass, stack []ast.Node, st *state) bool {
+	ident, _ := stack[len(stack)-1].(*ast.Ident)
+	node, i := enclosingParens(stack, len(stack)-1)
+
+	if i < 0 {
+		return false
+	}
+
+	switch parent := stack[i].(type) {
+	case *ast.SelectorExpr:
+		if parent.X != node {
+			return false
+		}
+
+		if sel, ok := pass.TypesInfo.Selections[parent]; ok && sel.Kind() == types.MethodVal {
+			rw.pointerMethods = rw.pointerMethods || hasPointerReceiver(sel.Obj())
+
+			return true
+		}
+
+		rw.fieldAddress = rw.fieldAddress || addressesCopy(pass, stack[:i+1])
+
+		return true
+	case *ast.StarExpr:
+		// *recv is the value itself now
+		rw.edits = append(rw.edits, analysis.TextEdit{Pos: parent.Pos(), End: parent.X.Pos()})
+		rw.fieldAddress = rw.fieldAddress || addressesCopy(pass, stack[:i+1])
+
+		return true
+	case *ast.CallExpr:
+		callee, index, ok := paramCallee(pass, parent, node)
+		if !ok || !st.params.isReadOnly(pass, callee, index) {
+			return false
+		}
+
+		rw.edits = append(rw.edits, analysis.TextEdit{Pos: ident.Pos(), End: ident.Pos(), NewText: []byte("&")})
+		rw.addressed = true
+
+		return true
+	}
+
+	return false
+}
+
+// evidence returns how safe the planned rewrite is. Reading the receiver, and
+// passing its address to parameters that only read it, behave the same on a
+// copy; addresses taken of the copy or its fields no longer alias the
+// caller's value, which only matters if they are written through or compared.
+func (rw *receiverRewrite) evidence(recv *ast.Field, st *state) fixsafety.Evidence {
+	if !rw.used {
+		return fixsafety.Evidence{Level: fixsafety.Safe, Reasons: []string{st.msg.Sprintf(messages.FixReceiverUnused)}}
+	}
+
+	var likely []string
+
+	if rw.pointerMethods {
+		likely = append(likely, st.msg.Sprintf(messages.FixReceiverPointerMethods))
+	}
+
+	if rw.fieldAddress {
+		likely = append(likely, st.msg.Sprintf(messages.FixReceiverFieldAddress))
+	}
+
+	if len(likely) > 0 {
+		return fixsafety.Evidence{Level: fixsafety.Likely, Reasons: likely}
+	}
+
+	reasons := []string{st.msg.Sprintf(messages.FixReceiverRead)}
+	if rw.addressed {
+		reasons = append(reasons, st.msg.Sprintf(messages.FixReceiverReadOnlyArgs, recv.Names[0].Name))
+	}
+
+	return fixsafety.Evidence{Level: fixsafety.Safe, Reasons: reasons}
+}
+
+// enclosingParens returns the outermost parenthesized expression around
+// stack[i], and the index of its parent in stack (-1 if there is none).
+func enclosingParens(stack []ast.Node, i int) (ast.Node, int) {
+	node := stack[i]
+
+	for i--; i >= 0; i-- {
+		if _, ok := stack[i].(*ast.ParenExpr); !ok {
+			break
+		}
+
+		node = stack[i]
+	}
+
+	return node, i
+}
+
+// addressesCopy reports whether the expression at the top of stack, which
+// denotes memory of the receiver, has its address taken: by &, or by calling
+// a pointer-receiver method on it. Field and array index chains lead to more
+// of that memory; pointer fields and slice elements do not, since a copy
+// shares them.
+func addressesCopy(pass *analysis.Pass, stack []ast.Node) bool {
+	for i := len(stack) - 1; i >= 0; {
+		node, p := enclosingParens(stack, i)
+		if p < 0 {
+			return false
+		}
+
+		switch parent := stack[p].(type) {
+		case *ast.UnaryExpr:
+			return parent.Op == token.AND
+		case *ast.SelectorExpr:
+			if parent.X != node {
+				return false
+			}
+
+			if isPointer(pass.TypesInfo.TypeOf(parent.X)) {
+				return false
+			}
+
+			if sel, ok := pass.TypesInfo.Selections[parent]; ok && sel.Kind() == types.MethodVal {
+				return hasPointerReceiver(sel.Obj())
+			}
+		case *ast.IndexExpr:
+			if parent.X != node {
+				return false
+			}
+
+			if _, ok := pass.TypesInfo.TypeOf(parent.X).Underlying().(*types.Array); !ok {
+				return false
+			}
+		default:
+			return false
+		}
+
+		i = p
+	}
+
+	return false
+}
+
+// hasPointerReceiver reports whether obj is a method with a pointer receiver.
+func hasPointerReceiver(obj types.Object) bool {
+	sig, ok := obj.Type().(*types.Signature)
+
+	return ok && sig.Recv() != nil && isPointer(sig.Recv().Type())
+}
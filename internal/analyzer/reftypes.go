@@ -0,0 +1,151 @@
+package analyzer
+
+import (
+	"go/ast"
+	"go/token"
+	"go/types"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/ast/inspector"
+
+	"github.com/mickamy/pointless/internal/messages"
+	"github.com/mickamy/pointless/internal/rules"
+)
+
+// referenceKind returns the message describing t if it is a map, slice, channel,
+// or function type (i.e. a type that already refers to its contents), or "" otherwise.
+func referenceKind(t types.Type) messages.ID {
+	switch t.Underlying().(type) {
+	case *types.Map:
+		return messages.KindMaps
+	case *types.Slice:
+		return messages.KindSlices
+	case *types.Chan:
+		return messages.KindChannels
+	case *types.Signature:
+		return messages.KindFunctions
+	}
+
+	return ""
+}
+
+// findIndirectUses finds variables of pointer-to-reference type (*map, *[]T, *chan, *func)
+// that are used other than by reading through them. Writing through the pointer
+// (*p = append(*p, x)), comparing it with nil, or passing it on all rely on the pointer.
+func findIndirectUses(pass *analysis.Pass, inspect *inspector.Inspector) map[types.Object]token.Pos {
+	result := make(map[types.Object]token.Pos)
+
+	inspect.WithStack([]ast.Node{(*ast.Ident)(nil)}, func(n ast.Node, push bool, stack []ast.Node) bool {
+		if !push {
+			return true
+		}
+
+		ident, ok := n.(*ast.Ident)
+		if !ok {
+			return true
+		}
+
+		obj, ok := pass.TypesInfo.Uses[ident].(*types.Var)
+		if !ok {
+			return true
+		}
+
+		ptr, ok := obj.Type().(*types.Pointer)
+		if !ok || referenceKind(ptr.Elem()) == "" {
+			return true
+		}
+
+		if !isDerefRead(stack) && !result[obj].IsValid() {
+			result[obj] = ident.Pos()
+		}
+
+		return true
+	})
+
+	return result
+}
+
+// isDerefRead reports whether the identifier at the top of stack appears as *ident
+// in a read-only position.
+func isDerefRead(stack []ast.Node) bool {
+	if len(stack) < 3 {
+		return false
+	}
+
+	ident := stack[len(stack)-1]
+
+	// Skip any parentheses around the identifier
+	i := len(stack) - 2
+	for i > 0 {
+		if _, ok := stack[i].(*ast.ParenExpr); !ok {
+			break
+		}
+
+		i--
+	}
+
+	star, ok := stack[i].(*ast.StarExpr)
+	if !ok || ast.Unparen(star.X) != ident {
+		return false
+	}
+
+	switch parent := stack[i-1].(type) {
+	case *ast.AssignStmt:
+		for _, lhs := range parent.Lhs {
+			if ast.Unparen(lhs) == star {
+				return false
+			}
+		}
+	case *ast.UnaryExpr:
+		return parent.Op != token.AND
+	}
+
+	return true
+}
+
+// checkRefPointerParams checks function parameters and results of pointer-to-reference type.
+func checkRefPointerParams(pass *analysis.Pass, fn *ast.FuncDecl, st *state) {
+	if fn.Type.Params != nil {
+		for _, field := range fn.Type.Params.List {
+			checkRefPointerField(pass, field.Type, field.Names, st)
+		}
+	}
+
+	if fn.Type.Results != nil && !st.nilReturns[fn].IsValid() {
+		for _, field := range fn.Type.Results.List {
+			checkRefPointerField(pass, field.Type, field.Names, st)
+		}
+	}
+}
+
+// checkRefPointerField reports typ if it is a pointer to a reference type and none
+// of the given names rely on the pointer.
+func checkRefPointerField(pass *analysis.Pass, typ ast.Expr, names []*ast.Ident, st *state) {
+	star, ok := ast.Unparen(typ).(*ast.StarExpr)
+	if !ok {
+		return
+	}
+
+	tv, ok := pass.TypesInfo.Types[star.X]
+	if !ok {
+		return
+	}
+
+	kind := referenceKind(tv.Type)
+	if kind == "" {
+		return
+	}
+
+	for _, name := range names {
+		if obj := pass.TypesInfo.Defs[name]; obj != nil {
+			if pos := st.indirectUses[obj]; pos.IsValid() {
+				st.explain.skipped(pass, star.Pos(), "%s relies on the pointer at line %d", name.Name, lineOf(pass, pos))
+
+				return
+			}
+		}
+	}
+
+	typeName := types.TypeString(tv.Type, types.RelativeTo(pass.Pkg))
+	st.reportf(pass, tv.Type, rules.ReferencePointer, star, messages.ReferencePointer, typeName, typeName, st.msg.Sprintf(kind))
+}
@@ -0,0 +1,271 @@
+package analyzer
+
+import (
+	"fmt"
+	"go/token"
+	"go/types"
+	"strings"
+
+	"golang.org/x/tools/go/analysis"
+
+	"github.com/mickamy/pointless/internal/config"
+	"github.com/mickamy/pointless/internal/messages"
+	"github.com/mickamy/pointless/internal/rules"
+	"github.com/mickamy/pointless/internal/severity"
+)
+
+// showSuppressed reports findings suppressed by nolint comments too.
+var showSuppressed bool
+
+// report reports d unless its position is suppressed by a nolint comment. In
+// -show-suppressed mode such diagnostics are marked as suppressed and point at
+// the suppressing comment instead.
+// In -group-by-type mode, diagnostics about subject are held back and merged by flushGroups.
+func (st *state) report(pass *analysis.Pass, subject types.Type, d analysis.Diagnostic) {
+	// Skip if the pointers to the type are declared intentional
+	if subject != nil && isPointerType(pass, subject) {
+		st.explain.skipped(pass, d.Pos, "%s is annotated with //pointless:pointer", typeString(pass, subject))
+
+		return
+	}
+
+	// Skip if the type is enforced, which enforced-value reports on its own
+	if subject != nil && d.Category != rules.EnforcedValue && isEnforced(pass, subject) {
+		st.explain.skipped(pass, d.Pos, "%s is annotated with //pointless:enforce; enforced-value reports it", typeString(pass, subject))
+
+		return
+	}
+
+	// Skip if the type is exempt by skip-if or the SkipIf option
+	if subject != nil && st.skipIf != nil && st.skipIf.Match(subject) {
+		st.explain.skipped(pass, d.Pos, "type matches skip-if %s", st.skipIf)
+
+		return
+	}
+
+	if subject != nil && st.opts.SkipIf != nil && st.opts.SkipIf(subject) {
+		st.explain.skipped(pass, d.Pos, "type is exempt by the SkipIf option")
+
+		return
+	}
+
+	if subject != nil && !st.checkComparisons(pass, subject, &d) {
+		return
+	}
+
+	if subject != nil {
+		st.noteZeroValue(pass, subject, &d)
+	}
+
+	if st.symbols.filtered(pass, st, d.Pos) || st.excludeRules.excluded(pass, st, d) || !st.testRuleEnabled(pass, d) {
+		st.metrics.Filtered++
+
+		return
+	}
+
+	if c := st.nolint.suppressing(subject, d.Pos); c != nil {
+		st.metrics.Suppressed++
+
+		if !st.opts.ShowSuppressed {
+			st.explain.skipped(pass, d.Pos, "suppressed by nolint comment at line %d", lineOf(pass, c.Pos()))
+
+			return
+		}
+
+		d.Message = st.msg.Sprintf(messages.Suppressed, d.Message)
+		d.Related = append(d.Related, analysis.RelatedInformation{
+			Pos:     c.Pos(),
+			End:     c.End(),
+			Message: st.msg.Sprintf(messages.SuppressedBy),
+		})
+	}
+
+	if st.opts.Verbose {
+		d.Message = st.withLayout(pass, subject, d.Message)
+	}
+
+	st.guardFixes(pass, &d)
+	st.explain.flagged(pass, d.Pos, d.Message)
+
+	if st.groups != nil && subject != nil {
+		st.groups.add(pass, subject, d)
+
+		return
+	}
+
+	reportRule(pass, st.severityOf(pass, d.Category, d.Pos), st.opts.Config.DocsBaseURL, d)
+}
+
+// bounds returns the size bounds of findings, the last argument of their
+// messages: the threshold, and the min-size if set.
+func (st *state) bounds() string {
+	if st.minSize > 0 {
+		return st.msg.Sprintf(messages.SizeBounds, st.minSize, st.opts.Threshold)
+	}
+
+	return st.msg.Sprintf(messages.Threshold, st.opts.Threshold)
+}
+
+// severityOf returns the severity of a finding of rule at pos: the configured
+// one, capped by tests.severity in test files.
+func (st *state) severityOf(pass *analysis.Pass, rule string, pos token.Pos) string {
+	if config.IsTestFile(pass.Fset.File(pos).Name()) {
+		return st.opts.Config.TestSeverity(rule)
+	}
+
+	return st.opts.Config.SeverityOf(rule)
+}
+
+// testRuleEnabled reports whether d is reported: findings in test files only
+// are if tests.rules lists their rule, or is empty.
+func (st *state) testRuleEnabled(pass *analysis.Pass, d analysis.Diagnostic) bool {
+	if !config.IsTestFile(pass.Fset.File(d.Pos).Name()) || st.opts.Config.TestRuleEnabled(d.Category) {
+		return true
+	}
+
+	st.explain.skipped(pass, d.Pos, "%s is not listed in tests.rules", d.Category)
+
+	return false
+}
+
+// reportf is the state-aware counterpart of pass.ReportRangef for the given
+// rule, formatting the message from the configured catalog. The diagnostic
+// covers rng, usually the *T expression, so editors highlight just that.
+func (st *state) reportf(pass *analysis.Pass, subject types.Type, rule string, rng analysis.Range, id messages.ID, args ...any) {
+	st.report(pass, subject, analysis.Diagnostic{Pos: rng.Pos(), End: rng.End(), Category: rule, Message: st.msg.Sprintf(id, args...)})
+}
+
+// reportRule reports d with the given severity, linking it to the
+// documentation of its rule (d.Category) under docs (see rules.URLAt).
+func reportRule(pass *analysis.Pass, sev, docs string, d analysis.Diagnostic) {
+	if d.URL == "" {
+		d.URL = rules.URLAt(docs, d.Category)
+	}
+
+	d.Message = severity.Tag(sev, d.Message)
+
+	pass.Report(d)
+}
+
+// typeRelated returns related information pointing at the definition of a named
+// type, with its size breakdown for structs, with -verbose or
+// Options.TypeDefinitions.
+func (st *state) typeRelated(pass *analysis.Pass, t types.Type) []analysis.RelatedInformation {
+	if !st.opts.Verbose && !st.opts.TypeDefinitions {
+		return nil
+	}
+
+	named, ok := types.Unalias(t).(*types.Named)
+	if !ok || !named.Obj().Pos().IsValid() {
+		return nil
+	}
+
+	typeName := types.TypeString(t, types.RelativeTo(pass.Pkg))
+	msg := st.msg.Sprintf(messages.TypeDefined, typeName)
+
+	if s, ok := named.Underlying().(*types.Struct); ok && s.NumFields() > 0 {
+		msg = st.msg.Sprintf(messages.TypeDefinedSize, typeName, sizeBreakdown(pass, s))
+	}
+
+	return []analysis.RelatedInformation{{
+		Pos:     named.Obj().Pos(),
+		Message: msg,
+	}}
+}
+
+// sizeBreakdown describes how the size of a struct adds up, e.g.
+// "32 bytes = ID 8 + Name 16 + Age 4 + IsActive 1 + padding 3".
+func sizeBreakdown(pass *analysis.Pass, st *types.Struct) string {
+	fields := make([]*types.Var, st.NumFields())
+	for i := range fields {
+		fields[i] = st.Field(i)
+	}
+
+	total := pass.TypesSizes.Sizeof(st)
+
+	var parts []string
+
+	sum := int64(0)
+	for _, f := range fields {
+		size := pass.TypesSizes.Sizeof(f.Type())
+		sum += size
+		parts = append(parts, fmt.Sprintf("%s %d", fieldName(f), size))
+	}
+
+	if padding := total - sum; padding > 0 {
+		parts = append(parts, fmt.Sprintf("padding %d", padding))
+	}
+
+	return fmt.Sprintf("%d bytes = %s", total, strings.Join(parts, " + "))
+}
+
+// withLayout appends the field-by-field layout of subject, if it is a struct,
+// to msg: every field's type, offset, and size, and the padding between them.
+func (st *state) withLayout(pass *analysis.Pass, subject types.Type, msg string) string {
+	if subject == nil || !hasFixedSize(subject) {
+		return msg
+	}
+
+	s, ok := subject.Underlying().(*types.Struct)
+	if !ok || s.NumFields() == 0 {
+		return msg
+	}
+
+	fields := make([]*types.Var, s.NumFields())
+	for i := range fields {
+		fields[i] = s.Field(i)
+	}
+
+	offsets := pass.TypesSizes.Offsetsof(fields)
+
+	var parts []string
+
+	end := int64(0)
+	for i, f := range fields {
+		if padding := offsets[i] - end; padding > 0 {
+			parts = append(parts, st.msg.Sprintf(messages.LayoutPadding, padding))
+		}
+
+		size := pass.TypesSizes.Sizeof(f.Type())
+		parts = append(parts, st.msg.Sprintf(messages.LayoutField, fieldName(f), typeString(pass, f.Type()), offsets[i], size))
+		end = offsets[i] + size
+	}
+
+	if padding := pass.TypesSizes.Sizeof(s) - end; padding > 0 {
+		parts = append(parts, st.msg.Sprintf(messages.LayoutPadding, padding))
+	}
+
+	return st.msg.Sprintf(messages.Layout, msg, typeString(pass, subject), strings.Join(parts, ", "))
+}
+
+// fieldName returns the name of a struct field, using "_" for blank fields.
+func fieldName(f *types.Var) string {
+	if f.Name() == "" {
+		return "_"
+	}
+
+	return f.Name()
+}
+
+// validateSeverities returns an error if the config names an unknown severity.
+func validateSeverities(c config.Config) error {
+	if c.DefaultSeverity != "" {
+		if err := severity.Validate(c.DefaultSeverity); err != nil {
+			return fmt.Errorf("invalid default-severity: %w", err)
+		}
+	}
+
+	for rule, sev := range c.Severity {
+		if err := severity.Validate(sev); err != nil {
+			return fmt.Errorf("invalid severity of %s: %w", rule, err)
+		}
+	}
+
+	if c.Tests.Severity != "" {
+		if err := severity.Validate(c.Tests.Severity); err != nil {
+			return fmt.Errorf("invalid tests.severity: %w", err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,132 @@
+package analyzer
+
+import (
+	"go/ast"
+	"go/token"
+	"go/types"
+
+	"golang.org/x/tools/go/analysis"
+
+	"github.com/mickamy/pointless/internal/fixsafety"
+	"github.com/mickamy/pointless/internal/messages"
+	"github.com/mickamy/pointless/internal/rules"
+)
+
+// checkDefinedAddresses checks the variables declared by p := &v, with v a
+// local struct variable (see checkAddressedLocal).
+func checkDefinedAddresses(pass *analysis.Pass, assign *ast.AssignStmt, st *state) {
+	if assign.Tok != token.DEFINE || len(assign.Lhs) != len(assign.Rhs) {
+		return
+	}
+
+	for i, lhs := range assign.Lhs {
+		if name, ok := lhs.(*ast.Ident); ok {
+			checkAddressedLocal(pass, name, assign.Rhs[i], st)
+		}
+	}
+}
+
+// checkDeclaredAddresses checks the variables declared by var p = &v, with v a
+// local struct variable (see checkAddressedLocal).
+func checkDeclaredAddresses(pass *analysis.Pass, vs *ast.ValueSpec, st *state) {
+	if len(vs.Names) != len(vs.Values) {
+		return
+	}
+
+	for i, name := range vs.Names {
+		checkAddressedLocal(pass, name, vs.Values[i], st)
+	}
+}
+
+// checkAddressedLocal checks the variable name initialized with value. If
+// value is &v with v a local struct variable, and name is only used through
+// field access and dereference (see localPointerUses), it is an alias of v
+// that every use could name directly.
+func checkAddressedLocal(pass *analysis.Pass, name *ast.Ident, value ast.Expr, st *state) {
+	addr, ok := ast.Unparen(value).(*ast.UnaryExpr)
+	if !ok || addr.Op != token.AND {
+		return
+	}
+
+	target, ok := ast.Unparen(addr.X).(*ast.Ident)
+	if !ok {
+		return
+	}
+
+	v, ok := pass.TypesInfo.Uses[target].(*types.Var)
+	if !ok || v.IsField() || v.Parent() == nil || v.Parent() == pass.Pkg.Scope() {
+		return
+	}
+
+	obj, ok := pass.TypesInfo.Defs[name].(*types.Var)
+	if !ok || !isLocalStructPointer(pass, obj) {
+		return
+	}
+
+	// Skip if the pointer is used as a pointer: passed on, stored, compared
+	if pos := st.localPointers.disqualified[obj]; pos.IsValid() {
+		st.explain.skipped(pass, name.Pos(), "%s relies on the pointer at line %d", name.Name, lineOf(pass, pos))
+
+		return
+	}
+
+	// Skip if the pointer is reassigned, so that it does not always point to the variable
+	if st.localPointers.initialized[obj] {
+		st.explain.skipped(pass, name.Pos(), "%s is reassigned with a new &T{...} value", name.Name)
+
+		return
+	}
+
+	t, size, ok := smallStructType(pass, st, name.Pos(), v.Type())
+	if !ok {
+		return
+	}
+
+	st.reportf(pass, t, rules.PointerRoundTrip, name, messages.PointerRoundTrip, name.Name, target.Name, target.Name, size, st.bounds())
+}
+
+// checkDereferencedAddress checks *&x expressions, which copy x through a
+// pointer that nothing else sees. The suggested fix drops the *&; it is left
+// out for *&T{...}, whose composite literal may need parentheses in the
+// header of an if, for, or switch statement.
+func checkDereferencedAddress(pass *analysis.Pass, star *ast.StarExpr, st *state) {
+	addr, ok := ast.Unparen(star.X).(*ast.UnaryExpr)
+	if !ok || addr.Op != token.AND {
+		return
+	}
+
+	tv, ok := pass.TypesInfo.Types[star]
+	if !ok || !tv.IsValue() {
+		return
+	}
+
+	t, size, ok := smallStructType(pass, st, star.Pos(), tv.Type)
+	if !ok {
+		return
+	}
+
+	operand := types.ExprString(addr.X)
+	d := analysis.Diagnostic{
+		Pos:      star.Pos(),
+		End:      star.End(),
+		Category: rules.PointerRoundTrip,
+		Message:  st.msg.Sprintf(messages.PointerRoundTripDeref, types.ExprString(star), operand, size, st.bounds()),
+	}
+
+	if _, isLit := ast.Unparen(addr.X).(*ast.CompositeLit); !isLit {
+		edits := []analysis.TextEdit{{Pos: star.Pos(), End: addr.X.Pos()}}
+
+		// Drop the closing parentheses of *(&x)
+		if addr.X.End() != star.End() {
+			edits = append(edits, analysis.TextEdit{Pos: addr.X.End(), End: star.End()})
+		}
+
+		evidence := fixsafety.Evidence{Level: fixsafety.Safe, Reasons: []string{st.msg.Sprintf(messages.FixDereferencedAddress)}}
+		d.SuggestedFixes = []analysis.SuggestedFix{{
+			Message:   fixsafety.Tag(st.msg.Sprintf(messages.PointerRoundTripFix, operand), evidence),
+			TextEdits: edits,
+		}}
+	}
+
+	st.report(pass, t, d)
+}
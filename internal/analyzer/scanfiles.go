@@ -0,0 +1,59 @@
+package analyzer
+
+import (
+	"runtime"
+	"sync"
+
+	"golang.org/x/tools/go/ast/inspector"
+)
+
+// scanWorkers bounds the goroutines scanning the files of a package.
+var scanWorkers = runtime.GOMAXPROCS(0)
+
+// scanFiles calls scan with the cursor of each file of the package on at most
+// scanWorkers goroutines, and returns the results in the order of the files.
+// Scans only read the syntax and type information of the package, so they may
+// run concurrently; merging their results in file order keeps the first use
+// found the same as that of a serial scan.
+func scanFiles[T any](inspect *inspector.Inspector, scan func(file inspector.Cursor) T) []T {
+	var files []inspector.Cursor
+	for file := range inspect.Root().Children() {
+		files = append(files, file)
+	}
+
+	results := make([]T, len(files))
+
+	workers := min(scanWorkers, len(files))
+	if workers <= 1 {
+		for i, file := range files {
+			results[i] = scan(file)
+		}
+
+		return results
+	}
+
+	next := make(chan int)
+
+	var wg sync.WaitGroup
+
+	wg.Add(workers)
+
+	for range workers {
+		go func() {
+			defer wg.Done()
+
+			for i := range next {
+				results[i] = scan(files[i])
+			}
+		}()
+	}
+
+	for i := range files {
+		next <- i
+	}
+
+	close(next)
+	wg.Wait()
+
+	return results
+}
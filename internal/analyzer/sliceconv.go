@@ -0,0 +1,323 @@
+package analyzer
+
+import (
+	"go/ast"
+	"go/token"
+	"go/types"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/ast/inspector"
+	"golang.org/x/tools/go/types/typeutil"
+
+	"github.com/mickamy/pointless/internal/messages"
+	"github.com/mickamy/pointless/internal/rules"
+)
+
+// sliceConversion is a helper function only converting []T to []*T, or []*T
+// to []T:
+//
+//	func toPointers(items []Item) []*Item {
+//		ptrs := make([]*Item, len(items))
+//		for i := range items {
+//			ptrs[i] = &items[i]
+//		}
+//
+//		return ptrs
+//	}
+type sliceConversion struct {
+	fn *types.Func
+	// elem is T.
+	elem types.Type
+	// toPointers is set for []T to []*T, and unset for []*T to []T.
+	toPointers bool
+	// out is the local variable holding the converted slice.
+	out types.Object
+	// calls are the calls of fn in the package, in source order.
+	calls []*ast.CallExpr
+}
+
+// sliceConversions records the slice conversion helpers of the package, if
+// the slice-conversion rule is enabled.
+type sliceConversions struct {
+	funcs map[*ast.FuncDecl]*sliceConversion
+	// results maps the variables holding converted slices to their helper.
+	results map[types.Object]*sliceConversion
+}
+
+// findSliceConversions finds the functions of the package whose body only
+// converts their slice parameter between []T and []*T, and their callers.
+func findSliceConversions(pass *analysis.Pass, inspect *inspector.Inspector) sliceConversions {
+	result := sliceConversions{funcs: make(map[*ast.FuncDecl]*sliceConversion), results: make(map[types.Object]*sliceConversion)}
+	byFunc := make(map[*types.Func]*sliceConversion)
+
+	inspect.Preorder([]ast.Node{(*ast.FuncDecl)(nil)}, func(n ast.Node) {
+		decl, ok := n.(*ast.FuncDecl)
+		if !ok {
+			return
+		}
+
+		if c := matchSliceConversion(pass, decl); c != nil {
+			result.funcs[decl] = c
+			result.results[c.out] = c
+			byFunc[c.fn] = c
+		}
+	})
+
+	if len(byFunc) == 0 {
+		return result
+	}
+
+	inspect.Preorder([]ast.Node{(*ast.CallExpr)(nil)}, func(n ast.Node) {
+		call, ok := n.(*ast.CallExpr)
+		if !ok {
+			return
+		}
+
+		if fn := typeutil.StaticCallee(pass.TypesInfo, call); fn != nil {
+			if c, ok := byFunc[fn.Origin()]; ok {
+				c.calls = append(c.calls, call)
+			}
+		}
+	})
+
+	return result
+}
+
+// inverse returns the first helper converting back what c converts, or nil.
+func (s sliceConversions) inverse(c *sliceConversion) *ast.FuncDecl {
+	var result *ast.FuncDecl
+
+	for decl, other := range s.funcs {
+		if other.toPointers == c.toPointers || !types.Identical(other.elem, c.elem) {
+			continue
+		}
+
+		if result == nil || decl.Pos() < result.Pos() {
+			result = decl
+		}
+	}
+
+	return result
+}
+
+// matchSliceConversion returns the conversion decl makes, or nil. The body
+// must be a declaration of the converted slice, a range loop over the
+// parameter adding each element's address (&in[i], &v) or value (*v, *in[i])
+// to it, and a return of it.
+func matchSliceConversion(pass *analysis.Pass, decl *ast.FuncDecl) *sliceConversion {
+	if decl.Recv != nil || decl.Body == nil || len(decl.Body.List) != 3 {
+		return nil
+	}
+
+	fn, ok := pass.TypesInfo.Defs[decl.Name].(*types.Func)
+	if !ok {
+		return nil
+	}
+
+	sig, ok := fn.Type().(*types.Signature)
+	if !ok || sig.Params().Len() != 1 || sig.Results().Len() != 1 || sig.TypeParams().Len() > 0 {
+		return nil
+	}
+
+	in := sig.Params().At(0)
+
+	from, ok := types.Unalias(in.Type()).(*types.Slice)
+	if !ok {
+		return nil
+	}
+
+	to, ok := types.Unalias(sig.Results().At(0).Type()).(*types.Slice)
+	if !ok {
+		return nil
+	}
+
+	c := &sliceConversion{fn: fn}
+
+	switch {
+	case isPointerTo(to.Elem(), from.Elem()):
+		c.elem, c.toPointers = from.Elem(), true
+	case isPointerTo(from.Elem(), to.Elem()):
+		c.elem = to.Elem()
+	default:
+		return nil
+	}
+
+	c.out = declaredSlice(pass, decl.Body.List[0])
+	if c.out == nil {
+		return nil
+	}
+
+	loop, ok := decl.Body.List[1].(*ast.RangeStmt)
+	if !ok || objectOf(pass, loop.X) != in || len(loop.Body.List) != 1 {
+		return nil
+	}
+
+	ret, ok := decl.Body.List[2].(*ast.ReturnStmt)
+	if !ok || len(ret.Results) != 1 || objectOf(pass, ret.Results[0]) != c.out {
+		return nil
+	}
+
+	var key, value types.Object
+	if loop.Key != nil {
+		key = objectOf(pass, loop.Key)
+	}
+
+	if loop.Value != nil {
+		value = objectOf(pass, loop.Value)
+	}
+
+	// isElement reports whether e is in[key] or the range value
+	isElement := func(e ast.Expr) bool {
+		if index, ok := ast.Unparen(e).(*ast.IndexExpr); ok {
+			return key != nil && objectOf(pass, index.X) == in && objectOf(pass, index.Index) == key
+		}
+
+		return value != nil && objectOf(pass, e) == value
+	}
+
+	added := addedElement(pass, loop.Body.List[0], c.out, key)
+	if added == nil {
+		return nil
+	}
+
+	if c.toPointers {
+		addr, ok := ast.Unparen(added).(*ast.UnaryExpr)
+		if !ok || addr.Op != token.AND || !isElement(addr.X) {
+			return nil
+		}
+	} else {
+		star, ok := ast.Unparen(added).(*ast.StarExpr)
+		if !ok || !isElement(star.X) {
+			return nil
+		}
+	}
+
+	return c
+}
+
+// isPointerTo reports whether ptr is an unnamed pointer to elem.
+func isPointerTo(ptr, elem types.Type) bool {
+	p, ok := types.Unalias(ptr).(*types.Pointer)
+
+	return ok && types.Identical(p.Elem(), elem)
+}
+
+// declaredSlice returns the local variable the statement stmt declares:
+// out := make(...), out := []T{}, or var out []T.
+func declaredSlice(pass *analysis.Pass, stmt ast.Stmt) types.Object {
+	switch s := stmt.(type) {
+	case *ast.AssignStmt:
+		if s.Tok != token.DEFINE || len(s.Lhs) != 1 || len(s.Rhs) != 1 {
+			return nil
+		}
+
+		switch rhs := ast.Unparen(s.Rhs[0]).(type) {
+		case *ast.CallExpr:
+			if !isBuiltinCall(pass, rhs, "make") {
+				return nil
+			}
+		case *ast.CompositeLit:
+			if len(rhs.Elts) > 0 {
+				return nil
+			}
+		default:
+			return nil
+		}
+
+		return objectOf(pass, s.Lhs[0])
+	case *ast.DeclStmt:
+		gen, ok := s.Decl.(*ast.GenDecl)
+		if !ok || gen.Tok != token.VAR || len(gen.Specs) != 1 {
+			return nil
+		}
+
+		vs, ok := gen.Specs[0].(*ast.ValueSpec)
+		if !ok || len(vs.Names) != 1 || len(vs.Values) > 0 {
+			return nil
+		}
+
+		return pass.TypesInfo.Defs[vs.Names[0]]
+	}
+
+	return nil
+}
+
+// addedElement returns the expression stmt adds to out: E of
+// out = append(out, E), or of out[key] = E.
+func addedElement(pass *analysis.Pass, stmt ast.Stmt, out, key types.Object) ast.Expr {
+	assign, ok := stmt.(*ast.AssignStmt)
+	if !ok || assign.Tok != token.ASSIGN || len(assign.Lhs) != 1 || len(assign.Rhs) != 1 {
+		return nil
+	}
+
+	if index, ok := ast.Unparen(assign.Lhs[0]).(*ast.IndexExpr); ok {
+		if key == nil || objectOf(pass, index.X) != out || objectOf(pass, index.Index) != key {
+			return nil
+		}
+
+		return assign.Rhs[0]
+	}
+
+	call, ok := ast.Unparen(assign.Rhs[0]).(*ast.CallExpr)
+	if !ok || objectOf(pass, assign.Lhs[0]) != out || !isBuiltinCall(pass, call, "append") || len(call.Args) != 2 || call.Ellipsis.IsValid() {
+		return nil
+	}
+
+	if objectOf(pass, call.Args[0]) != out {
+		return nil
+	}
+
+	return call.Args[1]
+}
+
+// checkSliceConversion checks fn, for the opt-in slice-conversion rule, if it
+// is a slice conversion helper: such adapters hint that the pointer form is
+// pointless on one side, and standardizing on []T removes them.
+func checkSliceConversion(pass *analysis.Pass, fn *ast.FuncDecl, st *state) {
+	if !st.ruleEnabled(rules.SliceConversion) {
+		return
+	}
+
+	c, ok := st.sliceConversions.funcs[fn]
+	if !ok {
+		return
+	}
+
+	t, size, ok := smallStructType(pass, st, fn.Name.Pos(), c.elem)
+	if !ok {
+		return
+	}
+
+	typeName := typeString(pass, t)
+
+	from, to := "[]"+typeName, "[]*"+typeName
+	if !c.toPointers {
+		from, to = to, from
+	}
+
+	d := analysis.Diagnostic{
+		Pos:      fn.Name.Pos(),
+		End:      fn.Name.End(),
+		Category: rules.SliceConversion,
+		Message:  st.msg.Sprintf(messages.SliceConversion, fn.Name.Name, from, to, typeName, size, st.bounds()),
+	}
+
+	if decl := st.sliceConversions.inverse(c); decl != nil {
+		d.Message = st.msg.Sprintf(messages.SliceConversionPair, d.Message, decl.Name.Name)
+		d.Related = append(d.Related, analysis.RelatedInformation{
+			Pos:     decl.Name.Pos(),
+			End:     decl.Name.End(),
+			Message: st.msg.Sprintf(messages.SliceConversionInverse, decl.Name.Name),
+		})
+	}
+
+	for _, call := range c.calls {
+		d.Related = append(d.Related, analysis.RelatedInformation{
+			Pos:     call.Pos(),
+			End:     call.End(),
+			Message: st.msg.Sprintf(messages.SliceConversionCall, types.ExprString(call)),
+		})
+	}
+
+	st.report(pass, t, d)
+}
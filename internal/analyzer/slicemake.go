@@ -0,0 +1,212 @@
+package analyzer
+
+import (
+	"go/ast"
+	"go/token"
+	"go/types"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/ast/inspector"
+)
+
+// sliceMakes maps make([]*T, ...) calls to the variable or field the slice is
+// stored in, where that is where its type is decided:
+//
+//	items := make([]*T, n)          // a new variable
+//	var items = make([]*T, n)       // a variable without a declared type
+//	Store{items: make([]*T, n)}     // a struct field
+//	s.items = make([]*T, n)         // a struct field
+//
+// Calls in other contexts are not recorded: returned slices are checked with
+// the result type, and variables declared as []*T with their declaration.
+type sliceMakes map[*ast.CallExpr]types.Object
+
+// findSliceMakes finds the pointer slice constructions of the package and where
+// they are stored.
+func findSliceMakes(pass *analysis.Pass, inspect *inspector.Inspector) sliceMakes {
+	result := make(sliceMakes)
+
+	inspect.WithStack([]ast.Node{(*ast.CallExpr)(nil)}, func(n ast.Node, push bool, stack []ast.Node) bool {
+		if !push {
+			return true
+		}
+
+		call, ok := n.(*ast.CallExpr)
+		if !ok || pointerSliceMake(pass, call) == nil {
+			return true
+		}
+
+		if obj := sliceDestination(pass, stack); obj != nil {
+			result[call] = obj
+		}
+
+		return true
+	})
+
+	return result
+}
+
+// pointerSliceMake returns the slice type of a make([]*T, ...) call, or nil.
+func pointerSliceMake(pass *analysis.Pass, call *ast.CallExpr) *ast.ArrayType {
+	ident, ok := ast.Unparen(call.Fun).(*ast.Ident)
+	if !ok || ident.Name != "make" || len(call.Args) < 1 {
+		return nil
+	}
+
+	if _, ok := pass.TypesInfo.Uses[ident].(*types.Builtin); !ok {
+		return nil
+	}
+
+	arr, ok := ast.Unparen(call.Args[0]).(*ast.ArrayType)
+	if !ok || arr.Len != nil {
+		return nil
+	}
+
+	if _, _, ok := pointerElem(pass, arr.Elt); !ok {
+		return nil
+	}
+
+	return arr
+}
+
+// sliceDestination returns the variable or field the expression at the top of
+// stack is stored in (see sliceMakes), or nil.
+func sliceDestination(pass *analysis.Pass, stack []ast.Node) types.Object {
+	node := stack[len(stack)-1]
+
+	i := len(stack) - 2
+	for i >= 0 {
+		if _, isParen := stack[i].(*ast.ParenExpr); !isParen {
+			break
+		}
+
+		node = stack[i]
+		i--
+	}
+
+	if i < 0 {
+		return nil
+	}
+
+	switch parent := stack[i].(type) {
+	case *ast.AssignStmt:
+		j := indexOf(parent.Rhs, node)
+		if j < 0 || len(parent.Lhs) != len(parent.Rhs) {
+			return nil
+		}
+
+		// Plain assignments to variables are checked with their declaration
+		if v, ok := objectOf(pass, parent.Lhs[j]).(*types.Var); ok && (parent.Tok == token.DEFINE || v.IsField()) {
+			return v
+		}
+	case *ast.ValueSpec:
+		if j := indexOf(parent.Values, node); j >= 0 && parent.Type == nil && j < len(parent.Names) {
+			return pass.TypesInfo.Defs[parent.Names[j]]
+		}
+	case *ast.KeyValueExpr:
+		if parent.Value != node || i == 0 {
+			return nil
+		}
+
+		if _, ok := stack[i-1].(*ast.CompositeLit); !ok {
+			return nil
+		}
+
+		if key, ok := parent.Key.(*ast.Ident); ok {
+			if v, ok := pass.TypesInfo.Uses[key].(*types.Var); ok && v.IsField() {
+				return v
+			}
+		}
+	case *ast.CompositeLit:
+		j := indexOf(parent.Elts, node)
+		if st, ok := underlyingStruct(pass.TypesInfo.TypeOf(parent)); ok && j >= 0 && j < st.NumFields() {
+			return st.Field(j)
+		}
+	}
+
+	return nil
+}
+
+// indexOf returns the index of node in exprs, or -1.
+func indexOf(exprs []ast.Expr, node ast.Node) int {
+	for i, e := range exprs {
+		if e == node {
+			return i
+		}
+	}
+
+	return -1
+}
+
+// underlyingStruct returns the struct type of a composite literal of type t,
+// possibly through a pointer.
+func underlyingStruct(t types.Type) (*types.Struct, bool) {
+	if t == nil {
+		return nil, false
+	}
+
+	if ptr, ok := t.Underlying().(*types.Pointer); ok {
+		t = ptr.Elem()
+	}
+
+	st, ok := t.Underlying().(*types.Struct)
+
+	return st, ok
+}
+
+// checkSliceMake checks a make([]*T, ...) call whose slice is stored in a
+// variable or field (see sliceMakes) whose elements are never nil.
+func checkSliceMake(pass *analysis.Pass, call *ast.CallExpr, st *state) {
+	obj, ok := st.sliceMakes[call]
+	if !ok {
+		return
+	}
+
+	arr := pointerSliceMake(pass, call)
+	elem, named, _ := pointerElem(pass, arr.Elt)
+
+	// Skip if the field declaration decides the type
+	if v, ok := obj.(*types.Var); ok && v.IsField() {
+		st.explain.skipped(pass, arr.Pos(), "the slice is stored in field %s, which is checked where it is declared", v.Name())
+
+		return
+	}
+
+	// The opt-in slice-conversion rule reports conversion helpers as a whole
+	if c, ok := st.sliceConversions.results[obj]; ok {
+		st.explain.skipped(pass, arr.Pos(), "%s converts a value slice, which the slice-conversion rule reports", c.fn.Name())
+
+		return
+	}
+
+	// The opt-in sort-pointer-slice rule covers slices only filled with value
+	// addresses to be sorted or iterated
+	if p, ok := st.sortProxies.covered(obj); ok && named == nil {
+		checkSortPointerSlice(pass, arr, elem, obj, p, st)
+
+		return
+	}
+
+	if pos := st.nils.element(obj); pos.IsValid() {
+		st.explain.skipped(pass, arr.Pos(), "elements are compared with or set to nil at line %d", lineOf(pass, pos))
+
+		return
+	}
+
+	if pos := st.goroutines.slice(obj); pos.IsValid() {
+		if named == nil {
+			checkSharedSlice(pass, arr, elem, obj, pos, st)
+		} else {
+			st.explain.skipped(pass, arr.Pos(), "elements are shared with a goroutine at line %d", lineOf(pass, pos))
+		}
+
+		return
+	}
+
+	t, size, ok := smallStructType(pass, st, arr.Pos(), elem)
+	if !ok {
+		return
+	}
+
+	reportSlicePointer(pass, st, arr, t, named, size, qualifiedTypeString)
+}
@@ -0,0 +1,384 @@
+package analyzer
+
+import (
+	"go/ast"
+	"go/token"
+	"go/types"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/ast/inspector"
+	"golang.org/x/tools/go/types/typeutil"
+
+	"github.com/mickamy/pointless/internal/messages"
+	"github.com/mickamy/pointless/internal/rules"
+)
+
+// sortProxy describes a function-scoped []*T variable (T a struct) filled
+// with the addresses of values, as in
+//
+//	ptrs := make([]*T, len(items))
+//	for i := range items {
+//		ptrs[i] = &items[i]
+//	}
+//
+//	sort.Slice(ptrs, func(i, j int) bool { return ptrs[i].Name < ptrs[j].Name })
+type sortProxy struct {
+	// source is the slice or array all elements point into, or nil if they
+	// come from several of them, from variables, or from &T{...}.
+	source types.Object
+	// built marks slices filled with value addresses at least once.
+	built bool
+	// sorted is the first sort.Slice-like call sorting the slice.
+	sorted token.Pos
+	// iterated is the first range statement over the slice.
+	iterated token.Pos
+	// disqualified is the first use relying on the pointers beyond sorting
+	// and reading through them.
+	disqualified token.Pos
+}
+
+// sortProxies maps the local []*T variables whose elements point at values
+// to how they are used, if the sort-pointer-slice rule is enabled.
+type sortProxies map[types.Object]*sortProxy
+
+// covered returns the sort proxy of obj if it is only built from values, and
+// sorted or iterated: the uses a []T copy or an index slice can take over.
+func (s sortProxies) covered(obj types.Object) (*sortProxy, bool) {
+	p, ok := s[obj]
+	if !ok || !p.built || p.disqualified.IsValid() || (!p.sorted.IsValid() && !p.iterated.IsValid()) {
+		return nil, false
+	}
+
+	return p, true
+}
+
+// findSortProxies scans every use of local []*T variables (see sortProxy).
+// Besides filling the slice with value addresses (append(ptrs, &items[i]),
+// ptrs[i] = &v, &T{...}), uses that a []T or an index slice can take over are
+// sorting it with sort.Slice, sort.SliceStable, slices.SortFunc, or
+// slices.SortStableFunc, ranging over it, len and cap, and reading through
+// its elements (ptrs[i].F, *ptrs[i]). Range variables must only be used like
+// values too; see localPointers. Writes through the elements disqualify the
+// slice, since they update the values pointed to and not a copy.
+func findSortProxies(pass *analysis.Pass, inspect *inspector.Inspector, localPointers localPointerUses) sortProxies {
+	result := make(sortProxies)
+
+	// rangeValues maps the value variables of range statements over the
+	// slices to the slice
+	rangeValues := make(map[types.Object]types.Object)
+
+	inspect.WithStack([]ast.Node{(*ast.Ident)(nil)}, func(n ast.Node, push bool, stack []ast.Node) bool {
+		if !push {
+			return true
+		}
+
+		ident, ok := n.(*ast.Ident)
+		if !ok {
+			return true
+		}
+
+		obj, ok := pass.TypesInfo.Uses[ident].(*types.Var)
+		if !ok || !isLocalStructPointerSlice(pass, obj) {
+			return true
+		}
+
+		p, ok := result[obj]
+		if !ok {
+			p = &sortProxy{}
+			result[obj] = p
+		}
+
+		if !p.disqualified.IsValid() && !classifySortProxyUse(pass, obj, p, stack, localPointers, rangeValues) {
+			p.disqualified = ident.Pos()
+		}
+
+		return true
+	})
+
+	disqualify := func(e ast.Expr, pos token.Pos) {
+		if index, ok := ast.Unparen(e).(*ast.IndexExpr); ok {
+			e = index.X
+		}
+
+		obj := objectOf(pass, e)
+		if slice, ok := rangeValues[obj]; ok {
+			obj = slice
+		}
+
+		if p, ok := result[obj]; ok && !p.disqualified.IsValid() {
+			p.disqualified = pos
+		}
+	}
+
+	forEachPointerWrite(pass, inspect, func(e ast.Expr, pos token.Pos) {
+		if p := writtenPointer(pass, e); p != nil {
+			disqualify(p, pos)
+		}
+	})
+
+	// p.Update() may write through p as well
+	inspect.Preorder([]ast.Node{(*ast.CallExpr)(nil)}, func(n ast.Node) {
+		sel, ok := ast.Unparen(n.(*ast.CallExpr).Fun).(*ast.SelectorExpr)
+		if !ok {
+			return
+		}
+
+		if selection, ok := pass.TypesInfo.Selections[sel]; ok && selection.Kind() == types.MethodVal && hasPointerReceiver(selection.Obj()) {
+			disqualify(sel.X, n.Pos())
+		}
+	})
+
+	return result
+}
+
+// isLocalStructPointerSlice reports whether v is a function-scoped variable
+// of type []*T where T is a struct.
+func isLocalStructPointerSlice(pass *analysis.Pass, v *types.Var) bool {
+	if v.IsField() || v.Parent() == nil || v.Parent() == pass.Pkg.Scope() {
+		return false
+	}
+
+	s, ok := types.Unalias(v.Type()).(*types.Slice)
+	if !ok {
+		return false
+	}
+
+	ptr, ok := types.Unalias(s.Elem()).(*types.Pointer)
+	if !ok {
+		return false
+	}
+
+	_, ok = ptr.Elem().Underlying().(*types.Struct)
+
+	return ok
+}
+
+// classifySortProxyUse records the use of obj at the top of stack in p, and
+// reports whether a []T or an index slice could take it over.
+func classifySortProxyUse(pass *analysis.Pass, obj *types.Var, p *sortProxy, stack []ast.Node, localPointers localPointerUses, rangeValues map[types.Object]types.Object) bool {
+	node, i := unparenParent(stack, len(stack)-1)
+	if i < 0 {
+		return false
+	}
+
+	elem := obj.Type().Underlying().(*types.Slice).Elem().(*types.Pointer).Elem()
+
+	switch parent := stack[i].(type) {
+	case *ast.AssignStmt:
+		// ptrs = append(ptrs, ...) and ptrs = make(...): the append itself is
+		// classified with its first argument
+		j := indexOf(parent.Lhs, node)
+		if j < 0 || len(parent.Lhs) != len(parent.Rhs) {
+			return false
+		}
+
+		call, ok := ast.Unparen(parent.Rhs[j]).(*ast.CallExpr)
+		if !ok {
+			return false
+		}
+
+		return isBuiltinCall(pass, call, "make") || (isBuiltinCall(pass, call, "append") && len(call.Args) > 0 && objectOf(pass, call.Args[0]) == obj)
+	case *ast.CallExpr:
+		switch {
+		case isBuiltinCall(pass, parent, "len"), isBuiltinCall(pass, parent, "cap"):
+			return true
+		case isSortCall(pass, parent) && parent.Args[0] == node:
+			if !p.sorted.IsValid() {
+				p.sorted = parent.Pos()
+			}
+
+			return true
+		case isBuiltinCall(pass, parent, "append") && parent.Args[0] == node && !parent.Ellipsis.IsValid():
+			if i == 0 {
+				return false
+			}
+
+			assign, ok := stack[i-1].(*ast.AssignStmt)
+			if !ok || len(assign.Lhs) != len(assign.Rhs) {
+				return false
+			}
+
+			if j := indexOf(assign.Rhs, parent); j < 0 || objectOf(pass, assign.Lhs[j]) != obj {
+				return false
+			}
+
+			for _, arg := range parent.Args[1:] {
+				if !p.addValueAddress(pass, arg, elem) {
+					return false
+				}
+			}
+
+			return true
+		}
+	case *ast.RangeStmt:
+		if parent.X != node {
+			return false
+		}
+
+		if value, ok := parent.Value.(*ast.Ident); ok {
+			v := objectOf(pass, value)
+			if v != nil && localPointers.disqualified[v].IsValid() {
+				return false
+			}
+
+			if v != nil {
+				rangeValues[v] = obj
+			}
+		} else if parent.Value != nil {
+			return false
+		}
+
+		if !p.iterated.IsValid() {
+			p.iterated = parent.Pos()
+		}
+
+		return true
+	case *ast.IndexExpr:
+		if parent.X != node {
+			return false
+		}
+
+		return classifySortProxyElement(pass, p, stack[:i+1], elem)
+	}
+
+	return false
+}
+
+// classifySortProxyElement classifies the use of the element index (ptrs[i])
+// at the top of stack: reading through it, or setting it to a value address.
+func classifySortProxyElement(pass *analysis.Pass, p *sortProxy, stack []ast.Node, elem types.Type) bool {
+	node, i := unparenParent(stack, len(stack)-1)
+	if i < 0 {
+		return false
+	}
+
+	switch parent := stack[i].(type) {
+	case *ast.SelectorExpr:
+		return parent.X == node
+	case *ast.StarExpr:
+		return true
+	case *ast.AssignStmt:
+		j := indexOf(parent.Lhs, node)
+		if j < 0 || parent.Tok != token.ASSIGN || len(parent.Lhs) != len(parent.Rhs) {
+			return false
+		}
+
+		return p.addValueAddress(pass, parent.Rhs[j], elem)
+	}
+
+	return false
+}
+
+// addValueAddress records an element added to the slice, and reports whether
+// it is the address of a value of type elem: &items[i] of a []T or [n]T, &v
+// of a local T variable, or &T{...}.
+func (p *sortProxy) addValueAddress(pass *analysis.Pass, expr ast.Expr, elem types.Type) bool {
+	addr, ok := ast.Unparen(expr).(*ast.UnaryExpr)
+	if !ok || addr.Op != token.AND {
+		return false
+	}
+
+	var source types.Object
+
+	switch x := ast.Unparen(addr.X).(type) {
+	case *ast.CompositeLit:
+	case *ast.Ident:
+		v, ok := pass.TypesInfo.Uses[x].(*types.Var)
+		if !ok || v.IsField() || v.Parent() == nil || v.Parent() == pass.Pkg.Scope() || !types.Identical(v.Type(), elem) {
+			return false
+		}
+	case *ast.IndexExpr:
+		v, ok := objectOf(pass, x.X).(*types.Var)
+		if !ok || !hasElem(v.Type(), elem) {
+			return false
+		}
+
+		source = v
+	default:
+		return false
+	}
+
+	if !p.built {
+		p.source = source
+	} else if p.source != source {
+		p.source = nil
+	}
+
+	p.built = true
+
+	return true
+}
+
+// hasElem reports whether t is a slice or array of elem.
+func hasElem(t types.Type, elem types.Type) bool {
+	switch u := t.Underlying().(type) {
+	case *types.Slice:
+		return types.Identical(u.Elem(), elem)
+	case *types.Array:
+		return types.Identical(u.Elem(), elem)
+	}
+
+	return false
+}
+
+// unparenParent returns the outermost parenthesized expression around
+// stack[i] and the index of its parent in stack, or -1.
+func unparenParent(stack []ast.Node, i int) (ast.Node, int) {
+	node := stack[i]
+
+	i--
+	for i >= 0 {
+		if _, isParen := stack[i].(*ast.ParenExpr); !isParen {
+			break
+		}
+
+		node = stack[i]
+		i--
+	}
+
+	return node, i
+}
+
+// isBuiltinCall reports whether call calls the builtin function name.
+func isBuiltinCall(pass *analysis.Pass, call *ast.CallExpr, name string) bool {
+	fn, ok := typeutil.Callee(pass.TypesInfo, call).(*types.Builtin)
+
+	return ok && fn.Name() == name
+}
+
+// isSortCall reports whether call sorts its first argument with a less or
+// compare function: sort.Slice, sort.SliceStable, slices.SortFunc, or
+// slices.SortStableFunc.
+func isSortCall(pass *analysis.Pass, call *ast.CallExpr) bool {
+	fn, ok := typeutil.Callee(pass.TypesInfo, call).(*types.Func)
+	if !ok || fn.Pkg() == nil || len(call.Args) != 2 {
+		return false
+	}
+
+	switch fn.Pkg().Path() {
+	case "sort":
+		return fn.Name() == "Slice" || fn.Name() == "SliceStable"
+	case "slices":
+		return fn.Name() == "SortFunc" || fn.Name() == "SortStableFunc"
+	}
+
+	return false
+}
+
+// checkSortPointerSlice reports the []*T slice arr stored in obj, a sort
+// proxy (see sortProxies), for the opt-in sort-pointer-slice rule.
+func checkSortPointerSlice(pass *analysis.Pass, arr *ast.ArrayType, elem types.Type, obj types.Object, p *sortProxy, st *state) {
+	t, size, ok := smallStructType(pass, st, arr.Pos(), elem)
+	if !ok {
+		return
+	}
+
+	typeName := qualifiedTypeString(pass, t)
+
+	msg := st.msg.Sprintf(messages.SortPointerSlice, typeName, typeName, obj.Name(), size, st.bounds())
+	if p.source != nil {
+		msg = st.msg.Sprintf(messages.SortPointerSliceSource, p.source.Name(), typeName, obj.Name(), p.source.Name(), size, st.bounds())
+	}
+
+	st.report(pass, t, analysis.Diagnostic{Pos: arr.Pos(), End: arr.End(), Category: rules.SortPointerSlice, Message: msg})
+}
@@ -0,0 +1,57 @@
+package analyzer
+
+import (
+	"go/token"
+	"go/types"
+
+	"golang.org/x/tools/go/analysis"
+
+	"github.com/mickamy/pointless/internal/messages"
+	"github.com/mickamy/pointless/internal/rules"
+)
+
+// strict reports the cases the heuristics cannot decide, like -enable=needs-review.
+var strict bool
+
+// needsReview reports the pointer at pos as needing manual review, with the
+// reason formatted from id, if the needs-review rule is enabled. Checks call it
+// where they would otherwise skip a pointer they could not analyze.
+func (st *state) needsReview(pass *analysis.Pass, pos token.Pos, id messages.ID, args ...any) {
+	if !st.ruleEnabled(rules.NeedsReview) {
+		return
+	}
+
+	reason := st.msg.Sprintf(id, args...)
+	st.report(pass, nil, analysis.Diagnostic{Pos: pos, Category: rules.NeedsReview, Message: st.msg.Sprintf(messages.NeedsReview, reason)})
+}
+
+// hasFixedSize reports whether t has the same size in every instantiation, that
+// is, its layout does not depend on a type parameter.
+func hasFixedSize(t types.Type) bool {
+	return fixedSize(t, make(map[types.Type]bool))
+}
+
+func fixedSize(t types.Type, seen map[types.Type]bool) bool {
+	if seen[t] {
+		return true
+	}
+
+	seen[t] = true
+
+	switch t := types.Unalias(t).(type) {
+	case *types.TypeParam:
+		return false
+	case *types.Named:
+		return fixedSize(t.Underlying(), seen)
+	case *types.Array:
+		return fixedSize(t.Elem(), seen)
+	case *types.Struct:
+		for i := range t.NumFields() {
+			if !fixedSize(t.Field(i).Type(), seen) {
+				return false
+			}
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,199 @@
+package analyzer
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"log/slog"
+	"regexp"
+
+	"golang.org/x/tools/go/analysis"
+
+	"github.com/mickamy/pointless/internal/symbol"
+)
+
+// span is a half-open source range [pos, end).
+type span struct {
+	pos, end token.Pos
+}
+
+// containsPos reports whether any span contains pos.
+func containsPos(spans []span, pos token.Pos) bool {
+	for _, s := range spans {
+		if s.pos <= pos && pos < s.end {
+			return true
+		}
+	}
+
+	return false
+}
+
+// findIgnoredSymbols returns the source ranges of functions, methods, and
+// package-level variables listed in the ignore-symbols config.
+func findIgnoredSymbols(pass *analysis.Pass, symbols []string) []span {
+	if len(symbols) == 0 {
+		return nil
+	}
+
+	ignored := make(map[string]bool, len(symbols))
+	for _, s := range symbols {
+		ignored[s] = true
+	}
+
+	matches := func(names []string) bool {
+		for _, name := range names {
+			for _, qualified := range qualifySymbol(pass, name) {
+				if ignored[qualified] {
+					return true
+				}
+			}
+		}
+
+		return false
+	}
+
+	var result []span
+
+	for _, f := range pass.Files {
+		for _, decl := range f.Decls {
+			switch d := decl.(type) {
+			case *ast.FuncDecl:
+				if names := symbol.FuncNames(d); matches(names) {
+					slog.Debug("symbol ignored", "symbol", names[0], "pos", pass.Fset.Position(d.Pos()))
+
+					result = append(result, span{d.Pos(), d.End()})
+				}
+			case *ast.GenDecl:
+				if d.Tok != token.VAR {
+					continue
+				}
+
+				// A single, ungrouped declaration covers the var keyword too
+				ungrouped := len(d.Specs) == 1
+
+				for _, spec := range d.Specs {
+					vs, ok := spec.(*ast.ValueSpec)
+					if !ok {
+						continue
+					}
+
+					for _, name := range vs.Names {
+						if !matches([]string{name.Name}) {
+							continue
+						}
+
+						slog.Debug("symbol ignored", "symbol", name.Name, "pos", pass.Fset.Position(name.Pos()))
+
+						if ungrouped {
+							result = append(result, span{d.Pos(), d.End()})
+						} else {
+							result = append(result, span{vs.Pos(), vs.End()})
+						}
+
+						break
+					}
+				}
+			}
+		}
+	}
+
+	return result
+}
+
+// qualifySymbol returns name as written unqualified, qualified with the
+// package name, and qualified with the package path.
+func qualifySymbol(pass *analysis.Pass, name string) []string {
+	return []string{
+		name,
+		pass.Pkg.Name() + "." + name,
+		pass.Pkg.Path() + "." + name,
+	}
+}
+
+// symbolFilter limits findings to the symbols matching include-symbols, and
+// drops those in symbols matching ignore-symbols-regex. Findings outside of
+// functions, methods, and package-level variables belong to no symbol: they
+// are dropped with include-symbols and kept otherwise.
+type symbolFilter struct {
+	include, ignore *regexp.Regexp
+}
+
+// newSymbolFilter compiles the include-symbols and ignore-symbols-regex
+// expressions, or returns nil if neither is set.
+func newSymbolFilter(include, ignore string) (*symbolFilter, error) {
+	if include == "" && ignore == "" {
+		return nil, nil
+	}
+
+	f := &symbolFilter{}
+
+	if include != "" {
+		re, err := regexp.Compile(include)
+		if err != nil {
+			return nil, fmt.Errorf("invalid include-symbols: %w", err)
+		}
+
+		f.include = re
+	}
+
+	if ignore != "" {
+		re, err := regexp.Compile(ignore)
+		if err != nil {
+			return nil, fmt.Errorf("invalid ignore-symbols-regex: %w", err)
+		}
+
+		f.ignore = re
+	}
+
+	return f, nil
+}
+
+// filtered reports whether the finding at pos is dropped by the filter, and
+// explains why. Symbols match if any of their names (see symbol.FuncNames),
+// unqualified or qualified with the package name or path, matches.
+func (f *symbolFilter) filtered(pass *analysis.Pass, st *state, pos token.Pos) bool {
+	if f == nil {
+		return false
+	}
+
+	var names []string
+
+	for _, file := range pass.Files {
+		if file.FileStart <= pos && pos <= file.FileEnd {
+			names = symbol.EnclosingNames(file, pos)
+
+			break
+		}
+	}
+
+	matches := func(re *regexp.Regexp) bool {
+		for _, name := range names {
+			for _, qualified := range qualifySymbol(pass, name) {
+				if re.MatchString(qualified) {
+					return true
+				}
+			}
+		}
+
+		return false
+	}
+
+	name := "(none)"
+	if len(names) > 0 {
+		name = names[0]
+	}
+
+	if f.include != nil && !matches(f.include) {
+		st.explain.skipped(pass, pos, "symbol %s does not match include-symbols %s", name, f.include)
+
+		return true
+	}
+
+	if f.ignore != nil && matches(f.ignore) {
+		st.explain.skipped(pass, pos, "symbol %s matches ignore-symbols-regex %s", name, f.ignore)
+
+		return true
+	}
+
+	return false
+}
@@ -1,4 +1,6 @@
-package a
+package a // want package:"interfaces"
+
+import "context"
 
 // SmallStruct is a small struct (32 bytes on 64-bit)
 type SmallStruct struct {
@@ -15,6 +17,20 @@ type LargeStruct struct {
 	Field3 [512]byte
 }
 
+// Registry stores SmallStruct pointers by identity.
+type Registry struct {
+	items []*SmallStruct
+}
+
+func (r *Registry) Add(s *SmallStruct) { // want Add:"mutatesReceiver"
+	r.items = append(r.items, s)
+}
+
+// Node holds a pointer back to its owner.
+type Node struct {
+	owner *SmallStruct
+}
+
 // --- Return type checks ---
 
 func GetSmallStruct() *SmallStruct { // want "consider returning value instead of pointer: SmallStruct is .* bytes"
@@ -52,15 +68,30 @@ func (s *SmallStruct) FullName() string { // want "consider using value receiver
 }
 
 // OK: mutates receiver
-func (s *SmallStruct) SetName(name string) {
+func (s *SmallStruct) SetName(name string) { // want SetName:"mutatesReceiver"
 	s.Name = name
 }
 
 // OK: mutates receiver field
-func (s *SmallStruct) IncrementAge() {
+func (s *SmallStruct) IncrementAge() { // want IncrementAge:"mutatesReceiver"
 	s.Age++
 }
 
+// OK: receiver identity is stored in a registry
+func (s *SmallStruct) Register(r *Registry) {
+	r.Add(s)
+}
+
+// OK: receiver identity is stored in a field
+func (s *SmallStruct) Attach(n *Node) {
+	n.owner = s
+}
+
+// OK: receiver identity is stored in a composite literal
+func (s *SmallStruct) Wrap() Node {
+	return Node{owner: s}
+}
+
 // OK: struct is large
 func (l *LargeStruct) GetField1() []byte {
 	return l.Field1[:]
@@ -115,3 +146,45 @@ func GetSmallStructIgnore() *SmallStruct {
 func GetSmallStructBlanket() *SmallStruct {
 	return &SmallStruct{}
 }
+
+//nolint:pointless // until=2999-12-31 waiting for API v2
+func GetSmallStructUntil() *SmallStruct {
+	return &SmallStruct{}
+}
+
+//nolint:pointless // until=2000-01-01 expired // want "stale suppression: nolint expired on 2000-01-01"
+func GetSmallStructExpired() *SmallStruct { // want "consider returning value instead of pointer"
+	return &SmallStruct{}
+}
+
+//nolint:pointless // until=someday // want `invalid nolint expiration "someday": expected until=YYYY-MM-DD`
+func GetSmallStructMalformed() *SmallStruct {
+	return &SmallStruct{}
+}
+
+func nilUsageInRange() {
+	// OK: range value compared with nil
+	items := make([]*SmallStruct, 10)
+	for _, it := range items {
+		if it == nil {
+			return
+		}
+	}
+
+	// OK: range value compared with nil
+	var others []*SmallStruct
+	for _, o := range others {
+		if nil != o {
+			return
+		}
+	}
+}
+
+// --- Opt-in rules ---
+
+type requestKey struct{}
+
+// OK: context-value is not enabled
+func withRequest(ctx context.Context) context.Context {
+	return context.WithValue(ctx, requestKey{}, &SmallStruct{})
+}
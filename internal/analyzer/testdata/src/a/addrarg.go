@@ -0,0 +1,83 @@
+package a
+
+type Settings struct {
+	Title   string
+	Retries int
+}
+
+// Flagged: only read, and every caller passes an address
+func render(s *Settings) string { // want `consider accepting s Settings instead of \*Settings: render only reads it and all 2 callers pass the address of a value` render:"readOnlyParams\\(0\\)"
+	return s.Title
+}
+
+func useRender() {
+	var s Settings
+	_ = render(&s)
+	_ = render(&Settings{Title: "x"})
+}
+
+// Flagged: only read, and the only caller passes an address
+func summarize(s *Settings) int { // want `consider accepting s Settings instead of \*Settings: summarize only reads it and its only caller passes the address of a value` summarize:"readOnlyParams\\(0\\)"
+	return s.Retries
+}
+
+func useSummarize() {
+	var s Settings
+	_ = summarize(&s)
+}
+
+// OK: mutates through the pointer
+func applyDefaults(s *Settings) {
+	if s.Retries == 0 {
+		s.Retries = 3
+	}
+}
+
+func useApplyDefaults() {
+	var s Settings
+	applyDefaults(&s)
+}
+
+// OK: a caller passes an existing pointer
+func describe(s *Settings) string { // want describe:"readOnlyParams\\(0\\)"
+	return s.Title
+}
+
+func useDescribe(p *Settings) { // want useDescribe:"readOnlyParams\\(0\\)"
+	var s Settings
+	_ = describe(&s)
+	_ = describe(p)
+}
+
+// OK: deferred calls read the value later
+func logSettings(s *Settings) { // want logSettings:"readOnlyParams\\(0\\)"
+	_ = s.Title
+}
+
+func useLogSettings() {
+	var s Settings
+	defer logSettings(&s)
+	s.Title = "changed"
+}
+
+// OK: used as a value
+func format(s *Settings) string { // want format:"readOnlyParams\\(0\\)"
+	return s.Title
+}
+
+var formatter func(*Settings) string = format
+
+func useFormat() {
+	var s Settings
+	_ = format(&s)
+}
+
+// OK: exported
+func Render(s *Settings) string { // want Render:"readOnlyParams\\(0\\)"
+	return s.Title
+}
+
+func useExported() {
+	var s Settings
+	_ = Render(&s)
+}
@@ -0,0 +1,42 @@
+package a
+
+// Request is built by chaining its methods.
+type Request struct {
+	method string
+	path   string
+	limit  int
+}
+
+// OK: returns the receiver for chaining
+func (r *Request) WithMethod(method string) *Request { // want WithMethod:"mutatesReceiver"
+	r.method = method
+
+	return r
+}
+
+// OK: returns the receiver for chaining, on every path
+func (r *Request) WithLimit(n int) *Request { // want WithLimit:"mutatesReceiver"
+	if n < 0 {
+		return r
+	}
+
+	r.limit = n
+
+	return r
+}
+
+// Flagged: returns a new request rather than its receiver
+func (r *Request) Clone() *Request { // want "consider using value receiver: Request is .* bytes" "consider returning value instead of pointer: Request is .* bytes"
+	c := *r
+
+	return &c
+}
+
+// Flagged: only returns its receiver on some paths
+func (r *Request) Or(other *Request) *Request { // want "consider using value receiver: Request is .* bytes" "consider returning value instead of pointer: Request is .* bytes"
+	if r.path == "" {
+		return other
+	}
+
+	return r
+}
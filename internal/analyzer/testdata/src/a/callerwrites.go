@@ -0,0 +1,66 @@
+package a
+
+// Counter is shared by the callers of current.
+type Counter struct {
+	Name  string
+	Count int
+}
+
+var counters = map[string]*Counter{"a": {Name: "a"}}
+
+// OK: callers increment the shared counter
+func current() *Counter {
+	return counters["a"]
+}
+
+func hit() {
+	c := current()
+	c.Count++
+}
+
+// OK: the result is written to at once
+func latest() *Counter {
+	return counters["a"]
+}
+
+func reset() {
+	latest().Count = 0
+}
+
+// OK: a caller writes through the result, held with an error
+func lookup(name string) (*Counter, error) {
+	return &Counter{Name: name}, nil
+}
+
+func rename() error {
+	var c, err = lookup("a")
+	if err != nil {
+		return err
+	}
+
+	*c = Counter{Name: "b"}
+
+	return nil
+}
+
+// Flagged: callers only read the result
+func snapshot() *Counter { // want "consider returning value instead of pointer: Counter is .* bytes"
+	return &Counter{Name: "a"}
+}
+
+func total() int {
+	c := snapshot()
+
+	return c.Count
+}
+
+// Flagged: the write goes to a copy
+func fresh() *Counter { // want "consider returning Counter instead of a pointer: its only caller dereferences"
+	return &Counter{}
+}
+
+func touch() {
+	c := *fresh()
+	c.Count++
+	_ = c
+}
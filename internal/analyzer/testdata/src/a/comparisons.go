@@ -0,0 +1,40 @@
+package a
+
+type Span struct {
+	Start, End int
+}
+
+// Flagged, with a note: callers compare the returned pointers
+func newSpan(start, end int) *Span { // want `consider returning value instead of pointer: Span is 16 bytes \(threshold: 1024 bytes\); note: pointers to Span are compared at line 13, which would compare values instead of identities`
+	return &Span{Start: start, End: end}
+}
+
+func sameSpan() bool {
+	return newSpan(0, 1) == newSpan(0, 1)
+}
+
+type Batch struct {
+	IDs []int
+}
+
+// OK: the pointers are compared, and Batch values cannot be
+func newBatch() *Batch {
+	return &Batch{}
+}
+
+func sameBatch(a, b *Batch) bool {
+	return a == b
+}
+
+type Window struct {
+	IDs []int
+}
+
+// Flagged: Window is not comparable, but its pointers are only compared with nil
+func newWindow() *Window { // want `consider returning value instead of pointer: Window is 24 bytes \(threshold: 1024 bytes\); its zero value Window\{\} is ready to use$`
+	return &Window{}
+}
+
+func hasWindow(w *Window) bool {
+	return w != nil
+}
@@ -0,0 +1,33 @@
+package a
+
+func clearShared() {
+	sharedItems[0] = nil
+}
+
+// --- Nil usage through helpers ---
+
+func clearFirst(items []*SmallStruct) {
+	items[0] = nil
+}
+
+func clearVia(items []*SmallStruct) {
+	clearFirst(items)
+}
+
+func nilUsageThroughHelpers() {
+	// OK: clearFirst sets elements to nil
+	items := make([]*SmallStruct, 10)
+	clearFirst(items)
+
+	// OK: clearVia sets elements to nil through clearFirst
+	others := make([]*SmallStruct, 10)
+	clearVia(others)
+
+	// Flagged: the helper only reads elements
+	read := make([]*SmallStruct, 10) // want "consider using \\[\\]a.SmallStruct instead of \\[\\]\\*a.SmallStruct"
+	countItems(read)
+}
+
+func countItems(items []*SmallStruct) int {
+	return len(items)
+}
@@ -0,0 +1,51 @@
+package a
+
+type Coord struct {
+	Lat, Lng float64
+}
+
+// Flagged with callers: every caller dereferences the result
+func origin() *Coord { // want `consider returning Coord instead of a pointer: all 3 callers dereference the result immediately`
+	return &Coord{}
+}
+
+func useOrigin() []Coord {
+	c := *origin()
+	all := []Coord{*(origin())}
+
+	return append(all, c, *origin())
+}
+
+// Flagged without callers: a caller keeps the pointer
+func home() *Coord { // want `consider returning value instead of pointer: Coord is 16 bytes`
+	return &Coord{Lat: 1}
+}
+
+func useHome() {
+	_ = *home()
+	keep(home())
+}
+
+func keep(c *Coord) {
+	c.Lat++
+}
+
+// OK: a caller writes through the result
+func work() *Coord {
+	return &Coord{Lng: 1}
+}
+
+func useWork() {
+	_ = *work()
+	(*work()).Lat = 2
+}
+
+// Flagged without callers: used as a value
+func away() *Coord { // want `consider returning value instead of pointer: Coord is 16 bytes`
+	return &Coord{}
+}
+
+func useAway() {
+	f := away
+	_ = *f()
+}
@@ -0,0 +1,48 @@
+package a
+
+// --- []*T struct fields ---
+
+type Catalog struct {
+	Items    []*SmallStruct // want "consider using \\[\\]a.SmallStruct instead of \\[\\]\\*a.SmallStruct"
+	Large    []*LargeStruct // OK: struct is large
+	Optional []*SmallStruct // OK: elements are compared with nil
+	Cleared  []*SmallStruct // OK: a helper sets elements to nil
+	Shared   []*Ticker      // OK: elements are shared with a goroutine
+	Owned    []*SmallStruct // OK: stores pointers owned elsewhere
+
+	nested struct {
+		entries []*SmallStruct // want "consider using \\[\\]a.SmallStruct instead of \\[\\]\\*a.SmallStruct"
+	}
+}
+
+// Appending fresh allocations does not share identity
+func (c *Catalog) AddItem(name string) { // want AddItem:"mutatesReceiver"
+	c.Items = append(c.Items, &SmallStruct{Name: name})
+	c.nested.entries = append(c.nested.entries, new(SmallStruct))
+}
+
+func countOptional(c Catalog) int {
+	n := 0
+
+	for _, it := range c.Optional {
+		if it != nil {
+			n++
+		}
+	}
+
+	return n
+}
+
+func clearCatalog(c Catalog) {
+	clearFirst(c.Cleared)
+}
+
+func watchShared(c Catalog, out chan<- int) {
+	for i := range c.Shared {
+		go c.Shared[i].Watch(out)
+	}
+}
+
+func (c *Catalog) Adopt(s *SmallStruct) { // want Adopt:"mutatesReceiver"
+	c.Owned = append(c.Owned, s)
+}
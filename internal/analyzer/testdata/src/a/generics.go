@@ -0,0 +1,20 @@
+package a
+
+// OK: the size of a generic struct depends on its type arguments
+type Box[T any] struct {
+	Value T
+	Count int
+}
+
+func (b *Box[T]) Get() T {
+	return b.Value
+}
+
+func NewBox[T any](v T) *Box[T] {
+	return &Box[T]{Value: v}
+}
+
+// OK: what *T points to depends on the instantiation
+func Ref[T any](v T) *T {
+	return &v
+}
@@ -0,0 +1,54 @@
+package a
+
+// --- Pointers shared with goroutines ---
+
+type Ticker struct {
+	Count int
+}
+
+// OK: the goroutine must observe the caller's Ticker
+func (t *Ticker) Watch(out chan<- int) {
+	go func() {
+		out <- t.Count
+	}()
+}
+
+// OK: the goroutine runs with the same receiver
+func (t *Ticker) Start(out chan<- int) {
+	go t.Watch(out)
+}
+
+func (t *Ticker) Current() int { // want "consider using value receiver: Ticker is .* bytes"
+	return t.Count
+}
+
+func localPointerInGoroutine(done chan struct{}) {
+	// OK: captured by the goroutine
+	var p *Ticker
+	p = &Ticker{}
+
+	go func() {
+		_ = p.Count
+		close(done)
+	}()
+}
+
+func pointerSliceInGoroutine(out chan<- int) {
+	// OK: element pointers are handed to goroutines
+	items := make([]*Ticker, 10)
+	for i := range items {
+		go items[i].Watch(out)
+	}
+
+	// OK: range values are captured by goroutines
+	others := make([]*Ticker, 10)
+	for _, it := range others {
+		go func() {
+			out <- it.Count
+		}()
+	}
+
+	// Flagged: not shared
+	local := make([]*Ticker, 10) // want "consider using \\[\\]a.Ticker instead of \\[\\]\\*a.Ticker"
+	_ = local
+}
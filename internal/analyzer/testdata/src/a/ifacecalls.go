@@ -0,0 +1,31 @@
+package a
+
+// --- Interface call checks ---
+
+// Gauge is only ever used through anonymous interfaces.
+type Gauge struct {
+	value int
+}
+
+func (g *Gauge) Set(v int) { // want Set:"mutatesReceiver"
+	g.value = v
+}
+
+// OK: called through an interface whose dynamic type can only be *Gauge
+func (g *Gauge) Read() int {
+	return g.value
+}
+
+func (g *Gauge) Describe() string { // want "consider using value receiver: Gauge is .* bytes"
+	return "gauge"
+}
+
+func readGauge(v any) int {
+	if r, ok := v.(interface {
+		Set(int)
+		Read() int
+	}); ok {
+		return r.Read()
+	}
+	return 0
+}
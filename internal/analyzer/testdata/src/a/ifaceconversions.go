@@ -0,0 +1,83 @@
+package a
+
+import (
+	"fmt"
+	"io"
+)
+
+// --- Interface conversion checks ---
+
+// Label only implements fmt.Stringer as *Label, which nothing holds.
+type Label struct {
+	text string
+	hits int
+}
+
+func (l *Label) Hit() { // want Hit:"mutatesReceiver"
+	l.hits++
+}
+
+// Reported: fmt.Stringer being in the dependency graph is not enough
+func (l *Label) String() string { // want "consider using value receiver: Label is .* bytes"
+	return l.text
+}
+
+// Handle is returned as an io.Closer.
+type Handle struct {
+	fd     int
+	closed bool
+}
+
+func (h *Handle) Release() { // want Release:"mutatesReceiver"
+	h.closed = true
+}
+
+// OK: *Handle is returned as an io.Closer below
+func (h *Handle) Close() error {
+	return fmt.Errorf("close %d", h.fd)
+}
+
+func openHandle(fd int) io.Closer {
+	return &Handle{fd: fd}
+}
+
+// Title is stored in a slice of fmt.Stringer.
+type Title struct {
+	text string
+	n    int
+}
+
+func (t *Title) Bump() { // want Bump:"mutatesReceiver"
+	t.n++
+}
+
+// OK: *Title is an element of a []fmt.Stringer below
+func (t *Title) String() string {
+	return t.text
+}
+
+var titles = []fmt.Stringer{&Title{text: "a"}}
+
+// Badge is asserted from a fmt.Stringer in a type switch.
+type Badge struct {
+	text string
+	n    int
+}
+
+func (b *Badge) Bump() { // want Bump:"mutatesReceiver"
+	b.n++
+}
+
+// OK: *Badge is a case of the type switch on a fmt.Stringer below
+func (b *Badge) String() string {
+	return b.text
+}
+
+func badgeText(s fmt.Stringer) string {
+	switch s := s.(type) {
+	case *Badge:
+		return s.text
+	default:
+		return s.String()
+	}
+}
@@ -0,0 +1,53 @@
+package a
+
+// --- Variables declared by if, for, and switch init statements ---
+
+func initStatements(items []SmallStruct, x any) int64 {
+	var total int64
+
+	if p := (&SmallStruct{ID: 1}); p.Age > 0 { // want "consider declaring p := SmallStruct{...} instead of a pointer: it is only initialized with &SmallStruct{...} and dereferenced"
+		total += p.ID
+	}
+
+	for p, i := new(SmallStruct), 0; i < len(items); i++ { // want "consider declaring p := SmallStruct{...} instead of a pointer"
+		p.Age += items[i].Age
+		total += int64(p.Age)
+	}
+
+	switch p := (&SmallStruct{}); { // want "consider declaring p := SmallStruct{...} instead of a pointer"
+	case p.ID > 0:
+		total++
+	}
+
+	switch p := (&SmallStruct{ID: 2}); v := x.(type) { // want "consider declaring p := SmallStruct{...} instead of a pointer"
+	case int:
+		total += int64(v) + p.ID
+	}
+
+	// OK: compared with nil
+	if p := (&SmallStruct{}); p != nil {
+		total++
+	}
+
+	// OK: an existing pointer
+	if p := GetSmallStruct(); p.ID > 0 {
+		total++
+	}
+
+	// OK: passed on as a pointer
+	if p := (&SmallStruct{}); p.ID == 0 {
+		keepPointer(p)
+	}
+
+	// OK: struct is large
+	if p := (&LargeStruct{}); p.Field1[0] != 0 {
+		total++
+	}
+
+	// Pointer slices made in init statements are checked like other make calls
+	if s := make([]*SmallStruct, 0, len(items)); len(s) == 0 { // want "consider using \\[\\]a.SmallStruct instead of \\[\\]\\*a.SmallStruct"
+		total += int64(cap(s))
+	}
+
+	return total
+}
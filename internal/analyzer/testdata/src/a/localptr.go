@@ -0,0 +1,52 @@
+package a
+
+// --- Local pointer variable checks ---
+
+func localPointerVars(cond bool) int64 {
+	var p *SmallStruct // want "consider declaring var p SmallStruct instead of \\*SmallStruct: it is only initialized with &SmallStruct{...} and dereferenced"
+	p = &SmallStruct{ID: 1}
+	p.Age = 3
+
+	var q = new(SmallStruct) // OK: no explicit type
+	_ = q
+
+	var r *SmallStruct = &SmallStruct{} // want "consider declaring var r SmallStruct instead of \\*SmallStruct"
+	use(*r)
+
+	// OK: compared with nil
+	var s *SmallStruct
+	if cond {
+		s = &SmallStruct{}
+	}
+	if s == nil {
+		return 0
+	}
+
+	// OK: passed on as a pointer
+	var t *SmallStruct
+	t = &SmallStruct{}
+	keepPointer(t)
+
+	// OK: initialized from an existing pointer
+	var u *SmallStruct = GetSmallStruct()
+	_ = u.ID
+
+	// OK: never allocated
+	var v *SmallStruct
+	_ = v
+
+	// OK: struct is large
+	var w *LargeStruct
+	w = &LargeStruct{}
+	_ = w.Field1
+
+	return p.ID + s.ID
+}
+
+func use(s SmallStruct) {
+	_ = s
+}
+
+func keepPointer(s *SmallStruct) {
+	_ = s
+}
@@ -0,0 +1,78 @@
+package a
+
+import "sync"
+
+type Cell struct {
+	X, Y int
+}
+
+// Flagged: set of small comparable values keyed by pointer
+type Visited map[*Cell]bool // want `consider using Cell as the map key instead of \*Cell: pointer keys compare by identity, not by value`
+
+type Grid struct {
+	cells map[*Cell]string // want `consider using Cell as the map key instead of \*Cell`
+}
+
+func countCells(seen map[*Cell]int) int { // want `consider using Cell as the map key instead of \*Cell`
+	return len(seen)
+}
+
+func newGrid() Grid {
+	// The field is checked where it is declared
+	return Grid{cells: make(map[*Cell]string)}
+}
+
+func localCells() int {
+	m := make(map[*Cell]bool)           // want `consider using Cell as the map key instead of \*Cell`
+	var n = map[string]map[*Cell]bool{} // want `consider using Cell as the map key instead of \*Cell`
+
+	return len(m) + len(n)
+}
+
+// OK: nodes are written through pointers, so pointers identify them
+type Vertex struct {
+	ID      int
+	Visited bool
+}
+
+func markVisited(v *Vertex) {
+	v.Visited = true
+}
+
+var vertices map[*Vertex]bool
+
+// OK: guarded by a mutex locked through the pointer
+type Guarded struct {
+	mu sync.Mutex
+	n  int
+}
+
+func guardedLen(g *Guarded) int {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	return g.n
+}
+
+var guards map[*Guarded]struct{}
+
+// OK: equality compares the pointer field anyway
+type Linked struct {
+	Name string
+	Next *Linked
+}
+
+var linked map[*Linked]bool
+
+// OK: not comparable
+type Tagged struct {
+	Tags []string
+}
+
+var tagged map[*Tagged]int
+
+// OK: declared in another package
+var groups map[*sync.WaitGroup]int
+
+// OK: value keys
+var cells map[Cell]bool
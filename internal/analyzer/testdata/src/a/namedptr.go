@@ -0,0 +1,42 @@
+package a
+
+// --- Aliases and defined pointer types ---
+
+type SmallRef = *SmallStruct
+
+type SmallHandle *SmallStruct
+
+type LargeRef = *LargeStruct
+
+func NewSmallRef() SmallRef { // want "consider returning value instead of pointer: SmallRef is \\*SmallStruct and SmallStruct is .* bytes"
+	return &SmallStruct{}
+}
+
+func NewSmallHandle() SmallHandle { // want "consider returning value instead of pointer: SmallHandle is \\*SmallStruct and SmallStruct is .* bytes"
+	return &SmallStruct{}
+}
+
+// OK: may return nil
+func FindSmallRef(ok bool) SmallRef {
+	if !ok {
+		return nil
+	}
+
+	return &SmallStruct{}
+}
+
+// OK: too large
+func NewLargeRef() LargeRef {
+	return &LargeStruct{}
+}
+
+func ListSmallRefs() []SmallRef { // want "consider using \\[\\]SmallStruct instead of \\[\\]SmallRef \\(\\*SmallStruct\\)"
+	return []SmallRef{}
+}
+
+var smallHandles []SmallHandle // want "consider using \\[\\]a.SmallStruct instead of \\[\\]a.SmallHandle \\(\\*a.SmallStruct\\)"
+
+func makeSmallRefs() {
+	refs := make([]SmallRef, 10) // want "consider using \\[\\]a.SmallStruct instead of \\[\\]a.SmallRef \\(\\*a.SmallStruct\\)"
+	_ = refs
+}
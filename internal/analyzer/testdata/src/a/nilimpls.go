@@ -0,0 +1,40 @@
+package a
+
+// Token is a lexical token.
+type Token struct {
+	Kind int
+	Text string
+}
+
+// Source produces tokens, returning nil at the end of the input.
+type Source interface {
+	Next() *Token
+}
+
+type emptySource struct{}
+
+func (emptySource) Next() *Token { // want Next:"returnsNil"
+	return nil
+}
+
+type fixedSource struct {
+	tok Token
+}
+
+// OK: emptySource.Next returns nil, so callers of Source handle nil
+func (s *fixedSource) Next() *Token {
+	return &s.tok
+}
+
+var _ Source = (*fixedSource)(nil)
+
+// Lexer is not a Source: its Next takes an argument.
+type Lexer struct {
+	tok Token
+}
+
+func (l Lexer) Next(int) *Token { // want "consider returning value instead of pointer: Token is .* bytes"
+	t := l.tok
+
+	return &t
+}
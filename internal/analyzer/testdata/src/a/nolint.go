@@ -0,0 +1,58 @@
+package a
+
+// --- Nolint on multi-line declarations ---
+
+// OK: the comment on the declaration line covers results on later lines
+func lookupPair( //nolint:pointless
+	key string,
+) (
+	*SmallStruct,
+	*SmallStruct,
+) {
+	return &SmallStruct{}, &SmallStruct{}
+}
+
+// OK: the comment at the end of the signature covers the whole declaration
+func lookupTrailing(
+	key string,
+) (*SmallStruct, error) { //nolint:pointless
+	return &SmallStruct{}, nil
+}
+
+// OK: the comment above covers the statements in the body too
+//
+//nolint:pointless
+func buildAll() {
+	items := make([]*SmallStruct, 10)
+	_ = items
+}
+
+var (
+	//nolint:pointless
+	suppressedItems []*SmallStruct
+
+	flaggedItems []*SmallStruct // want "consider using \\[\\]a.SmallStruct instead of \\[\\]\\*a.SmallStruct"
+)
+
+// The comment on a field covers that field only
+type nolintFields struct {
+	suppressed []*SmallStruct //nolint:pointless
+	flagged    []*SmallStruct // want "consider using \\[\\]a.SmallStruct instead of \\[\\]\\*a.SmallStruct"
+
+	//nolint:pointless
+	above []*SmallStruct
+	below []*SmallStruct // want "consider using \\[\\]a.SmallStruct instead of \\[\\]\\*a.SmallStruct"
+
+	nested struct {
+		inner []*SmallStruct //nolint:pointless
+		other []*SmallStruct // want "consider using \\[\\]a.SmallStruct instead of \\[\\]\\*a.SmallStruct"
+	}
+}
+
+func nolintInBody() *SmallStruct { // want "consider returning value instead of pointer"
+	//nolint:pointless
+	items := make([]*SmallStruct, 10)
+	_ = items
+
+	return &SmallStruct{}
+}
@@ -0,0 +1,61 @@
+package a
+
+// --- Constructors taking *Options ---
+
+type ClientOptions struct {
+	Timeout int
+	Retries int
+}
+
+type Client struct {
+	timeout int
+}
+
+func (o *ClientOptions) setDefaults() {
+	o.Retries = 3
+}
+
+// Flagged: only fields are read
+func NewClient(opts *ClientOptions) Client { // want "consider accepting opts ClientOptions instead of \\*ClientOptions: NewClient never mutates or nil-checks it" NewClient:"readOnlyParams\\(0\\)"
+	return Client{timeout: opts.Timeout}
+}
+
+// OK: nil means defaults
+func NewClientOrDefault(opts *ClientOptions) Client {
+	if opts == nil {
+		return Client{}
+	}
+
+	return Client{timeout: opts.Timeout}
+}
+
+// OK: writes to the caller's options
+func NewClientWithDefaults(opts *ClientOptions) Client {
+	if opts.Timeout == 0 {
+		opts.Timeout = 30
+	}
+
+	return Client{timeout: opts.Timeout}
+}
+
+// OK: pointer-receiver method may mutate the options
+func NewClientSetDefaults(opts *ClientOptions) Client {
+	opts.setDefaults()
+
+	return Client{timeout: opts.Timeout}
+}
+
+// OK: passed on
+func NewClientDelegate(opts *ClientOptions) Client { // want NewClientDelegate:"readOnlyParams\\(0\\)"
+	return NewClient(opts)
+}
+
+// OK: not a constructor
+func dial(opts *ClientOptions) Client { // want dial:"readOnlyParams\\(0\\)"
+	return Client{timeout: opts.Timeout}
+}
+
+// OK: not an options struct
+func NewFromSmall(s *SmallStruct) Client { // want NewFromSmall:"readOnlyParams\\(0\\)"
+	return Client{timeout: int(s.ID)}
+}
@@ -0,0 +1,40 @@
+package a
+
+// --- Parenthesized type expressions ---
+
+// gofmt drops some of these parentheses, but generated code may keep them.
+
+func newParenPointer() (*SmallStruct) { // want "consider returning value instead of pointer"
+	return &SmallStruct{}
+}
+
+func parenElemSlice() []*(SmallStruct) { // want "consider using \\[\\]SmallStruct instead of \\[\\]\\*SmallStruct"
+	return []*SmallStruct{}
+}
+
+func parenPointerSlice() [](*SmallStruct) { // want "consider using \\[\\]SmallStruct instead of \\[\\]\\*SmallStruct"
+	return []*SmallStruct{}
+}
+
+func parenSlice() ([]*SmallStruct) { // want "consider using \\[\\]SmallStruct instead of \\[\\]\\*SmallStruct"
+	return []*SmallStruct{}
+}
+
+func parenTuple() (s (*SmallStruct), ok bool) { // want "consider returning value instead of pointer"
+	return &SmallStruct{}, true
+}
+
+type parenReceiver struct {
+	n int
+}
+
+func (p (*parenReceiver)) get() int { // want "consider using value receiver"
+	return p.n
+}
+
+var parenItems ([]*SmallStruct) // want "consider using \\[\\]a.SmallStruct instead of \\[\\]\\*a.SmallStruct"
+
+func parenMake() {
+	items := make(([]*SmallStruct), 0) // want "consider using \\[\\]a.SmallStruct instead of \\[\\]\\*a.SmallStruct"
+	_ = items
+}
@@ -0,0 +1,68 @@
+package a
+
+// --- Pointer to reference type checks ---
+
+func CountKeys(m *map[string]int) int { // want "consider using map\\[string\\]int instead of \\*map\\[string\\]int: maps are already reference types"
+	return len(*m)
+}
+
+func Drain(ch *chan int) { // want "consider using chan int instead of \\*chan int: channels are already reference types"
+	for range *ch {
+	}
+}
+
+func Apply(f *func(int) int, v int) int { // want "consider using func\\(int\\) int instead of \\*func\\(int\\) int: functions are already reference types"
+	return (*f)(v)
+}
+
+func Labels() *[]string { // want "consider using \\[\\]string instead of \\*\\[\\]string: slices are already reference types"
+	s := []string{"a"}
+	return &s
+}
+
+// OK: appends through the pointer
+func AppendTo(buf *[]byte, b byte) {
+	*buf = append(*buf, b)
+}
+
+// OK: replaces the map
+func ResetMap(m *map[string]int) {
+	*m = map[string]int{}
+}
+
+// OK: pointer is optional
+func MaybeLen(s *[]int) int {
+	if s == nil {
+		return 0
+	}
+	return len(*s)
+}
+
+// OK: pointer is passed on
+func Forward(s *[]int) {
+	AppendInts(s)
+}
+
+func AppendInts(s *[]int) {
+	*s = append(*s, 1)
+}
+
+// OK: may return nil
+func FindLabels(ok bool) *[]string {
+	if !ok {
+		return nil
+	}
+	s := []string{}
+	return &s
+}
+
+func refVars() {
+	var m *map[string]int // want "consider using map\\[string\\]int instead of \\*map\\[string\\]int: maps are already reference types"
+	_ = len(*m)
+
+	// OK: compared with nil
+	var s *[]int
+	if s == nil {
+		return
+	}
+}
@@ -0,0 +1,18 @@
+package a
+
+// --- Nil usage tracking regressions ---
+
+func shadowedSlices() {
+	items := make([]*SmallStruct, 10) // want "consider using \\[\\]a.SmallStruct instead of \\[\\]\\*a.SmallStruct"
+	{
+		// OK: the shadowing variable's elements are compared with nil
+		items := make([]*SmallStruct, 10)
+		if items[0] == nil {
+			return
+		}
+	}
+	_ = items
+}
+
+// OK: elements are set to nil in crossfile.go
+var sharedItems []*SmallStruct
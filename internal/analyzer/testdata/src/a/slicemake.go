@@ -0,0 +1,54 @@
+package a
+
+// --- make([]*T, ...) outside short variable declarations ---
+
+var registry = make([]*SmallStruct, 0, 8) // want "consider using \\[\\]a.SmallStruct instead of \\[\\]\\*a.SmallStruct"
+
+// Slices stored in fields are reported where the field is declared
+type itemStore struct {
+	items  []*SmallStruct // want "consider using \\[\\]a.SmallStruct instead of \\[\\]\\*a.SmallStruct"
+	spares []*SmallStruct // want "consider using \\[\\]a.SmallStruct instead of \\[\\]\\*a.SmallStruct"
+	slots  []*SmallStruct // OK: elements are set to nil
+}
+
+func varWithInitializer(n int) int {
+	var items = make([]*SmallStruct, 0, n) // want "consider using \\[\\]a.SmallStruct instead of \\[\\]\\*a.SmallStruct"
+
+	return len(items) + len(registry)
+}
+
+func keyedStructLiteral(n int) itemStore {
+	return itemStore{items: make([]*SmallStruct, n)}
+}
+
+func positionalStructLiteral(n int) itemStore {
+	return itemStore{make([]*SmallStruct, n), nil, nil}
+}
+
+func fieldAssignment(s *itemStore, n int) {
+	s.spares = make([]*SmallStruct, n)
+}
+
+func fieldWithNilElements(s *itemStore, n int) {
+	s.slots = make([]*SmallStruct, n)
+	s.slots[0] = nil
+}
+
+// OK: the type is decided by the result type
+func returnedSlice(n int) []SmallStruct {
+	_ = make([]*SmallStruct, n)
+
+	return make([]SmallStruct, n)
+}
+
+// Flagged once, at the declared type that decides it
+func declaredType(n int) {
+	var items []*SmallStruct // want "consider using \\[\\]a.SmallStruct instead of \\[\\]\\*a.SmallStruct"
+	items = make([]*SmallStruct, n)
+	_ = items
+}
+
+// OK: passed on to a function
+func passedOn(n int) int {
+	return len(make([]*SmallStruct, n))
+}
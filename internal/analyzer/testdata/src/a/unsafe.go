@@ -0,0 +1,105 @@
+package a
+
+import (
+	"reflect"
+	"unsafe"
+)
+
+// --- Pointers converted with unsafe.Pointer ---
+
+type Header struct {
+	Len int
+	Cap int
+}
+
+type Pinned struct {
+	ID int
+}
+
+type pinnedRef = *Pinned
+
+func addressOf(h *Header) uintptr {
+	return uintptr(unsafe.Pointer(h))
+}
+
+func pinnedAt(p unsafe.Pointer) pinnedRef {
+	return (*Pinned)(p)
+}
+
+// OK: Header pointers are converted to uintptr
+func NewHeader() *Header {
+	return &Header{}
+}
+
+// OK: Header pointers are converted to uintptr
+func (h *Header) Size() int {
+	return h.Len
+}
+
+// OK: Pinned pointers are created from unsafe.Pointer
+func NewPinned() *Pinned {
+	return &Pinned{}
+}
+
+// OK: Pinned pointers are created from unsafe.Pointer
+func listPinned() {
+	items := make([]*Pinned, 10)
+	_ = items
+}
+
+// --- Types whose layout is inspected ---
+
+type Packet struct {
+	Kind uint8
+	Len  uint32
+}
+
+type Frame struct {
+	Seq  int
+	Body [16]byte
+}
+
+type Record struct {
+	ID int
+}
+
+type Entry struct {
+	Key int
+}
+
+type Slot struct {
+	N int
+}
+
+var (
+	packetSize  = unsafe.Sizeof(Packet{})
+	frameOffset = unsafe.Offsetof(Frame{}.Body)
+	recordType  = reflect.TypeOf((*Record)(nil)).Elem()
+	entryType   = reflect.TypeFor[Entry]()
+	slotPtrSize = unsafe.Sizeof(&Slot{})
+)
+
+// OK: the layout of Packet is inspected with unsafe.Sizeof
+func NewPacket() *Packet {
+	return &Packet{}
+}
+
+// OK: the layout of Frame is inspected with unsafe.Offsetof
+func NewFrame() *Frame {
+	return &Frame{}
+}
+
+// OK: Record is inspected with reflect.TypeOf
+func NewRecord() *Record {
+	return &Record{}
+}
+
+// OK: Entry is inspected with reflect.TypeFor
+func NewEntry() *Entry {
+	return &Entry{}
+}
+
+// Flagged: only the size of a pointer to Slot is taken
+func NewSlot() *Slot { // want "consider returning value instead of pointer: Slot is 8 bytes"
+	return &Slot{}
+}
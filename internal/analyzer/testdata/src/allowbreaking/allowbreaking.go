@@ -0,0 +1,23 @@
+package allowbreaking
+
+type Point struct {
+	X, Y int
+}
+
+// Origin returns a Point with -allow-breaking, although importers break.
+func Origin() *Point { // want `consider returning Point instead of a pointer: its only caller dereferences the result immediately`
+	return &Point{}
+}
+
+func unit() *Point { // want `consider returning Point instead of a pointer: its only caller dereferences the result immediately`
+	p := Point{X: 1, Y: 1}
+
+	return &p
+}
+
+func sum() Point {
+	o := *Origin()
+	u := *unit()
+
+	return Point{X: o.X + u.X, Y: o.Y + u.Y}
+}
@@ -0,0 +1,46 @@
+package atomiccounter
+
+import "sync"
+
+type Stats struct {
+	name string
+	mu   sync.Mutex
+	hits int64
+	miss int64
+}
+
+func (s *Stats) hit() { // want `^\[info\] consider sync/atomic counters: hit only updates the integer counters hits of its \*Stats receiver`
+	s.mu.Lock()
+	s.hits++
+	s.mu.Unlock()
+}
+
+func (s *Stats) record(hit bool, n int64) { // want `counters hits, miss of its \*Stats receiver`
+	if hit {
+		s.hits += n
+	} else {
+		s.miss += n
+	}
+}
+
+func (s *Stats) reset() { // want `counters hits, miss of its`
+	s.hits = 0
+	s.miss = 0
+}
+
+// OK: writes a field that is no counter
+func (s *Stats) rename(name string) {
+	s.name = name
+	s.hits = 0
+}
+
+// OK: sets the counter to anything but zero
+func (s *Stats) set(n int64) {
+	s.hits = n
+}
+
+// OK: calls a mutating method
+func (s *Stats) hitAndReset() {
+	s.hits++
+	s.reset()
+}
@@ -0,0 +1,64 @@
+package b
+
+import (
+	"ifaces"
+	"mid"
+)
+
+// Counter satisfies ifaces.Resetter only via *Counter, but never references it.
+type Counter struct {
+	name string
+	n    int
+}
+
+func (c *Counter) Reset() { // want Reset:"mutatesReceiver"
+	c.n = 0
+}
+
+// Reported: implementing ifaces.Resetter is not enough, *Counter is never held through it
+func (c *Counter) Name() string { // want "consider using value receiver: Counter is .* bytes"
+	return c.name
+}
+
+// Timer satisfies ifaces.Resetter only via *Timer, and is passed as one.
+type Timer struct {
+	name string
+	n    int
+}
+
+func (t *Timer) Reset() { // want Reset:"mutatesReceiver"
+	t.n = 0
+}
+
+// OK: Name is part of ifaces.Resetter, which *Timer is passed as below
+func (t *Timer) Name() string {
+	return t.name
+}
+
+func resetTimers(a, b *Timer) {
+	mid.ResetAll(a, b)
+}
+
+func (c *Counter) Value() int { // want "consider using value receiver: Counter is .* bytes"
+	return mid.Count(c.n)
+}
+
+// FixedCache implements ifaces.Cache, whose implementation by mid.MapCache
+// returns nil.
+type FixedCache struct {
+	entry ifaces.Entry
+}
+
+// OK: mid.MapCache.Get returns nil, so callers of ifaces.Cache handle it
+func (c FixedCache) Get(string) *ifaces.Entry {
+	e := c.entry
+
+	return &e
+}
+
+// Indexed has a Get method of its own, which no interface requires.
+type Indexed struct{}
+
+func (Indexed) Get(int) *ifaces.Entry { // want "consider returning value"
+	return &ifaces.Entry{}
+}
@@ -0,0 +1,36 @@
+package builder
+
+// Query is built by chaining its methods.
+type Query struct {
+	table string
+	limit int
+}
+
+func NewQuery(table string) *Query { // want "consider returning value instead of pointer: Query is .* bytes"
+	return &Query{table: table}
+}
+
+func (q *Query) Limit(n int) *Query { // want `consider a value builder: Limit returns its \*Query receiver for chaining; as func \(Query\) Limit\(\.\.\.\) Query` Limit:"mutatesReceiver"
+	q.limit = n
+
+	return q
+}
+
+func (q *Query) From(table string) *Query { // want "consider a value builder: From returns its [*]Query receiver" From:"mutatesReceiver"
+	q.table = table
+
+	return q
+}
+
+// Large is built by chaining too, but is over the threshold.
+type Large struct {
+	buf [2048]byte
+	n   int
+}
+
+// OK: Large is too large
+func (l *Large) Grow(n int) *Large { // want Grow:"mutatesReceiver"
+	l.n += n
+
+	return l
+}
@@ -0,0 +1,12 @@
+package buildtags
+
+type Item struct {
+	V int
+}
+
+// The nil assignment in excluded.go is not part of the build.
+var items []*Item // want "consider using \\[\\]buildtags.Item instead of \\[\\]\\*buildtags.Item"
+
+func First() Item {
+	return *items[0]
+}
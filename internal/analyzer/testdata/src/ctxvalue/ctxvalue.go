@@ -0,0 +1,37 @@
+package ctxvalue
+
+import "context"
+
+type requestKey struct{}
+
+type RequestInfo struct {
+	ID   int
+	Path string
+}
+
+type Large struct {
+	Data [2048]byte
+}
+
+func withRequest(ctx context.Context, id int) context.Context {
+	return context.WithValue(ctx, requestKey{}, &RequestInfo{ID: id}) // want "consider storing RequestInfo instead of \\*RequestInfo in the context"
+}
+
+func withNew(ctx context.Context) context.Context {
+	return context.WithValue(ctx, requestKey{}, new(RequestInfo)) // want "consider storing RequestInfo instead of \\*RequestInfo in the context"
+}
+
+// OK: the value is stored as a value
+func withValue(ctx context.Context, id int) context.Context {
+	return context.WithValue(ctx, requestKey{}, RequestInfo{ID: id})
+}
+
+// OK: an existing pointer may be shared on purpose
+func withExisting(ctx context.Context, info *RequestInfo) context.Context {
+	return context.WithValue(ctx, requestKey{}, info)
+}
+
+// OK: too large
+func withLarge(ctx context.Context) context.Context {
+	return context.WithValue(ctx, requestKey{}, &Large{})
+}
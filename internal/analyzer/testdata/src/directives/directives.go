@@ -0,0 +1,71 @@
+package directives
+
+// Handle is compared by identity by its users.
+//
+//pointless:ignore-type
+type Handle struct {
+	ID    int
+	Peers []*Handle
+}
+
+func NewHandle() *Handle {
+	return &Handle{}
+}
+
+func (h *Handle) Name() int {
+	return h.ID
+}
+
+type (
+	// Token is opaque.
+	//pointless:ignore-type
+	Token struct {
+		V int
+	}
+
+	Other struct {
+		V int
+	}
+)
+
+func NewToken() *Token {
+	return &Token{}
+}
+
+func NewOther() *Other { // want "consider returning value instead of pointer"
+	return &Other{}
+}
+
+type Trailing struct { //pointless:ignore-type
+	V int
+}
+
+func NewTrailing() *Trailing {
+	return &Trailing{}
+}
+
+// The file directive of generated.go covers that file alone
+func MakeGenerated() *Generated { // want "consider returning value instead of pointer"
+	return &Generated{}
+}
+
+// A directive above a declaration covers all of it
+//
+//pointless:ignore
+func Multi(
+	a int,
+) (
+	*Other,
+	error,
+) {
+	return &Other{V: a}, nil
+}
+
+//pointless:ignore-type // until=2000-01-01 // want "stale suppression: nolint expired on 2000-01-01"
+type Expired struct {
+	V int
+}
+
+func NewExpired() *Expired { // want "consider returning value instead of pointer"
+	return &Expired{}
+}
@@ -0,0 +1,11 @@
+//pointless:ignore-file generated by a tool, not worth editing by hand
+
+package directives
+
+type Generated struct {
+	X int
+}
+
+func NewGenerated() *Generated {
+	return &Generated{}
+}
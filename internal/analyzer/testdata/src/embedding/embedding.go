@@ -0,0 +1,119 @@
+package embedding // want package:"interfaces"
+
+import "embeddingdep"
+
+// T is a small struct.
+type T struct {
+	x int
+}
+
+// A is an alias of T.
+type A = T
+
+// Methods on an alias are reported for the aliased type
+func (a *A) X() int { // want "consider using value receiver: T is .* bytes"
+	return a.x
+}
+
+// Base is embedded by value in Outer.
+type Base struct {
+	n int
+}
+
+// OK: promoted to Outer, which implements Counter only as *Outer
+func (b *Base) N() int {
+	return b.n
+}
+
+// Reported: no interface of Outer requires it
+func (b *Base) Twice() int { // want "consider using value receiver: Base is .* bytes"
+	return b.n * 2
+}
+
+// Counter is implemented by *Outer.
+type Counter interface {
+	N() int
+	Inc()
+}
+
+// Outer embeds Base by value.
+type Outer struct {
+	Base
+}
+
+func (o *Outer) Inc() { // want Inc:"mutatesReceiver"
+	o.n++
+}
+
+var _ Counter = (*Outer)(nil)
+
+// Shadowed is embedded by Shadowing, which declares its own Load.
+type Shadowed struct {
+	v int
+}
+
+// Reported: Shadowing.Load shadows it
+func (s *Shadowed) Load() int { // want "consider using value receiver: Shadowed is .* bytes"
+	return s.v
+}
+
+// Shadowing implements embeddingdep.Store with its own methods, but the
+// package never converts it.
+type Shadowing struct {
+	Shadowed
+	w int
+}
+
+// Reported: *Shadowing is never held through embeddingdep.Store
+func (s *Shadowing) Load() int { // want "consider using value receiver: Shadowing is .* bytes"
+	return s.w
+}
+
+func (s *Shadowing) Save(v int) { // want Save:"mutatesReceiver"
+	s.w = v
+}
+
+// Loader is embedded by Saver.
+type Loader struct {
+	v int
+}
+
+// OK: promoted to Saver, which implements embeddingdep.Store (another package) only as *Saver
+func (l *Loader) Load() int {
+	return l.v
+}
+
+// Saver embeds Loader by value, through Middle.
+type Saver struct {
+	Middle
+}
+
+// Middle embeds Loader by value.
+type Middle struct {
+	Loader
+}
+
+func (s *Saver) Save(v int) { // want Save:"mutatesReceiver"
+	s.v = v
+}
+
+// Shared is embedded by pointer, which already promotes every method of *Shared.
+type Shared struct {
+	v int
+}
+
+// Reported: embedding *Shared promotes it to the value method set already
+func (s *Shared) Load() int { // want "consider using value receiver: Shared is .* bytes"
+	return s.v
+}
+
+// Holder embeds *Shared.
+type Holder struct {
+	*Shared
+}
+
+func (h *Holder) Save(v int) { // want Save:"mutatesReceiver"
+	h.v = v
+}
+
+var _ embeddingdep.Store = (*Saver)(nil)
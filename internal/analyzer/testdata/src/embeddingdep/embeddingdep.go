@@ -0,0 +1,7 @@
+package embeddingdep
+
+// Store is implemented by structs of other packages.
+type Store interface {
+	Load() int
+	Save(int)
+}
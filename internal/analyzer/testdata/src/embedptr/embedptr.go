@@ -0,0 +1,99 @@
+package embedptr // want package:"interfaces"
+
+type Config struct {
+	Name string
+	Port int
+}
+
+func (c Config) Addr() string { return c.Name }
+
+type Server struct {
+	*Config // want `consider embedding Config instead of \*Config: it is never nil, and each copy of Server would then hold its own Config instead of sharing one \(24 bytes, threshold: 1024 bytes\)$`
+	Debug   bool
+}
+
+func NewServer() Server {
+	return Server{Config: &Config{Name: "localhost", Port: 80}}
+}
+
+type Counter struct {
+	n int
+}
+
+func (c *Counter) Inc() { c.n++ } // want Inc:"mutatesReceiver"
+
+func (c Counter) Value() int { return c.n }
+
+type Gauge struct {
+	v int
+}
+
+func (g *Gauge) Set(v int) { g.v = v } // want Set:"mutatesReceiver"
+
+type Stats struct {
+	*Gauge // want `consider embedding Gauge instead of \*Gauge: .*; the pointer-receiver methods of \*Gauge \(Set\) would be promoted to \*Stats only, not to Stats values$`
+}
+
+func NewStats() Stats {
+	return Stats{new(Gauge)}
+}
+
+// Optional relies on a nil *Config.
+type Optional struct {
+	*Config
+}
+
+func (o Optional) Configured() bool { return o.Config != nil }
+
+// Shared holds a *Config owned elsewhere.
+type Shared struct {
+	*Config
+}
+
+var defaults = &Config{Name: "default"}
+
+func NewShared() Shared {
+	return Shared{Config: defaults}
+}
+
+type Node struct {
+	Value int
+}
+
+func (n *Node) Set(v int) { // want Set:"mutatesReceiver"
+	if n == nil {
+		return
+	}
+
+	n.Value = v
+}
+
+// List relies on the nil-safe methods of *Node.
+type List struct {
+	*Node
+}
+
+type Incrementer interface {
+	Inc()
+}
+
+// Tally satisfies Incrementer through (*Counter).Inc.
+type Tally struct {
+	*Counter
+}
+
+var _ Incrementer = Tally{}
+
+type Large struct {
+	Data [2048]byte
+}
+
+type Holder struct {
+	*Large
+}
+
+// Named embeds *Config along with other named fields.
+type Named struct {
+	ID      int
+	*Config // want `consider embedding Config instead of \*Config`
+}
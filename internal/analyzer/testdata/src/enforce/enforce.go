@@ -0,0 +1,48 @@
+package enforce
+
+// Money is always passed around by value, whatever its size.
+//
+//pointless:enforce
+type Money struct { // want Money:"pointless:enforce"
+	Amount   int64
+	Currency string
+	Notes    [16]string
+}
+
+// Money is large, but enforced anyway.
+func Parse(s string) *Money { // want `Money is annotated with //pointless:enforce: use Money instead of \*Money`
+	if s == "" {
+		return nil
+	}
+
+	return &Money{Currency: s}
+}
+
+// The receiver is mutated, but the type is enforced: one finding, not two.
+func (m *Money) Add(n int64) { // want `Money is annotated with //pointless:enforce` Add:"mutatesReceiver"
+	m.Amount += n
+}
+
+func Sum(ms []*Money) int64 { // want `Money is annotated with //pointless:enforce`
+	var total int64
+	for _, m := range ms {
+		total += m.Amount
+	}
+
+	return total
+}
+
+type Plain struct {
+	V int
+}
+
+// Dereferences are not pointer types.
+func Amount(m Money) int64 {
+	p := &m
+
+	return (*p).Amount
+}
+
+func NewPlain() *Plain { // want "consider returning value instead of pointer"
+	return &Plain{}
+}
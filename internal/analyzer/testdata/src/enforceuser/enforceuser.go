@@ -0,0 +1,16 @@
+package enforceuser
+
+import "enforce"
+
+// Enforced across packages, via the imported fact.
+type Wallet struct {
+	Balance *enforce.Money // want `enforce.Money is annotated with //pointless:enforce: use enforce.Money instead of \*enforce.Money`
+}
+
+func Total(w Wallet) int64 {
+	if w.Balance == nil {
+		return 0
+	}
+
+	return w.Balance.Amount
+}
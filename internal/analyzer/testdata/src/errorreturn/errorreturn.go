@@ -0,0 +1,105 @@
+package errorreturn
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+)
+
+type Config struct {
+	Name string
+	Port int
+}
+
+var ErrEmpty = errors.New("empty")
+
+type parseError struct{ msg string }
+
+func (e parseError) Error() string { return e.msg }
+
+func Parse(data []byte) (*Config, error) { // want `consider returning \(Config, error\) instead of \(\*Config, error\): nil is only returned along with a non-nil error`
+	if len(data) == 0 {
+		return nil, ErrEmpty
+	}
+
+	var c Config
+	if err := json.Unmarshal(data, &c); err != nil {
+		return nil, fmt.Errorf("parsing config: %w", err)
+	}
+
+	return &c, nil
+}
+
+func Guarded(data []byte) (*Config, error) { // want `consider returning \(Config, error\)`
+	var c Config
+
+	err := json.Unmarshal(data, &c)
+	if err != nil && len(data) > 0 {
+		return nil, err
+	}
+
+	if len(data) > 100 {
+		return nil, &parseError{msg: "too long"}
+	}
+
+	if len(data) > 50 {
+		return nil, io.ErrUnexpectedEOF
+	}
+
+	return &c, nil
+}
+
+// Not flagged: nil may come with a nil error
+func Lookup(name string) (*Config, error) {
+	if name == "" {
+		return nil, nil
+	}
+
+	return &Config{Name: name}, nil
+}
+
+// Not flagged: the error of another call may be nil
+func Load(data []byte) (*Config, error) {
+	var c Config
+	if len(data) == 0 {
+		return nil, validate(data)
+	}
+
+	return &c, nil
+}
+
+// Not flagged: the error is not the one checked
+func Unchecked(data []byte) (*Config, error) {
+	var c Config
+
+	err := json.Unmarshal(data, &c)
+	if len(data) == 0 {
+		return nil, err
+	}
+
+	return &c, nil
+}
+
+// Not flagged: a caller compares the result with nil
+func Find(name string) (*Config, error) {
+	if name == "" {
+		return nil, ErrEmpty
+	}
+
+	return &Config{Name: name}, nil
+}
+
+func useFind() bool {
+	c, err := Find("x")
+
+	return err == nil && c != nil
+}
+
+func validate(data []byte) error {
+	if len(data) > 10 {
+		return ErrEmpty
+	}
+
+	return nil
+}
@@ -0,0 +1,34 @@
+package excluderules
+
+// User is a small struct.
+type User struct {
+	name string
+}
+
+// Config is a small struct.
+type Config struct {
+	debug bool
+}
+
+func NewUser() *User { // want "consider returning value instead of pointer: User is .* bytes"
+	return &User{}
+}
+
+func (u *User) Name() string { // want "consider using value receiver: User is .* bytes"
+	return u.name
+}
+
+// OK: matches the text condition
+func NewConfig() *Config {
+	return &Config{}
+}
+
+// OK: matches the source condition
+func LegacyUser() *User { // legacy-api
+	return &User{}
+}
+
+// Reported: the entry lists other linters only
+func DefaultUser() *User { // want "consider returning value instead of pointer: User is .* bytes"
+	return &User{}
+}
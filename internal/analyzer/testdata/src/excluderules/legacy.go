@@ -0,0 +1,10 @@
+package excluderules
+
+// OK: value-receiver is excluded in this file
+func (c *Config) Debug() bool {
+	return c.debug
+}
+
+func CurrentUser() *User { // want "consider returning value instead of pointer: User is .* bytes"
+	return &User{}
+}
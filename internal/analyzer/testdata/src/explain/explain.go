@@ -0,0 +1,28 @@
+package explain
+
+type Small struct {
+	ID int
+}
+
+type Large struct {
+	Data [2048]byte
+}
+
+func (s *Small) SetID(id int) { // want SetID:"mutatesReceiver"
+	s.ID = id
+}
+
+func Find(ok bool) *Small {
+	if !ok {
+		return nil
+	}
+	return &Small{}
+}
+
+func NewLarge() *Large {
+	return &Large{}
+}
+
+func NewSmall() *Small { // want "consider returning value instead of pointer"
+	return &Small{}
+}
@@ -0,0 +1,13 @@
+package explainstd
+
+import "errors"
+
+var ErrClosed = errors.New("closed")
+
+type Small struct {
+	ID int
+}
+
+func NewSmall() *Small { // want "consider returning value instead of pointer"
+	return &Small{}
+}
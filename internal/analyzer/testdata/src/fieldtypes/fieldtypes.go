@@ -0,0 +1,61 @@
+package fieldtypes
+
+import (
+	"database/sql"
+	"sync"
+)
+
+// OK: has a sync.Mutex field
+type Counter struct {
+	mu sync.Mutex
+	n  int
+}
+
+func NewCounter() *Counter {
+	return &Counter{}
+}
+
+// OK: a nested struct has a sync.Mutex field
+type Stats struct {
+	hits Counter
+}
+
+func NewStats() *Stats {
+	return &Stats{}
+}
+
+// OK: an array element has a sync.Mutex field
+type Shards struct {
+	shards [4]Counter
+}
+
+func NewShards() *Shards {
+	return &Shards{}
+}
+
+// OK: has a *sql.DB field
+type Repo struct {
+	db *sql.DB
+}
+
+func NewRepo() *Repo {
+	return &Repo{}
+}
+
+// Holds a *sync.Mutex, which is not listed
+type Guard struct {
+	mu *sync.Mutex
+}
+
+func NewGuard() *Guard { // want "consider returning value instead of pointer: Guard is .* bytes"
+	return &Guard{}
+}
+
+// OK: holds a sql.DB by value, which has sync.Mutex fields of its own
+type Conn struct {
+	db sql.DB
+}
+
+func NewConn() *Conn {
+	return &Conn{}
+}
@@ -0,0 +1,67 @@
+package fix
+
+type Point struct {
+	X, Y int
+	Tags [2]string
+}
+
+// Only read: the body is unchanged
+func (p *Point) Sum() int { // want "consider using value receiver: Point is 48 bytes"
+	return p.X + p.Y
+}
+
+// Dereferences become the value itself
+func (p *Point) Copy() Point { // want "consider using value receiver: Point is 48 bytes"
+	return *p
+}
+
+func (p *Point) Swapped() Point { // want "consider using value receiver: Point is 48 bytes"
+	return Point{X: (*p).Y, Y: p.X}
+}
+
+// Read-only pointer parameters get the address of the copy
+func (p *Point) Length() int { // want "consider using value receiver: Point is 48 bytes"
+	return lengthOf(p)
+}
+
+func (p *Point) Calls() int { // want "consider using value receiver: Point is 48 bytes"
+	return p.Sum() + p.Length()
+}
+
+func (p *Point) Move(dx int) { // want Move:"mutatesReceiver"
+	p.X += dx
+}
+
+// OK: mutates the receiver through Move
+func (p *Point) Nudge() { // want Nudge:"mutatesReceiver"
+	p.Move(1)
+}
+
+// Addresses of fields point into the copy
+func (p *Point) FirstTag() *string { // want "consider using value receiver: Point is 48 bytes"
+	return &p.Tags[0]
+}
+
+// No fix: a nil check on a value does not compile
+func (p *Point) Valid() bool { // want "consider using value receiver: Point is 48 bytes"
+	return p != nil && p.X >= 0
+}
+
+// No fix: the pointer is not passed to a read-only parameter
+func (p *Point) Store() {
+	keep(p)
+}
+
+func (_ *Point) Zero() int { // want "consider using value receiver: Point is 48 bytes"
+	return 0
+}
+
+func lengthOf(p *Point) int { // want lengthOf:"readOnlyParams\\(0\\)"
+	return p.X*p.X + p.Y*p.Y
+}
+
+var last *Point
+
+func keep(p *Point) {
+	last = p
+}
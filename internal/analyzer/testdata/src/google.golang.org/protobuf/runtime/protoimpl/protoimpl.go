@@ -0,0 +1,9 @@
+package protoimpl
+
+type MessageState struct {
+	atomicMessageInfo *int
+}
+
+type SizeCache = int32
+
+type UnknownFields = []byte
@@ -0,0 +1,9 @@
+package gorm
+
+// Model mirrors gorm.Model.
+type Model struct {
+	ID uint
+}
+
+// DB mirrors gorm.DB.
+type DB struct{}
@@ -0,0 +1,24 @@
+package grouped
+
+type Point struct {
+	X, Y int
+}
+
+type Size struct {
+	W, H int
+}
+
+func NewPoint() *Point { // want `Point is flagged at 3 sites \(return-pointer 2, slice-pointer 1\): consider using values instead of pointers`
+	return &Point{}
+}
+
+func Origin() *Point {
+	return &Point{}
+}
+
+var points []*Point
+
+// Flagged once: reported unchanged
+func NewSize() *Size { // want "consider returning value instead of pointer: Size is .* bytes"
+	return &Size{}
+}
@@ -0,0 +1,25 @@
+package grpcpreset
+
+import "google.golang.org/protobuf/runtime/protoimpl"
+
+// OK: generated by protoc-gen-go
+type GetUserRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Id string
+}
+
+func (x *GetUserRequest) GetId() string {
+	return x.Id
+}
+
+// Flagged: named like a message, but not one
+type RetryRequest struct {
+	Attempts int
+}
+
+func (r *RetryRequest) Remaining(limit int) int { // want "consider using value receiver: RetryRequest is .* bytes"
+	return limit - r.Attempts
+}
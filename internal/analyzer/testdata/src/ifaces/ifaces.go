@@ -0,0 +1,18 @@
+package ifaces
+
+// Resetter is satisfied by types that can reset their state.
+type Resetter interface {
+	Reset()
+	Name() string
+}
+
+// Entry is a cached value.
+type Entry struct {
+	Key string
+	N   int
+}
+
+// Cache looks up entries, returning nil for missing keys.
+type Cache interface {
+	Get(key string) *Entry
+}
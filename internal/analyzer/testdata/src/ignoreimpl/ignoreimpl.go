@@ -0,0 +1,70 @@
+package ignoreimpl // want package:"interfaces"
+
+import (
+	"database/sql/driver"
+	"fmt"
+)
+
+// OK: implements error with a value receiver
+type NotFound struct {
+	ID int
+}
+
+func (e NotFound) Error() string {
+	return fmt.Sprintf("%d not found", e.ID)
+}
+
+func (e *NotFound) Key() int {
+	return e.ID
+}
+
+func Lookup() *NotFound {
+	return &NotFound{}
+}
+
+// OK: implements driver.Valuer with a pointer receiver
+type Money struct {
+	Cents int64
+}
+
+func (m *Money) Value() (driver.Value, error) {
+	return m.Cents, nil
+}
+
+func (m *Money) Dollars() int64 {
+	return m.Cents / 100
+}
+
+func Prices() []*Money {
+	return []*Money{}
+}
+
+// OK: implements the local named interface
+type Tag struct {
+	Label string
+}
+
+func (t Tag) Name() string {
+	return t.Label
+}
+
+func (t *Tag) Len() int {
+	return len(t.Label)
+}
+
+type named interface {
+	Name() string
+}
+
+// Flagged: implements none of the listed interfaces
+type Point struct {
+	X, Y int
+}
+
+func (p *Point) Sum() int { // want "consider using value receiver: Point is 16 bytes"
+	return p.X + p.Y
+}
+
+func Origin() *Point { // want "consider returning value instead of pointer: Point is 16 bytes"
+	return &Point{}
+}
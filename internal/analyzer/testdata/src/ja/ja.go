@@ -0,0 +1,29 @@
+package ja
+
+type Point struct {
+	X, Y int
+}
+
+func NewPoint() *Point { // want "ポインタではなく値を返すことを検討してください: Point は .* バイトです"
+	return &Point{}
+}
+
+var points []*Point // want "\\[\\]\\*ja.Point ではなく \\[\\]ja.Point の使用を検討してください"
+
+func origin() *Point { // want "ポインタではなく Point を返すことを検討してください: 唯一の呼び出し元は結果をすぐに参照外ししています"
+	return &Point{}
+}
+
+func useOrigin() Point {
+	return *origin()
+}
+
+func describe(p *Point) int { // want "\\*Point ではなく p Point を受け取ることを検討してください: describe はこれを読み取るだけで、唯一の呼び出し元は値のアドレスを渡しています" describe:"readOnlyParams\\(0\\)"
+	return p.X
+}
+
+func useDescribe() int {
+	var p Point
+
+	return describe(&p)
+}
@@ -0,0 +1,33 @@
+package linedirectives
+
+type Token struct {
+	Kind int
+}
+
+// The code below is generated from grammar.y, which the config excludes.
+//
+//line grammar.y:1
+func newToken() *Token {
+	return &Token{}
+}
+
+//line parser.go:14
+func newFlagged() *Token { // want "consider returning value instead of pointer"
+	return &Token{}
+}
+
+// The code below is generated from template.tmpl, whose lines are past the
+// end of this file; nolint comments must still cover their own line here.
+//
+//line template.tmpl:400
+type (
+	Page struct {
+		Next  []*Token // want `consider using \[\]linedirectives.Token instead of \[\]\*linedirectives.Token`
+		Items []*Token //nolint:pointless
+	}
+)
+
+// Excluded by the exclude-rules entry matching template.tmpl.
+func (p *Page) Len() int {
+	return len(p.Items) + len(p.Next)
+}
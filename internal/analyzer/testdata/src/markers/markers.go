@@ -0,0 +1,24 @@
+package markers
+
+// NopLogger discards everything.
+type NopLogger struct{}
+
+func (l *NopLogger) Log(msg string) { // want "consider using value receiver: NopLogger has no fields, so there is nothing to mutate or copy"
+	_ = msg
+}
+
+func (*NopLogger) Flush() { // want "consider using value receiver: NopLogger has no fields"
+}
+
+func (l *NopLogger) Logf(msg string) { // want "consider using value receiver: NopLogger has no fields"
+	l.Log(msg)
+}
+
+// No fix offered: the receiver is passed on as *NopLogger
+func (l *NopLogger) Self() { // want "consider using value receiver: NopLogger has no fields"
+	keep(l)
+}
+
+func keep(l *NopLogger) {
+	_ = l
+}
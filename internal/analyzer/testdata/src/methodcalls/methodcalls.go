@@ -0,0 +1,53 @@
+package methodcalls
+
+type Counter struct {
+	n int
+}
+
+func (c *Counter) Reset() { // want Reset:"mutatesReceiver"
+	c.n = 0
+}
+
+func (c *Counter) inc() {
+	c.n++
+}
+
+// OK: the method value mutates the receiver when called
+func (c *Counter) Bound() { // want Bound:"mutatesReceiver"
+	f := c.inc
+	f()
+}
+
+// OK: deferred calls mutate the receiver too
+func (c *Counter) Deferred() { // want Deferred:"mutatesReceiver"
+	defer c.inc()
+}
+
+// OK: the method expression is called on the receiver
+func (c *Counter) Expr() { // want Expr:"mutatesReceiver"
+	(*Counter).inc(c)
+}
+
+// OK: mutates through a chain of methods
+func (c *Counter) Twice() { // want Twice:"mutatesReceiver"
+	c.Bound()
+	c.Bound()
+}
+
+func (c *Counter) get() int { // want "consider using value receiver"
+	return c.n
+}
+
+// Calls a pointer-receiver method that only reads
+func (c *Counter) Value() int { // want "consider using value receiver"
+	return c.get()
+}
+
+type Stats struct {
+	hits Counter
+}
+
+// OK: mutates a field through its method
+func (s *Stats) Clear() { // want Clear:"mutatesReceiver"
+	s.hits.Reset()
+}
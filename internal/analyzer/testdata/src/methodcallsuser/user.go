@@ -0,0 +1,21 @@
+package methodcallsuser
+
+import "methodcalls"
+
+type Tally struct {
+	c methodcalls.Counter
+}
+
+// OK: mutates the field through a method of another package, via the fact
+func (t *Tally) Clear() { // want Clear:"mutatesReceiver"
+	t.c.Reset()
+}
+
+// OK: the method value of another package mutates the field
+func (t *Tally) Later() func() { // want Later:"mutatesReceiver"
+	return t.c.Twice
+}
+
+func (t *Tally) Count() int { // want "consider using value receiver"
+	return t.c.Value()
+}
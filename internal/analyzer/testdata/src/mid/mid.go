@@ -0,0 +1,30 @@
+package mid
+
+import "ifaces"
+
+// ResetAll resets every resetter.
+func ResetAll(rs ...ifaces.Resetter) {
+	for _, r := range rs {
+		r.Reset()
+	}
+}
+
+// Count returns the number of items.
+func Count(n int) int {
+	return n
+}
+
+// MapCache implements ifaces.Cache with a map.
+type MapCache struct {
+	entries map[string]ifaces.Entry
+}
+
+// Get returns nil for missing keys.
+func (c MapCache) Get(key string) *ifaces.Entry {
+	e, ok := c.entries[key]
+	if !ok {
+		return nil
+	}
+
+	return &e
+}
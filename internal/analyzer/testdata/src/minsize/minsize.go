@@ -0,0 +1,26 @@
+package minsize
+
+// ID is a tiny wrapper below min-size
+type ID struct {
+	n int
+}
+
+type Point struct {
+	X, Y int
+}
+
+func NewID() *ID {
+	return &ID{}
+}
+
+func NewPoint() *Point { // want `^consider returning value instead of pointer: Point is 16 bytes \(min-size: 16 bytes, threshold: 1024 bytes\)`
+	return &Point{}
+}
+
+func IDs() []*ID {
+	return []*ID{}
+}
+
+func Points() []*Point { // want `\(16 bytes, min-size: 16 bytes, threshold: 1024 bytes\)`
+	return []*Point{}
+}
@@ -0,0 +1,102 @@
+package paramfacts
+
+type Config struct {
+	Name    string
+	Retries int
+}
+
+var last *Config
+
+func Describe(c *Config) string { // want Describe:"readOnlyParams\\(0\\)"
+	return c.Name
+}
+
+func Copy(c *Config, n int) Config { // want Copy:"readOnlyParams\\(0\\)"
+	v := *c
+	v.Retries = n
+
+	return v
+}
+
+// Forwards to a read-only parameter.
+func Forward(prefix string, c *Config) string { // want Forward:"readOnlyParams\\(1\\)"
+	return prefix + Describe(c)
+}
+
+// Mutually recursive readers stay read-only.
+func Even(c *Config, n int) bool { // want Even:"readOnlyParams\\(0\\)"
+	if n == 0 {
+		return true
+	}
+
+	return Odd(c, n-1)
+}
+
+func Odd(c *Config, n int) bool { // want Odd:"readOnlyParams\\(0\\)"
+	if n == 0 {
+		return c.Retries > 0
+	}
+
+	return Even(c, n-1)
+}
+
+func (c Config) Summary() string {
+	return c.Name
+}
+
+// Value-receiver method calls only read.
+func Summarize(c *Config) string { // want Summarize:"readOnlyParams\\(0\\)"
+	return c.Summary()
+}
+
+func (c *Config) Reset() { // want Reset:"mutatesReceiver"
+	c.Retries = 0
+}
+
+// --- Not read-only ---
+
+func SetName(c *Config, name string) {
+	c.Name = name
+}
+
+func Increment(c *Config) {
+	c.Retries++
+}
+
+func CallsPointerMethod(c *Config) {
+	c.Reset()
+}
+
+func Store(c *Config) {
+	last = c
+}
+
+func Identity(c *Config) any {
+	return c
+}
+
+func NilCheck(c *Config) string {
+	if c == nil {
+		return ""
+	}
+
+	return c.Name
+}
+
+func FieldAddress(c *Config) *int {
+	return &c.Retries
+}
+
+func ForwardToMutator(c *Config) {
+	SetName(c, "x")
+}
+
+func Variadic(cs ...*Config) {}
+
+func ForwardVariadic(c *Config) {
+	Variadic(c)
+}
+
+func ForwardDynamic(c *Config, f func(*Config)) {
+	f(c)
+}
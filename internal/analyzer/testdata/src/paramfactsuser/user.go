@@ -0,0 +1,12 @@
+package paramfactsuser
+
+import "paramfacts"
+
+// Read-only across packages, via the imported fact.
+func Label(c *paramfacts.Config) string { // want Label:"readOnlyParams\\(0\\)"
+	return paramfacts.Forward("config: ", c)
+}
+
+func Rename(c *paramfacts.Config) {
+	paramfacts.SetName(c, "renamed")
+}
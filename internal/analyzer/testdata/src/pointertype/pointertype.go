@@ -0,0 +1,28 @@
+package pointertype
+
+// Conn is shared by identity: its users hold on to the same connection.
+//
+//pointless:pointer
+type Conn struct { // want Conn:"pointless:pointer"
+	ID int
+}
+
+func Dial() *Conn {
+	return &Conn{}
+}
+
+func (c *Conn) Name() int {
+	return c.ID
+}
+
+func Pool() []*Conn {
+	return []*Conn{{ID: 1}}
+}
+
+type Plain struct {
+	V int
+}
+
+func NewPlain() *Plain { // want "consider returning value instead of pointer"
+	return &Plain{}
+}
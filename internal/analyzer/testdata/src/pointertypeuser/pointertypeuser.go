@@ -0,0 +1,24 @@
+package pointertypeuser
+
+import "pointertype"
+
+// Suppressed across packages, via the imported fact.
+type Client struct {
+	Conns []*pointertype.Conn
+}
+
+func Redial() *pointertype.Conn {
+	return pointertype.Dial()
+}
+
+func Peers(c Client) []*pointertype.Conn {
+	return c.Conns
+}
+
+type Local struct {
+	V int
+}
+
+func NewLocal() *Local { // want "consider returning value instead of pointer"
+	return &Local{}
+}
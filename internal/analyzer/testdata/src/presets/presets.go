@@ -0,0 +1,63 @@
+package presets
+
+import "gorm.io/gorm"
+
+// OK: embeds gorm.Model
+type User struct {
+	gorm.Model
+	Name string
+}
+
+func (u *User) DisplayName() string {
+	return u.Name
+}
+
+func NewUser() *User {
+	return &User{}
+}
+
+// OK: implements a gorm hook
+type Order struct {
+	Total int
+}
+
+func (o *Order) BeforeCreate(tx *gorm.DB) error {
+	_ = tx
+
+	return nil
+}
+
+func (o *Order) Amount() int {
+	return o.Total
+}
+
+// OK: has gorm tags
+type Item struct {
+	SKU string `gorm:"primaryKey"`
+}
+
+func ListItems() []*Item {
+	return []*Item{}
+}
+
+// OK: generated protobuf message
+type Message struct {
+	Body string
+}
+
+func (m *Message) ProtoReflect() any {
+	return m
+}
+
+func (m *Message) GetBody() string {
+	return m.Body
+}
+
+// Plain is not matched by any preset.
+type Plain struct {
+	Value int
+}
+
+func (p *Plain) Get() int { // want "consider using value receiver: Plain is .* bytes"
+	return p.Value
+}
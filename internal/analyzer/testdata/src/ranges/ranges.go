@@ -0,0 +1,17 @@
+package ranges
+
+type Point struct {
+	X, Y int
+}
+
+func (p *Point) Len() int { // want `consider using value receiver`
+	return p.X + p.Y
+}
+
+func Origin() *Point { // want `consider returning value`
+	return &Point{}
+}
+
+func Points() []*Point { // want `consider using \[\]Point`
+	return []*Point{{X: 1}}
+}
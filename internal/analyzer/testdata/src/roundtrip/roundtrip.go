@@ -0,0 +1,80 @@
+package roundtrip
+
+type Point struct {
+	X, Y int
+}
+
+func (p *Point) Move(dx int) { p.X += dx } // want Move:"mutatesReceiver"
+
+func (p Point) Sum() int { return p.X + p.Y }
+
+func origin() Point { return Point{} }
+
+func readThroughAlias() int {
+	tmp := origin()
+	p := &tmp // want `pointer round-trip: p only points to tmp and is used through field access and dereference; consider using tmp directly`
+
+	return p.X + p.Sum()
+}
+
+func writeThroughAlias() Point {
+	pt := Point{X: 1}
+	var p = &pt // want `pointer round-trip: p only points to pt`
+	p.Move(2)
+	p.Y = 3
+
+	return *p
+}
+
+// OK: the pointer is passed on
+func passed() {
+	tmp := origin()
+	p := &tmp
+	keep(p)
+}
+
+// OK: the pointer is compared with nil
+func compared() bool {
+	tmp := origin()
+	p := &tmp
+
+	return p != nil
+}
+
+// OK: the pointer is reassigned
+func reassigned(other bool) int {
+	tmp := origin()
+	p := &tmp
+
+	if other {
+		p = &Point{X: 1}
+	}
+
+	return p.X
+}
+
+// OK: the pointer aliases a field, not a variable
+func field(s struct{ pt Point }) int {
+	p := &s.pt
+
+	return p.X
+}
+
+var global Point
+
+// OK: the pointer aliases a package-level variable
+func pkgLevel() int {
+	p := &global
+
+	return p.X
+}
+
+func keep(*Point) {}
+
+func copies(pt Point) (Point, Point, Point) {
+	a := *&pt          // want `pointer round-trip: \*&pt dereferences the address it takes; consider using pt directly`
+	b := *(&pt)        // want `pointer round-trip: \*\(&pt\) dereferences the address it takes`
+	c := *&Point{X: 1} // want `pointer round-trip: \*&Point\{…\} dereferences the address it takes; consider using Point\{…\} directly`
+
+	return a, b, c
+}
@@ -0,0 +1,17 @@
+package severity
+
+type Point struct {
+	X, Y int
+}
+
+func NewPoint() *Point { // want `^\[info\] consider returning value instead of pointer`
+	return &Point{}
+}
+
+func (p *Point) Sum() int { // want `^\[hint\] consider using value receiver`
+	return p.X + p.Y
+}
+
+func Points() []*Point { // want `^consider using \[\]Point instead of \[\]\*Point`
+	return []*Point{}
+}
@@ -0,0 +1,40 @@
+package skipif
+
+import "time"
+
+// OK: has a time.Duration field
+type Counter struct {
+	every time.Duration
+	n     int
+}
+
+func NewCounter() *Counter {
+	return &Counter{}
+}
+
+func (c *Counter) Value() int {
+	return c.n
+}
+
+// OK: the name matches DTO$
+type UserDTO struct {
+	Name string
+}
+
+func NewUserDTO() *UserDTO {
+	return &UserDTO{}
+}
+
+// --- Reported ---
+
+type Point struct {
+	X, Y int
+}
+
+func NewPoint() *Point { // want "consider returning value instead of pointer"
+	return &Point{}
+}
+
+func (p *Point) Sum() int { // want "consider using value receiver"
+	return p.X + p.Y
+}
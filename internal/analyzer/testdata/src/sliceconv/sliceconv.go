@@ -0,0 +1,75 @@
+package sliceconv
+
+type User struct {
+	ID   int
+	Name string
+}
+
+type Group struct {
+	ID int
+}
+
+type Big struct {
+	Data [2048]byte
+}
+
+func toPointers(users []User) []*User { // want `toPointers only converts \[\]User to \[\]\*User: consider standardizing on \[\]User, so that no adapter is needed \(24 bytes, threshold: 1024 bytes\); fromPointers converts it back`
+	out := make([]*User, len(users))
+	for i := range users {
+		out[i] = &users[i]
+	}
+
+	return out
+}
+
+func fromPointers(users []*User) []User { // want `fromPointers only converts \[\]\*User to \[\]User: consider standardizing on \[\]User, so that no adapter is needed \(24 bytes, threshold: 1024 bytes\); toPointers converts it back`
+	var out []User
+	for _, u := range users {
+		out = append(out, *u)
+	}
+
+	return out
+}
+
+func Names(users []User) []string {
+	ptrs := toPointers(users)
+
+	names := make([]string, 0, len(ptrs))
+	for _, u := range fromPointers(ptrs) {
+		names = append(names, u.Name)
+	}
+
+	return names
+}
+
+// Flagged alone: there is no conversion back
+func Refs(groups []Group) []*Group { // want `Refs only converts \[\]Group to \[\]\*Group: consider standardizing on \[\]Group, so that no adapter is needed \(8 bytes, threshold: 1024 bytes\)$`
+	out := []*Group{}
+	for _, u := range groups {
+		out = append(out, &u)
+	}
+
+	return out
+}
+
+// OK: Big exceeds the threshold
+func bigPointers(bigs []Big) []*Big {
+	out := make([]*Big, len(bigs))
+	for i := range bigs {
+		out[i] = &bigs[i]
+	}
+
+	return out
+}
+
+// OK: the loop does more than convert
+func active(users []User) []*User { // want `consider using \[\]User instead of \[\]\*User`
+	out := make([]*User, 0, len(users)) // want `consider using \[\]sliceconv.User instead of \[\]\*sliceconv.User`
+	for i := range users {
+		if users[i].ID != 0 {
+			out = append(out, &users[i])
+		}
+	}
+
+	return out
+}
@@ -0,0 +1,126 @@
+package sortptr
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"sort"
+)
+
+type Item struct {
+	Name string
+	Rank int
+}
+
+// Flagged: the slice only points into items to be sorted
+func SortedNames(items []Item) []string {
+	ptrs := make([]*Item, len(items)) // want `consider sorting a copy of items, or a \[\]int of indexes into it, instead of \[\]\*sortptr.Item: ptrs only points into items to be sorted or iterated`
+	for i := range items {
+		ptrs[i] = &items[i]
+	}
+
+	sort.Slice(ptrs, func(i, j int) bool { return ptrs[i].Name < ptrs[j].Name })
+
+	names := make([]string, 0, len(ptrs))
+	for _, p := range ptrs {
+		names = append(names, p.Name)
+	}
+
+	return names
+}
+
+// Flagged: built with append and sorted with slices.SortFunc
+func Ranked(items []Item) []Item {
+	var ptrs []*Item // want `consider sorting a copy of items, or a \[\]int of indexes into it, instead of \[\]\*sortptr.Item`
+	for i := range items {
+		ptrs = append(ptrs, &items[i])
+	}
+
+	slices.SortFunc(ptrs, func(a, b *Item) int { return cmp.Compare(a.Rank, b.Rank) })
+
+	out := make([]Item, 0, len(ptrs))
+	for _, p := range ptrs {
+		out = append(out, *p)
+	}
+
+	return out
+}
+
+// Flagged: built from fresh values, which a []Item holds as well
+func Print(names []string) {
+	ptrs := make([]*Item, 0, len(names)) // want `consider using \[\]sortptr.Item instead of \[\]\*sortptr.Item: ptrs is only filled with the addresses of values to be sorted or iterated`
+	for i, name := range names {
+		ptrs = append(ptrs, &Item{Name: name, Rank: i})
+	}
+
+	for _, p := range ptrs {
+		fmt.Println(p.Name, p.Rank)
+	}
+}
+
+// OK for sort-pointer-slice: sorting through the pointers reorders nothing in
+// items, but the slice is returned
+func SortedView(items []Item) []*Item { // want `consider using \[\]Item instead of \[\]\*Item: better cache locality`
+	ptrs := make([]*Item, len(items)) // want `consider using \[\]sortptr.Item instead of \[\]\*sortptr.Item: better cache locality`
+	for i := range items {
+		ptrs[i] = &items[i]
+	}
+
+	sort.Slice(ptrs, func(i, j int) bool { return ptrs[i].Rank < ptrs[j].Rank })
+
+	return ptrs
+}
+
+// OK: the pointers are used to write to items in sorted order
+func Renumber(items []Item) {
+	var ptrs []*Item // want `consider using \[\]sortptr.Item instead of \[\]\*sortptr.Item: better cache locality`
+	for i := range items {
+		ptrs = append(ptrs, &items[i])
+	}
+
+	sort.Slice(ptrs, func(i, j int) bool { return ptrs[i].Name < ptrs[j].Name })
+
+	for i, p := range ptrs {
+		bump(p, i)
+	}
+}
+
+func bump(p *Item, rank int) {
+	p.Rank = rank
+}
+
+// OK: the elements are compared with nil
+func First(items []Item) string {
+	ptrs := []*Item{nil}
+	for i := range items {
+		ptrs = append(ptrs, &items[i])
+	}
+
+	sort.Slice(ptrs, func(i, j int) bool { return ptrs[i] != nil && ptrs[j] == nil })
+
+	return ptrs[0].Name
+}
+
+// OK for sort-pointer-slice: the slice is neither sorted nor iterated
+func Count(items []Item) int {
+	var ptrs []*Item // want `consider using \[\]sortptr.Item instead of \[\]\*sortptr.Item: better cache locality`
+	for i := range items {
+		ptrs = append(ptrs, &items[i])
+	}
+
+	return len(ptrs)
+}
+
+// OK: the pointers are written through in sorted order
+func Rerank(items []Item) {
+	ptrs := make([]*Item, len(items)) // want `consider using \[\]sortptr.Item instead of \[\]\*sortptr.Item: better cache locality`
+	for i := range items {
+		ptrs[i] = &items[i]
+	}
+
+	sort.Slice(ptrs, func(i, j int) bool { return ptrs[i].Name < ptrs[j].Name })
+
+	for i, p := range ptrs {
+		p.Rank = i
+	}
+}
@@ -0,0 +1,39 @@
+package strict
+
+type Box[T any] struct {
+	Value T
+	Count int
+}
+
+func (b *Box[T]) Get() T { // want `needs manual review: the size of Box\[T\] depends on its type arguments`
+	return b.Value
+}
+
+func NewBox[T any](v T) *Box[T] { // want `needs manual review: the size of Box\[T\] depends on its type arguments`
+	return &Box[T]{Value: v}
+}
+
+func Ref[T any](v T) *T { // want `needs manual review: T is a type parameter, so what it points to depends on the instantiation`
+	return &v
+}
+
+// Instantiated types have a known size and are checked as usual
+type Pair struct{ A, B int }
+
+func NewPairBox() *Box[Pair] { // want `consider returning value instead of pointer: Box\[Pair\] is 24 bytes`
+	return &Box[Pair]{}
+}
+
+// Large structs are still skipped without review
+type Large struct {
+	Data [2048]byte
+}
+
+func NewLarge() *Large {
+	return &Large{}
+}
+
+//nolint:pointless // reviewed: generic on purpose
+func Suppressed[T any](v T) *T {
+	return &v
+}
@@ -0,0 +1,14 @@
+package suppressed
+
+type Small struct {
+	ID int
+}
+
+//nolint:pointless
+func NewSmall() *Small { // want "suppressed: consider returning value instead of pointer: Small is .* bytes"
+	return &Small{}
+}
+
+func Get() *Small { // want "consider returning value instead of pointer: Small is .* bytes"
+	return &Small{}
+}
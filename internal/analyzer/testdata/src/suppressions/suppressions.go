@@ -0,0 +1,28 @@
+package suppressions
+
+// Store is a small struct.
+type Store struct {
+	name string
+}
+
+// OK: listed in the suppressions file as suppressions.(*Store).Get
+func (s *Store) Get() string {
+	return s.name
+}
+
+// OK: listed in the suppressions file as suppressions.NewStore
+func NewStore() *Store {
+	return &Store{}
+}
+
+// OK: listed in the suppressions file as suppressions.stores
+var stores []*Store
+
+func (s *Store) Name() string { // want "consider using value receiver: Store is .* bytes"
+	return s.name
+}
+
+// Reported: the suppressions file lists (*Store).Get, not Store.Get of another type
+func OtherStore() *Store { // want "consider returning value instead of pointer: Store is .* bytes"
+	return &Store{}
+}
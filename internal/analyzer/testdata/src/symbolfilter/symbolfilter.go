@@ -0,0 +1,37 @@
+package symbolfilter
+
+// User is a small struct.
+type User struct {
+	name string
+}
+
+// Repo is a small repository.
+type Repo struct {
+	users []User
+}
+
+func GetUser() *User { // want "consider returning value instead of pointer: User is .* bytes"
+	return &User{}
+}
+
+func (r *Repo) GetFirst() *User { // want "consider using value receiver: Repo is .* bytes" "consider returning value instead of pointer: User is .* bytes"
+	return &r.users[0]
+}
+
+// OK: matches ignore-symbols-regex
+func GetUserForTest() *User {
+	return &User{}
+}
+
+// OK: does not match include-symbols
+func NewUser() *User {
+	return &User{}
+}
+
+// OK: does not match include-symbols
+func (r *Repo) Len() int {
+	return len(r.users)
+}
+
+// OK: does not match include-symbols
+var users []*User
@@ -0,0 +1,32 @@
+package symbols
+
+// Server is a small struct.
+type Server struct {
+	addr string
+}
+
+// OK: listed as (*Server).Handler
+func (s *Server) Handler() string {
+	return s.addr
+}
+
+// OK: listed as symbols.NewServer
+func NewServer() *Server {
+	return &Server{}
+}
+
+// OK: listed as Server.Addr
+func (s *Server) Addr() string {
+	return s.addr
+}
+
+// OK: listed as defaultServers
+var defaultServers []*Server
+
+func (s *Server) Name() string { // want "consider using value receiver: Server is .* bytes"
+	return s.addr
+}
+
+func OtherServer() *Server { // want "consider returning value instead of pointer: Server is .* bytes"
+	return &Server{}
+}
@@ -0,0 +1,18 @@
+package testfiles
+
+type Point struct {
+	X, Y int
+}
+
+// Fixture is over the threshold of test files, but not of the others
+type Fixture struct {
+	Points [16]Point
+}
+
+func NewPoint() *Point { // want `^consider returning value instead of pointer`
+	return &Point{}
+}
+
+func NewFixture() *Fixture { // want `^consider returning value instead of pointer`
+	return &Fixture{}
+}
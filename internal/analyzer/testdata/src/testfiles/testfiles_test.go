@@ -0,0 +1,22 @@
+package testfiles
+
+import "testing"
+
+func newTestPoint() *Point { // want `^\[info\] consider returning value instead of pointer`
+	return &Point{}
+}
+
+func newTestFixture() *Fixture {
+	return &Fixture{}
+}
+
+// Only return-pointer is reported in test files
+func (p *Point) testSum() int {
+	return p.X + p.Y
+}
+
+func TestPoint(t *testing.T) {
+	if newTestPoint().testSum() != 0 || newTestFixture() == nil {
+		t.Fail()
+	}
+}
@@ -0,0 +1,9 @@
+package typedefs
+
+type Point struct {
+	X, Y int
+}
+
+func (p *Point) Sum() int { // want "consider using value receiver: Point is 16 bytes"
+	return p.X + p.Y
+}
@@ -0,0 +1,17 @@
+package units
+
+type TwoWords struct {
+	A, B int
+}
+
+type ThreeWords struct {
+	A, B, C int
+}
+
+func NewTwoWords() *TwoWords { // want "consider returning value instead of pointer: TwoWords is .* bytes \\(threshold: .* bytes\\)"
+	return &TwoWords{}
+}
+
+func NewThreeWords() *ThreeWords {
+	return &ThreeWords{}
+}
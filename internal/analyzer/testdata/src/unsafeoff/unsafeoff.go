@@ -0,0 +1,19 @@
+package unsafeoff
+
+import "unsafe"
+
+type Header struct {
+	Len int
+	Cap int
+}
+
+var headerSize = unsafe.Sizeof(Header{})
+
+func addressOf(h *Header) uintptr {
+	return uintptr(unsafe.Pointer(h))
+}
+
+// Flagged: respect-unsafe is off
+func NewHeader() *Header { // want "consider returning value instead of pointer: Header is 16 bytes"
+	return &Header{}
+}
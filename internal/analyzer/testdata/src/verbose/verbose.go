@@ -0,0 +1,23 @@
+package verbose
+
+type Padded struct {
+	Enabled bool
+	Count   int64
+	Limit   int32
+}
+
+func (p *Padded) Total() int64 { // want `consider using value receiver: Padded is 24 bytes \(threshold: 1024 bytes\) and method doesn't mutate receiver; layout of Padded: Enabled bool at offset 0 \(1 bytes\), padding \(7 bytes\), Count int64 at offset 8 \(8 bytes\), Limit int32 at offset 16 \(4 bytes\), padding \(4 bytes\)`
+	return p.Count + int64(p.Limit)
+}
+
+type Empty struct{}
+
+// Types without fields have no layout to show
+func NewEmpty() *Empty { // want `consider returning value instead of pointer: Empty is 0 bytes \(threshold: 1024 bytes\)$`
+	return &Empty{}
+}
+
+// Findings about reference types are not about a struct
+func Keys(m *map[string]int) int { // want `consider using map\[string\]int instead of \*map\[string\]int: maps are already reference types$`
+	return len(*m)
+}
@@ -0,0 +1,141 @@
+package workerpool
+
+import "sync"
+
+type Job struct {
+	ID   int
+	Path string
+}
+
+type Result struct {
+	ID  int
+	Err error
+}
+
+// Group runs functions on goroutines, like errgroup.Group.
+type Group struct {
+	n int
+}
+
+func (g *Group) Go(f func() error) { // want Go:"mutatesReceiver"
+	g.n++
+	go func() { _ = f() }()
+}
+
+func process(j *Job) {} // want process:"readOnlyParams\\(0\\)"
+
+func RunChannel(paths []string) {
+	jobs := make(chan *Job, len(paths)) // want `consider a chan Job: the \*Job jobs sent on jobs to the goroutines at line 33 are never nil`
+
+	var wg sync.WaitGroup
+	for range 4 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := range jobs {
+				process(j)
+			}
+		}()
+	}
+
+	for i, p := range paths {
+		jobs <- &Job{ID: i, Path: p}
+	}
+
+	close(jobs)
+	wg.Wait()
+}
+
+func worker(jobs <-chan *Job) {
+	for j := range jobs {
+		process(j)
+	}
+}
+
+func RunWorkers(paths []string) {
+	var jobs = make(chan *Job) // want `consider a chan Job: the \*Job jobs sent on jobs`
+	for range 4 {
+		go worker(jobs)
+	}
+
+	for _, p := range paths {
+		jobs <- &Job{Path: p}
+	}
+
+	close(jobs)
+}
+
+func RunQueue(paths []string) error {
+	queue := make([]*Job, 0, len(paths)) // want `consider \[\]Job: the \*Job elements of queue reach the goroutines at line 76 and are never nil`
+	for _, p := range paths {
+		queue = append(queue, &Job{Path: p})
+	}
+
+	var g Group
+	for _, j := range queue {
+		g.Go(func() error {
+			process(j)
+
+			return nil
+		})
+	}
+
+	return nil
+}
+
+// Nil marks the end of the jobs.
+func RunSentinel(paths []string) {
+	jobs := make(chan *Job)
+	go func() {
+		for {
+			j := <-jobs
+			if j == nil {
+				return
+			}
+
+			process(j)
+		}
+	}()
+
+	for _, p := range paths {
+		jobs <- &Job{Path: p}
+	}
+
+	jobs <- nil
+}
+
+func checkedWorker(jobs <-chan *Job) {
+	for j := range jobs {
+		if j != nil {
+			process(j)
+		}
+	}
+}
+
+// The worker checks for nil.
+func RunChecked() {
+	jobs := make(chan *Job)
+	go checkedWorker(jobs)
+	close(jobs)
+}
+
+// Workers store their results in the jobs.
+func RunResults(n int) []*Result {
+	results := make(chan *Result)
+	go func() {
+		for r := range results {
+			r.Err = nil
+		}
+	}()
+
+	close(results)
+
+	return nil
+}
+
+// Not shared with a goroutine.
+func Drain() {
+	jobs := make(chan *Job, 1)
+	jobs <- &Job{}
+	process(<-jobs)
+}
@@ -0,0 +1,55 @@
+package zerovalue
+
+// Point has a usable zero value: nothing initializes it.
+type Point struct {
+	X, Y int
+}
+
+func NewPoint(x, y int) *Point { // want `consider returning value instead of pointer: Point is 16 bytes \(threshold: 1024 bytes\); its zero value Point\{\} is ready to use$`
+	return &Point{X: x, Y: y}
+}
+
+// Counter is set up by its constructor through an unexported field.
+type Counter struct {
+	name string
+	n    int
+}
+
+func NewCounter(name string) *Counter { // want `consider returning value instead of pointer: Counter is 24 bytes \(threshold: 1024 bytes\)$`
+	return &Counter{name: name}
+}
+
+// Index writes to a map field, nil in the zero value.
+type Index struct {
+	Names map[string]int
+}
+
+func (i Index) Add(name string) { i.Names[name] = len(i.Names) }
+
+func NewIndex() *Index { // want `consider returning value instead of pointer: Index is 8 bytes \(threshold: 1024 bytes\)$`
+	return &Index{Names: make(map[string]int)}
+}
+
+// Queue sends on a channel field, nil in the zero value.
+type Queue struct {
+	Jobs chan int
+}
+
+func (q Queue) Push(job int) { q.Jobs <- job }
+
+func NewQueue() *Queue { // want `consider returning value instead of pointer: Queue is 8 bytes \(threshold: 1024 bytes\)$`
+	return &Queue{Jobs: make(chan int, 1)}
+}
+
+// Settings is set up by assigning an unexported field.
+type Settings struct {
+	Name  string
+	level int
+}
+
+func NewSettings() *Settings { // want `consider returning value instead of pointer: Settings is 24 bytes \(threshold: 1024 bytes\)$`
+	s := &Settings{Name: "default"}
+	s.level = 1
+
+	return s
+}
@@ -0,0 +1,183 @@
+package analyzer
+
+import (
+	"go/ast"
+	"go/token"
+	"go/types"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/ast/inspector"
+	"golang.org/x/tools/go/types/typeutil"
+)
+
+// unsafeTypes maps named types the package handles at a low level to the
+// first such use: converting their pointers to or from unsafe.Pointer (and
+// from there to uintptr), inspecting their layout with unsafe.Sizeof,
+// Alignof, or Offsetof, or inspecting them with reflect.TypeOf or TypeFor.
+// Code doing so likely relies on the address or the representation of the
+// value staying as it is.
+type unsafeTypes map[*types.TypeName]unsafeUse
+
+// unsafeUse is the first low-level use of a type.
+type unsafeUse struct {
+	pos token.Pos
+	// what describes the use, completing "T ...".
+	what string
+}
+
+// findUnsafeConversions scans the package for unsafe.Pointer(p) and (*T)(ptr)
+// conversions, and for the unsafe and reflect calls inspecting types.
+func findUnsafeConversions(pass *analysis.Pass, inspect *inspector.Inspector) unsafeTypes {
+	result := make(unsafeTypes)
+
+	inspect.Preorder([]ast.Node{(*ast.CallExpr)(nil)}, func(n ast.Node) {
+		call, ok := n.(*ast.CallExpr)
+		if !ok {
+			return
+		}
+
+		if what, t := inspectedType(pass, call); t != nil {
+			result.recordValue(t, call.Pos(), what)
+
+			return
+		}
+
+		if len(call.Args) != 1 {
+			return
+		}
+
+		fun, ok := pass.TypesInfo.Types[call.Fun]
+		if !ok || !fun.IsType() {
+			return // not a conversion
+		}
+
+		arg := pass.TypesInfo.TypeOf(call.Args[0])
+		if arg == nil {
+			return
+		}
+
+		switch {
+		case isUnsafePointer(fun.Type):
+			result.record(arg, call.Pos(), "pointers are converted with unsafe.Pointer")
+		case isUnsafePointer(arg):
+			result.record(fun.Type, call.Pos(), "pointers are converted with unsafe.Pointer")
+		}
+	})
+
+	return result
+}
+
+func isUnsafePointer(t types.Type) bool {
+	basic, ok := t.Underlying().(*types.Basic)
+
+	return ok && basic.Kind() == types.UnsafePointer
+}
+
+// inspectedType returns the type whose layout or representation the call
+// inspects, and a description of the use: the type of the operand of
+// unsafe.Sizeof and Alignof, the struct of the field of unsafe.Offsetof, the
+// type of the argument of reflect.TypeOf, or the type argument of
+// reflect.TypeFor. It returns a nil type for other calls.
+func inspectedType(pass *analysis.Pass, call *ast.CallExpr) (string, types.Type) {
+	if fn, ok := typeutil.Callee(pass.TypesInfo, call).(*types.Builtin); ok && len(call.Args) == 1 {
+		switch fn.Name() {
+		case "Sizeof", "Alignof":
+			// unsafe.Sizeof(p) is the size of the pointer
+			if t := pass.TypesInfo.TypeOf(call.Args[0]); t != nil && !isPointer(t) {
+				return "layout is inspected with unsafe." + fn.Name(), t
+			}
+		case "Offsetof":
+			if sel, ok := ast.Unparen(call.Args[0]).(*ast.SelectorExpr); ok {
+				return "layout is inspected with unsafe.Offsetof", pass.TypesInfo.TypeOf(sel.X)
+			}
+		}
+
+		return "", nil
+	}
+
+	fn := typeutil.StaticCallee(pass.TypesInfo, call)
+	if fn == nil || fn.Pkg() == nil || fn.Pkg().Path() != "reflect" {
+		return "", nil
+	}
+
+	switch fn.Name() {
+	case "TypeOf":
+		if len(call.Args) == 1 {
+			return "is inspected with reflect.TypeOf", pass.TypesInfo.TypeOf(call.Args[0])
+		}
+	case "TypeFor":
+		if inst, ok := pass.TypesInfo.Instances[calleeIdent(call.Fun)]; ok && inst.TypeArgs.Len() == 1 {
+			return "is inspected with reflect.TypeFor", inst.TypeArgs.At(0)
+		}
+	}
+
+	return "", nil
+}
+
+// calleeIdent returns the identifier naming the function of a call expression,
+// as in f, pkg.f, and pkg.f[T], or nil.
+func calleeIdent(fun ast.Expr) *ast.Ident {
+	switch f := ast.Unparen(fun).(type) {
+	case *ast.Ident:
+		return f
+	case *ast.SelectorExpr:
+		return f.Sel
+	case *ast.IndexExpr:
+		return calleeIdent(f.X)
+	case *ast.IndexListExpr:
+		return calleeIdent(f.X)
+	}
+
+	return nil
+}
+
+// record records a low-level use of the pointer type t.
+func (u unsafeTypes) record(t types.Type, pos token.Pos, what string) {
+	if ptr, ok := t.Underlying().(*types.Pointer); ok {
+		u.add(ptr.Elem(), pos, what)
+	}
+}
+
+// recordValue records a low-level use of t, or of the type t points to.
+func (u unsafeTypes) recordValue(t types.Type, pos token.Pos, what string) {
+	if ptr, ok := types.Unalias(t).(*types.Pointer); ok {
+		t = ptr.Elem()
+	}
+
+	u.add(t, pos, what)
+}
+
+// add records a low-level use of t if it is a named type.
+func (u unsafeTypes) add(t types.Type, pos token.Pos, what string) {
+	named, ok := types.Unalias(t).(*types.Named)
+	if !ok {
+		return
+	}
+
+	if obj := named.Origin().Obj(); !u.lookup(named).pos.IsValid() {
+		u[obj] = unsafeUse{pos: pos, what: what}
+	}
+}
+
+// lookup returns the first low-level use of t, if any.
+func (u unsafeTypes) lookup(t types.Type) unsafeUse {
+	named, ok := types.Unalias(t).(*types.Named)
+	if !ok {
+		return unsafeUse{}
+	}
+
+	return u[named.Origin().Obj()]
+}
+
+// skipped explains that the finding about t at pos is skipped for the
+// low-level use of t, if any, and reports whether there is one.
+func (u unsafeTypes) skipped(pass *analysis.Pass, st *state, pos token.Pos, t types.Type) bool {
+	use := u.lookup(t)
+	if !use.pos.IsValid() {
+		return false
+	}
+
+	st.explain.skipped(pass, pos, "%s %s at line %d", typeString(pass, t), use.what, lineOf(pass, use.pos))
+
+	return true
+}
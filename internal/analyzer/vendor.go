@@ -0,0 +1,68 @@
+package analyzer
+
+import (
+	"go/build"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+
+	"golang.org/x/tools/go/analysis"
+)
+
+// modCache is the module cache directory, or "" if it cannot be determined.
+var modCache = sync.OnceValue(func() string {
+	if dir := os.Getenv("GOMODCACHE"); dir != "" {
+		return filepath.Clean(dir)
+	}
+
+	gopath := filepath.SplitList(build.Default.GOPATH)
+	if len(gopath) == 0 || gopath[0] == "" {
+		return ""
+	}
+
+	return filepath.Join(gopath[0], "pkg", "mod")
+})
+
+// isThirdParty reports whether filename is vendored, i.e. below a vendor
+// directory at any depth, or in the module cache.
+func isThirdParty(filename string) bool {
+	dir := filepath.Dir(filepath.Clean(filename))
+	if slices.Contains(strings.Split(filepath.ToSlash(dir), "/"), "vendor") {
+		return true
+	}
+
+	cache := modCache()
+
+	return cache != "" && strings.HasPrefix(dir, cache+string(filepath.Separator))
+}
+
+// isThirdPartyPackage reports whether all files of the package are third-party
+// code (see isThirdParty).
+func isThirdPartyPackage(pass *analysis.Pass) bool {
+	if len(pass.Files) == 0 {
+		return false
+	}
+
+	for _, f := range pass.Files {
+		if !isThirdParty(pass.Fset.File(f.Pos()).Name()) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// isStandardLibraryPackage reports whether the files of the package are in
+// GOROOT. Standard library packages are only analyzed for the facts of their
+// dependents: drivers never report on them.
+func isStandardLibraryPackage(pass *analysis.Pass) bool {
+	if len(pass.Files) == 0 || build.Default.GOROOT == "" {
+		return false
+	}
+
+	src := filepath.Join(filepath.Clean(build.Default.GOROOT), "src") + string(filepath.Separator)
+
+	return strings.HasPrefix(pass.Fset.File(pass.Files[0].Pos()).Name(), src)
+}
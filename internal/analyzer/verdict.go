@@ -0,0 +1,351 @@
+package analyzer
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"go/types"
+	"reflect"
+	"sort"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/analysis/passes/inspect"
+	"golang.org/x/tools/go/ast/inspector"
+
+	"github.com/mickamy/pointless/internal/messages"
+)
+
+// Verdicts of struct types (see NewVerdict).
+const (
+	// VerdictValueFriendly means that the type is small and nothing relies on
+	// pointers to it: values can replace the pointers.
+	VerdictValueFriendly = "value-friendly"
+	// VerdictPointerRequired means that methods mutate the receiver, nil
+	// pointers stand for missing values, or fields must not be copied.
+	VerdictPointerRequired = "pointer-required"
+	// VerdictTooLarge means that the type is larger than the threshold.
+	VerdictTooLarge = "too large"
+)
+
+// Verdict is the type-level guidance for a struct type of a package,
+// aggregating the uses the per-site checks rely on.
+type Verdict struct {
+	// Type is the package-path-qualified name of the type.
+	Type string
+	// Position is the position of the type's declaration.
+	Position token.Position
+	// Size is the size of the type in bytes.
+	Size int64
+	// Verdict is VerdictValueFriendly, VerdictPointerRequired, or
+	// VerdictTooLarge.
+	Verdict string
+	// Evidence lists the uses backing the verdict, most telling first.
+	Evidence []Evidence
+}
+
+// Evidence is a use backing a verdict.
+type Evidence struct {
+	// Reason is the kind of use: "mutated receiver", "nil", "sync field", or
+	// "size".
+	Reason string
+	// Message describes the use.
+	Message string
+	// Position is the position of the use, if any.
+	Position token.Position
+}
+
+// verdictsType is the ResultType of the analyzers returned by NewVerdict.
+var verdictsType = reflect.TypeFor[[]Verdict]()
+
+// NewVerdict returns an analyzer whose result is the Verdict of every
+// non-generic struct type declared at the package level, in declaration
+// order. Only opts.Threshold, opts.ThresholdUnit, and the settings of
+// opts.Config deciding which files are analyzed apply.
+func NewVerdict(opts Options) *analysis.Analyzer {
+	return &analysis.Analyzer{
+		Name:       "pointlessverdict",
+		Doc:        "summarizes, per struct type, whether values can replace pointers",
+		URL:        Analyzer.URL,
+		Run:        func(pass *analysis.Pass) (any, error) { return runVerdict(pass, opts) },
+		Requires:   []*analysis.Analyzer{inspect.Analyzer},
+		ResultType: verdictsType,
+	}
+}
+
+func runVerdict(pass *analysis.Pass, opts Options) (any, error) {
+	ispct, ok := pass.ResultOf[inspect.Analyzer].(*inspector.Inspector)
+	if !ok || !opts.Config.IncludeVendor && isThirdPartyPackage(pass) {
+		return []Verdict(nil), nil
+	}
+
+	limit, err := thresholdBytes(pass, opts.Threshold, opts.ThresholdUnit)
+	if err != nil {
+		return nil, err
+	}
+
+	msg, err := messages.NewPrinter(opts.Config.Lang)
+	if err != nil {
+		return nil, fmt.Errorf("invalid lang: %w", err)
+	}
+
+	mutations := findReceiverMutations(pass, ispct, false)
+	nils := buildNilIndex(pass, ispct)
+	returns := nilResults(pass, ispct)
+
+	var result []Verdict
+
+	for _, f := range pass.Files {
+		if opts.Config.ShouldExclude(pass.Fset.File(f.Pos()).Name()) {
+			continue
+		}
+
+		for _, decl := range f.Decls {
+			gd, ok := decl.(*ast.GenDecl)
+			if !ok || gd.Tok != token.TYPE {
+				continue
+			}
+
+			for _, spec := range gd.Specs {
+				ts, ok := spec.(*ast.TypeSpec)
+				if !ok || ts.TypeParams != nil || ts.Assign.IsValid() {
+					continue
+				}
+
+				tn, ok := pass.TypesInfo.Defs[ts.Name].(*types.TypeName)
+				if !ok {
+					continue
+				}
+
+				if _, ok := tn.Type().Underlying().(*types.Struct); !ok {
+					continue
+				}
+
+				result = append(result, typeVerdict(pass, msg, tn, limit, mutations, nils, returns[tn]))
+			}
+		}
+	}
+
+	return result, nil
+}
+
+// typeVerdict returns the verdict of the struct type tn, with the evidence
+// described by msg.
+func typeVerdict(pass *analysis.Pass, msg *messages.Printer, tn *types.TypeName, limit int, mutations map[*ast.FuncDecl]token.Pos, nils nilIndex, returns []nilResult) Verdict {
+	size := sizeOf(pass, tn.Type())
+	v := Verdict{
+		Type:     types.TypeString(tn.Type(), nil),
+		Position: pass.Fset.Position(tn.Pos()),
+		Size:     size,
+	}
+
+	if size > int64(limit) {
+		v.Verdict = VerdictTooLarge
+		v.Evidence = []Evidence{{Reason: "size", Message: msg.Sprintf(messages.EvidenceTooLarge, size, limit)}}
+
+		return v
+	}
+
+	evidence := func(reason string, pos token.Pos, id messages.ID, args ...any) {
+		v.Evidence = append(v.Evidence, Evidence{Reason: reason, Message: msg.Sprintf(id, args...), Position: pass.Fset.Position(pos)})
+	}
+
+	for _, field := range lockFields(tn.Type(), nil) {
+		evidence("sync field", field.Pos(), messages.EvidenceSyncField, field.Name(), typeString(pass, field.Type()))
+	}
+
+	for fn, pos := range mutations {
+		if pos.IsValid() && isPointerReceiverOf(pass, fn, tn) {
+			evidence("mutated receiver", pos, messages.EvidenceMutatedReceiver, tn.Name(), fn.Name.Name)
+		}
+	}
+
+	for obj, pos := range nils.values {
+		if pointsTo(obj.Type(), tn) {
+			evidence("nil", pos, messages.EvidenceNil, obj.Name())
+		}
+	}
+
+	for _, r := range returns {
+		evidence("nil", r.pos, messages.EvidenceNilReturn, r.fn.Name.Name, tn.Name())
+	}
+
+	// Map iteration order is random: sort the evidence by reason, then position
+	order := map[string]int{"sync field": 0, "mutated receiver": 1, "nil": 2}
+	sort.SliceStable(v.Evidence, func(i, j int) bool {
+		a, b := v.Evidence[i], v.Evidence[j]
+		if order[a.Reason] != order[b.Reason] {
+			return order[a.Reason] < order[b.Reason]
+		}
+
+		if a.Position.Filename != b.Position.Filename {
+			return a.Position.Filename < b.Position.Filename
+		}
+
+		return a.Position.Offset < b.Position.Offset
+	})
+
+	if len(v.Evidence) > 0 {
+		v.Verdict = VerdictPointerRequired
+
+		return v
+	}
+
+	v.Verdict = VerdictValueFriendly
+	v.Evidence = []Evidence{{Reason: "size", Message: msg.Sprintf(messages.EvidenceValueFriendly, size, limit)}}
+
+	return v
+}
+
+// lockFields returns the fields of the struct t, or of the structs it holds
+// by value, whose values must not be copied: those of the types of sync and
+// sync/atomic, and those whose pointers implement sync.Locker.
+func lockFields(t types.Type, seen map[types.Type]bool) []*types.Var {
+	s, ok := t.Underlying().(*types.Struct)
+	if !ok || seen[t] {
+		return nil
+	}
+
+	if seen == nil {
+		seen = make(map[types.Type]bool)
+	}
+
+	seen[t] = true
+
+	var result []*types.Var
+
+	for i := range s.NumFields() {
+		f := s.Field(i)
+
+		if isLock(f.Type()) {
+			result = append(result, f)
+
+			continue
+		}
+
+		if len(lockFields(f.Type(), seen)) > 0 {
+			result = append(result, f)
+		}
+	}
+
+	return result
+}
+
+// isLock reports whether values of t must not be copied (see lockFields).
+func isLock(t types.Type) bool {
+	if named, ok := types.Unalias(t).(*types.Named); ok && named.Obj().Pkg() != nil {
+		switch named.Obj().Pkg().Path() {
+		case "sync", "sync/atomic":
+			return true
+		}
+	}
+
+	if _, ok := t.Underlying().(*types.Interface); ok {
+		return false
+	}
+
+	ptr := types.NewMethodSet(types.NewPointer(t))
+
+	return ptr.Lookup(nil, "Lock") != nil && ptr.Lookup(nil, "Unlock") != nil
+}
+
+// isPointerReceiverOf reports whether fn is a method of *tn.
+func isPointerReceiverOf(pass *analysis.Pass, fn *ast.FuncDecl, tn *types.TypeName) bool {
+	if fn.Recv == nil || len(fn.Recv.List) == 0 {
+		return false
+	}
+
+	return pointsTo(pass.TypesInfo.TypeOf(fn.Recv.List[0].Type), tn)
+}
+
+// pointsTo reports whether t is a pointer to the named type tn.
+func pointsTo(t types.Type, tn *types.TypeName) bool {
+	if t == nil {
+		return false
+	}
+
+	ptr, ok := types.Unalias(t).(*types.Pointer)
+	if !ok {
+		return false
+	}
+
+	named, ok := types.Unalias(ptr.Elem()).(*types.Named)
+
+	return ok && named.Origin().Obj() == tn
+}
+
+// nilResult is a function returning a nil pointer.
+type nilResult struct {
+	fn  *ast.FuncDecl
+	pos token.Pos
+}
+
+// nilResults maps the named types of the package to the functions returning
+// nil pointers to them, with their first such return.
+func nilResults(pass *analysis.Pass, inspect *inspector.Inspector) map[*types.TypeName][]nilResult {
+	result := make(map[*types.TypeName][]nilResult)
+	seen := make(map[*ast.FuncDecl]map[*types.TypeName]bool)
+
+	inspect.WithStack([]ast.Node{(*ast.ReturnStmt)(nil)}, func(n ast.Node, push bool, stack []ast.Node) bool {
+		if !push {
+			return true
+		}
+
+		// The innermost function must be a declaration: function literals have results of their own
+		var fn *ast.FuncDecl
+
+		for i := len(stack) - 2; i >= 0 && fn == nil; i-- {
+			switch f := stack[i].(type) {
+			case *ast.FuncLit:
+				return true
+			case *ast.FuncDecl:
+				fn = f
+			}
+		}
+
+		if fn == nil {
+			return true
+		}
+
+		obj, ok := pass.TypesInfo.Defs[fn.Name].(*types.Func)
+		if !ok {
+			return true
+		}
+
+		results := obj.Signature().Results()
+		ret, _ := n.(*ast.ReturnStmt)
+
+		if results.Len() != len(ret.Results) {
+			return true
+		}
+
+		for i, e := range ret.Results {
+			if !isNil(e) {
+				continue
+			}
+
+			ptr, ok := types.Unalias(results.At(i).Type()).(*types.Pointer)
+			if !ok {
+				continue
+			}
+
+			named, ok := types.Unalias(ptr.Elem()).(*types.Named)
+			if !ok || named.Obj().Pkg() != pass.Pkg {
+				continue
+			}
+
+			tn := named.Origin().Obj()
+			if seen[fn] == nil {
+				seen[fn] = make(map[*types.TypeName]bool)
+			}
+
+			if !seen[fn][tn] {
+				seen[fn][tn] = true
+				result[tn] = append(result[tn], nilResult{fn: fn, pos: ret.Pos()})
+			}
+		}
+
+		return true
+	})
+
+	return result
+}
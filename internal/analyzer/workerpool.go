@@ -0,0 +1,231 @@
+package analyzer
+
+import (
+	"go/ast"
+	"go/token"
+	"go/types"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/ast/inspector"
+
+	"github.com/mickamy/pointless/internal/messages"
+	"github.com/mickamy/pointless/internal/rules"
+)
+
+// jobChannels records the channels of pointers the package declares, which
+// may feed worker pools:
+//
+//	jobs := make(chan *Job, n)      // a new variable
+//	var jobs = make(chan *Job)      // a variable without a declared type
+//	var jobs chan *Job              // a declared variable
+type jobChannels struct {
+	// vars maps the chan *T type expressions deciding the type of a variable
+	// to the variable.
+	vars map[*ast.ChanType]types.Object
+	// nils maps channels to the first nil sent on them or nil check of a
+	// value received from them, here or in a function they are passed to.
+	nils map[types.Object]token.Pos
+}
+
+// findJobChannels finds the pointer channels declared by the package and
+// where their elements are nil, given the nil uses of the package.
+func findJobChannels(pass *analysis.Pass, inspect *inspector.Inspector, nils nilIndex) jobChannels {
+	result := jobChannels{
+		vars: make(map[*ast.ChanType]types.Object),
+		nils: make(map[types.Object]token.Pos),
+	}
+
+	// received maps variables holding values received from a channel to it
+	received := make(map[types.Object]types.Object)
+
+	// calls holds arguments passed to parameters of functions in this package
+	var calls []argumentEdge
+
+	declare := func(name *ast.Ident, typ ast.Expr) {
+		ch, ok := ast.Unparen(typ).(*ast.ChanType)
+		if !ok || !isPointer(pass.TypesInfo.TypeOf(ch.Value)) {
+			return
+		}
+
+		if obj := pass.TypesInfo.Defs[name]; obj != nil {
+			result.vars[ch] = obj
+		}
+	}
+
+	nodeFilter := []ast.Node{
+		(*ast.AssignStmt)(nil),
+		(*ast.ValueSpec)(nil),
+		(*ast.RangeStmt)(nil),
+		(*ast.SendStmt)(nil),
+		(*ast.CallExpr)(nil),
+	}
+
+	inspect.Preorder(nodeFilter, func(n ast.Node) {
+		switch node := n.(type) {
+		case *ast.AssignStmt:
+			if node.Tok == token.DEFINE && len(node.Lhs) == len(node.Rhs) {
+				for i, lhs := range node.Lhs {
+					if name, ok := lhs.(*ast.Ident); ok {
+						declare(name, madeType(pass, node.Rhs[i]))
+					}
+				}
+			}
+
+			// v := <-ch, v, ok := <-ch, and case v := <-ch in a select
+			if len(node.Rhs) == 1 {
+				if recv, ok := ast.Unparen(node.Rhs[0]).(*ast.UnaryExpr); ok && recv.Op == token.ARROW {
+					if v, ch := objectOf(pass, node.Lhs[0]), objectOf(pass, recv.X); v != nil && ch != nil {
+						received[v] = ch
+					}
+				}
+			}
+		case *ast.ValueSpec:
+			for i, name := range node.Names {
+				switch {
+				case node.Type != nil:
+					declare(name, node.Type)
+				case len(node.Values) == len(node.Names):
+					declare(name, madeType(pass, node.Values[i]))
+				}
+			}
+		case *ast.RangeStmt:
+			// for v := range ch
+			t := pass.TypesInfo.TypeOf(node.X)
+			if t == nil || node.Key == nil {
+				return
+			}
+
+			if _, ok := t.Underlying().(*types.Chan); !ok {
+				return
+			}
+
+			if v, ch := objectOf(pass, node.Key), objectOf(pass, node.X); v != nil && ch != nil {
+				received[v] = ch
+			}
+		case *ast.SendStmt:
+			if ch := objectOf(pass, node.Chan); ch != nil && isNil(node.Value) {
+				markFirst(result.nils, ch, node.Pos())
+			}
+		case *ast.CallExpr:
+			calls = append(calls, argumentEdges(pass, node)...)
+		}
+	})
+
+	// A nil check of a received value is one of the channel's elements
+	for v, ch := range received {
+		if pos, ok := nils.values[v]; ok {
+			markFirst(result.nils, ch, pos)
+		}
+	}
+
+	// A worker checking the values received from its parameter does so for
+	// the caller's channel too; iterate to follow chains of helpers.
+	for changed := true; changed; {
+		changed = false
+
+		for _, call := range calls {
+			if _, ok := result.nils[call.arg]; ok {
+				continue
+			}
+
+			if _, ok := result.nils[call.param]; ok {
+				result.nils[call.arg] = call.pos
+				changed = true
+			}
+		}
+	}
+
+	return result
+}
+
+// madeType returns the type argument of a make call, or nil.
+func madeType(pass *analysis.Pass, e ast.Expr) ast.Expr {
+	call, ok := ast.Unparen(e).(*ast.CallExpr)
+	if !ok || len(call.Args) < 1 {
+		return nil
+	}
+
+	ident, ok := ast.Unparen(call.Fun).(*ast.Ident)
+	if !ok || ident.Name != "make" {
+		return nil
+	}
+
+	if _, ok := pass.TypesInfo.Uses[ident].(*types.Builtin); !ok {
+		return nil
+	}
+
+	return call.Args[0]
+}
+
+// checkJobChannel checks a chan *T declaring a channel variable (see
+// jobChannels). Sending the pointers of small jobs to a worker pool shares
+// each job between the sender and a worker; sending values gives each worker
+// its own copy.
+func checkJobChannel(pass *analysis.Pass, ch *ast.ChanType, st *state) {
+	obj, ok := st.jobChannels.vars[ch]
+	if !ok || !st.ruleEnabled(rules.WorkerPool) {
+		return
+	}
+
+	// Skip if no goroutine receives the channel, so that it feeds no worker pool
+	shared := st.goroutines.values[obj]
+	if !shared.IsValid() {
+		st.explain.skipped(pass, ch.Pos(), "%s is not shared with a goroutine", obj.Name())
+
+		return
+	}
+
+	if pos := st.jobChannels.nils[obj]; pos.IsValid() {
+		st.explain.skipped(pass, ch.Pos(), "nil is sent on %s or received from it at line %d", obj.Name(), lineOf(pass, pos))
+
+		return
+	}
+
+	elem, named, ok := pointerElem(pass, ch.Value)
+	if !ok || named != nil {
+		return
+	}
+
+	t, size, ok := workItem(pass, st, ch.Pos(), elem)
+	if !ok {
+		return
+	}
+
+	typeName := typeString(pass, t)
+	st.reportf(pass, t, rules.WorkerPool, ch, messages.WorkerPoolChannel, typeName, typeName, obj.Name(), lineOf(pass, shared), size, st.bounds())
+}
+
+// checkSharedSlice handles a []*T slice held by the variable obj whose
+// elements reach the goroutine started at shared, which slice-pointer leaves
+// alone: the elements are work items. The opt-in worker-pool rule reports it
+// with the trade-offs of a slice of values.
+func checkSharedSlice(pass *analysis.Pass, arr *ast.ArrayType, elem types.Type, obj types.Object, shared token.Pos, st *state) {
+	if !st.ruleEnabled(rules.WorkerPool) {
+		st.explain.skipped(pass, arr.Pos(), "elements are shared with a goroutine at line %d; worker-pool reports it", lineOf(pass, shared))
+
+		return
+	}
+
+	t, size, ok := workItem(pass, st, arr.Pos(), elem)
+	if !ok {
+		return
+	}
+
+	typeName := typeString(pass, t)
+	st.reportf(pass, t, rules.WorkerPool, arr, messages.WorkerPoolSlice, typeName, typeName, obj.Name(), lineOf(pass, shared), size, st.bounds())
+}
+
+// workItem is smallStructType for the elements handed to goroutines, which
+// are also skipped if the package writes through pointers to them: workers
+// then likely store their results in the items.
+func workItem(pass *analysis.Pass, st *state, pos token.Pos, t types.Type) (types.Type, int64, bool) {
+	if named, ok := types.Unalias(t).(*types.Named); ok {
+		if at := st.pointerWrites[named.Origin().Obj()]; at.IsValid() {
+			st.explain.skipped(pass, pos, "%s values are written through a pointer at line %d", named.Obj().Name(), lineOf(pass, at))
+
+			return nil, 0, false
+		}
+	}
+
+	return smallStructType(pass, st, pos, t)
+}
@@ -0,0 +1,206 @@
+package analyzer
+
+import (
+	"go/ast"
+	"go/token"
+	"go/types"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/ast/inspector"
+
+	"github.com/mickamy/pointless/internal/messages"
+	"github.com/mickamy/pointless/internal/rules"
+)
+
+// zeroValues maps the struct types of the package whose zero value is likely
+// not usable as is to the first sign of it: a constructor setting unexported
+// fields, which code outside the package cannot set, or a method writing to a
+// map or sending on a channel held in a field, which is nil in the zero value.
+type zeroValues map[*types.TypeName]token.Pos
+
+// findZeroValues finds the must-initialize signs of the package's struct types.
+// Constructors are the functions, not methods, with a T or *T result.
+func findZeroValues(pass *analysis.Pass, inspect *inspector.Inspector) zeroValues {
+	result := make(zeroValues)
+
+	block := func(tn *types.TypeName, pos token.Pos) {
+		if tn != nil && tn.Pkg() == pass.Pkg && !result[tn].IsValid() {
+			result[tn] = pos
+		}
+	}
+
+	inspect.Preorder([]ast.Node{(*ast.FuncDecl)(nil)}, func(n ast.Node) {
+		fn, ok := n.(*ast.FuncDecl)
+		if !ok || fn.Body == nil {
+			return
+		}
+
+		obj, ok := pass.TypesInfo.Defs[fn.Name].(*types.Func)
+		if !ok {
+			return
+		}
+
+		if fn.Recv != nil {
+			if len(fn.Recv.List) > 0 {
+				block(namedObject(derefType(pass.TypesInfo.TypeOf(fn.Recv.List[0].Type))), nilFieldWrite(pass, fn.Body))
+			}
+
+			return
+		}
+
+		constructs := make(map[*types.TypeName]bool)
+
+		results := obj.Signature().Results()
+		for i := range results.Len() {
+			if tn := namedObject(derefType(results.At(i).Type())); tn != nil {
+				constructs[tn] = true
+			}
+		}
+
+		if len(constructs) == 0 {
+			return
+		}
+
+		ast.Inspect(fn.Body, func(n ast.Node) bool {
+			var tn *types.TypeName
+
+			switch node := n.(type) {
+			case *ast.CompositeLit:
+				tn = namedObject(pass.TypesInfo.TypeOf(node))
+				if constructs[tn] && setsUnexportedField(pass, node) {
+					block(tn, node.Pos())
+				}
+			case *ast.AssignStmt:
+				for _, lhs := range node.Lhs {
+					sel, ok := ast.Unparen(lhs).(*ast.SelectorExpr)
+					if !ok {
+						continue
+					}
+
+					field, ok := pass.TypesInfo.ObjectOf(sel.Sel).(*types.Var)
+					if !ok || !field.IsField() || field.Exported() {
+						continue
+					}
+
+					if tn = namedObject(derefType(pass.TypesInfo.TypeOf(sel.X))); constructs[tn] {
+						block(tn, node.Pos())
+					}
+				}
+			}
+
+			return true
+		})
+	})
+
+	return result
+}
+
+// derefType returns the element type of t if it is a pointer, or t.
+func derefType(t types.Type) types.Type {
+	if ptr, ok := types.Unalias(t).(*types.Pointer); ok {
+		return ptr.Elem()
+	}
+
+	return t
+}
+
+// setsUnexportedField reports whether the struct literal lit sets an
+// unexported field.
+func setsUnexportedField(pass *analysis.Pass, lit *ast.CompositeLit) bool {
+	s, ok := pass.TypesInfo.TypeOf(lit).Underlying().(*types.Struct)
+	if !ok {
+		return false
+	}
+
+	for i, elt := range lit.Elts {
+		if kv, ok := elt.(*ast.KeyValueExpr); ok {
+			if key, ok := kv.Key.(*ast.Ident); ok && !ast.IsExported(key.Name) {
+				return true
+			}
+
+			continue
+		}
+
+		if i < s.NumFields() && !s.Field(i).Exported() {
+			return true
+		}
+	}
+
+	return false
+}
+
+// nilFieldWrite returns the first statement of body writing to a map field
+// (x.m[k] = v) or sending on a channel field (x.ch <- v), or token.NoPos.
+func nilFieldWrite(pass *analysis.Pass, body *ast.BlockStmt) token.Pos {
+	pos := token.NoPos
+
+	isField := func(e ast.Expr) bool {
+		sel, ok := ast.Unparen(e).(*ast.SelectorExpr)
+		if !ok {
+			return false
+		}
+
+		v, ok := pass.TypesInfo.ObjectOf(sel.Sel).(*types.Var)
+
+		return ok && v.IsField()
+	}
+
+	ast.Inspect(body, func(n ast.Node) bool {
+		if pos.IsValid() {
+			return false
+		}
+
+		switch node := n.(type) {
+		case *ast.AssignStmt:
+			for _, lhs := range node.Lhs {
+				idx, ok := ast.Unparen(lhs).(*ast.IndexExpr)
+				if !ok || !isField(idx.X) {
+					continue
+				}
+
+				if _, ok := pass.TypesInfo.TypeOf(idx.X).Underlying().(*types.Map); ok {
+					pos = node.Pos()
+				}
+			}
+		case *ast.SendStmt:
+			if isField(node.Chan) {
+				pos = node.Pos()
+			}
+		}
+
+		return true
+	})
+
+	return pos
+}
+
+// zeroValueRules lists the rules suggesting to return values, for which a
+// usable zero value makes the suggestion safer: the callers of a function
+// returning T can declare a T too, and (T, error) results return it with
+// errors.
+var zeroValueRules = map[string]bool{
+	rules.ReturnPointer:      true,
+	rules.ReturnPointerError: true,
+}
+
+// noteZeroValue notes in the message of d, suggesting to return values of
+// subject, that the zero value of subject is ready to use, if no sign says
+// otherwise. Types of other packages are not noted: their constructors are
+// not analyzed.
+func (st *state) noteZeroValue(pass *analysis.Pass, subject types.Type, d *analysis.Diagnostic) {
+	if !zeroValueRules[d.Category] {
+		return
+	}
+
+	obj := namedObject(derefType(subject))
+	if obj == nil || obj.Pkg() != pass.Pkg || st.zeroValues[obj].IsValid() {
+		return
+	}
+
+	// Types without fields have nothing to initialize either way
+	if s, ok := obj.Type().Underlying().(*types.Struct); !ok || s.NumFields() == 0 {
+		return
+	}
+
+	d.Message = st.msg.Sprintf(messages.ZeroValueUsable, d.Message, obj.Name())
+}
@@ -0,0 +1,216 @@
+// Package apicompat compares the exported API of two versions of a package,
+// in the spirit of golang.org/x/exp/apidiff, to tell whether a change would
+// break importers.
+package apicompat
+
+import (
+	"fmt"
+	"go/types"
+	"sort"
+	"strings"
+)
+
+// Incompatible returns the changes from before to after that can break code
+// importing the package, sorted. The packages must be type-checked
+// separately from sources with the same import path. Both may share imported
+// packages.
+//
+// Additions are compatible, except for methods added to exported interfaces.
+// Parameter and result names are ignored; moving a method from the pointer
+// receiver to the value receiver keeps both method sets intact and is
+// compatible too.
+func Incompatible(before, after *types.Package) []string {
+	var changes []string
+
+	for _, name := range before.Scope().Names() {
+		o := before.Scope().Lookup(name)
+		if !o.Exported() {
+			continue
+		}
+
+		n := after.Scope().Lookup(name)
+		if n == nil || !n.Exported() {
+			changes = append(changes, name+": removed")
+
+			continue
+		}
+
+		changes = append(changes, compareObjects(name, o, n)...)
+	}
+
+	sort.Strings(changes)
+
+	return changes
+}
+
+func compareObjects(name string, o, n types.Object) []string {
+	if kind(o) != kind(n) {
+		return []string{fmt.Sprintf("%s: changed from %s to %s", name, kind(o), kind(n))}
+	}
+
+	switch o := o.(type) {
+	case *types.TypeName:
+		return compareTypes(name, o.Type(), n.Type())
+	case *types.Func:
+		if a, b := typeString(o.Type()), typeString(n.Type()); a != b {
+			return []string{fmt.Sprintf("%s: changed from %s to %s", name, a, b)}
+		}
+	default:
+		if a, b := typeString(o.Type()), typeString(n.Type()); a != b {
+			return []string{fmt.Sprintf("%s: type changed from %s to %s", name, a, b)}
+		}
+	}
+
+	return nil
+}
+
+// compareTypes compares exported types: their underlying types and the method
+// sets of T and *T.
+func compareTypes(name string, o, n types.Type) []string {
+	var changes []string
+
+	switch ou := o.Underlying().(type) {
+	case *types.Struct:
+		nu, ok := n.Underlying().(*types.Struct)
+		if !ok {
+			return []string{fmt.Sprintf("%s: changed from %s to %s", name, typeString(ou), typeString(n.Underlying()))}
+		}
+
+		changes = append(changes, compareFields(name, ou, nu)...)
+	case *types.Interface:
+		// Any change to an interface breaks either its callers or its implementers
+		if a, b := typeString(ou), typeString(n.Underlying()); a != b {
+			return []string{fmt.Sprintf("%s: changed from %s to %s", name, a, b)}
+		}
+	default:
+		if a, b := typeString(ou), typeString(n.Underlying()); a != b {
+			return []string{fmt.Sprintf("%s: changed from %s to %s", name, a, b)}
+		}
+	}
+
+	changes = append(changes, compareMethods(name, o, n)...)
+
+	if _, isPtr := o.Underlying().(*types.Pointer); !isPtr && !types.IsInterface(o) {
+		changes = append(changes, compareMethods("*"+name, types.NewPointer(o), types.NewPointer(n))...)
+	}
+
+	return changes
+}
+
+func compareFields(name string, o, n *types.Struct) []string {
+	var changes []string
+
+	fields := make(map[string]*types.Var, n.NumFields())
+	for i := range n.NumFields() {
+		fields[n.Field(i).Name()] = n.Field(i)
+	}
+
+	for i := range o.NumFields() {
+		f := o.Field(i)
+		if !f.Exported() {
+			continue
+		}
+
+		nf, ok := fields[f.Name()]
+		if !ok {
+			changes = append(changes, fmt.Sprintf("%s.%s: removed", name, f.Name()))
+
+			continue
+		}
+
+		if a, b := typeString(f.Type()), typeString(nf.Type()); a != b {
+			changes = append(changes, fmt.Sprintf("%s.%s: type changed from %s to %s", name, f.Name(), a, b))
+		}
+	}
+
+	return changes
+}
+
+// compareMethods compares the exported methods in the method sets of o and n.
+func compareMethods(name string, o, n types.Type) []string {
+	var changes []string
+
+	om, nm := types.NewMethodSet(o), types.NewMethodSet(n)
+
+	for i := range om.Len() {
+		m := om.At(i).Obj()
+		if !m.Exported() {
+			continue
+		}
+
+		sel := nm.Lookup(m.Pkg(), m.Name())
+		if sel == nil {
+			changes = append(changes, fmt.Sprintf("%s.%s: removed from the method set", name, m.Name()))
+
+			continue
+		}
+
+		if a, b := typeString(m.Type()), typeString(sel.Obj().Type()); a != b {
+			changes = append(changes, fmt.Sprintf("%s.%s: changed from %s to %s", name, m.Name(), a, b))
+		}
+	}
+
+	return changes
+}
+
+// typeString formats t with package-path-qualified names and without
+// parameter and result names, so that types from separately type-checked
+// versions of a package compare equal if they match.
+func typeString(t types.Type) string {
+	if sig, ok := t.(*types.Signature); ok {
+		return signatureString(sig)
+	}
+
+	return types.TypeString(t, nil)
+}
+
+func signatureString(sig *types.Signature) string {
+	var b strings.Builder
+
+	b.WriteString("func")
+	writeTuple(&b, sig.Params(), sig.Variadic())
+
+	switch sig.Results().Len() {
+	case 0:
+	case 1:
+		b.WriteString(" " + typeString(sig.Results().At(0).Type()))
+	default:
+		b.WriteByte(' ')
+		writeTuple(&b, sig.Results(), false)
+	}
+
+	return b.String()
+}
+
+func writeTuple(b *strings.Builder, tuple *types.Tuple, variadic bool) {
+	b.WriteByte('(')
+
+	for i := range tuple.Len() {
+		if i > 0 {
+			b.WriteString(", ")
+		}
+
+		t := tuple.At(i).Type()
+		if s, ok := t.(*types.Slice); ok && variadic && i == tuple.Len()-1 {
+			b.WriteString("...")
+			t = s.Elem()
+		}
+
+		b.WriteString(typeString(t))
+	}
+
+	b.WriteByte(')')
+}
+
+func kind(obj types.Object) string {
+	switch obj.(type) {
+	case *types.TypeName:
+		return "type"
+	case *types.Func:
+		return "func"
+	case *types.Const:
+		return "const"
+	default:
+		return "var"
+	}
+}
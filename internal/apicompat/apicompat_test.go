@@ -0,0 +1,106 @@
+package apicompat_test
+
+import (
+	"go/ast"
+	"go/importer"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"reflect"
+	"testing"
+
+	"github.com/mickamy/pointless/internal/apicompat"
+)
+
+func check(t *testing.T, src string) *types.Package {
+	t.Helper()
+
+	fset := token.NewFileSet()
+
+	f, err := parser.ParseFile(fset, "p.go", "package p\n\n"+src, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	conf := types.Config{Importer: importer.Default()}
+
+	pkg, err := conf.Check("example.com/p", fset, []*ast.File{f}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return pkg
+}
+
+func TestIncompatible(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name          string
+		before, after string
+		want          []string
+	}{
+		{
+			name:   "pointer to value receiver",
+			before: "type T struct{}\n\nfunc (*T) M() {}",
+			after:  "type T struct{}\n\nfunc (T) M() {}",
+		},
+		{
+			name:   "value to pointer receiver",
+			before: "type T struct{}\n\nfunc (T) M() {}",
+			after:  "type T struct{}\n\nfunc (*T) M() {}",
+			want:   []string{"T.M: removed from the method set"},
+		},
+		{
+			name:   "pointer to value result",
+			before: "type T struct{}\n\nfunc New() *T { return nil }",
+			after:  "type T struct{}\n\nfunc New() T { return T{} }",
+			want:   []string{"New: changed from func() *example.com/p.T to func() example.com/p.T"},
+		},
+		{
+			name:   "renamed parameter",
+			before: "func F(a int) {}",
+			after:  "func F(b int) {}",
+		},
+		{
+			name:   "field type",
+			before: "type S struct{ Items []*int }",
+			after:  "type S struct{ Items []int }",
+			want:   []string{"S.Items: type changed from []*int to []int"},
+		},
+		{
+			name:   "removed and unexported",
+			before: "func F() {}\n\nfunc G() {}\n\nfunc h() {}",
+			after:  "func g() {}",
+			want:   []string{"F: removed", "G: removed"},
+		},
+		{
+			name:   "additions",
+			before: "type S struct{ A int }",
+			after:  "type S struct{ A, B int }\n\nfunc (S) M() {}\n\nvar V int",
+		},
+		{
+			name:   "interface method added",
+			before: "type I interface{ M() }",
+			after:  "type I interface{ M(); N() }",
+			want:   []string{"I: changed from interface{M()} to interface{M(); N()}"},
+		},
+		{
+			name:   "variadic",
+			before: "func F(xs ...int) {}",
+			after:  "func F(xs []int) {}",
+			want:   []string{"F: changed from func(...int) to func([]int)"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got := apicompat.Incompatible(check(t, tt.before), check(t, tt.after))
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
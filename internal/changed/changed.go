@@ -0,0 +1,203 @@
+// Package changed finds the packages affected by uncommitted changes in a git
+// work tree, so that local runs can skip everything else.
+package changed
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// Packages returns the import paths of the packages below dir that contain Go
+// files with uncommitted changes, including untracked files. If rdeps is set,
+// the packages below dir that import them, directly or transitively, are
+// included too.
+func Packages(dir string, rdeps bool) ([]string, error) {
+	files, err := changedFiles(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(files) == 0 {
+		return nil, nil
+	}
+
+	pkgs, err := listPackages(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	return affected(pkgs, files, rdeps), nil
+}
+
+// pkg is a package as listed by go list.
+type pkg struct {
+	ImportPath string
+	Dir        string
+	Imports    []string
+}
+
+// affected returns the import paths of the packages containing files, plus
+// their reverse dependencies among pkgs if rdeps is set, sorted.
+func affected(pkgs []pkg, files []string, rdeps bool) []string {
+	byDir := make(map[string]string, len(pkgs))
+	importers := make(map[string][]string)
+
+	for _, p := range pkgs {
+		byDir[filepath.Clean(p.Dir)] = p.ImportPath
+
+		for _, imp := range p.Imports {
+			importers[imp] = append(importers[imp], p.ImportPath)
+		}
+	}
+
+	seen := make(map[string]bool)
+
+	var queue []string
+
+	for _, f := range files {
+		if path, ok := byDir[filepath.Dir(f)]; ok && !seen[path] {
+			seen[path] = true
+			queue = append(queue, path)
+		}
+	}
+
+	for rdeps && len(queue) > 0 {
+		path := queue[0]
+		queue = queue[1:]
+
+		for _, importer := range importers[path] {
+			if !seen[importer] {
+				seen[importer] = true
+				queue = append(queue, importer)
+			}
+		}
+	}
+
+	result := make([]string, 0, len(seen))
+	for path := range seen {
+		result = append(result, path)
+	}
+
+	sort.Strings(result)
+
+	return result
+}
+
+// changedFiles returns the absolute paths of the existing .go files with
+// uncommitted changes in the git work tree containing dir.
+func changedFiles(dir string) ([]string, error) {
+	root, err := git(dir, "rev-parse", "--show-toplevel")
+	if err != nil {
+		return nil, err
+	}
+
+	status, err := git(dir, "status", "--porcelain=v1", "-z", "--untracked-files=all")
+	if err != nil {
+		return nil, err
+	}
+
+	var files []string
+
+	for _, name := range parseStatus(status) {
+		if !strings.HasSuffix(name, ".go") {
+			continue
+		}
+
+		path := filepath.Join(realPath(strings.TrimSpace(string(root))), filepath.FromSlash(name))
+
+		// Skip if deleted; its package, if any, is still found through its other files
+		if _, err := os.Stat(path); err != nil {
+			continue
+		}
+
+		files = append(files, path)
+	}
+
+	return files, nil
+}
+
+// parseStatus returns the paths in the output of `git status --porcelain=v1 -z`.
+// For renames and copies, only the new path is returned.
+func parseStatus(status []byte) []string {
+	var paths []string
+
+	entries := bytes.Split(status, []byte{0})
+	for i := 0; i < len(entries); i++ {
+		entry := entries[i]
+		if len(entry) < 4 {
+			continue
+		}
+
+		paths = append(paths, string(entry[3:]))
+
+		// The original path of a rename or copy follows as a separate entry
+		if entry[0] == 'R' || entry[0] == 'C' {
+			i++
+		}
+	}
+
+	return paths
+}
+
+// listPackages lists the packages below dir with go list.
+func listPackages(dir string) ([]pkg, error) {
+	var stdout, stderr bytes.Buffer
+
+	cmd := exec.Command("go", "list", "-e", "-f", "{{.ImportPath}}\t{{.Dir}}\t{{join .Imports \" \"}}", "./...")
+	cmd.Dir = dir
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		return nil, fmt.Errorf("listing packages: %w: %s", err, bytes.TrimSpace(stderr.Bytes()))
+	}
+
+	var pkgs []pkg
+
+	sc := bufio.NewScanner(&stdout)
+	for sc.Scan() {
+		fields := strings.Split(sc.Text(), "\t")
+		if len(fields) != 3 {
+			continue
+		}
+
+		pkgs = append(pkgs, pkg{ImportPath: fields[0], Dir: realPath(fields[1]), Imports: strings.Fields(fields[2])})
+	}
+
+	if err := sc.Err(); err != nil {
+		return nil, fmt.Errorf("reading go list output: %w", err)
+	}
+
+	return pkgs, nil
+}
+
+// realPath resolves symlinks in path, so that the paths reported by git and go
+// list compare equal. It returns path unchanged if it cannot be resolved.
+func realPath(path string) string {
+	if resolved, err := filepath.EvalSymlinks(path); err == nil {
+		return resolved
+	}
+
+	return path
+}
+
+func git(dir string, args ...string) ([]byte, error) {
+	var stdout, stderr bytes.Buffer
+
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		return nil, fmt.Errorf("running git %s: %w: %s", args[0], err, bytes.TrimSpace(stderr.Bytes()))
+	}
+
+	return stdout.Bytes(), nil
+}
@@ -0,0 +1,82 @@
+package changed_test
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/mickamy/pointless/internal/changed"
+)
+
+func TestPackages(t *testing.T) {
+	t.Parallel()
+
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+
+	dir := t.TempDir()
+	write := func(name, content string) {
+		t.Helper()
+
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0o750); err != nil {
+			t.Fatal(err)
+		}
+
+		if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	run := func(args ...string) {
+		t.Helper()
+
+		cmd := exec.Command(args[0], args[1:]...) //nolint:gosec // G204: fixed test commands
+		cmd.Dir = dir
+
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("%v: %v\n%s", args, err, out)
+		}
+	}
+
+	write("go.mod", "module example.com/m\n\ngo 1.24\n")
+	write("a/a.go", "package a\n\nfunc A() {}\n")
+	write("b/b.go", "package b\n\nimport \"example.com/m/a\"\n\nfunc B() { a.A() }\n")
+	write("c/c.go", "package c\n\nimport \"example.com/m/b\"\n\nfunc C() { b.B() }\n")
+	write("d/d.go", "package d\n")
+	run("git", "init", "-q")
+	run("git", "add", "-A")
+	run("git", "-c", "user.name=test", "-c", "user.email=test@example.com", "commit", "-q", "-m", "init")
+
+	got, err := changed.Packages(dir, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(got) != 0 {
+		t.Errorf("clean tree: got %v, want none", got)
+	}
+
+	write("a/a.go", "package a\n\nfunc A() { _ = 1 }\n")
+	write("d/new.go", "package d\n")
+
+	got, err = changed.Packages(dir, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if want := []string{"example.com/m/a", "example.com/m/d"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+
+	got, err = changed.Packages(dir, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if want := []string{"example.com/m/a", "example.com/m/b", "example.com/m/c", "example.com/m/d"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("with reverse dependencies: got %v, want %v", got, want)
+	}
+}
@@ -2,69 +2,312 @@
 package config
 
 import (
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
+	"slices"
+	"strings"
+	"time"
 
 	"gopkg.in/yaml.v3"
+
+	"github.com/mickamy/pointless/internal/rules"
+	"github.com/mickamy/pointless/internal/severity"
 )
 
-// Config represents the pointless configuration.
+// Config represents the pointless configuration. The doc tags describe the
+// settings in the JSON Schema of the config file (see Schema).
 type Config struct {
-	Threshold int      `yaml:"threshold"`
-	Exclude   []string `yaml:"exclude"`
+	Threshold     int      `yaml:"threshold"      doc:"Largest struct size, in threshold-unit, that values are suggested for."`
+	ThresholdUnit string   `yaml:"threshold-unit" doc:"Unit of threshold; words and cache lines are converted to bytes for the target architecture."`
+	MinSize       int      `yaml:"min-size"       doc:"Smallest struct size, in threshold-unit, worth reporting: tiny wrappers below it are left alone."`
+	Exclude       []string `yaml:"exclude"        doc:"Files not to report on: slash-separated patterns where ** matches any number of directories."`
+	Include       []string `yaml:"include"        doc:"If set, the only files to report on: patterns as in exclude, which still applies to the files matched."`
+	Presets       []string `yaml:"presets"        doc:"Frameworks whose managed types are never reported."`
+	IgnoreSymbols []string `yaml:"ignore-symbols" doc:"Symbols not to report on, like (*Server).Handler."`
+	Enable        []string `yaml:"enable"         doc:"Opt-in rules to enable."`
+	Lang          string   `yaml:"lang"           doc:"Language of the messages."`
+	// IncludeVendor analyzes vendored and module cache code, which is skipped by default.
+	IncludeVendor bool `yaml:"include-vendor" doc:"Analyze vendored and module cache code too."`
+
+	// IgnoreImplements lists interfaces ("error", "driver.Valuer", or
+	// "database/sql/driver.Valuer") whose implementations are never reported.
+	IgnoreImplements []string `yaml:"ignore-implements" doc:"Interfaces whose implementations are never reported, like error or driver.Valuer."`
+	// IgnoreIfContainsFieldType lists field types ("sync.Mutex", "*sql.DB", or
+	// "database/sql.DB") whose presence, directly or in nested struct and array
+	// fields, exempts a struct.
+	IgnoreIfContainsFieldType []string `yaml:"ignore-if-contains-field-type" doc:"Field types, like sync.Mutex or *sql.DB, whose presence in a struct (or its nested structs) exempts it."`
+	// IncludeSymbols, if set, limits findings to the functions, methods, and
+	// variables whose name, unqualified or qualified with the package name or
+	// path, matches the regular expression.
+	IncludeSymbols string `yaml:"include-symbols" doc:"Regular expression limiting findings to the symbols it matches, like ^(pkg\\.)?Get."`
+	// IgnoreSymbolsRegex drops the findings in the symbols it matches, like
+	// IgnoreSymbols by regular expression.
+	IgnoreSymbolsRegex string `yaml:"ignore-symbols-regex" doc:"Regular expression dropping the findings in the symbols it matches."`
+	// RespectUnsafe, true unless set to false, exempts types whose pointers
+	// are converted with unsafe.Pointer, or whose layout is inspected with
+	// unsafe.Sizeof, Alignof, Offsetof, reflect.TypeOf, or reflect.TypeFor.
+	// Use UnsafeRespected to read it.
+	RespectUnsafe *bool `yaml:"respect-unsafe" doc:"Exempt types converted with unsafe.Pointer or inspected with unsafe.Sizeof, Offsetof, Alignof, or reflect.TypeOf (default true)."`
+	// SkipIf is an expression exempting the types it matches, e.g.
+	// `type.hasFieldOfType("sync.Mutex") || type.name.matches("DTO$")` (see
+	// internal/skipexpr).
+	SkipIf string `yaml:"skip-if" doc:"Expression exempting the types it matches, like type.name.matches(\"DTO$\")."`
+
+	// DefaultSeverity is the severity of findings of rules not listed in Severity.
+	DefaultSeverity string `yaml:"default-severity" doc:"Severity of findings of rules not listed in severity."`
+	// Severity overrides the severity per rule ID.
+	Severity map[string]string `yaml:"severity" doc:"Severity per rule ID."`
+
+	// Tests relaxes the checks in _test.go files, whose pointer-heavy
+	// fixtures rarely matter for performance.
+	Tests TestFiles `yaml:"tests" doc:"How findings in _test.go files are relaxed: by default, they are reported as info."`
+
+	// DocsBaseURL, if set, is where the findings link to the documentation of
+	// their rule instead of the published one, such as a company style guide
+	// (see rules.URLAt).
+	DocsBaseURL string `yaml:"docs-base-url" doc:"Base URL of the rule documentation findings link to, like an internal style guide: {rule} is replaced by the rule ID, which is appended otherwise."`
+
+	// ExcludeRules drops the findings matching any of the rules, like the
+	// exclude-rules of golangci-lint.
+	ExcludeRules []ExcludeRule `yaml:"exclude-rules" doc:"Rules dropping the findings matching all of their conditions, like the issues.exclude-rules of golangci-lint."`
+
+	// PackageBudget, if set, is the time (a time.ParseDuration string, like
+	// "5s") analyzing a single package may take before a warning names it.
+	// Use Budget to read it.
+	PackageBudget string `yaml:"package-budget" doc:"Time analyzing a single package may take before a warning names it, like 5s: generated packages are the usual culprits."`
+
+	// Format is the formatter run on the files changed by -fix.
+	Format string `yaml:"format" doc:"Formatter run on the files changed by -fix."`
+
+	// PathMatching selects how exclude patterns are matched and where config
+	// files are looked up: PathMatchingPortable or PathMatchingLegacy.
+	PathMatching string `yaml:"path-matching" doc:"How exclude patterns are matched and where config files are looked up."`
+
+	// Dir is the directory exclude patterns with a slash are relative to: that
+	// of the config file, or the module root of the working directory without
+	// one. If empty, each file's module root is used.
+	Dir string `yaml:"-"`
+
+	// File is the config file loaded, or empty if none was found.
+	File string `yaml:"-"`
+
+	// Suppressed holds the symbols from the generated suppressions file.
+	Suppressed []string `yaml:"-"`
+}
+
+// ExcludeRule drops the findings matching all of its conditions, with the
+// semantics of an exclude-rules entry of golangci-lint, so that rules can be
+// copied between the two configs. Regular expressions use the RE2 syntax of
+// package regexp and match anywhere in their input unless anchored.
+type ExcludeRule struct {
+	// Path matches the slash-separated path of the file, relative to Dir
+	// (see ShouldExclude).
+	Path string `yaml:"path" doc:"Regular expression matching the slash-separated path of the file, relative to the config file."`
+	// PathExcept matches the paths of the files the rule does not apply to.
+	PathExcept string `yaml:"path-except" doc:"Regular expression matching the paths of the files the rule does not apply to."`
+	// Rules lists the rule IDs the rule applies to, all if empty.
+	Rules []string `yaml:"rules" doc:"Rule IDs the rule applies to, all if empty."`
+	// Linters lists linters as golangci-lint does: the rule only applies to
+	// pointless findings if it is empty or lists pointless.
+	Linters []string `yaml:"linters" doc:"Linters, as in golangci-lint: the rule applies if it is empty or lists pointless."`
+	// Text matches the message of the finding.
+	Text string `yaml:"text" doc:"Regular expression matching the message of the finding."`
+	// Source matches the source line the finding starts on.
+	Source string `yaml:"source" doc:"Regular expression matching the source line the finding starts on."`
+}
+
+// TestFiles relaxes the checks in _test.go files.
+type TestFiles struct {
+	// Severity caps the severity of findings in test files; it is
+	// severity.Info if empty. Use TestSeverity to read it.
+	Severity string `yaml:"severity" doc:"Highest severity of findings in _test.go files (default info)."`
+	// Threshold, if set, replaces the threshold in test files, in the
+	// threshold unit.
+	Threshold int `yaml:"threshold" doc:"Threshold in _test.go files, in threshold-unit, instead of threshold."`
+	// Rules, if set, lists the only rules reported in test files.
+	Rules []string `yaml:"rules" doc:"The only rules reported in _test.go files, all if empty."`
+}
+
+// Threshold units. Words and cache lines are converted to bytes for the target
+// architecture, so the threshold keeps its meaning on 32-bit and 64-bit platforms.
+const (
+	UnitBytes      = "bytes"
+	UnitWords      = "words"
+	UnitCacheLines = "cachelines"
+)
+
+// Formatters run on the files changed by -fix.
+const (
+	// FormatGofmt formats with gofmt and fixes imports like goimports.
+	FormatGofmt = "gofmt"
+	// FormatGofumpt fixes imports like goimports, then formats with the
+	// gofumpt binary, which must be in PATH.
+	FormatGofumpt = "gofumpt"
+	// FormatNone writes the edits as they are.
+	FormatNone = "none"
+)
+
+// Formatters returns the supported values of the format setting.
+func Formatters() []string {
+	return []string{FormatGofmt, FormatGofumpt, FormatNone}
 }
 
 // DefaultConfig returns a config with default values.
 func DefaultConfig() Config {
 	return Config{
-		Threshold: 1024,
-		Exclude:   nil,
+		Threshold:                 1024,
+		ThresholdUnit:             UnitBytes,
+		MinSize:                   0,
+		Exclude:                   nil,
+		Include:                   nil,
+		Presets:                   nil,
+		IgnoreSymbols:             nil,
+		IncludeSymbols:            "",
+		IgnoreSymbolsRegex:        "",
+		IgnoreImplements:          nil,
+		IgnoreIfContainsFieldType: nil,
+		RespectUnsafe:             nil,
+		SkipIf:                    "",
+		Enable:                    nil,
+		Lang:                      "en",
+		IncludeVendor:             false,
+		DefaultSeverity:           severity.Default,
+		Severity:                  nil,
+		Tests:                     TestFiles{Severity: "", Threshold: 0, Rules: nil},
+		DocsBaseURL:               "",
+		ExcludeRules:              nil,
+		PackageBudget:             "",
+		Format:                    FormatGofmt,
+		PathMatching:              PathMatchingPortable,
+		Dir:                       "",
+		Suppressed:                nil,
 	}
 }
 
-// Load loads configuration from .pointless.yaml in the current directory or parent directories.
+// Load loads configuration from .pointless.yaml in the current directory or
+// parent directories, along with the symbols listed in
+// .pointless-suppressions.yaml.
+//
+// The search stops at the module root, the closest directory with a go.mod
+// file. A config file above it is only used if it sets path-matching: legacy,
+// with which the suppressions file is searched up to the filesystem root too.
+// The directory of the config file, or the module root without one, becomes
+// Dir.
+//
+// The problems found are of type *Error, joined (see Errors). Invalid
+// settings are replaced by their defaults, so the config returned is usable
+// despite them.
 func Load() (Config, error) {
+	wd, err := os.Getwd()
+	if err != nil {
+		return DefaultConfig(), newError(NotFound, "", fmt.Errorf("getting working directory: %w", err))
+	}
+
+	return LoadDir(wd)
+}
+
+// LoadDir is like Load, but searches from dir instead of the current directory.
+// The suppressions file is loaded whatever the problems with the config file,
+// and its own problems are reported separately, keeping the config loaded.
+func LoadDir(dir string) (Config, error) {
+	cfg, err := loadConfigFile(dir)
+
+	var errs []error
+	if err != nil {
+		errs = append(errs, err)
+	}
+
+	suppressed, err := findSuppressions(dir, cfg)
+	if err != nil {
+		errs = append(errs, err)
+	}
+
+	cfg.Suppressed = suppressed
+
+	return cfg, errors.Join(errs...)
+}
+
+// loadConfigFile loads the config file found from dir (see Load), or the
+// default config without one.
+func loadConfigFile(dir string) (Config, error) {
 	cfg := DefaultConfig()
 
-	path, err := findConfigFile()
+	path, aboveModule, err := findConfigFile(dir)
 	if err != nil {
-		return cfg, fmt.Errorf("finding config file: %w", err)
+		return cfg, newError(NotFound, "", fmt.Errorf("finding config file: %w", err))
 	}
 
 	if path == "" {
+		cfg.Dir = ModuleRoot(dir)
+
 		return cfg, nil
 	}
 
+	loaded := DefaultConfig()
+
 	data, err := os.ReadFile(path) //nolint:gosec // G304: path is from findConfigFile, not user input
 	if err != nil {
-		return cfg, fmt.Errorf("reading config file: %w", err)
+		return cfg, newError(Unreadable, path, fmt.Errorf("reading config file: %w", err))
+	}
+
+	if err := yaml.Unmarshal(data, &loaded); err != nil {
+		return cfg, newError(ParseError, path, fmt.Errorf("parsing config file: %w", err))
+	}
+
+	if aboveModule && loaded.PathMatching != PathMatchingLegacy {
+		return cfg, newError(NotFound, path, fmt.Errorf("ignoring %s above the module root (set path-matching: %s to use it)", path, PathMatchingLegacy))
+	}
+
+	loaded.Dir, loaded.File = filepath.Dir(path), path
+
+	// Invalid settings fall back to their defaults
+	return loaded.validate()
+}
+
+// findSuppressions loads the symbols of the suppressions file found from dir,
+// if any. A file above the module root is only used with path-matching:
+// legacy in cfg.
+func findSuppressions(dir string, cfg Config) ([]string, error) {
+	path, aboveModule, err := findFile(dir, SuppressionsFile)
+	if err != nil {
+		return nil, newError(NotFound, "", fmt.Errorf("finding suppressions file: %w", err))
 	}
 
-	if err := yaml.Unmarshal(data, &cfg); err != nil {
-		return cfg, fmt.Errorf("parsing config file: %w", err)
+	if path == "" || aboveModule && cfg.PathMatching != PathMatchingLegacy {
+		return nil, nil
 	}
 
-	return cfg, nil
+	return loadSuppressions(path)
 }
 
-// findConfigFile searches for .pointless.yaml or .pointless.yml in current and parent directories.
-func findConfigFile() (string, error) {
-	dir, err := os.Getwd()
+// configFiles are the names of the config file, in order of preference.
+var configFiles = []string{".pointless.yaml", ".pointless.yml"}
+
+// findConfigFile searches for .pointless.yaml or .pointless.yml in dir and its parents.
+func findConfigFile(dir string) (path string, aboveModule bool, err error) {
+	return findFile(dir, configFiles...)
+}
+
+// findFile searches for the first of names in dir and its parents.
+// aboveModule reports whether the file is above the module root.
+func findFile(dir string, names ...string) (path string, aboveModule bool, err error) {
+	dir, err = filepath.Abs(dir)
 	if err != nil {
-		return "", fmt.Errorf("getting working directory: %w", err)
+		return "", false, fmt.Errorf("resolving directory: %w", err)
 	}
 
 	for {
-		path := filepath.Join(dir, ".pointless.yaml")
-		if _, err := os.Stat(path); err == nil {
-			return path, nil
+		for _, name := range names {
+			path := filepath.Join(dir, name)
+			if _, err := os.Stat(path); err == nil {
+				return path, aboveModule, nil
+			}
 		}
 
-		// Also check .pointless.yml
-		path = filepath.Join(dir, ".pointless.yml")
-		if _, err := os.Stat(path); err == nil {
-			return path, nil
+		if _, err := os.Stat(filepath.Join(dir, "go.mod")); err == nil {
+			aboveModule = true
 		}
 
 		parent := filepath.Dir(dir)
@@ -75,20 +318,62 @@ func findConfigFile() (string, error) {
 		dir = parent
 	}
 
-	return "", nil
+	return "", false, nil
 }
 
-// ShouldExclude checks if a file path matches any exclude pattern.
-func (c Config) ShouldExclude(path string) bool {
-	for _, pattern := range c.Exclude {
-		if matched, _ := filepath.Match(pattern, path); matched {
-			return true
-		}
-		// Also try matching against the base name
-		if matched, _ := filepath.Match(pattern, filepath.Base(path)); matched {
-			return true
-		}
+// SeverityOf returns the configured severity of findings of the given rule;
+// that of advisory rules is capped by their rules.Rule.Severity.
+func (c Config) SeverityOf(rule string) string {
+	if sev, ok := c.Severity[rule]; ok {
+		return sev
+	}
+
+	sev := c.DefaultSeverity
+	if sev == "" {
+		sev = severity.Default
+	}
+
+	if r, ok := rules.Lookup(rule); ok && r.Severity != "" {
+		return severity.LeastSevere(sev, r.Severity)
+	}
+
+	return sev
+}
+
+// TestSeverity returns the severity of findings of the given rule in test
+// files: the less severe of SeverityOf and tests.severity.
+func (c Config) TestSeverity(rule string) string {
+	limit := c.Tests.Severity
+	if limit == "" {
+		limit = severity.Info
+	}
+
+	return severity.LeastSevere(c.SeverityOf(rule), limit)
+}
+
+// TestRuleEnabled reports whether findings of the given rule are reported in
+// test files (see TestFiles.Rules).
+func (c Config) TestRuleEnabled(rule string) bool {
+	return len(c.Tests.Rules) == 0 || slices.Contains(c.Tests.Rules, rule)
+}
+
+// IsTestFile reports whether filename is a _test.go file.
+func IsTestFile(filename string) bool {
+	return strings.HasSuffix(filename, "_test.go")
+}
+
+// Budget returns the package-budget, or 0 if it is not set.
+func (c Config) Budget() time.Duration {
+	d, err := time.ParseDuration(c.PackageBudget)
+	if err != nil {
+		return 0
 	}
 
-	return false
+	return d
+}
+
+// UnsafeRespected reports whether types used with unsafe or reflect are
+// exempt: respect-unsafe is on unless set to false.
+func (c Config) UnsafeRespected() bool {
+	return c.RespectUnsafe == nil || *c.RespectUnsafe
 }
@@ -0,0 +1,725 @@
+package config_test
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mickamy/pointless/internal/config"
+	"github.com/mickamy/pointless/internal/rules"
+	"github.com/mickamy/pointless/internal/severity"
+)
+
+// writeFiles creates the files under dir, with their parent directories.
+func writeFiles(t *testing.T, dir string, files map[string]string) {
+	t.Helper()
+
+	for name, content := range files {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0o750); err != nil {
+			t.Fatal(err)
+		}
+
+		if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestShouldExclude(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{"mod/go.mod": "module example.com/mod\n"})
+
+	file := filepath.Join(dir, "mod", "internal", "gen", "types.go")
+
+	tests := []struct {
+		name    string
+		pattern string
+		want    bool
+	}{
+		{"base name", "*.go", true},
+		{"other base name", "*_test.go", false},
+		{"module-relative", "internal/gen/*.go", true},
+		{"module-relative with dot", "./internal/gen/*.go", true},
+		{"module-relative directory only", "internal/*.go", false},
+		{"double star", "internal/**", true},
+		{"double star in the middle", "**/gen/*.go", true},
+		{"double star matching nothing", "internal/gen/**/types.go", true},
+		{"double star elsewhere", "legacy/**", false},
+		{"absolute", filepath.ToSlash(filepath.Join(dir, "mod", "internal", "gen")) + "/*.go", true},
+		{"absolute elsewhere", "/elsewhere/*.go", false},
+		{"backslashes", `internal\gen\*.go`, filepath.Separator == '\\'},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			c := config.Config{Exclude: []string{tt.pattern}}
+			if got := c.ShouldExclude(file); got != tt.want {
+				t.Errorf("ShouldExclude with %q = %v, want %v", tt.pattern, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestShouldExcludeDir(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{"go.mod": "module example.com/mod\n"})
+
+	c := config.Config{Exclude: []string{"gen/*.go"}, Dir: filepath.Join(dir, "internal")}
+
+	if file := filepath.Join(dir, "internal", "gen", "types.go"); !c.ShouldExclude(file) {
+		t.Errorf("ShouldExclude(%q) = false, want true", file)
+	}
+
+	// Files outside Dir never match relative patterns
+	c.Exclude = []string{"**/*.go"}
+	if file := filepath.Join(dir, "cmd", "main.go"); c.ShouldExclude(file) {
+		t.Errorf("ShouldExclude(%q) = true, want false", file)
+	}
+}
+
+func TestShouldExcludeInclude(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{"go.mod": "module example.com/mod\n"})
+
+	c := config.Config{Include: []string{"services/billing/**"}, Exclude: []string{"services/billing/gen/**"}, Dir: dir}
+
+	tests := []struct {
+		file string
+		want bool
+	}{
+		{filepath.Join(dir, "services", "billing", "invoice.go"), false},
+		{filepath.Join(dir, "services", "billing", "gen", "types.go"), true},
+		{filepath.Join(dir, "services", "search", "index.go"), true},
+		{filepath.Join(dir, "main.go"), true},
+	}
+
+	for _, tt := range tests {
+		if got := c.ShouldExclude(tt.file); got != tt.want {
+			t.Errorf("ShouldExclude(%q) = %v, want %v", tt.file, got, tt.want)
+		}
+	}
+}
+
+func TestShouldExcludeLegacy(t *testing.T) {
+	t.Parallel()
+
+	file := filepath.Join("internal", "gen", "types.go")
+
+	c := config.Config{Exclude: []string{filepath.Join("internal", "gen", "*.go")}, PathMatching: config.PathMatchingLegacy}
+	if !c.ShouldExclude(file) {
+		t.Errorf("ShouldExclude(%q) = false, want true", file)
+	}
+
+	// Legacy patterns with a slash never match relative to the module root
+	c.Exclude = []string{"gen/*.go"}
+	if c.ShouldExclude(file) {
+		t.Errorf("ShouldExclude(%q) = true, want false", file)
+	}
+}
+
+//nolint:paralleltest // changes the working directory
+func TestLoadStopsAtModuleRoot(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		".pointless.yaml":              "threshold: 64\n",
+		".pointless-suppressions.yaml": "suppressions:\n  - symbol: pkg.T\n",
+		"mod/go.mod":                   "module example.com/mod\n",
+		"mod/pkg/pkg.go":               "package pkg\n",
+	})
+	t.Chdir(filepath.Join(dir, "mod", "pkg"))
+
+	cfg, err := config.Load()
+	if err == nil || !strings.Contains(err.Error(), "above the module root") || !config.HasKind(err, config.NotFound) {
+		t.Errorf("Load() error = %v, want a config above the module root", err)
+	}
+
+	if cfg.Threshold != config.DefaultConfig().Threshold || len(cfg.Suppressed) != 0 {
+		t.Errorf("Load() = threshold %d, suppressed %v, want the defaults", cfg.Threshold, cfg.Suppressed)
+	}
+}
+
+//nolint:paralleltest // changes the working directory
+func TestLoadLegacyAboveModuleRoot(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		".pointless.yaml":              "threshold: 64\npath-matching: legacy\n",
+		".pointless-suppressions.yaml": "suppressions:\n  - symbol: pkg.T\n",
+		"mod/go.mod":                   "module example.com/mod\n",
+	})
+	t.Chdir(filepath.Join(dir, "mod"))
+
+	cfg, err := config.Load()
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+
+	if cfg.Threshold != 64 || len(cfg.Suppressed) != 1 {
+		t.Errorf("Load() = threshold %d, suppressed %v, want 64 and [pkg.T]", cfg.Threshold, cfg.Suppressed)
+	}
+}
+
+//nolint:paralleltest // changes the working directory
+func TestLoadAtModuleRoot(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		"go.mod":          "module example.com/mod\n",
+		".pointless.yaml": "threshold: 64\n",
+		"pkg/pkg.go":      "package pkg\n",
+	})
+	t.Chdir(filepath.Join(dir, "pkg"))
+
+	cfg, err := config.Load()
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+
+	if cfg.Threshold != 64 || cfg.PathMatching != config.PathMatchingPortable {
+		t.Errorf("Load() = threshold %d, path-matching %q, want 64 and %q", cfg.Threshold, cfg.PathMatching, config.PathMatchingPortable)
+	}
+
+	if !sameDir(t, cfg.Dir, dir) {
+		t.Errorf("Load() = dir %q, want %q", cfg.Dir, dir)
+	}
+}
+
+//nolint:paralleltest // changes the working directory
+func TestLoadWithoutConfigFile(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		"go.mod":     "module example.com/mod\n",
+		"pkg/pkg.go": "package pkg\n",
+	})
+	t.Chdir(filepath.Join(dir, "pkg"))
+
+	cfg, err := config.Load()
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+
+	if !sameDir(t, cfg.Dir, dir) {
+		t.Errorf("Load() = dir %q, want the module root %q", cfg.Dir, dir)
+	}
+}
+
+func TestLoadDirErrorKinds(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		config string
+		want   []config.ErrorKind
+	}{
+		{name: "valid", config: "threshold: 64\n"},
+		{name: "syntax", config: "threshold: [64\n", want: []config.ErrorKind{config.ParseError}},
+		{name: "type", config: "threshold: big\n", want: []config.ErrorKind{config.ParseError}},
+		{name: "values", config: "lang: fr\npresets: [nope]\n", want: []config.ErrorKind{config.InvalidValue, config.InvalidValue}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			dir := t.TempDir()
+			writeFiles(t, dir, map[string]string{"go.mod": "module example.com/mod\n", ".pointless.yaml": tt.config})
+
+			cfg, err := config.LoadDir(dir)
+
+			var got []config.ErrorKind
+			for _, e := range config.Errors(err) {
+				got = append(got, e.Kind)
+
+				if filepath.Base(e.Path) != ".pointless.yaml" {
+					t.Errorf("LoadDir() error path = %q, want the config file", e.Path)
+				}
+			}
+
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("LoadDir() error kinds = %v (%v), want %v", got, err, tt.want)
+			}
+
+			// Invalid settings fall back to their defaults
+			if cfg.Lang != config.DefaultConfig().Lang {
+				t.Errorf("LoadDir() = lang %q, want the default", cfg.Lang)
+			}
+		})
+	}
+}
+
+func TestLoadDirExcludeRules(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		"go.mod": "module example.com/mod\n",
+		".pointless.yaml": `exclude-rules:
+  - path: _test\.go$
+    rules: [value-receiver]
+  - linters: [pointless]
+  - text: "["
+  - source: "^//"
+    rules: [no-such-rule]
+  - linters: [govet]
+    text: shadow
+`,
+	})
+
+	cfg, err := config.LoadDir(dir)
+
+	// The entries without a condition, with an invalid regexp, or with an unknown rule are dropped
+	if got := len(config.Errors(err)); got != 3 || !config.HasKind(err, config.InvalidValue) {
+		t.Errorf("LoadDir() error = %v, want 3 invalid values", err)
+	}
+
+	want := []config.ExcludeRule{
+		{Path: `_test\.go$`, Rules: []string{"value-receiver"}},
+		{Linters: []string{"govet"}, Text: "shadow"},
+	}
+	if !reflect.DeepEqual(cfg.ExcludeRules, want) {
+		t.Errorf("LoadDir() exclude-rules = %+v, want %+v", cfg.ExcludeRules, want)
+	}
+}
+
+func TestLoadDirDocsBaseURL(t *testing.T) {
+	t.Parallel()
+
+	for _, tt := range []struct {
+		base, want string
+	}{
+		{base: "https://wiki.example.com/go/{rule}", want: "https://wiki.example.com/go/{rule}"},
+		{base: "wiki/go/", want: ""},
+	} {
+		dir := t.TempDir()
+		writeFiles(t, dir, map[string]string{
+			"go.mod":          "module example.com/mod\n",
+			".pointless.yaml": "docs-base-url: " + tt.base + "\n",
+		})
+
+		cfg, err := config.LoadDir(dir)
+		if invalid := tt.want == ""; invalid != config.HasKind(err, config.InvalidValue) {
+			t.Errorf("LoadDir() with docs-base-url %q error = %v", tt.base, err)
+		}
+
+		if cfg.DocsBaseURL != tt.want {
+			t.Errorf("LoadDir() docs-base-url = %q, want %q", cfg.DocsBaseURL, tt.want)
+		}
+	}
+}
+
+func TestLoadDirPackageBudget(t *testing.T) {
+	t.Parallel()
+
+	for _, tt := range []struct {
+		budget string
+		want   time.Duration
+	}{
+		{budget: "5s", want: 5 * time.Second},
+		{budget: "1500ms", want: 1500 * time.Millisecond},
+		{budget: "fast", want: 0},
+		{budget: "-1s", want: 0},
+	} {
+		dir := t.TempDir()
+		writeFiles(t, dir, map[string]string{
+			"go.mod":          "module example.com/mod\n",
+			".pointless.yaml": "package-budget: " + tt.budget + "\n",
+		})
+
+		cfg, err := config.LoadDir(dir)
+		if invalid := tt.want == 0; invalid != config.HasKind(err, config.InvalidValue) {
+			t.Errorf("LoadDir() with package-budget %q error = %v", tt.budget, err)
+		}
+
+		if got := cfg.Budget(); got != tt.want {
+			t.Errorf("LoadDir() package-budget %q = %v, want %v", tt.budget, got, tt.want)
+		}
+	}
+}
+
+func TestSeverityOfAdvisoryRule(t *testing.T) {
+	t.Parallel()
+
+	cfg := config.DefaultConfig()
+	if got := cfg.SeverityOf(rules.AtomicCounter); got != severity.Info {
+		t.Errorf("SeverityOf(%s) = %q, want %q", rules.AtomicCounter, got, severity.Info)
+	}
+
+	cfg.DefaultSeverity = severity.Hint
+	if got := cfg.SeverityOf(rules.AtomicCounter); got != severity.Hint {
+		t.Errorf("SeverityOf(%s) with default-severity hint = %q, want %q", rules.AtomicCounter, got, severity.Hint)
+	}
+
+	cfg.Severity = map[string]string{rules.AtomicCounter: severity.Warning}
+	if got := cfg.SeverityOf(rules.AtomicCounter); got != severity.Warning {
+		t.Errorf("SeverityOf(%s) configured as warning = %q, want %q", rules.AtomicCounter, got, severity.Warning)
+	}
+}
+
+func TestLoadDirMinSize(t *testing.T) {
+	t.Parallel()
+
+	for _, tt := range []struct {
+		config string
+		want   int
+	}{
+		{config: "min-size: 16\n", want: 16},
+		{config: "min-size: -1\n", want: 0},
+		{config: "threshold: 64\nmin-size: 128\n", want: 0},
+	} {
+		dir := t.TempDir()
+		writeFiles(t, dir, map[string]string{
+			"go.mod":          "module example.com/mod\n",
+			".pointless.yaml": tt.config,
+		})
+
+		cfg, err := config.LoadDir(dir)
+		if invalid := tt.want == 0; invalid != config.HasKind(err, config.InvalidValue) {
+			t.Errorf("LoadDir() with %q error = %v", tt.config, err)
+		}
+
+		if cfg.MinSize != tt.want {
+			t.Errorf("LoadDir() with %q min-size = %d, want %d", tt.config, cfg.MinSize, tt.want)
+		}
+	}
+}
+
+func TestLoadDirTests(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		"go.mod":          "module example.com/mod\n",
+		".pointless.yaml": "tests:\n  severity: hint\n  threshold: 4096\n  rules: [slice-pointer]\nseverity:\n  value-receiver: info\n",
+	})
+
+	cfg, err := config.LoadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if cfg.Tests.Threshold != 4096 {
+		t.Errorf("LoadDir() tests.threshold = %d, want 4096", cfg.Tests.Threshold)
+	}
+
+	if got := cfg.TestSeverity(rules.SlicePointer); got != severity.Hint {
+		t.Errorf("TestSeverity(%s) = %q, want %q", rules.SlicePointer, got, severity.Hint)
+	}
+
+	if !cfg.TestRuleEnabled(rules.SlicePointer) || cfg.TestRuleEnabled(rules.ValueReceiver) {
+		t.Errorf("TestRuleEnabled() must only enable the rules listed in tests.rules")
+	}
+
+	// Without tests.severity findings in test files are at most info
+	if got := config.DefaultConfig().TestSeverity(rules.SlicePointer); got != severity.Info {
+		t.Errorf("default TestSeverity(%s) = %q, want %q", rules.SlicePointer, got, severity.Info)
+	}
+
+	writeFiles(t, dir, map[string]string{
+		".pointless.yaml": "tests:\n  severity: loud\n  threshold: -1\n  rules: [no-such-rule]\n",
+	})
+
+	if _, err := config.LoadDir(dir); !config.HasKind(err, config.InvalidValue) {
+		t.Errorf("LoadDir() with invalid tests error = %v, want an invalid value", err)
+	}
+}
+
+func TestSuppressionsRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	want := config.Suppressions{Suppressions: []config.Suppression{
+		{Symbol: "example.com/mod.(*Server).Handler", Reason: "consider using value receiver", Fingerprints: []string{"a1", "b2"}},
+		{Symbol: "example.com/mod.defaultServers"},
+	}}
+
+	var buf bytes.Buffer
+	if err := config.WriteSuppressions(&buf, want); err != nil {
+		t.Fatal(err)
+	}
+
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		"go.mod":                "module example.com/mod\n",
+		".pointless.yaml":       "threshold: 64\n",
+		config.SuppressionsFile: buf.String(),
+		"pkg/pkg.go":            "package pkg\n",
+	})
+
+	cfg, err := config.LoadDir(filepath.Join(dir, "pkg"))
+	if err != nil {
+		t.Fatalf("LoadDir() error = %v", err)
+	}
+
+	symbols := []string{"example.com/mod.(*Server).Handler", "example.com/mod.defaultServers"}
+	if !slices.Equal(cfg.Suppressed, symbols) {
+		t.Errorf("LoadDir() suppressed = %q, want %q", cfg.Suppressed, symbols)
+	}
+
+	if cfg.Threshold != 64 {
+		t.Errorf("LoadDir() threshold = %d, want 64", cfg.Threshold)
+	}
+}
+
+func TestLoadDirInvalidSuppressions(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		"go.mod":                "module example.com/mod\n",
+		".pointless.yaml":       "threshold: 64\n",
+		config.SuppressionsFile: "suppressions: [\n",
+	})
+
+	cfg, err := config.LoadDir(dir)
+
+	errs := config.Errors(err)
+	if len(errs) != 1 || errs[0].Kind != config.ParseError || filepath.Base(errs[0].Path) != config.SuppressionsFile {
+		t.Errorf("LoadDir() error = %v, want a parse error of the suppressions file", err)
+	}
+
+	// The config file is loaded all the same
+	if cfg.Threshold != 64 || cfg.File == "" {
+		t.Errorf("LoadDir() = threshold %d from %q, want 64 from the config file", cfg.Threshold, cfg.File)
+	}
+}
+
+func TestLoadDirSuppressionsWithInvalidConfig(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		"go.mod":                "module example.com/mod\n",
+		".pointless.yaml":       "threshold: [64\n",
+		config.SuppressionsFile: "suppressions:\n  - symbol: example.com/mod.New\n",
+	})
+
+	cfg, err := config.LoadDir(dir)
+	if !config.HasKind(err, config.ParseError) {
+		t.Errorf("LoadDir() error = %v, want a parse error of the config file", err)
+	}
+
+	if !slices.Equal(cfg.Suppressed, []string{"example.com/mod.New"}) {
+		t.Errorf("LoadDir() suppressed = %q, want the suppressions file loaded", cfg.Suppressed)
+	}
+}
+
+func TestErrorsOfWrappedErrors(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		"go.work":             "go 1.24\n\nuse ./api\n",
+		"api/go.mod":          "module example.com/api\n",
+		"api/.pointless.yaml": "format: black\n",
+	})
+
+	_, err := config.LoadWorkspace(filepath.Join(dir, "go.work"))
+	if !config.HasKind(err, config.InvalidValue) || config.HasKind(err, config.ParseError) {
+		t.Errorf("LoadWorkspace() error = %v, want an invalid value only", err)
+	}
+
+	if !strings.Contains(err.Error(), "module ./api:") {
+		t.Errorf("LoadWorkspace() error = %v, want the module named", err)
+	}
+}
+
+// sameDir reports whether a and b name the same directory, which they may
+// spell differently through symbolic links (as /tmp on macOS).
+func sameDir(t *testing.T, a, b string) bool {
+	t.Helper()
+
+	ai, err := os.Stat(a)
+	if err != nil {
+		return false
+	}
+
+	bi, err := os.Stat(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return os.SameFile(ai, bi)
+}
+
+func TestUnsafeRespected(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		content string
+		want    bool
+	}{
+		{"unset", "threshold: 512\n", true},
+		{"on", "respect-unsafe: true\n", true},
+		{"off", "respect-unsafe: false\n", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			dir := t.TempDir()
+			writeFiles(t, dir, map[string]string{
+				"go.mod":          "module example.com/mod\n",
+				".pointless.yaml": tt.content,
+			})
+
+			cfg, err := config.LoadDir(dir)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if got := cfg.UnsafeRespected(); got != tt.want {
+				t.Errorf("UnsafeRespected() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLintExcludes(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	files := []string{
+		filepath.Join(dir, "main.go"),
+		filepath.Join(dir, "main_test.go"),
+		filepath.Join(dir, "internal", "gen", "types.go"),
+		filepath.Join(dir, "internal", "gen", "types_test.go"),
+	}
+
+	c := config.Config{
+		Dir:     dir,
+		Exclude: []string{"*_test.go", "internal/gen/*_test.go", "legacy/**", "internal/**", "internal/gen/*.go"},
+	}
+
+	got := make([]string, 0)
+	for _, p := range c.LintExcludes(files) {
+		got = append(got, p.String())
+	}
+
+	want := []string{
+		`exclude pattern "internal/gen/*_test.go" is shadowed by "*_test.go"`,
+		`exclude pattern "legacy/**" matches no analyzed file`,
+		`exclude pattern "internal/gen/*.go" is shadowed by "internal/**"`,
+	}
+	if !slices.Equal(got, want) {
+		t.Errorf("LintExcludes() = %q, want %q", got, want)
+	}
+}
+
+func TestLoadWorkspace(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		"go.work":            "go 1.24\n\nuse (\n\t./api\n\t./tools\n)\n",
+		".pointless.yaml":    "threshold: 64\n",
+		"api/go.mod":         "module example.com/api\n",
+		"api/.pointless.yml": "threshold: 8\nexclude: [\"gen/**\"]\n",
+		"tools/go.mod":       "module example.com/tools\n",
+	})
+
+	ws, err := config.LoadWorkspace(filepath.Join(dir, "go.work"))
+	if err != nil {
+		t.Fatalf("LoadWorkspace() error = %v", err)
+	}
+
+	if len(ws.Modules) != 2 || !ws.Modules[0].OwnConfig || ws.Modules[1].OwnConfig {
+		t.Fatalf("LoadWorkspace() = modules %+v, want api with its own config and tools without", ws.Modules)
+	}
+
+	tests := []struct {
+		file      string
+		threshold int
+	}{
+		{"api/server.go", 8},
+		{"api/internal/gen/types.go", 8},
+		{"tools/main.go", 64},
+		{"elsewhere/dep.go", 64},
+	}
+
+	for _, tt := range tests {
+		if got := ws.ConfigFor(filepath.Join(dir, tt.file)).Threshold; got != tt.threshold {
+			t.Errorf("ConfigFor(%q) = threshold %d, want %d", tt.file, got, tt.threshold)
+		}
+	}
+
+	// Exclude patterns of a module are relative to the module
+	api := ws.ConfigFor(filepath.Join(dir, "api", "server.go"))
+	if file := filepath.Join(dir, "api", "gen", "types.go"); !api.ShouldExclude(file) {
+		t.Errorf("ShouldExclude(%q) = false, want true", file)
+	}
+}
+
+//nolint:paralleltest // sets GOWORK
+func TestWorkFile(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{"go.work": "go 1.24\n", "mod/go.mod": "module example.com/mod\n"})
+
+	t.Setenv("GOWORK", "")
+
+	if got, want := config.WorkFile(dir), filepath.Join(dir, "go.work"); got != want {
+		t.Errorf("WorkFile(%q) = %q, want %q", dir, got, want)
+	}
+
+	// Modules of a workspace are not roots
+	if got := config.WorkFile(filepath.Join(dir, "mod")); got != "" {
+		t.Errorf("WorkFile(mod) = %q, want \"\"", got)
+	}
+
+	t.Setenv("GOWORK", "off")
+
+	if got := config.WorkFile(dir); got != "" {
+		t.Errorf("WorkFile(%q) with GOWORK=off = %q, want \"\"", dir, got)
+	}
+}
+
+func TestSchema(t *testing.T) {
+	t.Parallel()
+
+	schema, err := config.Schema()
+	if err != nil {
+		t.Fatalf("Schema() error = %v", err)
+	}
+
+	properties, ok := schema["properties"].(map[string]any)
+	if !ok {
+		t.Fatalf("Schema() = %v, want properties", schema)
+	}
+
+	// Every setting is described
+	for name, p := range properties {
+		if prop, ok := p.(map[string]any); !ok || prop["description"] == nil {
+			t.Errorf("property %q has no description", name)
+		}
+	}
+
+	for _, name := range []string{"threshold", "exclude", "skip-if", "path-matching"} {
+		if _, ok := properties[name]; !ok {
+			t.Errorf("Schema() has no property %q", name)
+		}
+	}
+
+	for _, name := range []string{"Dir", "Suppressed", "-"} {
+		if _, ok := properties[name]; ok {
+			t.Errorf("Schema() has property %q, which is not a setting", name)
+		}
+	}
+
+	// The defaults are valid values
+	format, _ := properties["format"].(map[string]any)
+	if enum, _ := format["enum"].([]string); !slices.Contains(enum, config.DefaultConfig().Format) {
+		t.Errorf("format = %v, want an enum with the default %q", format, config.DefaultConfig().Format)
+	}
+}
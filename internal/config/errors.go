@@ -0,0 +1,104 @@
+package config
+
+// ErrorKind classifies the problems found loading a config.
+type ErrorKind int
+
+// Kinds of problems found loading a config.
+const (
+	// NotFound means the config file could not be looked up, or that the one
+	// found is not used, being above the module root.
+	NotFound ErrorKind = iota + 1
+	// Unreadable means the config or suppressions file exists but could not
+	// be read.
+	Unreadable
+	// ParseError means the file is not valid YAML, or holds values of the
+	// wrong type.
+	ParseError
+	// InvalidValue means a setting has a value it does not accept. The value
+	// is replaced by its default, if it has one.
+	InvalidValue
+)
+
+// String returns the name of the kind, as in parse-error.
+func (k ErrorKind) String() string {
+	switch k {
+	case NotFound:
+		return "not-found"
+	case Unreadable:
+		return "unreadable"
+	case ParseError:
+		return "parse-error"
+	case InvalidValue:
+		return "invalid-value"
+	}
+
+	return "unknown"
+}
+
+// Error is a problem found loading a config. Load and LoadWorkspace return
+// the config they could load along with the problems found, joined, so the
+// caller can decide which ones to tolerate.
+type Error struct {
+	Kind ErrorKind
+	// Path is the file the problem is in, if known.
+	Path string
+	Err  error
+}
+
+func (e *Error) Error() string {
+	return e.Err.Error()
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// Errors returns the problems held by err, which may join several of them
+// and wrap them with context, in order. Errors that are not of type *Error
+// are not part of the result.
+func Errors(err error) []*Error {
+	var result []*Error
+
+	var walk func(err error)
+
+	walk = func(err error) {
+		if e, ok := err.(*Error); ok {
+			result = append(result, e)
+
+			return
+		}
+
+		switch u := err.(type) {
+		case interface{ Unwrap() []error }:
+			for _, err := range u.Unwrap() {
+				walk(err)
+			}
+		case interface{ Unwrap() error }:
+			walk(u.Unwrap())
+		}
+	}
+
+	if err != nil {
+		walk(err)
+	}
+
+	return result
+}
+
+// HasKind reports whether err holds a problem of one of the given kinds.
+func HasKind(err error, kinds ...ErrorKind) bool {
+	for _, e := range Errors(err) {
+		for _, k := range kinds {
+			if e.Kind == k {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// newError returns a problem of the given kind in the file at path.
+func newError(kind ErrorKind, path string, err error) error {
+	return &Error{Kind: kind, Path: path, Err: err}
+}
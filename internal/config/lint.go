@@ -0,0 +1,79 @@
+package config
+
+import "fmt"
+
+// ExcludeProblem is an exclude pattern that has no effect of its own on a set
+// of files.
+type ExcludeProblem struct {
+	// Pattern is the offending exclude pattern.
+	Pattern string
+	// ShadowedBy is the broader pattern matching every file Pattern matches, or
+	// "" if Pattern matches no file at all.
+	ShadowedBy string
+}
+
+func (p ExcludeProblem) String() string {
+	if p.ShadowedBy == "" {
+		return fmt.Sprintf("exclude pattern %q matches no analyzed file", p.Pattern)
+	}
+
+	return fmt.Sprintf("exclude pattern %q is shadowed by %q", p.Pattern, p.ShadowedBy)
+}
+
+// LintExcludes returns the exclude patterns that match none of files, or only
+// files that another pattern matches too, in the order of c.Exclude. Of two
+// patterns matching the same files, the later one is reported.
+func (c Config) LintExcludes(files []string) []ExcludeProblem {
+	matches := make([]map[string]bool, len(c.Exclude))
+
+	for i, pattern := range c.Exclude {
+		single := c
+		single.Exclude = []string{pattern}
+		single.Include = nil
+		matches[i] = make(map[string]bool)
+
+		for _, f := range files {
+			if single.ShouldExclude(f) {
+				matches[i][f] = true
+			}
+		}
+	}
+
+	var problems []ExcludeProblem
+
+	for i, pattern := range c.Exclude {
+		if len(matches[i]) == 0 {
+			problems = append(problems, ExcludeProblem{Pattern: pattern})
+
+			continue
+		}
+
+		for j, other := range c.Exclude {
+			if j == i || !subset(matches[i], matches[j]) {
+				continue
+			}
+
+			// Of two patterns matching the same files, keep the first
+			if len(matches[i]) == len(matches[j]) && j > i {
+				continue
+			}
+
+			problems = append(problems, ExcludeProblem{Pattern: pattern, ShadowedBy: other})
+
+			break
+		}
+	}
+
+	return problems
+}
+
+// subset reports whether every key of a is in b.
+func subset(a, b map[string]bool) bool {
+	for k := range a {
+		if !b[k] {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,198 @@
+package config
+
+import (
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// Path matching modes, selected with the path-matching setting.
+const (
+	// PathMatchingPortable matches exclude patterns against slash-separated
+	// paths, the same way on every platform, and only uses config files of the
+	// module being analyzed. It is the default.
+	PathMatchingPortable = "portable"
+	// PathMatchingLegacy matches exclude patterns against the paths as the
+	// platform spells them, and uses config files above the module root.
+	PathMatchingLegacy = "legacy"
+)
+
+// PathMatchings returns the supported values of the path-matching setting.
+func PathMatchings() []string {
+	return []string{PathMatchingPortable, PathMatchingLegacy}
+}
+
+// ShouldExclude checks if a file path matches any exclude pattern or, if
+// include patterns are set, none of them.
+//
+// Paths and patterns are compared with forward slashes (see path.Match), so
+// patterns work the same on Windows. A "**" path element matches any number of
+// directories. A pattern is matched against
+//   - the absolute path, if it is absolute ("/src/app/gen/*.go");
+//   - the path relative to Dir, if it contains a slash ("internal/legacy/**"),
+//     or relative to the root of the file's module (the closest directory
+//     with a go.mod) if Dir is empty;
+//   - the base name otherwise ("*_test.go").
+//
+// With path-matching: legacy, every pattern is matched against the path and
+// the base name as given, using the platform's separator.
+func (c Config) ShouldExclude(filename string) bool {
+	if c.matchesAny(c.Exclude, filename) {
+		return true
+	}
+
+	return len(c.Include) > 0 && !c.matchesAny(c.Include, filename)
+}
+
+// matchesAny reports whether filename matches any of patterns (see
+// ShouldExclude).
+func (c Config) matchesAny(patterns []string, filename string) bool {
+	if c.PathMatching == PathMatchingLegacy {
+		return matchesAnyLegacy(patterns, filename)
+	}
+
+	abs := filename
+	if a, err := filepath.Abs(filename); err == nil {
+		abs = a
+	}
+
+	slashed := filepath.ToSlash(abs)
+	base := path.Base(slashed)
+
+	var rel string
+
+	relOK := false
+
+	for _, pattern := range patterns {
+		pattern = filepath.ToSlash(pattern)
+
+		switch {
+		case isAbsPattern(pattern):
+			if matchPath(pattern, slashed) {
+				return true
+			}
+		case strings.Contains(pattern, "/"):
+			if !relOK {
+				rel, relOK = c.Relative(abs)
+				if !relOK {
+					continue
+				}
+			}
+
+			if matchPath(strings.TrimPrefix(pattern, "./"), rel) {
+				return true
+			}
+		default:
+			if matched, _ := path.Match(pattern, base); matched {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// matchesAnyLegacy is matchesAny with path-matching: legacy.
+func matchesAnyLegacy(patterns []string, filename string) bool {
+	for _, pattern := range patterns {
+		if matched, _ := filepath.Match(pattern, filename); matched {
+			return true
+		}
+		// Also try matching against the base name
+		if matched, _ := filepath.Match(pattern, filepath.Base(filename)); matched {
+			return true
+		}
+	}
+
+	return false
+}
+
+// isAbsPattern reports whether a slash-separated pattern is an absolute path,
+// including Windows paths with a drive letter ("C:/src/...").
+func isAbsPattern(pattern string) bool {
+	if strings.HasPrefix(pattern, "/") {
+		return true
+	}
+
+	return len(pattern) >= 3 && pattern[1] == ':' && pattern[2] == '/'
+}
+
+// Relative returns the slash-separated path of the absolute file name relative
+// to Dir, or to the root of its module if Dir is empty. It fails for files
+// outside that directory.
+func (c Config) Relative(abs string) (string, bool) {
+	root := c.Dir
+	if root == "" {
+		root = ModuleRoot(filepath.Dir(abs))
+	}
+
+	if root == "" {
+		return "", false
+	}
+
+	rel, err := filepath.Rel(root, abs)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", false
+	}
+
+	return filepath.ToSlash(rel), true
+}
+
+// matchPath reports whether the slash-separated name matches pattern, where a
+// "**" element matches zero or more elements.
+func matchPath(pattern, name string) bool {
+	return matchElems(strings.Split(pattern, "/"), strings.Split(name, "/"))
+}
+
+func matchElems(pattern, name []string) bool {
+	for len(pattern) > 0 {
+		if pattern[0] == "**" {
+			for i := range len(name) + 1 {
+				if matchElems(pattern[1:], name[i:]) {
+					return true
+				}
+			}
+
+			return false
+		}
+
+		if len(name) == 0 {
+			return false
+		}
+
+		if matched, _ := path.Match(pattern[0], name[0]); !matched {
+			return false
+		}
+
+		pattern, name = pattern[1:], name[1:]
+	}
+
+	return len(name) == 0
+}
+
+// moduleRoots caches ModuleRoot per directory.
+var moduleRoots sync.Map
+
+// ModuleRoot returns the closest directory containing a go.mod file among dir
+// and its parents, or "" if there is none.
+func ModuleRoot(dir string) string {
+	if cached, ok := moduleRoots.Load(dir); ok {
+		if root, ok := cached.(string); ok {
+			return root
+		}
+	}
+
+	root := ""
+
+	if _, err := os.Stat(filepath.Join(dir, "go.mod")); err == nil {
+		root = dir
+	} else if parent := filepath.Dir(dir); parent != dir {
+		root = ModuleRoot(parent)
+	}
+
+	moduleRoots.Store(dir, root)
+
+	return root
+}
@@ -0,0 +1,145 @@
+package config
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/mickamy/pointless/internal/messages"
+	"github.com/mickamy/pointless/internal/preset"
+	"github.com/mickamy/pointless/internal/rules"
+	"github.com/mickamy/pointless/internal/severity"
+)
+
+// SchemaURI is the JSON Schema dialect of Schema.
+const SchemaURI = "https://json-schema.org/draft/2020-12/schema"
+
+// Schema returns a JSON Schema for the config file, to be marshaled as JSON.
+// The properties are derived from the yaml and doc tags of Config, so they
+// cannot drift from what Load accepts, and settings taking one of a fixed set
+// of values list them.
+func Schema() (map[string]any, error) {
+	properties := make(map[string]any)
+
+	t := reflect.TypeFor[Config]()
+	for i := range t.NumField() {
+		f := t.Field(i)
+
+		name, _, _ := strings.Cut(f.Tag.Get("yaml"), ",")
+		if name == "" || name == "-" {
+			continue
+		}
+
+		prop, err := schemaOf(f.Type, enums()[name])
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", name, err)
+		}
+
+		if doc := f.Tag.Get("doc"); doc != "" {
+			prop["description"] = doc
+		}
+
+		if name == "severity" {
+			prop["propertyNames"] = map[string]any{"enum": ruleIDs(false)}
+		}
+
+		properties[name] = prop
+	}
+
+	return map[string]any{
+		"$schema":              SchemaURI,
+		"title":                "pointless config (.pointless.yaml)",
+		"type":                 "object",
+		"properties":           properties,
+		"additionalProperties": false,
+	}, nil
+}
+
+// schemaOf returns the schema of a setting of type t, whose values (or, for
+// lists and maps, elements) are restricted to enum if it is not empty.
+func schemaOf(t reflect.Type, enum []string) (map[string]any, error) {
+	switch t.Kind() {
+	case reflect.Int:
+		return map[string]any{"type": "integer", "minimum": 1}, nil
+	case reflect.Bool:
+		return map[string]any{"type": "boolean"}, nil
+	case reflect.Pointer:
+		return schemaOf(t.Elem(), enum)
+	case reflect.String:
+		if len(enum) > 0 {
+			return map[string]any{"type": "string", "enum": enum}, nil
+		}
+
+		return map[string]any{"type": "string"}, nil
+	case reflect.Slice:
+		items, err := schemaOf(t.Elem(), enum)
+		if err != nil {
+			return nil, err
+		}
+
+		return map[string]any{"type": "array", "items": items}, nil
+	case reflect.Struct:
+		properties := make(map[string]any)
+
+		for i := range t.NumField() {
+			f := t.Field(i)
+
+			name, _, _ := strings.Cut(f.Tag.Get("yaml"), ",")
+			if name == "" || name == "-" {
+				continue
+			}
+
+			prop, err := schemaOf(f.Type, enums()[name])
+			if err != nil {
+				return nil, fmt.Errorf("%s: %w", name, err)
+			}
+
+			if doc := f.Tag.Get("doc"); doc != "" {
+				prop["description"] = doc
+			}
+
+			properties[name] = prop
+		}
+
+		return map[string]any{"type": "object", "properties": properties, "additionalProperties": false}, nil
+	case reflect.Map:
+		values, err := schemaOf(t.Elem(), enum)
+		if err != nil {
+			return nil, err
+		}
+
+		return map[string]any{"type": "object", "additionalProperties": values}, nil
+	default:
+		return nil, fmt.Errorf("unsupported type %s", t)
+	}
+}
+
+// enums maps the settings taking one of a fixed set of values, or lists or
+// maps of them, to the values. The fields of exclude-rules entries are
+// listed too.
+func enums() map[string][]string {
+	return map[string][]string{
+		"threshold-unit":   {UnitBytes, UnitWords, UnitCacheLines},
+		"presets":          preset.Names(),
+		"enable":           ruleIDs(true),
+		"lang":             messages.Languages(),
+		"default-severity": severity.Names(),
+		"severity":         severity.Names(),
+		"format":           Formatters(),
+		"path-matching":    PathMatchings(),
+		"rules":            ruleIDs(false),
+	}
+}
+
+// ruleIDs returns the IDs of the rules, or of the opt-in rules only.
+func ruleIDs(optIn bool) []string {
+	var ids []string
+
+	for _, r := range rules.All() {
+		if r.OptIn || !optIn {
+			ids = append(ids, r.ID)
+		}
+	}
+
+	return ids
+}
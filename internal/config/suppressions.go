@@ -0,0 +1,69 @@
+package config
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"gopkg.in/yaml.v3"
+)
+
+// SuppressionsFile is the default name of the generated suppressions file.
+const SuppressionsFile = ".pointless-suppressions.yaml"
+
+// Suppressions is the content of a suppressions file. Unlike line-based
+// baselines, entries name symbols, so they survive code being moved around.
+type Suppressions struct {
+	Suppressions []Suppression `yaml:"suppressions"`
+}
+
+// Suppression suppresses all findings inside one symbol.
+type Suppression struct {
+	// Symbol is the package-path-qualified symbol, e.g. "example.com/pkg.(*Server).Handler".
+	Symbol string `yaml:"symbol"`
+	// Reason is the finding that caused the suppression to be generated.
+	Reason string `yaml:"reason,omitempty"`
+	// Fingerprints are the content-based fingerprints of the suppressed
+	// findings, for trackers that follow individual findings.
+	Fingerprints []string `yaml:"fingerprints,omitempty"`
+}
+
+// WriteSuppressions writes s to w as YAML.
+func WriteSuppressions(w io.Writer, s Suppressions) error {
+	if _, err := io.WriteString(w, "# Generated by pointless suppress. Entries suppress all findings in the named symbol.\n"); err != nil {
+		return fmt.Errorf("writing suppressions: %w", err)
+	}
+
+	enc := yaml.NewEncoder(w)
+	enc.SetIndent(2)
+
+	if err := enc.Encode(s); err != nil {
+		return fmt.Errorf("encoding suppressions: %w", err)
+	}
+
+	if err := enc.Close(); err != nil {
+		return fmt.Errorf("encoding suppressions: %w", err)
+	}
+
+	return nil
+}
+
+// loadSuppressions loads the symbols of the suppressions file at path.
+func loadSuppressions(path string) ([]string, error) {
+	data, err := os.ReadFile(path) //nolint:gosec // G304: path is from findFile, not user input
+	if err != nil {
+		return nil, newError(Unreadable, path, fmt.Errorf("reading suppressions file: %w", err))
+	}
+
+	var s Suppressions
+	if err := yaml.Unmarshal(data, &s); err != nil {
+		return nil, newError(ParseError, path, fmt.Errorf("parsing suppressions file: %w", err))
+	}
+
+	symbols := make([]string, 0, len(s.Suppressions))
+	for _, entry := range s.Suppressions {
+		symbols = append(symbols, entry.Symbol)
+	}
+
+	return symbols, nil
+}
@@ -0,0 +1,171 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"net/url"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/mickamy/pointless/internal/messages"
+	"github.com/mickamy/pointless/internal/preset"
+	"github.com/mickamy/pointless/internal/rules"
+	"github.com/mickamy/pointless/internal/severity"
+	"github.com/mickamy/pointless/internal/skipexpr"
+)
+
+// validate returns c with its invalid settings replaced by their defaults,
+// along with an InvalidValue problem for each invalid setting.
+func (c Config) validate() (Config, error) {
+	var errs []error
+
+	invalid := func(format string, args ...any) {
+		errs = append(errs, newError(InvalidValue, c.File, fmt.Errorf(format, args...)))
+	}
+
+	for _, name := range c.Presets {
+		if _, ok := preset.Lookup(name); !ok {
+			invalid("unknown preset %q (available: %s)", name, strings.Join(preset.Names(), ", "))
+		}
+	}
+
+	if c.MinSize < 0 || c.Threshold > 0 && c.MinSize > c.Threshold {
+		invalid("invalid min-size %d: expected a size between 0 and the threshold %d", c.MinSize, c.Threshold)
+
+		c.MinSize = 0
+	}
+
+	if _, err := messages.NewPrinter(c.Lang); err != nil {
+		invalid("invalid lang: %w", err)
+
+		c.Lang = messages.DefaultLang
+	}
+
+	if c.DefaultSeverity != "" {
+		if err := severity.Validate(c.DefaultSeverity); err != nil {
+			invalid("invalid default-severity: %w", err)
+
+			c.DefaultSeverity = severity.Default
+		}
+	}
+
+	for _, rule := range slices.Sorted(maps.Keys(c.Severity)) {
+		if _, ok := rules.Lookup(rule); !ok {
+			invalid("severity for unknown rule %q", rule)
+		}
+
+		if err := severity.Validate(c.Severity[rule]); err != nil {
+			invalid("invalid severity for %s: %w", rule, err)
+
+			delete(c.Severity, rule)
+		}
+	}
+
+	if c.Format != "" && !slices.Contains(Formatters(), c.Format) {
+		invalid("unknown format %q (available: %s)", c.Format, strings.Join(Formatters(), ", "))
+
+		c.Format = FormatGofmt
+	}
+
+	if !slices.Contains(PathMatchings(), c.PathMatching) {
+		invalid("unknown path-matching %q (available: %s)", c.PathMatching, strings.Join(PathMatchings(), ", "))
+
+		c.PathMatching = PathMatchingPortable
+	}
+
+	if c.SkipIf != "" {
+		if _, err := skipexpr.Parse(c.SkipIf); err != nil {
+			invalid("invalid skip-if: %w", err)
+
+			c.SkipIf = ""
+		}
+	}
+
+	for _, id := range c.Enable {
+		if r, ok := rules.Lookup(id); !ok || !r.OptIn {
+			invalid("enable lists %q, which is not an opt-in rule", id)
+		}
+	}
+
+	if c.DocsBaseURL != "" {
+		if u, err := url.Parse(rules.URLAt(c.DocsBaseURL, rules.ValueReceiver)); err != nil || !u.IsAbs() {
+			invalid("invalid docs-base-url %q: expected an absolute URL", c.DocsBaseURL)
+
+			c.DocsBaseURL = ""
+		}
+	}
+
+	if c.Tests.Severity != "" {
+		if err := severity.Validate(c.Tests.Severity); err != nil {
+			invalid("invalid tests.severity: %w", err)
+
+			c.Tests.Severity = ""
+		}
+	}
+
+	if c.Tests.Threshold < 0 {
+		invalid("invalid tests.threshold %d: expected a positive size", c.Tests.Threshold)
+
+		c.Tests.Threshold = 0
+	}
+
+	for _, rule := range c.Tests.Rules {
+		if _, ok := rules.Lookup(rule); !ok {
+			invalid("tests.rules lists unknown rule %q", rule)
+		}
+	}
+
+	if c.PackageBudget != "" {
+		if d, err := time.ParseDuration(c.PackageBudget); err != nil || d <= 0 {
+			invalid("invalid package-budget %q: expected a positive duration, like 5s", c.PackageBudget)
+
+			c.PackageBudget = ""
+		}
+	}
+
+	kept := c.ExcludeRules[:0:0]
+
+	for i, r := range c.ExcludeRules {
+		if err := r.validate(); err != nil {
+			invalid("invalid exclude-rules entry %d: %w", i+1, err)
+
+			continue
+		}
+
+		kept = append(kept, r)
+	}
+
+	c.ExcludeRules = kept
+
+	return c, errors.Join(errs...)
+}
+
+// validate checks that r has a condition besides linters, as golangci-lint
+// requires, and that its rules and regular expressions are valid.
+func (r ExcludeRule) validate() error {
+	if r.Path == "" && r.PathExcept == "" && r.Text == "" && r.Source == "" && len(r.Rules) == 0 {
+		return errors.New("no path, path-except, rules, text, or source condition")
+	}
+
+	for _, id := range r.Rules {
+		if _, ok := rules.Lookup(id); !ok {
+			return fmt.Errorf("unknown rule %q", id)
+		}
+	}
+
+	for _, re := range []struct{ name, expr string }{
+		{"path", r.Path},
+		{"path-except", r.PathExcept},
+		{"text", r.Text},
+		{"source", r.Source},
+	} {
+		if _, err := regexp.Compile(re.expr); err != nil {
+			return fmt.Errorf("invalid %s: %w", re.name, err)
+		}
+	}
+
+	return nil
+}
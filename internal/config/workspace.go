@@ -0,0 +1,134 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"golang.org/x/mod/modfile"
+)
+
+// Workspace holds the configs of the modules of a go.work workspace. Each
+// module uses its own config file, found like Load finds it from the module
+// root, or the config of the workspace root if it has none.
+type Workspace struct {
+	// Dir is the directory of the go.work file.
+	Dir string
+	// Config is the config of the workspace root.
+	Config Config
+	// Modules holds the modules of the workspace, in the order of go.work.
+	Modules []Module
+}
+
+// Module is a module of a workspace.
+type Module struct {
+	// Dir is the absolute directory of the module.
+	Dir string
+	// Config is the config applied to the packages of the module.
+	Config Config
+	// OwnConfig reports whether Config comes from a config file of the module
+	// rather than the workspace root.
+	OwnConfig bool
+}
+
+// WorkFile returns the go.work file the go command uses in dir if dir is the
+// root of a workspace: the GOWORK file if set, or dir/go.work. It returns ""
+// with GOWORK=off, or if there is no go.work in dir. Parent directories are not
+// searched: run in a module of a workspace, Load applies to that module alone.
+func WorkFile(dir string) string {
+	switch gowork := os.Getenv("GOWORK"); gowork {
+	case "off":
+		return ""
+	case "":
+	default:
+		if filepath.Dir(gowork) != filepath.Clean(dir) {
+			return ""
+		}
+
+		return gowork
+	}
+
+	path := filepath.Join(dir, "go.work")
+	if _, err := os.Stat(path); err != nil {
+		return ""
+	}
+
+	return path
+}
+
+// LoadWorkspace loads the configs of the workspace defined by the go.work file
+// at path. Like Load, it returns the configs it could load along with the
+// problems found, so the caller can warn about them and go on.
+func LoadWorkspace(path string) (Workspace, error) {
+	if abs, err := filepath.Abs(path); err == nil {
+		path = abs
+	}
+
+	ws := Workspace{Dir: filepath.Dir(path), Config: DefaultConfig()}
+
+	data, err := os.ReadFile(path) //nolint:gosec // G304: path is the go.work file of the working directory
+	if err != nil {
+		return ws, newError(Unreadable, path, fmt.Errorf("reading workspace file: %w", err))
+	}
+
+	wf, err := modfile.ParseWork(path, data, nil)
+	if err != nil {
+		return ws, newError(ParseError, path, fmt.Errorf("parsing workspace file: %w", err))
+	}
+
+	var errs []error
+
+	ws.Config, err = LoadDir(ws.Dir)
+	if err != nil {
+		errs = append(errs, err)
+	}
+
+	for _, use := range wf.Use {
+		dir := filepath.FromSlash(use.Path)
+		if !filepath.IsAbs(dir) {
+			dir = filepath.Join(ws.Dir, dir)
+		}
+
+		m := Module{Dir: dir, Config: ws.Config}
+
+		if path, aboveModule, _ := findConfigFile(dir); path != "" && !aboveModule {
+			cfg, err := LoadDir(dir)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("module %s: %w", use.Path, err))
+			}
+
+			m.Config, m.OwnConfig = cfg, true
+		}
+
+		ws.Modules = append(ws.Modules, m)
+	}
+
+	return ws, errors.Join(errs...)
+}
+
+// ConfigFor returns the config applied to the file: that of the innermost
+// module containing it, or the workspace root config for files outside the
+// modules (like dependencies in the module cache).
+func (w Workspace) ConfigFor(filename string) Config {
+	abs, err := filepath.Abs(filename)
+	if err != nil {
+		return w.Config
+	}
+
+	cfg, best := w.Config, ""
+
+	for _, m := range w.Modules {
+		rel, err := filepath.Rel(m.Dir, abs)
+		if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+			continue
+		}
+
+		if len(m.Dir) > len(best) {
+			cfg, best = m.Config, m.Dir
+		}
+	}
+
+	return cfg
+}
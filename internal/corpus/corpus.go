@@ -0,0 +1,150 @@
+// Package corpus describes pinned snapshots of real-world code and the number of
+// findings per rule recorded for each, so behavior changes show up as count diffs.
+package corpus
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+
+	"gopkg.in/yaml.v3"
+)
+
+// File is the content of a corpus file.
+type File struct {
+	Entries []Entry `yaml:"corpus"`
+}
+
+// Entry is one snapshot and its recorded finding counts.
+type Entry struct {
+	// Name identifies the entry in reports.
+	Name string `yaml:"name"`
+	// Module is a pinned module version (e.g. "golang.org/x/tools@v0.41.0"),
+	// analyzed from the module cache.
+	Module string `yaml:"module,omitempty"`
+	// Dir is a local directory, relative to the corpus file, analyzed instead of Module.
+	Dir string `yaml:"dir,omitempty"`
+	// Patterns are the package patterns to analyze ("./..." if empty).
+	Patterns []string `yaml:"patterns,omitempty"`
+	// Counts maps rule IDs to the recorded number of findings.
+	Counts map[string]int `yaml:"counts"`
+}
+
+// Load reads a corpus file.
+func Load(path string) (File, error) {
+	data, err := os.ReadFile(path) //nolint:gosec // G304: path is the user-provided corpus file
+	if err != nil {
+		return File{}, fmt.Errorf("reading corpus file: %w", err)
+	}
+
+	var f File
+	if err := yaml.Unmarshal(data, &f); err != nil {
+		return File{}, fmt.Errorf("parsing corpus file: %w", err)
+	}
+
+	for i, e := range f.Entries {
+		if (e.Module == "") == (e.Dir == "") {
+			return File{}, fmt.Errorf("corpus entry %d (%s): exactly one of module and dir must be set", i, e.Name)
+		}
+	}
+
+	return f, nil
+}
+
+// Write writes f to w as YAML.
+func Write(w io.Writer, f File) error {
+	if _, err := io.WriteString(w, "# Finding counts per rule; update with pointless selftest -update.\n"); err != nil {
+		return fmt.Errorf("writing corpus: %w", err)
+	}
+
+	enc := yaml.NewEncoder(w)
+	enc.SetIndent(2)
+
+	if err := enc.Encode(f); err != nil {
+		return fmt.Errorf("encoding corpus: %w", err)
+	}
+
+	if err := enc.Close(); err != nil {
+		return fmt.Errorf("encoding corpus: %w", err)
+	}
+
+	return nil
+}
+
+// Resolve returns the directory to analyze for e. Dir is relative to base, the
+// directory of the corpus file; modules are downloaded into the module cache.
+func (e Entry) Resolve(base string) (string, error) {
+	if e.Dir != "" {
+		if filepath.IsAbs(e.Dir) {
+			return e.Dir, nil
+		}
+
+		return filepath.Join(base, e.Dir), nil
+	}
+
+	var stdout, stderr bytes.Buffer
+
+	cmd := exec.Command("go", "mod", "download", "-json", e.Module) //nolint:gosec // G204: the module comes from the corpus file
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		return "", fmt.Errorf("downloading %s: %w: %s", e.Module, err, bytes.TrimSpace(stderr.Bytes()))
+	}
+
+	var mod struct {
+		Dir   string
+		Error string
+	}
+
+	if err := json.Unmarshal(stdout.Bytes(), &mod); err != nil {
+		return "", fmt.Errorf("parsing go mod download output: %w", err)
+	}
+
+	if mod.Error != "" {
+		return "", fmt.Errorf("downloading %s: %s", e.Module, mod.Error)
+	}
+
+	return mod.Dir, nil
+}
+
+// PatternsOrDefault returns the package patterns of e.
+func (e Entry) PatternsOrDefault() []string {
+	if len(e.Patterns) == 0 {
+		return []string{"./..."}
+	}
+
+	return e.Patterns
+}
+
+// Diff is a rule whose finding count changed.
+type Diff struct {
+	Rule      string
+	Want, Got int
+}
+
+// Compare returns the rules whose counts differ between want and got, sorted by rule.
+func Compare(want, got map[string]int) []Diff {
+	var diffs []Diff
+
+	for rule, n := range want {
+		if got[rule] != n {
+			diffs = append(diffs, Diff{Rule: rule, Want: n, Got: got[rule]})
+		}
+	}
+
+	for rule, n := range got {
+		if _, ok := want[rule]; !ok && n != 0 {
+			diffs = append(diffs, Diff{Rule: rule, Want: 0, Got: n})
+		}
+	}
+
+	sort.Slice(diffs, func(i, j int) bool { return diffs[i].Rule < diffs[j].Rule })
+
+	return diffs
+}
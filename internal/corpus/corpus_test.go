@@ -0,0 +1,69 @@
+package corpus_test
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/mickamy/pointless/internal/corpus"
+)
+
+func TestCompare(t *testing.T) {
+	t.Parallel()
+
+	want := map[string]int{"return-pointer": 3, "slice-pointer": 2, "value-receiver": 0}
+	got := map[string]int{"return-pointer": 3, "slice-pointer": 1, "local-pointer": 4}
+
+	diffs := corpus.Compare(want, got)
+	expected := []corpus.Diff{
+		{Rule: "local-pointer", Want: 0, Got: 4},
+		{Rule: "slice-pointer", Want: 2, Got: 1},
+	}
+
+	if !reflect.DeepEqual(diffs, expected) {
+		t.Errorf("Compare = %+v, want %+v", diffs, expected)
+	}
+}
+
+func TestWriteLoad(t *testing.T) {
+	t.Parallel()
+
+	f := corpus.File{Entries: []corpus.Entry{
+		{Name: "tools", Module: "golang.org/x/tools@v0.41.0", Patterns: []string{"./go/..."}, Counts: map[string]int{"return-pointer": 12}},
+		{Name: "local", Dir: "testdata", Counts: map[string]int{}},
+	}}
+
+	var buf bytes.Buffer
+	if err := corpus.Write(&buf, f); err != nil {
+		t.Fatal(err)
+	}
+
+	path := filepath.Join(t.TempDir(), "corpus.yaml")
+	if err := os.WriteFile(path, buf.Bytes(), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := corpus.Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !reflect.DeepEqual(got, f) {
+		t.Errorf("Load = %+v, want %+v", got, f)
+	}
+}
+
+func TestLoadRejectsAmbiguousEntries(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "corpus.yaml")
+	if err := os.WriteFile(path, []byte("corpus:\n  - name: both\n    module: example.com/m@v1.0.0\n    dir: m\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := corpus.Load(path); err == nil {
+		t.Error("Load succeeded, want an error for an entry with both module and dir")
+	}
+}
@@ -0,0 +1,160 @@
+// Package directives parses the comments that control pointless: nolint
+// comments naming it (or no linter at all) and //pointless: directives.
+//
+//	//nolint:pointless          like //pointless:ignore
+//	//pointless:ignore          the declaration the comment annotates
+//	//pointless:ignore-file     every finding in the file
+//	//pointless:ignore-type     every finding about the type declared below
+//
+// Two more state how a type is meant to be used, in every package using it:
+//
+//	//pointless:enforce         the type declared below is always used by value
+//	//pointless:pointer         the type declared below is meant to be used by pointer
+//
+// The ignore directives may end with an until=YYYY-MM-DD expiration date,
+// after which they no longer apply:
+//
+//	//nolint:pointless // until=2025-12-31 waiting for the v2 API
+package directives
+
+import (
+	"strings"
+)
+
+// Kind is the kind of a directive.
+type Kind int
+
+// Kinds of directives.
+const (
+	// Ignore suppresses the findings in the declaration a comment annotates:
+	// the one below it, or the one whose header holds it.
+	Ignore Kind = iota + 1
+	// IgnoreFile suppresses the findings in the file holding the comment.
+	IgnoreFile
+	// IgnoreType suppresses the findings about the type declared below the
+	// comment, wherever they are in the package.
+	IgnoreType
+	// Enforce requires the type declared below the comment to be used by
+	// value: every *T in the packages analyzed is a finding.
+	Enforce
+	// Pointer declares that the pointers to the type declared below the
+	// comment are intentional: no finding about the type is reported in the
+	// packages analyzed.
+	Pointer
+)
+
+// names maps the names following "pointless:" to the kinds of directives.
+var names = map[string]Kind{
+	"ignore":      Ignore,
+	"ignore-file": IgnoreFile,
+	"ignore-type": IgnoreType,
+	"enforce":     Enforce,
+	"pointer":     Pointer,
+}
+
+// String returns the directive as written, as in pointless:ignore-file.
+func (k Kind) String() string {
+	for name, kind := range names {
+		if kind == k {
+			return "pointless:" + name
+		}
+	}
+
+	return "pointless:unknown"
+}
+
+// Suppresses reports whether directives of kind k suppress findings, as the
+// ignore directives do.
+func (k Kind) Suppresses() bool {
+	return k == Ignore || k == IgnoreFile || k == IgnoreType
+}
+
+// Exported reports whether directives of kind k apply to the type they
+// annotate wherever it is used, in importing packages too.
+func (k Kind) Exported() bool {
+	return k == Enforce || k == Pointer
+}
+
+// Directive is a comment controlling pointless.
+type Directive struct {
+	Kind Kind
+	// Until is the expiration date of the directive as written after until=,
+	// or empty if it does not expire.
+	Until string
+}
+
+// untilPrefix marks the expiration date of a directive.
+const untilPrefix = "until="
+
+// Parse parses the text of a comment, including its // or /* */ markers, and
+// reports whether it is a directive.
+func Parse(text string) (Directive, bool) {
+	// Remove // or /* */ markers
+	if strings.HasPrefix(text, "//") {
+		text = strings.TrimPrefix(text, "//")
+	} else if strings.HasPrefix(text, "/*") {
+		text = strings.TrimPrefix(text, "/*")
+		text = strings.TrimSuffix(text, "*/")
+	}
+
+	text = strings.TrimSpace(text)
+
+	kind, ok := parseKind(text)
+	if !ok {
+		return Directive{}, false
+	}
+
+	d := Directive{Kind: kind}
+
+	if i := strings.Index(text, untilPrefix); i >= 0 {
+		d.Until = strings.TrimPrefix(text[i:], untilPrefix)
+		if j := strings.IndexAny(d.Until, " \t"); j >= 0 {
+			d.Until = d.Until[:j]
+		}
+	}
+
+	return d, true
+}
+
+// parseKind returns the kind of directive text, with its comment markers
+// removed, starts with.
+func parseKind(text string) (Kind, bool) {
+	if rest, ok := strings.CutPrefix(text, "pointless:"); ok {
+		kind, ok := names[firstWord(rest)]
+
+		return kind, ok
+	}
+
+	rest, ok := strings.CutPrefix(text, "nolint")
+	if !ok {
+		return 0, false
+	}
+
+	// Blanket //nolint
+	if rest == "" || rest[0] == ' ' || rest[0] == '\t' {
+		return Ignore, true
+	}
+
+	linters, ok := strings.CutPrefix(rest, ":")
+	if !ok {
+		return 0, false
+	}
+
+	// The linter list ends at the first space (e.g. "//nolint:pointless // reason")
+	for _, l := range strings.Split(firstWord(linters), ",") {
+		if strings.TrimSpace(l) == "pointless" {
+			return Ignore, true
+		}
+	}
+
+	return 0, false
+}
+
+// firstWord returns s up to its first space or tab.
+func firstWord(s string) string {
+	if i := strings.IndexAny(s, " \t"); i >= 0 {
+		return s[:i]
+	}
+
+	return s
+}
@@ -0,0 +1,48 @@
+package directives_test
+
+import (
+	"testing"
+
+	"github.com/mickamy/pointless/internal/directives"
+)
+
+func TestParse(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		text string
+		want directives.Directive
+		ok   bool
+	}{
+		{text: "//nolint:pointless", want: directives.Directive{Kind: directives.Ignore}, ok: true},
+		{text: "//nolint:errcheck,pointless // reason", want: directives.Directive{Kind: directives.Ignore}, ok: true},
+		{text: "// nolint (blanket)", want: directives.Directive{Kind: directives.Ignore}, ok: true},
+		{text: "/* nolint */", want: directives.Directive{Kind: directives.Ignore}, ok: true},
+		{text: "//nolint:errcheck", ok: false},
+		{text: "//nolint:errcheck // not pointless", ok: false},
+		{text: "//nolintx", ok: false},
+		{text: "//pointless:ignore", want: directives.Directive{Kind: directives.Ignore}, ok: true},
+		{text: "// pointless:ignore-file generated code", want: directives.Directive{Kind: directives.IgnoreFile}, ok: true},
+		{text: "//pointless:ignore-type // until=2999-12-31", want: directives.Directive{Kind: directives.IgnoreType, Until: "2999-12-31"}, ok: true},
+		{text: "//nolint:pointless // until=someday waiting", want: directives.Directive{Kind: directives.Ignore, Until: "someday"}, ok: true},
+		{text: "//pointless:enforce", want: directives.Directive{Kind: directives.Enforce}, ok: true},
+		{text: "//pointless:pointer identity matters", want: directives.Directive{Kind: directives.Pointer}, ok: true},
+		{text: "//pointless:ignored", ok: false},
+		{text: "// A pointless:ignore in prose", ok: false},
+	}
+
+	for _, tt := range tests {
+		got, ok := directives.Parse(tt.text)
+		if ok != tt.ok || got != tt.want {
+			t.Errorf("Parse(%q) = %+v, %v; want %+v, %v", tt.text, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestKindString(t *testing.T) {
+	t.Parallel()
+
+	if got := directives.IgnoreFile.String(); got != "pointless:ignore-file" {
+		t.Errorf("IgnoreFile.String() = %q", got)
+	}
+}
@@ -0,0 +1,141 @@
+// Package editapply applies the text edits of suggested fixes to source files,
+// for the code paths that fix files without the analysis driver: -fix outside
+// singlechecker and the checks that preview a fix. Edits are located by their
+// offsets in the token.File, never by the positions //line directives remap,
+// and overlapping edits are detected rather than silently merged.
+package editapply
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"go/token"
+	"slices"
+
+	"golang.org/x/tools/go/analysis"
+)
+
+// Edit replaces the bytes [Offset, End) of a file with NewText.
+type Edit struct {
+	Offset  int
+	End     int
+	NewText []byte
+}
+
+// Relation is how two edits of a file relate to each other.
+type Relation int
+
+const (
+	// Disjoint edits apply independently of each other.
+	Disjoint Relation = iota
+	// Duplicate edits are identical and apply once.
+	Duplicate
+	// Overlapping edits change the same bytes (or insert at the same offset)
+	// differently, so they cannot both apply.
+	Overlapping
+)
+
+// Relate returns how the edits a and b relate.
+func Relate(a, b Edit) Relation {
+	switch {
+	case a.Offset == b.Offset && a.End == b.End && bytes.Equal(a.NewText, b.NewText):
+		return Duplicate
+	case a.Offset < b.End && b.Offset < a.End, a.Offset == b.Offset:
+		return Overlapping
+	}
+
+	return Disjoint
+}
+
+// OverlapError is returned by Apply for two overlapping edits.
+type OverlapError struct {
+	A, B Edit
+}
+
+func (e *OverlapError) Error() string {
+	return fmt.Sprintf("overlapping edits at offsets [%d, %d) and [%d, %d)", e.A.Offset, e.A.End, e.B.Offset, e.B.End)
+}
+
+// FromTextEdits converts the edits of a suggested fix within the file tf to
+// offsets in tf. Edits in other files of the fix are left out.
+func FromTextEdits(tf *token.File, edits []analysis.TextEdit) []Edit {
+	var result []Edit
+
+	for _, e := range edits {
+		if base := tf.Base(); int(e.Pos) < base || int(e.Pos) > base+tf.Size() {
+			continue
+		}
+
+		start := tf.Offset(e.Pos)
+
+		end := start
+		if e.End.IsValid() {
+			end = tf.Offset(e.End)
+		}
+
+		result = append(result, Edit{Offset: start, End: end, NewText: e.NewText})
+	}
+
+	return result
+}
+
+// Apply returns src with edits applied, in any order. Duplicate edits are
+// applied once; overlapping edits fail with an *OverlapError, and edits out of
+// the bounds of src with an error. src and edits are not modified.
+func Apply(src []byte, edits []Edit) ([]byte, error) {
+	sorted := slices.Clone(edits)
+	slices.SortStableFunc(sorted, func(a, b Edit) int {
+		if a.Offset != b.Offset {
+			return a.Offset - b.Offset
+		}
+
+		return a.End - b.End
+	})
+
+	var out bytes.Buffer
+
+	last := 0
+
+	for i, e := range sorted {
+		if e.Offset < 0 || e.End < e.Offset || e.End > len(src) {
+			return nil, fmt.Errorf("invalid edit [%d, %d) of a %d-byte file", e.Offset, e.End, len(src))
+		}
+
+		// The edits applied so far are sorted and disjoint, and the previous
+		// edit is the last of them (or a duplicate of it), so e can only
+		// overlap that one
+		if i > 0 {
+			switch Relate(sorted[i-1], e) {
+			case Duplicate:
+				continue
+			case Overlapping:
+				return nil, &OverlapError{A: sorted[i-1], B: e}
+			}
+		}
+
+		out.Write(src[last:e.Offset])
+		out.Write(e.NewText)
+		last = e.End
+	}
+
+	out.Write(src[last:])
+
+	return out.Bytes(), nil
+}
+
+// Format is like Apply, and formats the result with gofmt's rules, so that
+// edits need not care about the indentation and alignment of what they
+// change. A result that does not parse is an error.
+func Format(src []byte, edits []Edit) ([]byte, error) {
+	out, err := Apply(src, edits)
+	if err != nil {
+		return nil, err
+	}
+
+	formatted, err := format.Source(out)
+	if err != nil {
+		return nil, fmt.Errorf("formatting the fixed source: %w", err)
+	}
+
+	return formatted, nil
+}
@@ -0,0 +1,138 @@
+package editapply_test
+
+import (
+	"errors"
+	"go/parser"
+	"go/token"
+	"testing"
+
+	"golang.org/x/tools/go/analysis"
+
+	"github.com/mickamy/pointless/internal/editapply"
+)
+
+func TestApply(t *testing.T) {
+	t.Parallel()
+
+	src := []byte("func (n *Nop) A() {}")
+
+	tests := []struct {
+		name  string
+		edits []editapply.Edit
+		want  string
+	}{
+		{"no edits", nil, "func (n *Nop) A() {}"},
+		{"deletion", []editapply.Edit{{Offset: 8, End: 9}}, "func (n Nop) A() {}"},
+		{"insertion", []editapply.Edit{{Offset: 17, End: 17, NewText: []byte(" int")}}, "func (n *Nop) A() int {}"},
+		{
+			"out of order",
+			[]editapply.Edit{{Offset: 14, End: 15, NewText: []byte("B")}, {Offset: 8, End: 9}},
+			"func (n Nop) B() {}",
+		},
+		{"duplicates", []editapply.Edit{{Offset: 8, End: 9}, {Offset: 8, End: 9}}, "func (n Nop) A() {}"},
+		{
+			"adjacent",
+			[]editapply.Edit{{Offset: 6, End: 8, NewText: []byte("m ")}, {Offset: 8, End: 9}},
+			"func (m Nop) A() {}",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := editapply.Apply(src, tt.edits)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if string(got) != tt.want {
+				t.Errorf("Apply() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestApplyErrors(t *testing.T) {
+	t.Parallel()
+
+	src := []byte("func (n *Nop) A() {}")
+
+	tests := []struct {
+		name    string
+		edits   []editapply.Edit
+		overlap bool
+	}{
+		{"overlapping", []editapply.Edit{{Offset: 6, End: 9}, {Offset: 8, End: 12}}, true},
+		{"nested", []editapply.Edit{{Offset: 6, End: 12}, {Offset: 8, End: 9}}, true},
+		{"insertions at one offset", []editapply.Edit{{Offset: 8, End: 8, NewText: []byte("a")}, {Offset: 8, End: 8, NewText: []byte("b")}}, true},
+		{"past the end", []editapply.Edit{{Offset: 18, End: 40}}, false},
+		{"reversed", []editapply.Edit{{Offset: 9, End: 8}}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := editapply.Apply(src, tt.edits)
+			if err == nil {
+				t.Fatal("Apply() succeeded, want an error")
+			}
+
+			var overlap *editapply.OverlapError
+			if errors.As(err, &overlap) != tt.overlap {
+				t.Errorf("Apply() error = %v, want an *OverlapError: %t", err, tt.overlap)
+			}
+		})
+	}
+}
+
+func TestFormat(t *testing.T) {
+	t.Parallel()
+
+	src := []byte("package p\n\ntype Nop struct{}\n\nfunc (n *Nop) A() {}\n")
+
+	got, err := editapply.Format(src, []editapply.Edit{{Offset: 38, End: 39, NewText: []byte("     ")}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if want := "package p\n\ntype Nop struct{}\n\nfunc (n Nop) A() {}\n"; string(got) != want {
+		t.Errorf("Format() = %q, want %q", got, want)
+	}
+
+	if _, err := editapply.Format(src, []editapply.Edit{{Offset: 0, End: 7}}); err == nil {
+		t.Error("Format() of a source that does not parse succeeded, want an error")
+	}
+}
+
+func TestFromTextEdits(t *testing.T) {
+	t.Parallel()
+
+	// The //line directive must not move the edit
+	src := "package p\n\n//line gen.y:100\nfunc (n *Nop) A() {}\n"
+
+	fset := token.NewFileSet()
+
+	f, err := parser.ParseFile(fset, "p.go", src, parser.SkipObjectResolution)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	other, err := parser.ParseFile(fset, "q.go", "package p\n", parser.SkipObjectResolution)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tf := fset.File(f.Pos())
+	star := f.FileStart + token.Pos(len("package p\n\n//line gen.y:100\nfunc (n "))
+
+	got := editapply.FromTextEdits(tf, []analysis.TextEdit{
+		{Pos: star, End: star + 1},
+		{Pos: other.Package, End: other.Package + 7, NewText: []byte("x")},
+	})
+
+	if len(got) != 1 || got[0].Offset != 36 || got[0].End != 37 {
+		t.Fatalf("FromTextEdits() = %+v, want the edit of p.go at [36, 37)", got)
+	}
+}
@@ -0,0 +1,185 @@
+// Package fix applies the suggested fixes of findings to the source files and
+// formats the files it changes, so that the edits do not fail the formatting
+// checks of the repository they are applied to.
+package fix
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"slices"
+	"sort"
+	"strings"
+
+	"golang.org/x/tools/imports"
+
+	"github.com/mickamy/pointless/internal/config"
+	"github.com/mickamy/pointless/internal/editapply"
+	"github.com/mickamy/pointless/internal/fixsafety"
+	"github.com/mickamy/pointless/internal/runner"
+)
+
+// Result describes the outcome of Apply.
+type Result struct {
+	// Files lists the modified files, sorted.
+	Files []string
+	// Unfixed holds the findings without a fix, with a fix less safe than
+	// allowed, or whose fix conflicts with the fix of an earlier finding.
+	Unfixed []runner.Finding
+}
+
+// Apply applies the first suggested fix of every finding, in order, if it is at
+// most as unsafe as limit (see internal/fixsafety; "" for fixsafety.Default),
+// and formats the modified files with formatter (one of config.Formatters, or
+// "" for gofmt). A fix whose edits overlap those of an earlier fix is skipped
+// as a whole. Identical edits from several findings are applied once.
+func Apply(findings []runner.Finding, formatter, limit string) (Result, error) {
+	if formatter == "" {
+		formatter = config.FormatGofmt
+	}
+
+	if !slices.Contains(config.Formatters(), formatter) {
+		return Result{}, fmt.Errorf("unknown formatter %q (available: %s)", formatter, strings.Join(config.Formatters(), ", "))
+	}
+
+	if limit == "" {
+		limit = fixsafety.Default
+	}
+
+	if err := fixsafety.Validate(limit); err != nil {
+		return Result{}, err
+	}
+
+	var result Result
+
+	edits := make(map[string][]runner.Edit)
+
+	for _, f := range findings {
+		if len(f.Fixes) == 0 || !fixsafety.Allows(limit, f.Fixes[0].Safety) || !addEdits(edits, f.Fixes[0].Edits) {
+			result.Unfixed = append(result.Unfixed, f)
+		}
+	}
+
+	for name, fileEdits := range edits {
+		if err := rewrite(name, fileEdits, formatter); err != nil {
+			return result, err
+		}
+
+		result.Files = append(result.Files, name)
+	}
+
+	sort.Strings(result.Files)
+
+	return result, nil
+}
+
+// addEdits adds the edits of a fix to byFile unless one of them overlaps an
+// edit already there. It reports whether the fix was added.
+func addEdits(byFile map[string][]runner.Edit, fix []runner.Edit) bool {
+	var added []runner.Edit
+
+	for _, e := range fix {
+		existing := byFile[e.Filename]
+
+		switch conflict(existing, e) {
+		case editapply.Duplicate:
+			continue
+		case editapply.Overlapping:
+			return false
+		}
+
+		added = append(added, e)
+	}
+
+	for _, e := range added {
+		byFile[e.Filename] = append(byFile[e.Filename], e)
+	}
+
+	return true
+}
+
+// conflict returns how e relates to the existing edits of its file.
+func conflict(existing []runner.Edit, e runner.Edit) editapply.Relation {
+	for _, x := range existing {
+		if r := editapply.Relate(fileEdit(x), fileEdit(e)); r != editapply.Disjoint {
+			return r
+		}
+	}
+
+	return editapply.Disjoint
+}
+
+// fileEdit returns the edit e within its file.
+func fileEdit(e runner.Edit) editapply.Edit {
+	return editapply.Edit{Offset: e.Offset, End: e.End, NewText: e.NewText}
+}
+
+// rewrite applies edits to the file name, formats it, and writes it back.
+func rewrite(name string, edits []runner.Edit, formatter string) error {
+	info, err := os.Stat(name)
+	if err != nil {
+		return fmt.Errorf("fixing %s: %w", name, err)
+	}
+
+	src, err := os.ReadFile(name) //nolint:gosec // G304: name is a file of the analyzed packages
+	if err != nil {
+		return fmt.Errorf("fixing %s: %w", name, err)
+	}
+
+	fileEdits := make([]editapply.Edit, len(edits))
+	for i, e := range edits {
+		fileEdits[i] = fileEdit(e)
+	}
+
+	out, err := editapply.Apply(src, fileEdits)
+	if err != nil {
+		return fmt.Errorf("fixing %s: %w", name, err)
+	}
+
+	out, err = formatSource(name, out, formatter)
+	if err != nil {
+		return fmt.Errorf("formatting %s: %w", name, err)
+	}
+
+	if err := os.WriteFile(name, out, info.Mode().Perm()); err != nil {
+		return fmt.Errorf("fixing %s: %w", name, err)
+	}
+
+	return nil
+}
+
+// formatSource formats the source of the file name with formatter.
+func formatSource(name string, src []byte, formatter string) ([]byte, error) {
+	if formatter == config.FormatNone {
+		return src, nil
+	}
+
+	out, err := imports.Process(name, src, &imports.Options{Comments: true, TabIndent: true, TabWidth: 8})
+	if err != nil {
+		return nil, fmt.Errorf("fixing imports: %w", err)
+	}
+
+	if formatter != config.FormatGofumpt {
+		return out, nil
+	}
+
+	path, err := exec.LookPath("gofumpt")
+	if err != nil {
+		return nil, errors.New("gofumpt is not installed (go install mvdan.cc/gofumpt@latest)")
+	}
+
+	var stdout, stderr bytes.Buffer
+
+	cmd := exec.Command(path) //nolint:gosec // G204: gofumpt from PATH, as configured
+	cmd.Stdin = bytes.NewReader(out)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		return nil, fmt.Errorf("running gofumpt: %w: %s", err, bytes.TrimSpace(stderr.Bytes()))
+	}
+
+	return stdout.Bytes(), nil
+}
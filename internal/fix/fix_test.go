@@ -0,0 +1,148 @@
+package fix_test
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/mickamy/pointless/internal/config"
+	"github.com/mickamy/pointless/internal/fix"
+	"github.com/mickamy/pointless/internal/fixsafety"
+	"github.com/mickamy/pointless/internal/runner"
+)
+
+const src = `package p
+
+import "fmt"
+
+type Nop struct{}
+
+func (n *Nop) A() {}
+
+func (n *Nop) B()   {}
+`
+
+// removeStar returns a finding whose fix deletes the first * after marker.
+func removeStar(t *testing.T, name, marker string) runner.Finding {
+	t.Helper()
+
+	i := strings.Index(src, marker)
+	if i < 0 {
+		t.Fatalf("%q not found", marker)
+	}
+
+	i += strings.IndexByte(src[i:], '*')
+
+	return runner.Finding{
+		Rule:  "empty-receiver",
+		Fixes: []runner.Fix{{Safety: fixsafety.Safe, Edits: []runner.Edit{{Filename: name, Offset: i, End: i + 1}}}},
+	}
+}
+
+func writeSource(t *testing.T) string {
+	t.Helper()
+
+	name := filepath.Join(t.TempDir(), "p.go")
+	if err := os.WriteFile(name, []byte(src), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	return name
+}
+
+func TestApply(t *testing.T) {
+	t.Parallel()
+
+	name := writeSource(t)
+	noFix := runner.Finding{Rule: "return-pointer"}
+	a, b := removeStar(t, name, "func (n *Nop) A"), removeStar(t, name, "func (n *Nop) B")
+	conflicting := runner.Finding{
+		Rule:  "empty-receiver",
+		Fixes: []runner.Fix{{Safety: fixsafety.Safe, Edits: []runner.Edit{{Filename: name, Offset: a.Fixes[0].Edits[0].Offset, End: a.Fixes[0].Edits[0].End + 1}}}},
+	}
+
+	result, err := fix.Apply([]runner.Finding{a, b, a, noFix, conflicting}, config.FormatGofmt, fixsafety.Safe)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !reflect.DeepEqual(result.Files, []string{name}) {
+		t.Errorf("files = %v, want [%s]", result.Files, name)
+	}
+
+	if len(result.Unfixed) != 2 || result.Unfixed[0].Rule != "return-pointer" || len(result.Unfixed[1].Fixes) == 0 {
+		t.Errorf("unfixed = %+v, want the finding without a fix and the conflicting one", result.Unfixed)
+	}
+
+	got, err := os.ReadFile(name) //nolint:gosec // G304: test file
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The unused import is removed and the extra spaces are formatted away
+	want := "package p\n\ntype Nop struct{}\n\nfunc (n Nop) A() {}\n\nfunc (n Nop) B() {}\n"
+	if string(got) != want {
+		t.Errorf("got:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestApplyNoFormat(t *testing.T) {
+	t.Parallel()
+
+	name := writeSource(t)
+
+	if _, err := fix.Apply([]runner.Finding{removeStar(t, name, "func (n *Nop) B")}, config.FormatNone, ""); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := os.ReadFile(name) //nolint:gosec // G304: test file
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if want := strings.Replace(src, "(n *Nop) B", "(n Nop) B", 1); string(got) != want {
+		t.Errorf("got:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestApplyUnknownFormatter(t *testing.T) {
+	t.Parallel()
+
+	if _, err := fix.Apply(nil, "prettier", ""); err == nil {
+		t.Error("expected an error for an unknown formatter")
+	}
+}
+
+func TestApplySafetyLimit(t *testing.T) {
+	t.Parallel()
+
+	name := writeSource(t)
+	likely := removeStar(t, name, "func (n *Nop) A")
+	likely.Fixes[0].Safety = fixsafety.Likely
+	unsafe := removeStar(t, name, "func (n *Nop) B")
+	unsafe.Fixes[0].Safety = fixsafety.Unsafe
+
+	result, err := fix.Apply([]runner.Finding{likely, unsafe}, config.FormatNone, fixsafety.Likely)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(result.Unfixed) != 1 || result.Unfixed[0].Fixes[0].Safety != fixsafety.Unsafe {
+		t.Errorf("unfixed = %+v, want the unsafe finding", result.Unfixed)
+	}
+
+	got, err := os.ReadFile(name) //nolint:gosec // G304: test file
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if want := strings.Replace(src, "(n *Nop) A", "(n Nop) A", 1); string(got) != want {
+		t.Errorf("got:\n%s\nwant:\n%s", got, want)
+	}
+
+	if _, err := fix.Apply(nil, config.FormatNone, "reckless"); err == nil {
+		t.Error("expected an error for an unknown safety level")
+	}
+}
@@ -0,0 +1,90 @@
+// Package fixsafety defines how safe a suggested fix is to apply, and how the
+// level and the evidence for it are tagged in the message of the fix.
+package fixsafety
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Levels, from safest to least safe.
+const (
+	// Safe fixes provably preserve the behavior of the program.
+	Safe = "safe"
+	// Likely fixes are believed to preserve it, but this is not proven.
+	Likely = "likely"
+	// Unsafe fixes may change behavior and need review.
+	Unsafe = "unsafe"
+)
+
+// Default is the least safe level -fix applies unless told otherwise.
+const Default = Safe
+
+// Names returns all levels, from safest to least safe.
+func Names() []string {
+	return []string{Safe, Likely, Unsafe}
+}
+
+// Validate returns an error if level is not a level.
+func Validate(level string) error {
+	for _, name := range Names() {
+		if level == name {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("unknown fix safety %q (expected one of %s)", level, strings.Join(Names(), ", "))
+}
+
+// Evidence is the level of a fix and the reasons the check assigned it.
+type Evidence struct {
+	Level   string
+	Reasons []string
+}
+
+// Tag appends the level and reasons of e to msg, as in
+// "Use value receiver [safe: the receiver is unused]".
+func Tag(msg string, e Evidence) string {
+	if len(e.Reasons) == 0 {
+		return msg + " [" + e.Level + "]"
+	}
+
+	return msg + " [" + e.Level + ": " + strings.Join(e.Reasons, "; ") + "]"
+}
+
+// Parse splits a message produced by Tag into the untagged message and the
+// evidence. Fixes without a tag are Unsafe, since nothing vouches for them.
+func Parse(msg string) (string, Evidence) {
+	i := strings.LastIndex(msg, " [")
+	if i < 0 || !strings.HasSuffix(msg, "]") {
+		return msg, Evidence{Level: Unsafe}
+	}
+
+	level, reasons, _ := strings.Cut(msg[i+2:len(msg)-1], ": ")
+	if Validate(level) != nil {
+		return msg, Evidence{Level: Unsafe}
+	}
+
+	e := Evidence{Level: level}
+	if reasons != "" {
+		e.Reasons = strings.Split(reasons, "; ")
+	}
+
+	return msg[:i], e
+}
+
+// Allows reports whether a fix of the given level may be applied when fixes up
+// to limit are.
+func Allows(limit, level string) bool {
+	return rank(level) <= rank(limit)
+}
+
+func rank(level string) int {
+	for i, name := range Names() {
+		if level == name {
+			return i
+		}
+	}
+
+	return len(Names())
+}
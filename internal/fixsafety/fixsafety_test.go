@@ -0,0 +1,58 @@
+package fixsafety_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/mickamy/pointless/internal/fixsafety"
+)
+
+func TestTagParse(t *testing.T) {
+	t.Parallel()
+
+	tests := []fixsafety.Evidence{
+		{Level: fixsafety.Safe, Reasons: []string{"the receiver is unused"}},
+		{Level: fixsafety.Likely, Reasons: []string{"a", "b"}},
+		{Level: fixsafety.Unsafe},
+	}
+
+	for _, e := range tests {
+		msg, got := fixsafety.Parse(fixsafety.Tag("Use value receiver", e))
+		if msg != "Use value receiver" || !reflect.DeepEqual(got, e) {
+			t.Errorf("Parse(Tag(%+v)) = %q, %+v", e, msg, got)
+		}
+	}
+}
+
+func TestParseUntagged(t *testing.T) {
+	t.Parallel()
+
+	for _, msg := range []string{"Use value receiver", "Use [x] here", "Keep [unknown: x]"} {
+		got, e := fixsafety.Parse(msg)
+		if got != msg || e.Level != fixsafety.Unsafe {
+			t.Errorf("Parse(%q) = %q, %+v; want the message unchanged and unsafe", msg, got, e)
+		}
+	}
+}
+
+func TestAllows(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		limit, level string
+		want         bool
+	}{
+		{fixsafety.Safe, fixsafety.Safe, true},
+		{fixsafety.Safe, fixsafety.Likely, false},
+		{fixsafety.Likely, fixsafety.Safe, true},
+		{fixsafety.Likely, fixsafety.Unsafe, false},
+		{fixsafety.Unsafe, fixsafety.Unsafe, true},
+		{fixsafety.Unsafe, "bogus", false},
+	}
+
+	for _, tt := range tests {
+		if got := fixsafety.Allows(tt.limit, tt.level); got != tt.want {
+			t.Errorf("Allows(%q, %q) = %v, want %v", tt.limit, tt.level, got, tt.want)
+		}
+	}
+}
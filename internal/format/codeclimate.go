@@ -0,0 +1,78 @@
+package format
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/mickamy/pointless/internal/runner"
+	"github.com/mickamy/pointless/internal/severity"
+)
+
+// codeClimateIssue is an issue in the Code Climate format, as read by GitLab's
+// Code Quality report.
+type codeClimateIssue struct {
+	Type        string              `json:"type"`
+	CheckName   string              `json:"check_name"`
+	Description string              `json:"description"`
+	Categories  []string            `json:"categories"`
+	Severity    string              `json:"severity"`
+	Fingerprint string              `json:"fingerprint"`
+	Location    codeClimateLocation `json:"location"`
+}
+
+type codeClimateLocation struct {
+	Path  string           `json:"path"`
+	Lines codeClimateLines `json:"lines"`
+}
+
+type codeClimateLines struct {
+	Begin int `json:"begin"`
+	End   int `json:"end"`
+}
+
+// writeCodeClimate writes findings as a JSON array of Code Climate issues. The
+// content-based fingerprints let GitLab tell new findings from moved ones.
+func writeCodeClimate(w io.Writer, findings []runner.Finding, _ Options) error {
+	issues := make([]codeClimateIssue, 0, len(findings))
+
+	for _, f := range findings {
+		path := relPath(f.Position.Filename)
+
+		end := f.Position.Line
+		if f.End.IsValid() && f.End.Line > end {
+			end = f.End.Line
+		}
+
+		issues = append(issues, codeClimateIssue{
+			Type:        "issue",
+			CheckName:   ruleOf(f),
+			Description: f.Message,
+			Categories:  []string{"Performance"},
+			Severity:    codeClimateSeverity(f.Severity),
+			Fingerprint: f.Fingerprint,
+			Location: codeClimateLocation{
+				Path:  path,
+				Lines: codeClimateLines{Begin: f.Position.Line, End: end},
+			},
+		})
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+
+	if err := enc.Encode(issues); err != nil {
+		return fmt.Errorf("writing codeclimate report: %w", err)
+	}
+
+	return nil
+}
+
+// codeClimateSeverity maps a finding severity to a Code Climate severity.
+func codeClimateSeverity(sev string) string {
+	if sev == severity.Info || sev == severity.Hint {
+		return "info"
+	}
+
+	return "minor"
+}
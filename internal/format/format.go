@@ -0,0 +1,111 @@
+// Package format writes pointless findings in the report formats understood by
+// CI systems.
+package format
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/mickamy/pointless/internal/runner"
+)
+
+// Text is the default format, which matches the output of the analysis driver.
+const Text = "text"
+
+// toolName identifies pointless in reports, and stands in for the rule of
+// findings without one (such as merged -group-by-type findings).
+const toolName = "pointless"
+
+// Options configures the formats that support variations.
+type Options struct {
+	// JUnitTestCase selects what a JUnit test case stands for: JUnitPerFile
+	// (default) or JUnitPerRule.
+	JUnitTestCase string
+	// ToolVersion is the version of pointless recorded in the JSON and SARIF
+	// reports, if not empty, so that a report tells which build produced it.
+	ToolVersion string
+	// DocsBaseURL is where the rule documentation linked from the SARIF
+	// report is published, as in the docs-base-url config (see rules.URLAt).
+	DocsBaseURL string
+}
+
+// Formatter writes findings to w.
+type Formatter func(w io.Writer, findings []runner.Finding, opts Options) error
+
+var formatters = map[string]Formatter{
+	Text:          writeText,
+	"codeclimate": writeCodeClimate,
+	"json":        writeJSON,
+	"junit":       writeJUnit,
+	"sarif":       writeSARIF,
+	"teamcity":    writeTeamCity,
+}
+
+// Lookup returns the formatter with the given name.
+func Lookup(name string) (Formatter, bool) {
+	f, ok := formatters[name]
+
+	return f, ok
+}
+
+// Names returns the names of all formats, sorted.
+func Names() []string {
+	names := make([]string, 0, len(formatters))
+	for name := range formatters {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	return names
+}
+
+// Write writes findings to w in the named format.
+func Write(w io.Writer, name string, findings []runner.Finding, opts Options) error {
+	f, ok := Lookup(name)
+	if !ok {
+		return fmt.Errorf("unknown format %q (available: %s)", name, strings.Join(Names(), ", "))
+	}
+
+	return f(w, findings, opts)
+}
+
+// writeText writes one "file:line:col: message" line per finding.
+func writeText(w io.Writer, findings []runner.Finding, _ Options) error {
+	for _, f := range findings {
+		if _, err := fmt.Fprintf(w, "%s: %s\n", f.Position, f.Message); err != nil {
+			return fmt.Errorf("writing finding: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// ruleOf returns the rule of f, or toolName if it has none.
+func ruleOf(f runner.Finding) string {
+	if f.Rule == "" {
+		return toolName
+	}
+
+	return f.Rule
+}
+
+// relPath returns filename relative to the working directory if it is below it,
+// so reports stay stable across checkouts in different locations.
+func relPath(filename string) string {
+	wd, err := os.Getwd()
+	if err != nil {
+		return filepath.ToSlash(filename)
+	}
+
+	rel, err := filepath.Rel(wd, filename)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return filepath.ToSlash(filename)
+	}
+
+	return filepath.ToSlash(rel)
+}
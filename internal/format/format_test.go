@@ -0,0 +1,334 @@
+package format_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"go/token"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/mickamy/pointless/internal/fixsafety"
+	"github.com/mickamy/pointless/internal/format"
+	"github.com/mickamy/pointless/internal/rules"
+	"github.com/mickamy/pointless/internal/runner"
+	"github.com/mickamy/pointless/internal/severity"
+)
+
+func sampleFindings() []runner.Finding {
+	return []runner.Finding{
+		{
+			Position:    token.Position{Filename: "/src/a/a.go", Line: 10, Column: 2},
+			Message:     "consider using []User instead of []*User",
+			Severity:    severity.Warning,
+			Rule:        rules.SlicePointer,
+			Package:     "example.com/a",
+			Fingerprint: "3a1f",
+			Fixes: []runner.Fix{{
+				Message:  "Use value receiver",
+				Safety:   fixsafety.Safe,
+				Evidence: []string{"the receiver is unused"},
+			}},
+		},
+		{
+			Position:    token.Position{Filename: "/src/a/b.go", Line: 3, Column: 1},
+			End:         token.Position{Filename: "/src/a/b.go", Line: 4, Column: 2},
+			Message:     "consider returning value instead of pointer: User's size is 'small' [32 bytes]",
+			Severity:    severity.Hint,
+			Rule:        rules.ReturnPointer,
+			Package:     "example.com/a",
+			Symbol:      "example.com/a.NewUser",
+			Fingerprint: "b07c",
+		},
+		{
+			Position:    token.Position{Filename: "/src/a/b.go", Line: 7, Column: 1},
+			Message:     "consider using []User instead of []*User",
+			Severity:    severity.Warning,
+			Rule:        rules.SlicePointer,
+			Package:     "example.com/a",
+			Fingerprint: "91d2",
+		},
+	}
+}
+
+// writeJSON writes findings in the named JSON-based format and decodes the result into v.
+func writeJSON(t *testing.T, name string, findings []runner.Finding, v any) {
+	t.Helper()
+
+	var buf bytes.Buffer
+	if err := format.Write(&buf, name, findings, format.Options{}); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := json.Unmarshal(buf.Bytes(), v); err != nil {
+		t.Fatalf("invalid JSON: %v\n%s", err, buf.String())
+	}
+}
+
+func TestWriteTeamCity(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+	if err := format.Write(&buf, "teamcity", sampleFindings(), format.Options{}); err != nil {
+		t.Fatal(err)
+	}
+
+	want := strings.Join([]string{
+		"##teamcity[inspectionType id='slice-pointer' name='slice-pointer' description='|[|]*T slices of small structs whose elements are never nil' category='pointless']",
+		"##teamcity[inspectionType id='return-pointer' name='return-pointer' description='functions returning *T for small structs that never return nil' category='pointless']",
+		"##teamcity[inspection typeId='slice-pointer' message='consider using |[|]User instead of |[|]*User' file='/src/a/a.go' line='10' SEVERITY='WARNING']",
+		"##teamcity[inspection typeId='return-pointer' message='consider returning value instead of pointer: User|'s size is |'small|' |[32 bytes|]' file='/src/a/b.go' line='3' SEVERITY='WEAK WARNING']",
+		"##teamcity[inspection typeId='slice-pointer' message='consider using |[|]User instead of |[|]*User' file='/src/a/b.go' line='7' SEVERITY='WARNING']",
+		"",
+	}, "\n")
+
+	if got := buf.String(); got != want {
+		t.Errorf("got:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestWriteJUnitPerFile(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+	if err := format.Write(&buf, "junit", sampleFindings(), format.Options{}); err != nil {
+		t.Fatal(err)
+	}
+
+	want := `<?xml version="1.0" encoding="UTF-8"?>
+<testsuites tests="2" failures="2">
+  <testsuite name="pointless" tests="2" failures="2">
+    <testcase name="/src/a/a.go" classname="pointless">
+      <failure message="1 finding" type="pointless">/src/a/a.go:10:2: consider using []User instead of []*User&#xA;</failure>
+    </testcase>
+    <testcase name="/src/a/b.go" classname="pointless">
+      <failure message="2 findings" type="pointless">/src/a/b.go:3:1: consider returning value instead of pointer: User&#39;s size is &#39;small&#39; [32 bytes]&#xA;/src/a/b.go:7:1: consider using []User instead of []*User&#xA;</failure>
+    </testcase>
+  </testsuite>
+</testsuites>
+`
+
+	if got := buf.String(); got != want {
+		t.Errorf("got:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestWriteJUnitPerRule(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+	if err := format.Write(&buf, "junit", sampleFindings(), format.Options{JUnitTestCase: format.JUnitPerRule}); err != nil {
+		t.Fatal(err)
+	}
+
+	got := buf.String()
+
+	var defaults int
+
+	for _, r := range rules.All() {
+		if !r.OptIn {
+			defaults++
+		}
+	}
+
+	if want := fmt.Sprintf(`<testsuites tests="%d" failures="2">`, defaults); !strings.Contains(got, want) {
+		t.Errorf("missing %s in:\n%s", want, got)
+	}
+
+	for _, want := range []string{
+		`<testcase name="value-receiver" classname="pointless"></testcase>`,
+		`<failure message="2 findings" type="pointless">/src/a/a.go:10:2: consider using`,
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("missing %s in:\n%s", want, got)
+		}
+	}
+
+	if strings.Contains(got, rules.ContextValue) {
+		t.Errorf("opt-in rule without findings listed:\n%s", got)
+	}
+}
+
+func TestWriteJUnitUnknownGrouping(t *testing.T) {
+	t.Parallel()
+
+	if err := format.Write(&bytes.Buffer{}, "junit", nil, format.Options{JUnitTestCase: "package"}); err == nil {
+		t.Error("expected an error for an unknown grouping")
+	}
+}
+
+func TestWriteCodeClimate(t *testing.T) {
+	t.Parallel()
+
+	var issues []map[string]any
+	writeJSON(t, "codeclimate", sampleFindings(), &issues)
+
+	if len(issues) != 3 {
+		t.Fatalf("got %d issues, want 3", len(issues))
+	}
+
+	if got := issues[0]["check_name"]; got != rules.SlicePointer {
+		t.Errorf("check_name = %v, want %s", got, rules.SlicePointer)
+	}
+
+	if got := issues[1]["fingerprint"]; got != "b07c" {
+		t.Errorf("fingerprint = %v, want b07c", got)
+	}
+
+	if got := issues[1]["severity"]; got != "info" {
+		t.Errorf("severity of a hint = %v, want info", got)
+	}
+
+	if got := issues[1]["location"]; !reflect.DeepEqual(got, map[string]any{
+		"path":  "/src/a/b.go",
+		"lines": map[string]any{"begin": float64(3), "end": float64(4)},
+	}) {
+		t.Errorf("location = %v", got)
+	}
+}
+
+func TestWriteJSON(t *testing.T) {
+	t.Parallel()
+
+	var got []map[string]any
+	writeJSON(t, "json", sampleFindings(), &got)
+
+	want := map[string]any{
+		"rule":        rules.ReturnPointer,
+		"message":     "consider returning value instead of pointer: User's size is 'small' [32 bytes]",
+		"severity":    severity.Hint,
+		"file":        "/src/a/b.go",
+		"line":        float64(3),
+		"column":      float64(1),
+		"package":     "example.com/a",
+		"symbol":      "example.com/a.NewUser",
+		"fingerprint": "b07c",
+	}
+
+	if len(got) != 3 || !reflect.DeepEqual(got[1], want) {
+		t.Errorf("got %v, want %v as the second finding", got, want)
+	}
+
+	wantFixes := []any{map[string]any{
+		"message":  "Use value receiver",
+		"safety":   fixsafety.Safe,
+		"evidence": []any{"the receiver is unused"},
+	}}
+	if len(got) > 0 && !reflect.DeepEqual(got[0]["fixes"], wantFixes) {
+		t.Errorf("fixes = %v, want %v", got[0]["fixes"], wantFixes)
+	}
+}
+
+func TestWriteSARIF(t *testing.T) {
+	t.Parallel()
+
+	var log struct {
+		Version string `json:"version"`
+		Runs    []struct {
+			Tool struct {
+				Driver struct {
+					Rules []struct {
+						ID string `json:"id"`
+					} `json:"rules"`
+				} `json:"driver"`
+			} `json:"tool"`
+			Results []struct {
+				RuleID           string `json:"ruleId"`
+				Level            string `json:"level"`
+				RelatedLocations []struct {
+					Message struct {
+						Text string `json:"text"`
+					} `json:"message"`
+				} `json:"relatedLocations"`
+				PartialFingerprints map[string]string `json:"partialFingerprints"`
+			} `json:"results"`
+		} `json:"runs"`
+	}
+	findings := sampleFindings()
+	findings[1].Related = []runner.Related{{
+		Position: token.Position{Filename: "/src/a/user.go", Line: 5, Column: 6},
+		Message:  "User is defined here: 32 bytes = ID 8 + Name 16 + Age 8",
+	}}
+
+	writeJSON(t, "sarif", findings, &log)
+
+	if log.Version != "2.1.0" || len(log.Runs) != 1 {
+		t.Fatalf("unexpected log: %+v", log)
+	}
+
+	run := log.Runs[0]
+	if len(run.Tool.Driver.Rules) != len(rules.All()) {
+		t.Errorf("got %d rules, want %d", len(run.Tool.Driver.Rules), len(rules.All()))
+	}
+
+	if len(run.Results) != 3 || run.Results[1].RuleID != rules.ReturnPointer || run.Results[1].Level != "note" || run.Results[1].PartialFingerprints["pointless/v1"] != "b07c" {
+		t.Errorf("unexpected results: %+v", run.Results)
+	}
+
+	if related := run.Results[1].RelatedLocations; len(related) != 1 || !strings.HasPrefix(related[0].Message.Text, "User is defined here") {
+		t.Errorf("relatedLocations = %+v, want the definition of User", related)
+	}
+
+	if related := run.Results[0].RelatedLocations; len(related) != 0 {
+		t.Errorf("relatedLocations = %+v, want none", related)
+	}
+}
+
+func TestWriteToolVersion(t *testing.T) {
+	t.Parallel()
+
+	opts := format.Options{ToolVersion: "v1.2.3"}
+
+	var buf bytes.Buffer
+	if err := format.Write(&buf, "json", sampleFindings(), opts); err != nil {
+		t.Fatal(err)
+	}
+
+	var findings []struct {
+		ToolVersion string `json:"tool_version"`
+	}
+	if err := json.Unmarshal(buf.Bytes(), &findings); err != nil || len(findings) == 0 || findings[0].ToolVersion != "v1.2.3" {
+		t.Errorf("tool_version missing from JSON (err %v): %s", err, buf.String())
+	}
+
+	buf.Reset()
+
+	if err := format.Write(&buf, "sarif", sampleFindings(), opts); err != nil {
+		t.Fatal(err)
+	}
+
+	var log struct {
+		Runs []struct {
+			Tool struct {
+				Driver struct {
+					Version string `json:"version"`
+				} `json:"driver"`
+			} `json:"tool"`
+		} `json:"runs"`
+	}
+	if err := json.Unmarshal(buf.Bytes(), &log); err != nil || len(log.Runs) != 1 || log.Runs[0].Tool.Driver.Version != "v1.2.3" {
+		t.Errorf("driver version missing from SARIF (err %v): %s", err, buf.String())
+	}
+}
+
+func TestWriteText(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+	if err := format.Write(&buf, format.Text, sampleFindings()[:1], format.Options{}); err != nil {
+		t.Fatal(err)
+	}
+
+	if got, want := buf.String(), "/src/a/a.go:10:2: consider using []User instead of []*User\n"; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
+
+func TestWriteUnknown(t *testing.T) {
+	t.Parallel()
+
+	if err := format.Write(&bytes.Buffer{}, "xml", nil, format.Options{}); err == nil {
+		t.Error("expected an error for an unknown format")
+	}
+}
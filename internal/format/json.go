@@ -0,0 +1,83 @@
+package format
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/mickamy/pointless/internal/runner"
+)
+
+type jsonFinding struct {
+	Rule        string        `json:"rule"`
+	Message     string        `json:"message"`
+	Severity    string        `json:"severity"`
+	File        string        `json:"file"`
+	Line        int           `json:"line"`
+	Column      int           `json:"column"`
+	Package     string        `json:"package"`
+	Symbol      string        `json:"symbol,omitempty"`
+	URL         string        `json:"url,omitempty"`
+	Fingerprint string        `json:"fingerprint"`
+	Related     []jsonRelated `json:"related,omitempty"`
+	Fixes       []jsonFix     `json:"fixes,omitempty"`
+	ToolVersion string        `json:"tool_version,omitempty"`
+}
+
+type jsonFix struct {
+	Message  string   `json:"message"`
+	Safety   string   `json:"safety"`
+	Evidence []string `json:"evidence,omitempty"`
+}
+
+type jsonRelated struct {
+	Message string `json:"message"`
+	File    string `json:"file"`
+	Line    int    `json:"line"`
+	Column  int    `json:"column"`
+}
+
+// writeJSON writes findings as a JSON array.
+func writeJSON(w io.Writer, findings []runner.Finding, opts Options) error {
+	out := make([]jsonFinding, 0, len(findings))
+
+	for _, f := range findings {
+		jf := jsonFinding{
+			Rule:        f.Rule,
+			Message:     f.Message,
+			Severity:    f.Severity,
+			File:        relPath(f.Position.Filename),
+			Line:        f.Position.Line,
+			Column:      f.Position.Column,
+			Package:     f.Package,
+			Symbol:      f.Symbol,
+			URL:         f.Diagnostic.URL,
+			Fingerprint: f.Fingerprint,
+			ToolVersion: opts.ToolVersion,
+		}
+
+		for _, r := range f.Related {
+			jf.Related = append(jf.Related, jsonRelated{
+				Message: r.Message,
+				File:    relPath(r.Position.Filename),
+				Line:    r.Position.Line,
+				Column:  r.Position.Column,
+			})
+		}
+
+		for _, fix := range f.Fixes {
+			jf.Fixes = append(jf.Fixes, jsonFix{Message: fix.Message, Safety: fix.Safety, Evidence: fix.Evidence})
+		}
+
+		out = append(out, jf)
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+
+	if err := enc.Encode(out); err != nil {
+		return fmt.Errorf("writing json report: %w", err)
+	}
+
+	return nil
+}
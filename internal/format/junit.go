@@ -0,0 +1,157 @@
+package format
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/mickamy/pointless/internal/rules"
+	"github.com/mickamy/pointless/internal/runner"
+)
+
+// JUnit test case groupings.
+const (
+	JUnitPerFile = "file"
+	JUnitPerRule = "rule"
+)
+
+type junitTestSuites struct {
+	XMLName  xml.Name         `xml:"testsuites"`
+	Tests    int              `xml:"tests,attr"`
+	Failures int              `xml:"failures,attr"`
+	Suites   []junitTestSuite `xml:"testsuite"`
+}
+
+type junitTestSuite struct {
+	Name     string          `xml:"name,attr"`
+	Tests    int             `xml:"tests,attr"`
+	Failures int             `xml:"failures,attr"`
+	Cases    []junitTestCase `xml:"testcase"`
+}
+
+type junitTestCase struct {
+	Name      string        `xml:"name,attr"`
+	ClassName string        `xml:"classname,attr"`
+	Failure   *junitFailure `xml:"failure,omitempty"`
+}
+
+type junitFailure struct {
+	Message string `xml:"message,attr"`
+	Type    string `xml:"type,attr"`
+	Text    string `xml:",chardata"`
+}
+
+// writeJUnit writes findings as a JUnit XML report with one failing test case
+// per file or per rule. In the per-rule grouping, rules without findings are
+// passing test cases, so the report has meaningful pass/fail counts.
+func writeJUnit(w io.Writer, findings []runner.Finding, opts Options) error {
+	var cases []junitTestCase
+
+	switch opts.JUnitTestCase {
+	case "", JUnitPerFile:
+		cases = junitCases(findings, func(f runner.Finding) string { return relPath(f.Position.Filename) })
+	case JUnitPerRule:
+		cases = junitRuleCases(findings)
+	default:
+		return fmt.Errorf("unknown junit test case grouping %q (expected %s or %s)", opts.JUnitTestCase, JUnitPerFile, JUnitPerRule)
+	}
+
+	suite := junitTestSuite{Name: toolName, Tests: len(cases), Cases: cases}
+	for _, c := range cases {
+		if c.Failure != nil {
+			suite.Failures++
+		}
+	}
+
+	report := junitTestSuites{Tests: suite.Tests, Failures: suite.Failures, Suites: []junitTestSuite{suite}}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return fmt.Errorf("writing junit report: %w", err)
+	}
+
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+
+	if err := enc.Encode(report); err != nil {
+		return fmt.Errorf("writing junit report: %w", err)
+	}
+
+	if _, err := io.WriteString(w, "\n"); err != nil {
+		return fmt.Errorf("writing junit report: %w", err)
+	}
+
+	return nil
+}
+
+// junitCases returns one failing test case per key, in order of first finding.
+func junitCases(findings []runner.Finding, key func(runner.Finding) string) []junitTestCase {
+	var (
+		order  []string
+		byKey  = make(map[string][]runner.Finding)
+		result []junitTestCase
+	)
+
+	for _, f := range findings {
+		k := key(f)
+		if _, ok := byKey[k]; !ok {
+			order = append(order, k)
+		}
+
+		byKey[k] = append(byKey[k], f)
+	}
+
+	for _, k := range order {
+		result = append(result, junitTestCase{Name: k, ClassName: toolName, Failure: junitFailureOf(byKey[k])})
+	}
+
+	return result
+}
+
+// junitRuleCases returns one test case per rule: failing for rules with
+// findings, passing for the other default rules.
+func junitRuleCases(findings []runner.Finding) []junitTestCase {
+	byRule := make(map[string][]runner.Finding)
+	for _, f := range findings {
+		byRule[ruleOf(f)] = append(byRule[ruleOf(f)], f)
+	}
+
+	var cases []junitTestCase
+
+	for _, r := range rules.All() {
+		fs, ok := byRule[r.ID]
+		// Skip opt-in rules without findings; they most likely did not run
+		if r.OptIn && !ok {
+			continue
+		}
+
+		c := junitTestCase{Name: r.ID, ClassName: toolName}
+		if ok {
+			c.Failure = junitFailureOf(fs)
+		}
+
+		cases = append(cases, c)
+		delete(byRule, r.ID)
+	}
+
+	// Findings without a known rule, such as merged -group-by-type findings
+	if fs, ok := byRule[toolName]; ok {
+		cases = append(cases, junitTestCase{Name: toolName, ClassName: toolName, Failure: junitFailureOf(fs)})
+	}
+
+	return cases
+}
+
+func junitFailureOf(findings []runner.Finding) *junitFailure {
+	var text strings.Builder
+	for _, f := range findings {
+		fmt.Fprintf(&text, "%s:%d:%d: %s\n", relPath(f.Position.Filename), f.Position.Line, f.Position.Column, f.Message)
+	}
+
+	message := "1 finding"
+	if len(findings) != 1 {
+		message = fmt.Sprintf("%d findings", len(findings))
+	}
+
+	return &junitFailure{Message: message, Type: toolName, Text: text.String()}
+}
@@ -0,0 +1,148 @@
+package format
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/mickamy/pointless/internal/rules"
+	"github.com/mickamy/pointless/internal/runner"
+	"github.com/mickamy/pointless/internal/severity"
+)
+
+// sarifFingerprintKey names the fingerprint algorithm in partialFingerprints;
+// bump the version if the algorithm changes.
+const sarifFingerprintKey = "pointless/v1"
+
+type sarifLog struct {
+	Schema  string     `json:"$schema"`
+	Version string     `json:"version"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool    sarifTool     `json:"tool"`
+	Results []sarifResult `json:"results"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name           string      `json:"name"`
+	Version        string      `json:"version,omitempty"`
+	InformationURI string      `json:"informationUri"`
+	Rules          []sarifRule `json:"rules"`
+}
+
+type sarifRule struct {
+	ID               string       `json:"id"`
+	ShortDescription sarifMessage `json:"shortDescription"`
+	HelpURI          string       `json:"helpUri,omitempty"`
+}
+
+type sarifResult struct {
+	RuleID              string            `json:"ruleId"`
+	Level               string            `json:"level"`
+	Message             sarifMessage      `json:"message"`
+	Locations           []sarifLocation   `json:"locations"`
+	RelatedLocations    []sarifLocation   `json:"relatedLocations,omitempty"`
+	PartialFingerprints map[string]string `json:"partialFingerprints"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifLocation struct {
+	PhysicalLocation sarifPhysicalLocation `json:"physicalLocation"`
+	Message          *sarifMessage         `json:"message,omitempty"`
+}
+
+type sarifPhysicalLocation struct {
+	ArtifactLocation sarifArtifactLocation `json:"artifactLocation"`
+	Region           sarifRegion           `json:"region"`
+}
+
+type sarifArtifactLocation struct {
+	URI string `json:"uri"`
+}
+
+type sarifRegion struct {
+	StartLine   int `json:"startLine"`
+	StartColumn int `json:"startColumn,omitempty"`
+	EndLine     int `json:"endLine,omitempty"`
+	EndColumn   int `json:"endColumn,omitempty"`
+}
+
+// writeSARIF writes findings as a SARIF 2.1.0 log, with the content-based
+// fingerprints as partialFingerprints and the related information as
+// relatedLocations.
+func writeSARIF(w io.Writer, findings []runner.Finding, opts Options) error {
+	driver := sarifDriver{Name: toolName, Version: opts.ToolVersion, InformationURI: "https://github.com/mickamy/pointless"}
+	for _, r := range rules.All() {
+		driver.Rules = append(driver.Rules, sarifRule{
+			ID:               r.ID,
+			ShortDescription: sarifMessage{Text: r.Summary},
+			HelpURI:          rules.URLAt(opts.DocsBaseURL, r.ID),
+		})
+	}
+
+	results := make([]sarifResult, 0, len(findings))
+
+	for _, f := range findings {
+		region := sarifRegion{StartLine: f.Position.Line, StartColumn: f.Position.Column}
+		if f.End.IsValid() {
+			region.EndLine, region.EndColumn = f.End.Line, f.End.Column
+		}
+
+		var related []sarifLocation
+
+		for _, r := range f.Related {
+			related = append(related, sarifLocation{
+				PhysicalLocation: sarifPhysicalLocation{
+					ArtifactLocation: sarifArtifactLocation{URI: relPath(r.Position.Filename)},
+					Region:           sarifRegion{StartLine: r.Position.Line, StartColumn: r.Position.Column},
+				},
+				Message: &sarifMessage{Text: r.Message},
+			})
+		}
+
+		results = append(results, sarifResult{
+			RuleID:  ruleOf(f),
+			Level:   sarifLevel(f.Severity),
+			Message: sarifMessage{Text: f.Message},
+			Locations: []sarifLocation{{PhysicalLocation: sarifPhysicalLocation{
+				ArtifactLocation: sarifArtifactLocation{URI: relPath(f.Position.Filename)},
+				Region:           region,
+			}}},
+			RelatedLocations:    related,
+			PartialFingerprints: map[string]string{sarifFingerprintKey: f.Fingerprint},
+		})
+	}
+
+	log := sarifLog{
+		Schema:  "https://json.schemastore.org/sarif-2.1.0.json",
+		Version: "2.1.0",
+		Runs:    []sarifRun{{Tool: sarifTool{Driver: driver}, Results: results}},
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+
+	if err := enc.Encode(log); err != nil {
+		return fmt.Errorf("writing sarif report: %w", err)
+	}
+
+	return nil
+}
+
+// sarifLevel maps a finding severity to a SARIF result level.
+func sarifLevel(sev string) string {
+	if sev == severity.Info || sev == severity.Hint {
+		return "note"
+	}
+
+	return "warning"
+}
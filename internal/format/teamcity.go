@@ -0,0 +1,98 @@
+package format
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/mickamy/pointless/internal/rules"
+	"github.com/mickamy/pointless/internal/runner"
+	"github.com/mickamy/pointless/internal/severity"
+)
+
+// writeTeamCity writes findings as TeamCity service messages: one inspectionType
+// per rule that has findings, followed by one inspection per finding.
+func writeTeamCity(w io.Writer, findings []runner.Finding, _ Options) error {
+	bw := bufio.NewWriter(w)
+	declared := make(map[string]bool)
+
+	for _, f := range findings {
+		id := ruleOf(f)
+		if declared[id] {
+			continue
+		}
+
+		declared[id] = true
+		description := "pointless findings"
+
+		if r, ok := rules.Lookup(id); ok {
+			description = r.Summary
+		}
+
+		writeServiceMessage(bw, "inspectionType",
+			"id", id,
+			"name", id,
+			"description", description,
+			"category", toolName,
+		)
+	}
+
+	for _, f := range findings {
+		writeServiceMessage(bw, "inspection",
+			"typeId", ruleOf(f),
+			"message", f.Message,
+			"file", relPath(f.Position.Filename),
+			"line", strconv.Itoa(f.Position.Line),
+			"SEVERITY", teamCitySeverity(f.Severity),
+		)
+	}
+
+	if err := bw.Flush(); err != nil {
+		return fmt.Errorf("writing teamcity messages: %w", err)
+	}
+
+	return nil
+}
+
+// teamCitySeverity maps a finding severity to a TeamCity inspection severity.
+func teamCitySeverity(sev string) string {
+	switch sev {
+	case severity.Info:
+		return "INFO"
+	case severity.Hint:
+		return "WEAK WARNING"
+	default:
+		return "WARNING"
+	}
+}
+
+// writeServiceMessage writes "##teamcity[name k1='v1' k2='v2' ...]".
+func writeServiceMessage(w *bufio.Writer, name string, attrs ...string) {
+	_, _ = w.WriteString("##teamcity[" + name)
+
+	for i := 0; i+1 < len(attrs); i += 2 {
+		_, _ = w.WriteString(" " + attrs[i] + "='" + teamCityEscape(attrs[i+1]) + "'")
+	}
+
+	_, _ = w.WriteString("]\n")
+}
+
+// teamCityEscaper escapes the characters with special meaning in service
+// message values.
+var teamCityEscaper = strings.NewReplacer(
+	"|", "||",
+	"'", "|'",
+	"\n", "|n",
+	"\r", "|r",
+	"[", "|[",
+	"]", "|]",
+	"\u0085", "|x",
+	"\u2028", "|l",
+	"\u2029", "|p",
+)
+
+func teamCityEscape(s string) string {
+	return teamCityEscaper.Replace(s)
+}
@@ -0,0 +1,132 @@
+package heapprof_test
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"runtime"
+	"runtime/pprof"
+	"strings"
+	"testing"
+
+	"github.com/mickamy/pointless/internal/analyzer"
+	"github.com/mickamy/pointless/internal/heapprof"
+	"github.com/mickamy/pointless/internal/runner"
+)
+
+var sink []*[64]byte
+
+//go:noinline
+func allocate() {
+	for range 1000 {
+		sink = append(sink, new([64]byte))
+	}
+}
+
+//nolint:paralleltest // sets runtime.MemProfileRate
+func TestRead(t *testing.T) {
+	rate := runtime.MemProfileRate
+	runtime.MemProfileRate = 1
+
+	t.Cleanup(func() { runtime.MemProfileRate = rate })
+
+	allocate()
+	runtime.GC()
+
+	var buf bytes.Buffer
+	if err := pprof.Lookup("allocs").WriteTo(&buf, 0); err != nil {
+		t.Fatal(err)
+	}
+
+	p, err := heapprof.Read(&buf)
+	if err != nil {
+		t.Fatalf("Read() error = %v", err)
+	}
+
+	if p.SampleType != "alloc_space" {
+		t.Errorf("Read() sample type = %q, want alloc_space", p.SampleType)
+	}
+
+	var allocated int64
+
+	for _, s := range p.Samples {
+		if len(s.Frames) > 0 && strings.HasSuffix(s.Frames[0].Function, ".allocate") {
+			allocated += s.Bytes
+
+			if filepath.Base(s.Frames[0].File) != "heapprof_test.go" || s.Frames[0].Line == 0 {
+				t.Errorf("Read() frame = %+v, want a line of heapprof_test.go", s.Frames[0])
+			}
+		}
+	}
+
+	if allocated < 64*1000 {
+		t.Errorf("Read() = %d bytes allocated by allocate, want at least %d", allocated, 64*1000)
+	}
+}
+
+func TestReadInvalid(t *testing.T) {
+	t.Parallel()
+
+	if _, err := heapprof.Read(strings.NewReader("not a profile")); err == nil {
+		t.Error("Read() of text succeeded, want an error")
+	}
+}
+
+func TestRank(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	files := map[string]string{
+		"go.mod": "module example.com/m\n\ngo 1.24\n",
+		"m.go": `package m
+
+type User struct{ ID int }
+
+type Item struct{ N int }
+
+func NewItem() *Item { return &Item{} }
+
+func NewUser() *User { return &User{} }
+`,
+	}
+
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	pkgs, err := runner.LoadDir(dir, "./...")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	findings, err := runner.Check(analyzer.Analyzer, pkgs)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Profiles built elsewhere record other absolute paths
+	p := &heapprof.Profile{Samples: []heapprof.Sample{
+		{Frames: []heapprof.Frame{{Function: "example.com/m.NewUser", File: "/build/src/m/m.go", Line: 9}}, Bytes: 3072},
+		{Frames: []heapprof.Frame{{Function: "strings.Repeat", File: "/go/src/strings/strings.go", Line: 600}}, Bytes: 1024},
+	}}
+
+	allocs := heapprof.Attribute(p, pkgs)
+	if allocs.Total != 4096 {
+		t.Errorf("Attribute() total = %d, want 4096", allocs.Total)
+	}
+
+	ranked := heapprof.Rank(findings, pkgs, allocs)
+	if len(ranked) != 2 {
+		t.Fatalf("Rank() = %d findings, want 2", len(ranked))
+	}
+
+	if want := "User is 8 bytes (threshold: 1024 bytes); its zero value User{} is ready to use [heap profile: 3.0 KiB of 4.0 KiB allocated for User, 75.0%]"; !strings.HasSuffix(ranked[0].Message, want) {
+		t.Errorf("Rank()[0] = %q, want the NewUser finding ending with %q", ranked[0].Message, want)
+	}
+
+	if strings.Contains(ranked[1].Message, "heap profile") {
+		t.Errorf("Rank()[1] = %q, want no allocations", ranked[1].Message)
+	}
+}
@@ -0,0 +1,270 @@
+package heapprof
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"go/types"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"golang.org/x/tools/go/packages"
+
+	"github.com/mickamy/pointless/internal/runner"
+)
+
+// Allocations are the bytes of a profile allocated for the named types of the
+// analyzed packages.
+type Allocations struct {
+	// Types maps the type names to the bytes allocated for their values.
+	Types map[*types.TypeName]int64
+	// Total is the number of bytes allocated in the profile.
+	Total int64
+}
+
+// site is a line of the source, which may allocate with &T{...}, T{...},
+// new(T), or make. Files are identified by the import path of their package
+// and their base name, which profiles record as the function names and file
+// paths of the machine that built the binary.
+type site struct {
+	pkg, file string
+	line      int
+}
+
+// Attribute maps the allocations of p to the types of pkgs. The bytes of a
+// sample go to the types allocated on the line of its first frame in a
+// package of pkgs, split evenly between them. Samples allocating in other
+// code only, such as the standard library, or on lines allocating no named
+// type, count toward the total only.
+func Attribute(p *Profile, pkgs []*packages.Package) Allocations {
+	paths, sites := allocationSites(pkgs)
+	result := Allocations{Types: make(map[*types.TypeName]int64)}
+
+	for _, s := range p.Samples {
+		result.Total += s.Bytes
+
+		for _, f := range s.Frames {
+			pkg := packagePath(f.Function, paths)
+			if pkg == "" {
+				continue
+			}
+
+			names := sites[site{pkg: pkg, file: path.Base(filepath.ToSlash(f.File)), line: f.Line}]
+			for _, tn := range names {
+				result.Types[tn] += s.Bytes / int64(len(names))
+			}
+
+			break
+		}
+	}
+
+	return result
+}
+
+// allocationSites returns the import paths of pkgs, keyed by the path that
+// qualifies their functions in profiles, and the lines of their files
+// allocating values of named types. Functions of main packages are qualified
+// with main.
+func allocationSites(pkgs []*packages.Package) (map[string]string, map[site][]*types.TypeName) {
+	paths := make(map[string]string)
+	result := make(map[site][]*types.TypeName)
+
+	for _, pkg := range pkgs {
+		if pkg.TypesInfo == nil {
+			continue
+		}
+
+		if pkg.Name == "main" {
+			paths["main"] = pkg.PkgPath
+		} else {
+			paths[pkg.PkgPath] = pkg.PkgPath
+		}
+
+		for _, f := range pkg.Syntax {
+			ast.Inspect(f, func(n ast.Node) bool {
+				var t types.Type
+
+				switch n := n.(type) {
+				case *ast.CompositeLit:
+					t = pkg.TypesInfo.TypeOf(n)
+				case *ast.CallExpr:
+					ident, ok := ast.Unparen(n.Fun).(*ast.Ident)
+					if !ok || len(n.Args) == 0 {
+						return true
+					}
+
+					if b, ok := pkg.TypesInfo.Uses[ident].(*types.Builtin); !ok || b.Name() != "new" && b.Name() != "make" {
+						return true
+					}
+
+					t = pkg.TypesInfo.TypeOf(n.Args[0])
+				default:
+					return true
+				}
+
+				tn := typeName(t)
+				if tn == nil {
+					return true
+				}
+
+				pos := pkg.Fset.Position(n.Pos())
+				key := site{pkg: pkg.PkgPath, file: filepath.Base(pos.Filename), line: pos.Line}
+
+				for _, seen := range result[key] {
+					if seen == tn {
+						return true
+					}
+				}
+
+				result[key] = append(result[key], tn)
+
+				return true
+			})
+		}
+	}
+
+	return paths, result
+}
+
+// typeName returns the named type t is, or holds the elements of as a
+// pointer, slice, array, map, or channel.
+func typeName(t types.Type) *types.TypeName {
+	for t != nil {
+		switch u := types.Unalias(t).(type) {
+		case *types.Named:
+			return u.Origin().Obj()
+		case *types.Pointer:
+			t = u.Elem()
+		case *types.Slice:
+			t = u.Elem()
+		case *types.Array:
+			t = u.Elem()
+		case *types.Map:
+			t = u.Elem()
+		case *types.Chan:
+			t = u.Elem()
+		default:
+			return nil
+		}
+	}
+
+	return nil
+}
+
+// packagePath returns the import path of the package of the function named
+// fn in a profile, as in example.com/m.(*T).M, if it is in paths. The last
+// element of the path may contain dots (gopkg.in/yaml.v3), so every prefix
+// ending before a dot of the last element is tried, the longest first.
+func packagePath(fn string, paths map[string]string) string {
+	slash := strings.LastIndex(fn, "/") + 1
+
+	for i := len(fn); i > slash; i-- {
+		if fn[i-1] != '.' {
+			continue
+		}
+
+		if p, ok := paths[fn[:i-1]]; ok {
+			return p
+		}
+	}
+
+	return ""
+}
+
+// Rank orders findings by the bytes allocated for their types, the largest
+// first, keeping the order of the others, and appends the allocations to the
+// messages of the findings with some. The type of a finding is the named type
+// of the expression it covers (usually the *T expression) in pkgs.
+func Rank(findings []runner.Finding, pkgs []*packages.Package, allocs Allocations) []runner.Finding {
+	if allocs.Total <= 0 {
+		return findings
+	}
+
+	exprs := findingTypes(pkgs)
+	bytes := make([]int64, len(findings))
+
+	for i := range findings {
+		f := &findings[i]
+
+		tn := exprs[span{start: f.Position, end: f.End}]
+		if tn == nil || allocs.Types[tn] == 0 {
+			continue
+		}
+
+		bytes[i] = allocs.Types[tn]
+		f.Message += fmt.Sprintf(" [heap profile: %s of %s allocated for %s, %.1f%%]",
+			formatBytes(bytes[i]), formatBytes(allocs.Total), tn.Name(), 100*float64(bytes[i])/float64(allocs.Total))
+	}
+
+	order := make([]int, len(findings))
+	for i := range order {
+		order[i] = i
+	}
+
+	sort.SliceStable(order, func(i, j int) bool {
+		return bytes[order[i]] > bytes[order[j]]
+	})
+
+	result := make([]runner.Finding, len(findings))
+	for i, j := range order {
+		result[i] = findings[j]
+	}
+
+	return result
+}
+
+// span is the source range of an expression.
+type span struct {
+	start, end token.Position
+}
+
+// findingTypes maps the source ranges of the type and value expressions of
+// pkgs to the named types they are about (see typeName).
+func findingTypes(pkgs []*packages.Package) map[span]*types.TypeName {
+	result := make(map[span]*types.TypeName)
+
+	for _, pkg := range pkgs {
+		if pkg.TypesInfo == nil {
+			continue
+		}
+
+		for _, f := range pkg.Syntax {
+			ast.Inspect(f, func(n ast.Node) bool {
+				e, ok := n.(ast.Expr)
+				if !ok {
+					return true
+				}
+
+				if tn := typeName(pkg.TypesInfo.TypeOf(e)); tn != nil {
+					key := span{start: pkg.Fset.Position(e.Pos()), end: pkg.Fset.Position(e.End())}
+					if _, ok := result[key]; !ok {
+						result[key] = tn
+					}
+				}
+
+				return true
+			})
+		}
+	}
+
+	return result
+}
+
+// formatBytes formats n bytes with a binary unit, as in 1.5 MiB.
+func formatBytes(n int64) string {
+	const unit = 1024
+
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+
+	div, exp := int64(unit), 0
+	for m := n / unit; m >= unit; m /= unit {
+		div *= unit
+		exp++
+	}
+
+	return fmt.Sprintf("%.1f %ciB", float64(n)/float64(div), "KMGTPE"[exp])
+}
@@ -0,0 +1,386 @@
+// Package heapprof reads heap profiles written by runtime/pprof and ranks
+// findings by the allocations of their types, so that the suggestions backed
+// by runtime evidence come first (see -pprof).
+package heapprof
+
+import (
+	"bufio"
+	"compress/gzip"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+)
+
+// Profile is the part of a pprof profile used to rank findings: the
+// allocation stacks with the bytes allocated on them.
+type Profile struct {
+	// Samples are the allocation stacks of the profile.
+	Samples []Sample
+	// SampleType is the type of the values of Samples, as in alloc_space.
+	SampleType string
+}
+
+// Sample is an allocation stack.
+type Sample struct {
+	// Frames are the frames of the stack, the allocating one first. Inlined
+	// calls have frames of their own.
+	Frames []Frame
+	// Bytes is the value of the sample for the selected sample type.
+	Bytes int64
+}
+
+// Frame is a source location of a stack.
+type Frame struct {
+	Function string
+	File     string
+	Line     int
+}
+
+// sampleTypes are the sample types of heap profiles holding bytes, in order
+// of preference: allocations over the whole run rather than live memory.
+var sampleTypes = []string{"alloc_space", "inuse_space"}
+
+// ReadFile reads the profile at path, gzip-compressed (as runtime/pprof
+// writes them) or not.
+func ReadFile(path string) (*Profile, error) {
+	f, err := os.Open(path) //nolint:gosec // G304: the profile is named by the user
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	p, err := Read(f)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+
+	return p, nil
+}
+
+// Read reads a profile in the protocol buffer format of pprof, gzip-compressed
+// or not.
+func Read(r io.Reader) (*Profile, error) {
+	br := bufio.NewReader(r)
+
+	if magic, err := br.Peek(2); err == nil && magic[0] == 0x1f && magic[1] == 0x8b {
+		zr, err := gzip.NewReader(br)
+		if err != nil {
+			return nil, fmt.Errorf("reading profile: %w", err)
+		}
+
+		r = zr
+	} else {
+		r = br
+	}
+
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("reading profile: %w", err)
+	}
+
+	raw, err := decodeProfile(data)
+	if err != nil {
+		return nil, fmt.Errorf("decoding profile: %w", err)
+	}
+
+	return raw.resolve()
+}
+
+// rawProfile is a decoded profile, whose strings, functions, and locations
+// are still referenced by index or ID.
+type rawProfile struct {
+	sampleTypes []int64
+	samples     []rawSample
+	locations   map[uint64][]rawLine
+	functions   map[uint64]rawFunction
+	strings     []string
+}
+
+type rawSample struct {
+	locations []uint64
+	values    []int64
+}
+
+type rawLine struct {
+	function uint64
+	line     int64
+}
+
+type rawFunction struct {
+	name, file int64
+}
+
+// resolve returns the profile with its references resolved, keeping the
+// values of the first sample type holding bytes.
+func (raw *rawProfile) resolve() (*Profile, error) {
+	str := func(i int64) string {
+		if i < 0 || i >= int64(len(raw.strings)) {
+			return ""
+		}
+
+		return raw.strings[i]
+	}
+
+	index := -1
+
+	for _, want := range sampleTypes {
+		for i, t := range raw.sampleTypes {
+			if str(t) == want {
+				index = i
+
+				break
+			}
+		}
+
+		if index >= 0 {
+			break
+		}
+	}
+
+	if index < 0 {
+		return nil, errors.New("not a heap profile: no alloc_space or inuse_space values")
+	}
+
+	p := &Profile{SampleType: str(raw.sampleTypes[index])}
+
+	for _, s := range raw.samples {
+		if index >= len(s.values) || s.values[index] == 0 {
+			continue
+		}
+
+		sample := Sample{Bytes: s.values[index]}
+
+		for _, id := range s.locations {
+			for _, l := range raw.locations[id] {
+				fn := raw.functions[l.function]
+				sample.Frames = append(sample.Frames, Frame{Function: str(fn.name), File: str(fn.file), Line: int(l.line)})
+			}
+		}
+
+		p.Samples = append(p.Samples, sample)
+	}
+
+	return p, nil
+}
+
+// Field numbers of the pprof messages (see profile.proto in
+// github.com/google/pprof).
+const (
+	profileSampleType  = 1
+	profileSample      = 2
+	profileLocation    = 4
+	profileFunction    = 5
+	profileStringTable = 6
+
+	valueTypeType = 1
+
+	sampleLocationID = 1
+	sampleValue      = 2
+
+	locationID   = 1
+	locationLine = 4
+
+	lineFunctionID = 1
+	lineLine       = 2
+
+	functionID       = 1
+	functionName     = 2
+	functionFilename = 4
+)
+
+func decodeProfile(data []byte) (*rawProfile, error) {
+	raw := &rawProfile{
+		locations: make(map[uint64][]rawLine),
+		functions: make(map[uint64]rawFunction),
+	}
+
+	err := decodeMessage(data, func(field int, _ int, _ uint64, b []byte) error {
+		switch field {
+		case profileSampleType:
+			var t int64
+
+			err := decodeMessage(b, func(field int, _ int, v uint64, _ []byte) error {
+				if field == valueTypeType {
+					t = int64(v)
+				}
+
+				return nil
+			})
+			raw.sampleTypes = append(raw.sampleTypes, t)
+
+			return err
+		case profileSample:
+			var s rawSample
+
+			err := decodeMessage(b, func(field int, wire int, v uint64, b []byte) error {
+				switch field {
+				case sampleLocationID:
+					return decodeRepeated(wire, v, b, func(v uint64) { s.locations = append(s.locations, v) })
+				case sampleValue:
+					return decodeRepeated(wire, v, b, func(v uint64) { s.values = append(s.values, int64(v)) })
+				}
+
+				return nil
+			})
+			raw.samples = append(raw.samples, s)
+
+			return err
+		case profileLocation:
+			var (
+				id    uint64
+				lines []rawLine
+			)
+
+			err := decodeMessage(b, func(field int, _ int, v uint64, b []byte) error {
+				switch field {
+				case locationID:
+					id = v
+				case locationLine:
+					var l rawLine
+
+					err := decodeMessage(b, func(field int, _ int, v uint64, _ []byte) error {
+						switch field {
+						case lineFunctionID:
+							l.function = v
+						case lineLine:
+							l.line = int64(v)
+						}
+
+						return nil
+					})
+					lines = append(lines, l)
+
+					return err
+				}
+
+				return nil
+			})
+			raw.locations[id] = lines
+
+			return err
+		case profileFunction:
+			var (
+				id uint64
+				fn rawFunction
+			)
+
+			err := decodeMessage(b, func(field int, _ int, v uint64, _ []byte) error {
+				switch field {
+				case functionID:
+					id = v
+				case functionName:
+					fn.name = int64(v)
+				case functionFilename:
+					fn.file = int64(v)
+				}
+
+				return nil
+			})
+			raw.functions[id] = fn
+
+			return err
+		case profileStringTable:
+			raw.strings = append(raw.strings, string(b))
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return raw, nil
+}
+
+// Wire types of the protocol buffer encoding.
+const (
+	wireVarint = 0
+	wireI64    = 1
+	wireBytes  = 2
+	wireI32    = 5
+)
+
+var errTruncated = errors.New("truncated message")
+
+// decodeMessage calls field for each field of the protocol buffer message
+// data, with the value of varint fields or the contents of length-delimited
+// ones.
+func decodeMessage(data []byte, field func(num, wire int, v uint64, b []byte) error) error {
+	for len(data) > 0 {
+		key, n := binary.Uvarint(data)
+		if n <= 0 {
+			return errTruncated
+		}
+
+		data = data[n:]
+		num, wire := int(key>>3), int(key&7)
+
+		var (
+			v uint64
+			b []byte
+		)
+
+		switch wire {
+		case wireVarint:
+			v, n = binary.Uvarint(data)
+			if n <= 0 {
+				return errTruncated
+			}
+
+			data = data[n:]
+		case wireI64:
+			if len(data) < 8 {
+				return errTruncated
+			}
+
+			data = data[8:]
+		case wireBytes:
+			size, n := binary.Uvarint(data)
+			if n <= 0 || uint64(len(data)-n) < size {
+				return errTruncated
+			}
+
+			b = data[n : n+int(size)]
+			data = data[n+int(size):]
+		case wireI32:
+			if len(data) < 4 {
+				return errTruncated
+			}
+
+			data = data[4:]
+		default:
+			return fmt.Errorf("unsupported wire type %d", wire)
+		}
+
+		if err := field(num, wire, v, b); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// decodeRepeated calls add for the value of a repeated varint field, or for
+// each value of a packed one.
+func decodeRepeated(wire int, v uint64, b []byte, add func(uint64)) error {
+	if wire != wireBytes {
+		add(v)
+
+		return nil
+	}
+
+	for len(b) > 0 {
+		v, n := binary.Uvarint(b)
+		if n <= 0 {
+			return errTruncated
+		}
+
+		add(v)
+
+		b = b[n:]
+	}
+
+	return nil
+}
@@ -0,0 +1,161 @@
+// Package logging provides the logger of the pointless command, which writes
+// warnings to stderr and, with -debug, how the run is configured and how each
+// pointer is decided.
+package logging
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"strconv"
+	"strings"
+	"sync"
+	"unicode"
+)
+
+// Handler is a slog.Handler writing one line per record, as in
+//
+//	pointless: warning: failed to load config err="..."
+//
+// The level is spelled like the severities of findings (warning rather than
+// WARN), and attributes follow the message as key=value pairs.
+type Handler struct {
+	mu    *sync.Mutex
+	w     io.Writer
+	level slog.Leveler
+	// attrs holds the formatted attributes added with WithAttrs.
+	attrs  string
+	prefix string
+}
+
+// NewHandler returns a handler writing the records of at least level to w.
+func NewHandler(w io.Writer, level slog.Leveler) *Handler {
+	return &Handler{mu: new(sync.Mutex), w: w, level: level}
+}
+
+// New returns a logger writing to w: warnings and errors, or everything with
+// debug.
+func New(w io.Writer, debug bool) *slog.Logger {
+	level := slog.LevelWarn
+	if debug {
+		level = slog.LevelDebug
+	}
+
+	return slog.New(NewHandler(w, level))
+}
+
+// Enabled reports whether records of level are written.
+func (h *Handler) Enabled(_ context.Context, level slog.Level) bool {
+	return level >= h.level.Level()
+}
+
+// Handle writes r.
+func (h *Handler) Handle(_ context.Context, r slog.Record) error {
+	var buf bytes.Buffer
+
+	fmt.Fprintf(&buf, "pointless: %s: %s%s", levelName(r.Level), r.Message, h.attrs)
+
+	r.Attrs(func(a slog.Attr) bool {
+		appendAttr(&buf, h.prefix, a)
+
+		return true
+	})
+
+	buf.WriteByte('\n')
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if _, err := h.w.Write(buf.Bytes()); err != nil {
+		return fmt.Errorf("writing log record: %w", err)
+	}
+
+	return nil
+}
+
+// WithAttrs returns a handler adding attrs to every record.
+func (h *Handler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	var buf bytes.Buffer
+	for _, a := range attrs {
+		appendAttr(&buf, h.prefix, a)
+	}
+
+	clone := *h
+	clone.attrs += buf.String()
+
+	return &clone
+}
+
+// WithGroup returns a handler qualifying the keys of later attributes with
+// name, as in name.key=value.
+func (h *Handler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+
+	clone := *h
+	clone.prefix += name + "."
+
+	return &clone
+}
+
+// levelName returns the name of level: debug, info, warning, or error, with
+// an offset for levels in between (warning+1).
+func levelName(level slog.Level) string {
+	base, name := slog.LevelError, "error"
+
+	switch {
+	case level < slog.LevelInfo:
+		base, name = slog.LevelDebug, "debug"
+	case level < slog.LevelWarn:
+		base, name = slog.LevelInfo, "info"
+	case level < slog.LevelError:
+		base, name = slog.LevelWarn, "warning"
+	}
+
+	if level == base {
+		return name
+	}
+
+	return fmt.Sprintf("%s%+d", name, level-base)
+}
+
+// appendAttr writes " key=value" to buf, expanding groups.
+func appendAttr(buf *bytes.Buffer, prefix string, a slog.Attr) {
+	a.Value = a.Value.Resolve()
+
+	if a.Equal(slog.Attr{}) {
+		return
+	}
+
+	if a.Value.Kind() == slog.KindGroup {
+		if a.Key != "" {
+			prefix += a.Key + "."
+		}
+
+		for _, ga := range a.Value.Group() {
+			appendAttr(buf, prefix, ga)
+		}
+
+		return
+	}
+
+	buf.WriteByte(' ')
+	buf.WriteString(prefix + a.Key)
+	buf.WriteByte('=')
+	buf.WriteString(quote(a.Value.String()))
+}
+
+// quote quotes s if it is empty or has spaces, quotes, equal signs, or
+// non-printable characters, so that key=value pairs stay apart.
+func quote(s string) string {
+	if s == "" || strings.ContainsFunc(s, func(r rune) bool {
+		return unicode.IsSpace(r) || r == '"' || r == '=' || !unicode.IsPrint(r)
+	}) {
+		return strconv.Quote(s)
+	}
+
+	return s
+}
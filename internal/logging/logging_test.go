@@ -0,0 +1,38 @@
+package logging_test
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/mickamy/pointless/internal/logging"
+)
+
+func TestLogger(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+
+	log := logging.New(&buf, false)
+	log.Debug("hidden")
+	log.Warn("failed to load config", "err", errors.New("no such file"))
+	log.With("package", "example.com/a").WithGroup("phase").Warn("slow", "checks", 3)
+
+	want := "pointless: warning: failed to load config err=\"no such file\"\n" +
+		"pointless: warning: slow package=example.com/a phase.checks=3\n"
+	if got := buf.String(); got != want {
+		t.Errorf("got:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestLoggerDebug(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+
+	logging.New(&buf, true).Debug("not flagged", "pos", "a.go:3:6", "reason", "")
+
+	if got, want := buf.String(), "pointless: debug: not flagged pos=a.go:3:6 reason=\"\"\n"; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
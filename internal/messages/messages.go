@@ -0,0 +1,327 @@
+// Package messages holds the catalogs of diagnostic messages, so every check
+// reports through the same layer and the output language can be configured.
+package messages
+
+import (
+	"fmt"
+	"sort"
+)
+
+// ID identifies a message in the catalogs.
+type ID string
+
+// Message identifiers.
+const (
+	ValueReceiver                ID = "value-receiver"
+	EmptyReceiver                ID = "empty-receiver"
+	EmptyReceiverFix             ID = "empty-receiver-fix"
+	FixReceiverUnused            ID = "fix-receiver-unused"
+	FixReceiverValueMethods      ID = "fix-receiver-value-methods"
+	FixReceiverPointerMethods    ID = "fix-receiver-pointer-methods"
+	ValueReceiverFix             ID = "value-receiver-fix"
+	FixReceiverRead              ID = "fix-receiver-read"
+	FixReceiverReadOnlyArgs      ID = "fix-receiver-read-only-args"
+	FixReceiverFieldAddress      ID = "fix-receiver-field-address"
+	ReturnPointer                ID = "return-pointer"
+	ReturnPointerDereferenced    ID = "return-pointer-dereferenced"
+	ReturnPointerDereferencedOne ID = "return-pointer-dereferenced-one"
+	ReturnPointerCall            ID = "return-pointer-call"
+	ReturnPointerFix             ID = "return-pointer-fix"
+	FixReturnCopied              ID = "fix-return-copied"
+	FixReturnDereferenced        ID = "fix-return-dereferenced"
+	FixReturnDeferred            ID = "fix-return-deferred"
+	NamedPointerReturn           ID = "named-pointer-return"
+	ReturnPointerError           ID = "return-pointer-error"
+	SlicePointer                 ID = "slice-pointer"
+	SortPointerSlice             ID = "sort-pointer-slice"
+	SortPointerSliceSource       ID = "sort-pointer-slice-source"
+	SliceConversion              ID = "slice-conversion"
+	SliceConversionPair          ID = "slice-conversion-pair"
+	SliceConversionInverse       ID = "slice-conversion-inverse"
+	SliceConversionCall          ID = "slice-conversion-call"
+	EmbeddedPointer              ID = "embedded-pointer"
+	EmbeddedPointerMethods       ID = "embedded-pointer-methods"
+	ReferencePointer             ID = "reference-pointer"
+	LocalPointer                 ID = "local-pointer"
+	LocalPointerDefine           ID = "local-pointer-define"
+	OptionsPointer               ID = "options-pointer"
+	ContextValue                 ID = "context-value"
+	ValueBuilder                 ID = "value-builder"
+	AddressArgument              ID = "address-argument"
+	AddressArgumentOne           ID = "address-argument-one"
+	AddressArgumentCall          ID = "address-argument-call"
+	MapPointerKey                ID = "map-pointer-key"
+	PointerRoundTrip             ID = "pointer-round-trip"
+	PointerRoundTripDeref        ID = "pointer-round-trip-deref"
+	PointerRoundTripFix          ID = "pointer-round-trip-fix"
+	FixDereferencedAddress       ID = "fix-dereferenced-address"
+	NeedsReview                  ID = "needs-review"
+	ReviewNoTypeInfo             ID = "review-no-type-info"
+	ReviewTypeParam              ID = "review-type-param"
+	ReviewTypeArgs               ID = "review-type-args"
+	InvalidNolintExpiration      ID = "invalid-nolint-expiration"
+	StaleNolint                  ID = "stale-nolint"
+	Suppressed                   ID = "suppressed"
+	SuppressedBy                 ID = "suppressed-by"
+	TypeDefined                  ID = "type-defined"
+	TypeDefinedSize              ID = "type-defined-size"
+	GroupedType                  ID = "grouped-type"
+	FixBreaksAPI                 ID = "fix-breaks-api"
+	Layout                       ID = "layout"
+	LayoutField                  ID = "layout-field"
+	LayoutPadding                ID = "layout-padding"
+	ComparedPointers             ID = "compared-pointers"
+	ZeroValueUsable              ID = "zero-value-usable"
+	EnforcedValue                ID = "enforced-value"
+	WorkerPoolChannel            ID = "worker-pool-channel"
+	WorkerPoolSlice              ID = "worker-pool-slice"
+	AtomicCounter                ID = "atomic-counter"
+	AtomicCounterUpdate          ID = "atomic-counter-update"
+
+	// Reference type kinds, used as arguments of ReferencePointer.
+	KindMaps      ID = "kind-maps"
+	KindSlices    ID = "kind-slices"
+	KindChannels  ID = "kind-channels"
+	KindFunctions ID = "kind-functions"
+
+	// Size bounds, used as the last argument of the messages of findings.
+	Threshold  ID = "threshold"
+	SizeBounds ID = "size-bounds"
+
+	// Evidence of the verdicts of pointless verdict.
+	EvidenceTooLarge        ID = "evidence-too-large"
+	EvidenceSyncField       ID = "evidence-sync-field"
+	EvidenceMutatedReceiver ID = "evidence-mutated-receiver"
+	EvidenceNil             ID = "evidence-nil"
+	EvidenceNilReturn       ID = "evidence-nil-return"
+	EvidenceValueFriendly   ID = "evidence-value-friendly"
+)
+
+// DefaultLang is the language used when none is configured.
+const DefaultLang = "en"
+
+var catalogs = map[string]map[ID]string{
+	"en": en,
+	"ja": ja,
+}
+
+var en = map[ID]string{
+	ValueReceiver:                "consider using value receiver: %s is %d bytes (%s) and method doesn't mutate receiver",
+	EmptyReceiver:                "consider using value receiver: %s has no fields, so there is nothing to mutate or copy",
+	EmptyReceiverFix:             "Use value receiver",
+	FixReceiverUnused:            "the receiver is unused",
+	FixReceiverValueMethods:      "the receiver is only used to call value-receiver methods",
+	FixReceiverPointerMethods:    "pointer-receiver methods called on the receiver get the address of a copy",
+	ValueReceiverFix:             "Use value receiver",
+	FixReceiverRead:              "the receiver is only read",
+	FixReceiverReadOnlyArgs:      "&%s is passed to parameters that only read it",
+	FixReceiverFieldAddress:      "addresses taken of the receiver's fields point into a copy",
+	ReturnPointer:                "consider returning value instead of pointer: %s is %d bytes (%s)",
+	ReturnPointerDereferenced:    "consider returning %s instead of a pointer: all %d callers dereference the result immediately (%d bytes, %s)",
+	ReturnPointerDereferencedOne: "consider returning %s instead of a pointer: its only caller dereferences the result immediately (%d bytes, %s)",
+	ReturnPointerCall:            "dereferenced by %s here",
+	ReturnPointerFix:             "Return %s",
+	FixReturnCopied:              "every caller copies the result right away",
+	FixReturnDereferenced:        "returned pointers are dereferenced in the function, which panics there if one is nil",
+	FixReturnDeferred:            "the result is copied before the deferred calls of the function run, not after",
+	NamedPointerReturn:           "consider returning value instead of pointer: %s is *%s and %s is %d bytes (%s)",
+	ReturnPointerError:           "consider returning (%s, error) instead of (*%s, error): nil is only returned along with a non-nil error, so the zero value can take its place (%d bytes, %s)",
+	SlicePointer:                 "consider using []%s instead of []%s: better cache locality and lower GC pressure (%d bytes, %s)",
+	SortPointerSlice:             "consider using []%s instead of []*%s: %s is only filled with the addresses of values to be sorted or iterated (%d bytes, %s)",
+	SliceConversion:              "%s only converts %s to %s: consider standardizing on []%s, so that no adapter is needed (%d bytes, %s)",
+	SliceConversionPair:          "%s; %s converts it back",
+	SliceConversionInverse:       "%s converts back here",
+	SliceConversionCall:          "converted by %s here",
+	SortPointerSliceSource:       "consider sorting a copy of %s, or a []int of indexes into it, instead of []*%s: %s only points into %s to be sorted or iterated (%d bytes, %s)",
+	EmbeddedPointer:              "consider embedding %s instead of *%s: it is never nil, and each copy of %s would then hold its own %s instead of sharing one (%d bytes, %s)",
+	EmbeddedPointerMethods:       "%s; the pointer-receiver methods of *%s (%s) would be promoted to *%s only, not to %s values",
+	ReferencePointer:             "consider using %s instead of *%s: %s are already reference types",
+	LocalPointer:                 "consider declaring var %s %s instead of *%s: it is only initialized with &%s{...} and dereferenced (%d bytes, %s)",
+	LocalPointerDefine:           "consider declaring %s := %s{...} instead of a pointer: it is only initialized with &%s{...} and dereferenced (%d bytes, %s)",
+	OptionsPointer:               "consider accepting %s %s instead of *%s: %s never mutates or nil-checks it (%d bytes, %s); functional options are an alternative",
+	AddressArgument:              "consider accepting %s %s instead of *%s: %s only reads it and all %d callers pass the address of a value (%d bytes, %s)",
+	AddressArgumentOne:           "consider accepting %s %s instead of *%s: %s only reads it and its only caller passes the address of a value (%d bytes, %s)",
+	AddressArgumentCall:          "called with %s here",
+	MapPointerKey:                "consider using %s as the map key instead of *%s: pointer keys compare by identity, not by value (%d bytes, %s)",
+	PointerRoundTrip:             "pointer round-trip: %s only points to %s and is used through field access and dereference; consider using %s directly (%d bytes, %s)",
+	PointerRoundTripDeref:        "pointer round-trip: %s dereferences the address it takes; consider using %s directly (%d bytes, %s)",
+	PointerRoundTripFix:          "Use %s directly",
+	FixDereferencedAddress:       "*&x is x",
+	ContextValue:                 "consider storing %s instead of *%s in the context: context values are read-only by convention and a pointer invites shared mutation (%d bytes, %s)",
+	ValueBuilder:                 "consider a value builder: %s returns its *%s receiver for chaining; as func (%s) %s(...) %s, each call would return a modified copy, and chains would no longer share one value (%d bytes, %s)",
+	NeedsReview:                  "needs manual review: %s",
+	ReviewNoTypeInfo:             "type information is unavailable",
+	ReviewTypeParam:              "%s is a type parameter, so what it points to depends on the instantiation",
+	ReviewTypeArgs:               "the size of %s depends on its type arguments",
+	InvalidNolintExpiration:      "invalid nolint expiration %q: expected until=YYYY-MM-DD",
+	StaleNolint:                  "stale suppression: nolint expired on %s",
+	Suppressed:                   "suppressed: %s",
+	SuppressedBy:                 "suppressed by this comment",
+	TypeDefined:                  "%s is defined here",
+	TypeDefinedSize:              "%s is defined here: %s",
+	GroupedType:                  "%s is flagged at %d sites (%s): consider using values instead of pointers",
+	FixBreaksAPI:                 "no fix suggested: it would break the public API (%s); use -allow-breaking to get it anyway",
+	Layout:                       "%s; layout of %s: %s",
+	LayoutField:                  "%s %s at offset %d (%d bytes)",
+	LayoutPadding:                "padding (%d bytes)",
+	ComparedPointers:             "%s; note: pointers to %s are compared at line %d, which would compare values instead of identities",
+	ZeroValueUsable:              "%s; its zero value %s{} is ready to use",
+	EnforcedValue:                "%s is annotated with //pointless:enforce: use %s instead of *%s",
+	WorkerPoolChannel:            "consider a chan %s: the *%s jobs sent on %s to the goroutines at line %d are never nil; values would give each worker its own copy, sharing no memory with the sender or other jobs, at the cost of copying each job (%d bytes, %s)",
+	WorkerPoolSlice:              "consider []%s: the *%s elements of %s reach the goroutines at line %d and are never nil; values would be contiguous, without pointer chasing, but goroutines writing neighboring elements may then contend for cache lines (false sharing), and goroutines given a copy no longer write to the slice (%d bytes, %s)",
+	AtomicCounter:                "consider sync/atomic counters: %s only updates the integer counters %s of its *%s receiver; atomic.Int64 and the like make the updates safe for concurrent use, and held through a pointer (*atomic.Int64) they let %s take a %s value receiver (%d bytes, %s)",
+	AtomicCounterUpdate:          "%s is updated here",
+	KindMaps:                     "maps",
+	KindSlices:                   "slices",
+	KindChannels:                 "channels",
+	KindFunctions:                "functions",
+	Threshold:                    "threshold: %d bytes",
+	SizeBounds:                   "min-size: %d bytes, threshold: %d bytes",
+	EvidenceTooLarge:             "%d bytes > threshold %d bytes",
+	EvidenceSyncField:            "field %s of type %s must not be copied",
+	EvidenceMutatedReceiver:      "(*%s).%s mutates the receiver",
+	EvidenceNil:                  "nil is assigned to or compared with %s",
+	EvidenceNilReturn:            "%s returns a nil *%s",
+	EvidenceValueFriendly:        "%d bytes <= threshold %d bytes, with no mutated receiver, nil use, or sync field",
+}
+
+var ja = map[ID]string{
+	ValueReceiver:                "値レシーバの使用を検討してください: %s は %d バイト (%s) で、メソッドはレシーバを変更しません",
+	EmptyReceiver:                "値レシーバの使用を検討してください: %s にはフィールドがないため、変更やコピーの対象がありません",
+	EmptyReceiverFix:             "値レシーバを使う",
+	FixReceiverUnused:            "レシーバは使われていません",
+	FixReceiverValueMethods:      "レシーバは値レシーバのメソッド呼び出しにのみ使われています",
+	FixReceiverPointerMethods:    "レシーバで呼び出すポインタレシーバのメソッドはコピーのアドレスを受け取ります",
+	ValueReceiverFix:             "値レシーバを使う",
+	FixReceiverRead:              "レシーバは読み取られるだけです",
+	FixReceiverReadOnlyArgs:      "&%s は読み取るだけのパラメータに渡されます",
+	FixReceiverFieldAddress:      "レシーバのフィールドのアドレスはコピーの中を指します",
+	ReturnPointer:                "ポインタではなく値を返すことを検討してください: %s は %d バイトです (%s)",
+	ReturnPointerDereferenced:    "ポインタではなく %[1]s を返すことを検討してください: %[2]d 個の呼び出し元はすべて結果をすぐに参照外ししています (%[3]d バイト、%[4]s)",
+	ReturnPointerDereferencedOne: "ポインタではなく %[1]s を返すことを検討してください: 唯一の呼び出し元は結果をすぐに参照外ししています (%[2]d バイト、%[3]s)",
+	ReturnPointerCall:            "ここで %s として参照外ししています",
+	ReturnPointerFix:             "%s を返す",
+	FixReturnCopied:              "すべての呼び出し元は結果をすぐにコピーしています",
+	FixReturnDereferenced:        "返すポインタは関数内で参照外しされ、nil ならそこでパニックします",
+	FixReturnDeferred:            "結果は関数の遅延呼び出しの後ではなく前にコピーされます",
+	NamedPointerReturn:           "ポインタではなく値を返すことを検討してください: %s は *%s で、%s は %d バイトです (%s)",
+	ReturnPointerError:           "(*%[2]s, error) ではなく (%[1]s, error) を返すことを検討してください: nil は nil でないエラーと一緒にのみ返されるため、ゼロ値で代用できます (%[3]d バイト、%[4]s)",
+	SlicePointer:                 "[]%[2]s ではなく []%[1]s の使用を検討してください: キャッシュ局所性が向上し、GC の負荷が下がります (%[3]d バイト、%[4]s)",
+	SortPointerSlice:             "[]*%[2]s ではなく []%[1]s の使用を検討してください: %[3]s にはソートまたは反復のために値のアドレスが格納されるだけです (%[4]d バイト、%[5]s)",
+	SliceConversion:              "%[1]s は %[2]s を %[3]s に変換するだけです: []%[4]s に統一すれば変換関数は不要になります (%[5]d バイト、%[6]s)",
+	SliceConversionPair:          "%[1]s。%[2]s が逆方向に変換しています",
+	SliceConversionInverse:       "ここで %s が逆方向に変換しています",
+	SliceConversionCall:          "ここで %s として変換しています",
+	SortPointerSliceSource:       "[]*%[2]s ではなく %[1]s のコピー、または %[1]s のインデックスの []int をソートすることを検討してください: %[3]s はソートまたは反復のために %[4]s を指すだけです (%[5]d バイト、%[6]s)",
+	EmbeddedPointer:              "*%[2]s ではなく %[1]s の埋め込みを検討してください: nil になることはなく、%[3]s の各コピーは %[4]s を共有せず個別に持つようになります (%[5]d バイト、%[6]s)",
+	EmbeddedPointerMethods:       "%[1]s。*%[2]s のポインタレシーバメソッド (%[3]s) は %[5]s の値には昇格せず、*%[4]s にのみ昇格します",
+	ReferencePointer:             "*%[2]s ではなく %[1]s の使用を検討してください: %[3]s はすでに参照型です",
+	LocalPointer:                 "*%[3]s ではなく var %[1]s %[2]s と宣言することを検討してください: &%[4]s{...} で初期化され、参照外しされるだけです (%[5]d バイト、%[6]s)",
+	LocalPointerDefine:           "ポインタではなく %[1]s := %[2]s{...} と宣言することを検討してください: &%[3]s{...} で初期化され、参照外しされるだけです (%[4]d バイト、%[5]s)",
+	OptionsPointer:               "*%[3]s ではなく %[1]s %[2]s を受け取ることを検討してください: %[4]s はこれを変更も nil チェックもしません (%[5]d バイト、%[6]s)。functional options も選択肢です",
+	AddressArgument:              "*%[3]s ではなく %[1]s %[2]s を受け取ることを検討してください: %[4]s はこれを読み取るだけで、%[5]d 個の呼び出し元はすべて値のアドレスを渡しています (%[6]d バイト、%[7]s)",
+	AddressArgumentOne:           "*%[3]s ではなく %[1]s %[2]s を受け取ることを検討してください: %[4]s はこれを読み取るだけで、唯一の呼び出し元は値のアドレスを渡しています (%[5]d バイト、%[6]s)",
+	AddressArgumentCall:          "ここで %s を渡して呼び出しています",
+	MapPointerKey:                "*%[2]s ではなく %[1]s をマップのキーに使うことを検討してください: ポインタのキーは値ではなく同一性で比較されます (%[3]d バイト、%[4]s)",
+	PointerRoundTrip:             "ポインタの往復: %[1]s は %[2]s だけを指し、フィールドアクセスと参照外しにのみ使われています。%[3]s を直接使うことを検討してください (%[4]d バイト、%[5]s)",
+	PointerRoundTripDeref:        "ポインタの往復: %[1]s は取得したアドレスをすぐに参照外ししています。%[2]s を直接使うことを検討してください (%[3]d バイト、%[4]s)",
+	PointerRoundTripFix:          "%s を直接使う",
+	FixDereferencedAddress:       "*&x は x と同じです",
+	ContextValue:                 "コンテキストには *%[2]s ではなく %[1]s を格納することを検討してください: コンテキストの値は慣例として読み取り専用で、ポインタは共有された値の変更を招きます (%[3]d バイト、%[4]s)",
+	ValueBuilder:                 "値のビルダーを検討してください: %[1]s はメソッドチェーンのために *%[2]s レシーバを返しています。func (%[3]s) %[4]s(...) %[5]s とすると、各呼び出しは変更したコピーを返し、チェーンは 1 つの値を共有しなくなります (%[6]d バイト、%[7]s)",
+	NeedsReview:                  "手動での確認が必要です: %s",
+	ReviewNoTypeInfo:             "型情報を取得できません",
+	ReviewTypeParam:              "%s は型パラメータのため、指す先はインスタンス化によって決まります",
+	ReviewTypeArgs:               "%s のサイズは型引数によって決まります",
+	InvalidNolintExpiration:      "nolint の有効期限 %q が不正です: until=YYYY-MM-DD の形式で指定してください",
+	StaleNolint:                  "古い抑制: nolint の有効期限は %s に切れています",
+	Suppressed:                   "抑制済み: %s",
+	SuppressedBy:                 "このコメントにより抑制されています",
+	TypeDefined:                  "%s はここで定義されています",
+	TypeDefinedSize:              "%s はここで定義されています: %s",
+	GroupedType:                  "%s は %d 箇所で検出されました (%s): ポインタではなく値の使用を検討してください",
+	FixBreaksAPI:                 "公開 API を壊すため修正は提示しません (%s)。-allow-breaking を指定すると提示します",
+	Layout:                       "%s; %s のレイアウト: %s",
+	LayoutField:                  "%s %s: オフセット %d (%d バイト)",
+	LayoutPadding:                "パディング (%d バイト)",
+	ComparedPointers:             "%[1]s; 注意: %[3]d 行目で %[2]s へのポインタが比較されており、同一性ではなく値の比較になります",
+	ZeroValueUsable:              "%s。ゼロ値 %s{} はそのまま使えます",
+	EnforcedValue:                "%[1]s には //pointless:enforce が指定されています: *%[3]s ではなく %[2]s を使用してください",
+	WorkerPoolChannel:            "chan %[1]s を検討してください: %[3]s で %[4]d 行目のゴルーチンに送られる *%[2]s のジョブは nil になりません。値にすると各ワーカーは送信側や他のジョブとメモリを共有しない自分のコピーを受け取りますが、ジョブごとにコピーが発生します (%[5]d バイト、%[6]s)",
+	WorkerPoolSlice:              "[]%[1]s を検討してください: %[3]s の *%[2]s 要素は %[4]d 行目のゴルーチンに渡され、nil になりません。値にすると要素は連続して配置されポインタの参照も不要になりますが、隣接する要素を書き込むゴルーチン同士がキャッシュラインを奪い合う (フォルスシェアリング) ことがあり、コピーを受け取ったゴルーチンの書き込みはスライスに反映されなくなります (%[5]d バイト、%[6]s)",
+	AtomicCounter:                "sync/atomic のカウンタを検討してください: %[1]s は *%[3]s レシーバの整数カウンタ %[2]s を更新するだけです。atomic.Int64 などを使えば更新を並行に安全に行え、ポインタ (*atomic.Int64) で保持すれば %[4]s は %[5]s の値レシーバを使えます (%[6]d バイト、%[7]s)",
+	AtomicCounterUpdate:          "%s はここで更新されます",
+	KindMaps:                     "マップ",
+	KindSlices:                   "スライス",
+	KindChannels:                 "チャネル",
+	KindFunctions:                "関数",
+	Threshold:                    "しきい値: %d バイト",
+	SizeBounds:                   "最小サイズ: %d バイト、しきい値: %d バイト",
+	EvidenceTooLarge:             "%d バイト > しきい値 %d バイト",
+	EvidenceSyncField:            "%[2]s 型のフィールド %[1]s はコピーしてはいけません",
+	EvidenceMutatedReceiver:      "(*%s).%s はレシーバを変更します",
+	EvidenceNil:                  "%s に nil が代入または比較されています",
+	EvidenceNilReturn:            "%s は nil の *%s を返します",
+	EvidenceValueFriendly:        "%d バイト <= しきい値 %d バイトで、レシーバの変更、nil の使用、コピーできないフィールドはありません",
+}
+
+// Printer formats messages in one language, falling back to English for
+// messages missing from its catalog.
+type Printer struct {
+	catalog map[ID]string
+}
+
+// NewPrinter returns a printer for lang ("" selects DefaultLang).
+func NewPrinter(lang string) (*Printer, error) {
+	if lang == "" {
+		lang = DefaultLang
+	}
+
+	catalog, ok := catalogs[lang]
+	if !ok {
+		return nil, fmt.Errorf("unknown language %q (available: %v)", lang, Languages())
+	}
+
+	return &Printer{catalog: catalog}, nil
+}
+
+// Sprintf formats the message id with args.
+func (p *Printer) Sprintf(id ID, args ...any) string {
+	format, ok := p.catalog[id]
+	if !ok {
+		format = en[id]
+	}
+
+	return fmt.Sprintf(format, args...)
+}
+
+// Languages returns the available languages, sorted.
+func Languages() []string {
+	langs := make([]string, 0, len(catalogs))
+	for lang := range catalogs {
+		langs = append(langs, lang)
+	}
+
+	sort.Strings(langs)
+
+	return langs
+}
+
+// Format returns the format string of id in lang, for tests of the catalogs.
+func Format(lang string, id ID) (string, bool) {
+	format, ok := catalogs[lang][id]
+
+	return format, ok
+}
+
+// IDs returns all message identifiers.
+func IDs() []ID {
+	ids := make([]ID, 0, len(en))
+	for id := range en {
+		ids = append(ids, id)
+	}
+
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+
+	return ids
+}
@@ -0,0 +1,76 @@
+package messages_test
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/mickamy/pointless/internal/messages"
+)
+
+// verb matches the formatting verbs of the English catalog.
+var verb = regexp.MustCompile(`%[sdq]`)
+
+// sampleArgs returns arguments of the right kinds for an English format.
+func sampleArgs(format string) []any {
+	var args []any
+
+	for i, v := range verb.FindAllString(format, -1) {
+		if v == "%d" {
+			args = append(args, i)
+		} else {
+			args = append(args, fmt.Sprintf("arg%d", i))
+		}
+	}
+
+	return args
+}
+
+func TestCatalogsAreComplete(t *testing.T) {
+	t.Parallel()
+
+	for _, lang := range messages.Languages() {
+		for _, id := range messages.IDs() {
+			en, _ := messages.Format("en", id)
+
+			format, ok := messages.Format(lang, id)
+			if !ok {
+				t.Errorf("%s: message %s is missing", lang, id)
+
+				continue
+			}
+
+			args := sampleArgs(en)
+			got := fmt.Sprintf(format, args...)
+
+			if strings.Contains(got, "%!") {
+				t.Errorf("%s: message %s does not take the English arguments: %q", lang, id, got)
+			}
+
+			// Every argument must appear in the translation
+			for _, arg := range args {
+				if s := fmt.Sprint(arg); !strings.Contains(got, s) {
+					t.Errorf("%s: message %s drops argument %s: %q", lang, id, s, got)
+				}
+			}
+		}
+	}
+}
+
+func TestNewPrinter(t *testing.T) {
+	t.Parallel()
+
+	p, err := messages.NewPrinter("")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got, want := p.Sprintf(messages.StaleNolint, "2000-01-01"), "stale suppression: nolint expired on 2000-01-01"; got != want {
+		t.Errorf("Sprintf = %q, want %q", got, want)
+	}
+
+	if _, err := messages.NewPrinter("xx"); err == nil {
+		t.Error("NewPrinter(xx) succeeded, want an error")
+	}
+}
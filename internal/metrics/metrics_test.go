@@ -0,0 +1,85 @@
+package metrics_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/mickamy/pointless/internal/analyzer"
+	"github.com/mickamy/pointless/internal/metrics"
+	"github.com/mickamy/pointless/internal/runner"
+)
+
+func TestNew(t *testing.T) {
+	t.Parallel()
+
+	var m analyzer.Metrics
+	m.Add(&analyzer.Metrics{
+		Packages:      1,
+		Phases:        map[string]time.Duration{analyzer.PhaseChecks: time.Millisecond},
+		PackagePhases: map[string]map[string]time.Duration{"example.com/a": {analyzer.PhaseChecks: time.Millisecond}},
+		Suppressed:    1,
+	})
+	m.Add(&analyzer.Metrics{
+		Packages:      1,
+		Phases:        map[string]time.Duration{analyzer.PhaseChecks: 2 * time.Millisecond},
+		PackagePhases: map[string]map[string]time.Duration{"example.com/b": {analyzer.PhaseChecks: 2 * time.Millisecond}},
+	})
+	m.Add(&analyzer.Metrics{Phases: map[string]time.Duration{analyzer.PhaseMutationScan: time.Millisecond}})
+
+	findings := []runner.Finding{{Rule: "value-receiver"}, {Rule: "slice-pointer"}, {Rule: "value-receiver"}}
+
+	got := metrics.New(10*time.Millisecond, 4*time.Millisecond, m, findings)
+	want := metrics.Summary{
+		Packages:   2,
+		DurationMS: 10,
+		PhasesMS:   map[string]float64{metrics.PhaseLoad: 4, analyzer.PhaseChecks: 3, analyzer.PhaseMutationScan: 1},
+		PackageTimings: []metrics.PackageTiming{
+			{Package: "example.com/b", TotalMS: 2, PhasesMS: map[string]float64{analyzer.PhaseChecks: 2}},
+			{Package: "example.com/a", TotalMS: 1, PhasesMS: map[string]float64{analyzer.PhaseChecks: 1}},
+		},
+		Findings:      map[string]int{"value-receiver": 2, "slice-pointer": 1},
+		FindingsTotal: 3,
+		Suppressed:    1,
+	}
+
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("New() = %+v, want %+v", got, want)
+	}
+}
+
+func TestPackageTimingString(t *testing.T) {
+	t.Parallel()
+
+	timing := metrics.PackageTiming{
+		Package:  "example.com/app/gen",
+		TotalMS:  12.5,
+		PhasesMS: map[string]float64{analyzer.PhaseChecks: 10, analyzer.PhaseNilScan: 2.5},
+	}
+
+	if got, want := timing.String(), "example.com/app/gen: 12.5ms (nil-scan 2.5ms, checks 10.0ms)"; got != want {
+		t.Errorf("String() = %q, want %q", got, want)
+	}
+}
+
+func TestWrite(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+	if err := metrics.Write(&buf, metrics.New(0, 0, analyzer.Metrics{}, nil)); err != nil {
+		t.Fatal(err)
+	}
+
+	var got map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &got); err != nil {
+		t.Fatalf("invalid JSON: %v\n%s", err, buf.String())
+	}
+
+	for _, key := range []string{"packages", "duration_ms", "phases_ms", "findings", "findings_total", "suppressed", "package_timings"} {
+		if _, ok := got[key]; !ok {
+			t.Errorf("missing key %q in %s", key, buf.String())
+		}
+	}
+}
@@ -0,0 +1,115 @@
+// Package preset provides built-in suppression profiles for frameworks whose
+// types rely on pointer semantics (ORMs, code generators, RPC stacks).
+package preset
+
+import (
+	"sort"
+)
+
+// Preset describes how to recognize types managed by a framework.
+type Preset struct {
+	// Name is the identifier used in the presets config list.
+	Name string
+	// TypePatterns are glob patterns matched against the type name and the
+	// package-qualified type name (e.g. "*Request", "example.com/ent.*").
+	TypePatterns []string
+	// EmbeddedTypes are package-qualified types whose embedding marks a
+	// framework type (e.g. "gorm.io/gorm.Model").
+	EmbeddedTypes []string
+	// FieldTypes are package-qualified types whose fields, embedded or not,
+	// mark a framework type (e.g. the state field of generated messages).
+	FieldTypes []string
+	// Interfaces are method sets implying pointer semantics; a type matches
+	// when its pointer method set contains every method of one of them.
+	Interfaces []Interface
+	// TagKeys are struct tag keys that mark a framework type (e.g. "xorm").
+	TagKeys []string
+	// Imports are the paths of the framework's modules, whose import
+	// suggests the preset to pointless init. They play no part in matching.
+	Imports []string
+}
+
+// Interface is a named method set used for structural matching, so presets
+// work without the framework's packages being loaded.
+type Interface struct {
+	Name    string
+	Methods []string
+}
+
+var builtin = map[string]Preset{
+	"gorm": {
+		Name:          "gorm",
+		EmbeddedTypes: []string{"gorm.io/gorm.Model", "github.com/jinzhu/gorm.Model"},
+		Interfaces: []Interface{
+			{Name: "callbacks.BeforeCreateInterface", Methods: []string{"BeforeCreate"}},
+			{Name: "callbacks.BeforeSaveInterface", Methods: []string{"BeforeSave"}},
+			{Name: "callbacks.BeforeUpdateInterface", Methods: []string{"BeforeUpdate"}},
+			{Name: "callbacks.BeforeDeleteInterface", Methods: []string{"BeforeDelete"}},
+			{Name: "callbacks.AfterCreateInterface", Methods: []string{"AfterCreate"}},
+			{Name: "callbacks.AfterSaveInterface", Methods: []string{"AfterSave"}},
+			{Name: "callbacks.AfterUpdateInterface", Methods: []string{"AfterUpdate"}},
+			{Name: "callbacks.AfterDeleteInterface", Methods: []string{"AfterDelete"}},
+			{Name: "callbacks.AfterFindInterface", Methods: []string{"AfterFind"}},
+		},
+		TagKeys: []string{"gorm"},
+		Imports: []string{"gorm.io/gorm", "github.com/jinzhu/gorm"},
+	},
+	"ent": {
+		Name: "ent",
+		Interfaces: []Interface{
+			// Generated entities scan rows into themselves.
+			{Name: "ent entity", Methods: []string{"scanValues", "assignValues"}},
+		},
+		Imports: []string{"entgo.io/ent"},
+	},
+	"protobuf": {
+		Name: "protobuf",
+		Interfaces: []Interface{
+			{Name: "protoreflect.ProtoMessage", Methods: []string{"ProtoReflect"}},
+			{Name: "protoiface.MessageV1", Methods: []string{"Reset", "String", "ProtoMessage"}},
+		},
+		Imports: []string{"google.golang.org/protobuf", "github.com/golang/protobuf"},
+	},
+	"grpc": {
+		Name: "grpc",
+		// Request and response messages generated by protoc-gen-go hold their
+		// state in a protoimpl.MessageState field, an alias of the internal type.
+		FieldTypes: []string{
+			"google.golang.org/protobuf/runtime/protoimpl.MessageState",
+			"google.golang.org/protobuf/internal/impl.MessageState",
+		},
+		Interfaces: []Interface{
+			{Name: "protoreflect.ProtoMessage", Methods: []string{"ProtoReflect"}},
+		},
+		Imports: []string{"google.golang.org/grpc"},
+	},
+	"xorm": {
+		Name:    "xorm",
+		TagKeys: []string{"xorm"},
+		Imports: []string{"xorm.io/xorm", "github.com/go-xorm/xorm"},
+	},
+	"sqlx": {
+		Name:    "sqlx",
+		TagKeys: []string{"db"},
+		Imports: []string{"github.com/jmoiron/sqlx"},
+	},
+}
+
+// Lookup returns the built-in preset with the given name.
+func Lookup(name string) (Preset, bool) {
+	p, ok := builtin[name]
+
+	return p, ok
+}
+
+// Names returns the names of all built-in presets, sorted.
+func Names() []string {
+	names := make([]string, 0, len(builtin))
+	for name := range builtin {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	return names
+}
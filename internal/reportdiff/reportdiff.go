@@ -0,0 +1,186 @@
+// Package reportdiff compares two JSON reports (see -format=json), so that the
+// effect of upgrading the linter or changing the config can be reviewed
+// before rolling it out.
+package reportdiff
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+)
+
+// Finding is a finding of a JSON report, with the fields the comparison
+// uses.
+type Finding struct {
+	Rule        string `json:"rule"`
+	Message     string `json:"message"`
+	File        string `json:"file"`
+	Line        int    `json:"line"`
+	Column      int    `json:"column"`
+	Package     string `json:"package"`
+	Symbol      string `json:"symbol,omitempty"`
+	Fingerprint string `json:"fingerprint"`
+}
+
+// Move is a finding reported at another position.
+type Move struct {
+	Old, New Finding
+}
+
+// Result is the difference between two reports. Each list is sorted by the
+// position of its findings in the new report, or the old one for removed
+// findings.
+type Result struct {
+	Added   []Finding
+	Removed []Finding
+	Moved   []Move
+}
+
+// Changed reports whether findings were added or removed. Moved findings are
+// the same findings and do not count.
+func (r Result) Changed() bool {
+	return len(r.Added) > 0 || len(r.Removed) > 0
+}
+
+// ReadFile reads the JSON report at path.
+func ReadFile(path string) ([]Finding, error) {
+	f, err := os.Open(path) //nolint:gosec // G304: the report is named by the user
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	findings, err := Read(f)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+
+	return findings, nil
+}
+
+// Read reads a JSON report.
+func Read(r io.Reader) ([]Finding, error) {
+	var findings []Finding
+	if err := json.NewDecoder(r).Decode(&findings); err != nil {
+		return nil, fmt.Errorf("reading json report: %w", err)
+	}
+
+	return findings, nil
+}
+
+// Compare returns the findings of newer that are not in older, those of older
+// that are not in newer, and those in both at different positions.
+//
+// Findings are the same if they have the same fingerprint, which survives
+// line drift and file renames. Findings without one, from reports of older
+// versions, are the same if they have the same rule, package, symbol, and
+// message.
+func Compare(older, newer []Finding) Result {
+	var result Result
+
+	// unmatched maps the keys of the old findings to those not matched yet,
+	// in report order
+	unmatched := make(map[string][]Finding)
+	for _, f := range older {
+		unmatched[key(f)] = append(unmatched[key(f)], f)
+	}
+
+	for _, f := range newer {
+		k := key(f)
+
+		olds := unmatched[k]
+		if len(olds) == 0 {
+			result.Added = append(result.Added, f)
+
+			continue
+		}
+
+		old := olds[0]
+		unmatched[k] = olds[1:]
+
+		if old.File != f.File || old.Line != f.Line || old.Column != f.Column {
+			result.Moved = append(result.Moved, Move{Old: old, New: f})
+		}
+	}
+
+	for _, olds := range unmatched {
+		result.Removed = append(result.Removed, olds...)
+	}
+
+	sortFindings(result.Added)
+	sortFindings(result.Removed)
+	sort.SliceStable(result.Moved, func(i, j int) bool {
+		return less(result.Moved[i].New, result.Moved[j].New)
+	})
+
+	return result
+}
+
+// key identifies a finding across reports.
+func key(f Finding) string {
+	if f.Fingerprint != "" {
+		return f.Fingerprint
+	}
+
+	return f.Rule + "\x00" + f.Package + "\x00" + f.Symbol + "\x00" + f.Message
+}
+
+// sortFindings sorts findings with less.
+func sortFindings(findings []Finding) {
+	sort.SliceStable(findings, func(i, j int) bool {
+		return less(findings[i], findings[j])
+	})
+}
+
+// less orders findings by position, then rule and message.
+func less(a, b Finding) bool {
+	if a.File != b.File {
+		return a.File < b.File
+	}
+
+	if a.Line != b.Line {
+		return a.Line < b.Line
+	}
+
+	if a.Column != b.Column {
+		return a.Column < b.Column
+	}
+
+	if a.Rule != b.Rule {
+		return a.Rule < b.Rule
+	}
+
+	return a.Message < b.Message
+}
+
+// Write prints r as text: added findings with +, removed ones with -, and
+// moved ones with ~ followed by their old and new positions, then a summary.
+func Write(w io.Writer, r Result) error {
+	for _, f := range r.Added {
+		if _, err := fmt.Fprintf(w, "+ %s: %s [%s]\n", position(f), f.Message, f.Rule); err != nil {
+			return err
+		}
+	}
+
+	for _, f := range r.Removed {
+		if _, err := fmt.Fprintf(w, "- %s: %s [%s]\n", position(f), f.Message, f.Rule); err != nil {
+			return err
+		}
+	}
+
+	for _, m := range r.Moved {
+		if _, err := fmt.Fprintf(w, "~ %s -> %s: %s [%s]\n", position(m.Old), position(m.New), m.New.Message, m.New.Rule); err != nil {
+			return err
+		}
+	}
+
+	_, err := fmt.Fprintf(w, "%d added, %d removed, %d moved\n", len(r.Added), len(r.Removed), len(r.Moved))
+
+	return err
+}
+
+func position(f Finding) string {
+	return fmt.Sprintf("%s:%d:%d", f.File, f.Line, f.Column)
+}
@@ -0,0 +1,94 @@
+package reportdiff_test
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/mickamy/pointless/internal/reportdiff"
+)
+
+func TestCompare(t *testing.T) {
+	t.Parallel()
+
+	kept := reportdiff.Finding{Rule: "return-pointer", File: "a.go", Line: 3, Column: 1, Fingerprint: "f1"}
+	moved := reportdiff.Finding{Rule: "value-receiver", File: "a.go", Line: 10, Column: 7, Fingerprint: "f2"}
+	removed := reportdiff.Finding{Rule: "slice-pointer", File: "b.go", Line: 5, Column: 9, Fingerprint: "f3"}
+	added := reportdiff.Finding{Rule: "local-pointer", File: "c.go", Line: 8, Column: 2, Fingerprint: "f4"}
+
+	// Findings without fingerprints are matched by rule, package, symbol, and message
+	legacy := reportdiff.Finding{Rule: "map-pointer-key", Message: "m", Package: "p", File: "d.go", Line: 1, Column: 1}
+
+	movedNew, legacyNew := moved, legacy
+	movedNew.Line, legacyNew.Line = 12, 4
+
+	got := reportdiff.Compare(
+		[]reportdiff.Finding{kept, moved, removed, legacy},
+		[]reportdiff.Finding{added, legacyNew, movedNew, kept},
+	)
+
+	want := reportdiff.Result{
+		Added:   []reportdiff.Finding{added},
+		Removed: []reportdiff.Finding{removed},
+		Moved:   []reportdiff.Move{{Old: moved, New: movedNew}, {Old: legacy, New: legacyNew}},
+	}
+
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Compare() = %+v, want %+v", got, want)
+	}
+
+	if !got.Changed() {
+		t.Error("Changed() = false, want true")
+	}
+}
+
+func TestCompareDuplicates(t *testing.T) {
+	t.Parallel()
+
+	// Identical findings are matched one to one
+	f := reportdiff.Finding{Rule: "return-pointer", File: "a.go", Line: 3, Column: 1, Fingerprint: "f1"}
+
+	got := reportdiff.Compare([]reportdiff.Finding{f}, []reportdiff.Finding{f, f})
+	if len(got.Added) != 1 || len(got.Removed) != 0 || len(got.Moved) != 0 {
+		t.Errorf("Compare() = %+v, want one added finding", got)
+	}
+
+	if got := reportdiff.Compare([]reportdiff.Finding{f}, []reportdiff.Finding{f}); got.Changed() {
+		t.Errorf("Compare() of identical reports = %+v, want no change", got)
+	}
+}
+
+func TestReadWrite(t *testing.T) {
+	t.Parallel()
+
+	report := `[
+  {"rule": "return-pointer", "message": "consider returning value", "severity": "warning",
+   "file": "a.go", "line": 3, "column": 1, "package": "example.com/a", "fingerprint": "f1",
+   "tool_version": "v1.2.0"}
+]`
+
+	older, err := reportdiff.Read(strings.NewReader("[]"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	newer, err := reportdiff.Read(strings.NewReader(report))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+	if err := reportdiff.Write(&buf, reportdiff.Compare(older, newer)); err != nil {
+		t.Fatal(err)
+	}
+
+	want := "+ a.go:3:1: consider returning value [return-pointer]\n1 added, 0 removed, 0 moved\n"
+	if buf.String() != want {
+		t.Errorf("Write() = %q, want %q", buf.String(), want)
+	}
+
+	if _, err := reportdiff.Read(strings.NewReader("{")); err == nil {
+		t.Error("Read() of invalid JSON succeeded, want an error")
+	}
+}
@@ -0,0 +1,132 @@
+// Package rules defines the stable identifiers of the pointless checks and
+// their built-in documentation.
+package rules
+
+import (
+	"embed"
+	"strings"
+
+	"github.com/mickamy/pointless/internal/severity"
+)
+
+// Rule identifiers. They are stable and referenced from diagnostics, configs,
+// and documentation.
+const (
+	ReturnPointer      = "return-pointer"
+	ReturnPointerError = "return-pointer-error"
+	EmbeddedPointer    = "embedded-pointer"
+	SlicePointer       = "slice-pointer"
+	SortPointerSlice   = "sort-pointer-slice"
+	SliceConversion    = "slice-conversion"
+	ValueReceiver      = "value-receiver"
+	EmptyReceiver      = "empty-receiver"
+	ReferencePointer   = "reference-pointer"
+	LocalPointer       = "local-pointer"
+	OptionsPointer     = "options-pointer"
+	AddressArgument    = "address-argument"
+	MapPointerKey      = "map-pointer-key"
+	PointerRoundTrip   = "pointer-round-trip"
+	ContextValue       = "context-value"
+	ValueBuilder       = "value-builder"
+	WorkerPool         = "worker-pool"
+	AtomicCounter      = "atomic-counter"
+	EnforcedValue      = "enforced-value"
+	StaleNolint        = "stale-nolint"
+	NeedsReview        = "needs-review"
+)
+
+// Rule describes a check.
+type Rule struct {
+	ID      string
+	Summary string
+	// OptIn rules only run when enabled in the config or with -enable.
+	OptIn bool
+	// Severity, if set, caps the severity of the findings of advisory rules,
+	// unless the config sets the severity of the rule.
+	Severity string
+}
+
+var all = []Rule{
+	{ID: ReturnPointer, Summary: "functions returning *T for small structs that never return nil"},
+	{ID: SlicePointer, Summary: "[]*T slices of small structs whose elements are never nil"},
+	{ID: ValueReceiver, Summary: "pointer receivers on small types that are never mutated"},
+	{ID: EmptyReceiver, Summary: "pointer receivers on zero-field marker types"},
+	{ID: ReferencePointer, Summary: "pointers to maps, slices, channels, and functions"},
+	{ID: LocalPointer, Summary: "local var p *T only initialized with &T{...} and dereferenced"},
+	{ID: OptionsPointer, Summary: "constructors taking *Options structs they only read"},
+	{ID: AddressArgument, Summary: "unexported functions only reading a *T parameter that every caller passes &x to"},
+	{ID: MapPointerKey, Summary: "map[*T]V keys of small comparable structs, which compare by identity"},
+	{ID: PointerRoundTrip, Summary: "p := &v aliases only used like v, and *&x copies"},
+	{ID: ContextValue, Summary: "small struct pointers stored with context.WithValue", OptIn: true},
+	{ID: ValueBuilder, Summary: "builder methods returning their pointer receiver for chaining", OptIn: true},
+	{ID: ReturnPointerError, Summary: "(*T, error) results of small structs that are only nil along with an error", OptIn: true},
+	{ID: EmbeddedPointer, Summary: "embedded *T fields of small structs that are never nil", OptIn: true},
+	{ID: SliceConversion, Summary: "helper functions converting between []T and []*T of small structs", OptIn: true},
+	{ID: SortPointerSlice, Summary: "local []*T slices of small structs filled with value addresses only to sort or iterate", OptIn: true},
+	{ID: EnforcedValue, Summary: "*T uses of types annotated with //pointless:enforce"},
+	{ID: WorkerPool, Summary: "chan *T job channels and []*T work queues of small structs feeding goroutines", OptIn: true},
+	{ID: AtomicCounter, Summary: "pointer-receiver methods only updating integer counters, which sync/atomic types could hold", OptIn: true, Severity: severity.Info},
+	{ID: StaleNolint, Summary: "nolint comments whose until= date has passed"},
+	{ID: NeedsReview, Summary: "pointers the heuristics cannot analyze, reported with -strict", OptIn: true},
+}
+
+//go:embed docs/*.md
+var docs embed.FS
+
+// docsURL is where the rule documentation is published.
+const docsURL = "https://github.com/mickamy/pointless/blob/main/internal/rules/docs/"
+
+// All returns all rules in documentation order.
+func All() []Rule {
+	return append([]Rule(nil), all...)
+}
+
+// Lookup returns the rule with the given ID.
+func Lookup(id string) (Rule, bool) {
+	for _, r := range all {
+		if r.ID == id {
+			return r, true
+		}
+	}
+
+	return Rule{}, false
+}
+
+// URL returns the address of the published documentation of the rule with the
+// given ID, or "" if there is no such rule.
+func URL(id string) string {
+	return URLAt("", id)
+}
+
+// URLAt is like URL, but for documentation published under base: base with
+// {rule} replaced by the ID if it contains it, or followed by the ID
+// otherwise. An empty base is the published documentation.
+func URLAt(base, id string) string {
+	if _, ok := Lookup(id); !ok {
+		return ""
+	}
+
+	if base == "" {
+		return docsURL + id + ".md"
+	}
+
+	if strings.Contains(base, "{rule}") {
+		return strings.ReplaceAll(base, "{rule}", id)
+	}
+
+	return base + id
+}
+
+// Doc returns the long-form documentation of the rule with the given ID.
+func Doc(id string) (string, bool) {
+	if _, ok := Lookup(id); !ok {
+		return "", false
+	}
+
+	data, err := docs.ReadFile("docs/" + id + ".md")
+	if err != nil {
+		return "", false
+	}
+
+	return strings.TrimSpace(string(data)) + "\n", true
+}
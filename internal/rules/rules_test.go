@@ -0,0 +1,59 @@
+package rules_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/mickamy/pointless/internal/rules"
+)
+
+func TestEveryRuleHasDoc(t *testing.T) {
+	t.Parallel()
+
+	for _, r := range rules.All() {
+		doc, ok := rules.Doc(r.ID)
+		if !ok {
+			t.Errorf("rule %s has no documentation", r.ID)
+
+			continue
+		}
+
+		if !strings.HasPrefix(doc, "# "+r.ID+"\n") {
+			t.Errorf("documentation of %s does not start with its title", r.ID)
+		}
+	}
+}
+
+func TestURL(t *testing.T) {
+	t.Parallel()
+
+	if got := rules.URL(rules.SlicePointer); !strings.HasSuffix(got, "/docs/slice-pointer.md") {
+		t.Errorf("URL(%q) = %q, want a link to its documentation", rules.SlicePointer, got)
+	}
+
+	if got := rules.URL("no-such-rule"); got != "" {
+		t.Errorf("URL of an unknown rule = %q, want empty", got)
+	}
+}
+
+func TestURLAt(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		base, want string
+	}{
+		{base: "", want: rules.URL(rules.SlicePointer)},
+		{base: "https://wiki.example.com/go/pointless#", want: "https://wiki.example.com/go/pointless#slice-pointer"},
+		{base: "https://wiki.example.com/go/{rule}.html", want: "https://wiki.example.com/go/slice-pointer.html"},
+	}
+
+	for _, tt := range tests {
+		if got := rules.URLAt(tt.base, rules.SlicePointer); got != tt.want {
+			t.Errorf("URLAt(%q, %q) = %q, want %q", tt.base, rules.SlicePointer, got, tt.want)
+		}
+	}
+
+	if got := rules.URLAt("https://wiki.example.com/", "no-such-rule"); got != "" {
+		t.Errorf("URLAt of an unknown rule = %q, want empty", got)
+	}
+}
@@ -0,0 +1,71 @@
+package runner
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"go/token"
+	"os"
+	"strings"
+)
+
+// Fingerprint returns the content-based fingerprint of a finding: a hash of its
+// rule, package, enclosing symbol, and whitespace-normalized source snippet.
+// Unlike positions, it survives line-number drift and file renames. n tells
+// apart otherwise identical findings, counting from 0 in position order.
+func Fingerprint(rule, pkg, symbol, snippet string, n int) string {
+	h := sha256.New()
+	for _, part := range []string{rule, pkg, symbol, normalizeSnippet(snippet)} {
+		h.Write([]byte(part))
+		h.Write([]byte{0})
+	}
+
+	for ; n > 0; n /= 256 {
+		h.Write([]byte{byte(n)})
+	}
+
+	return hex.EncodeToString(h.Sum(nil)[:16])
+}
+
+// normalizeSnippet collapses all whitespace, so reindenting or reflowing code
+// does not change fingerprints.
+func normalizeSnippet(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
+
+// assignFingerprints sets the fingerprints of findings, which are sorted by
+// position.
+func assignFingerprints(findings []Finding) {
+	seen := make(map[string]int)
+
+	for i := range findings {
+		f := &findings[i]
+		key := f.Rule + "\x00" + f.Package + "\x00" + f.Symbol + "\x00" + normalizeSnippet(f.snippet)
+		f.Fingerprint = Fingerprint(f.Rule, f.Package, f.Symbol, f.snippet, seen[key])
+		seen[key]++
+	}
+}
+
+// sources caches the lines of files by name for snippet extraction.
+type sources map[string][]string
+
+// snippet returns the source lines from pos to end (or just pos's line), or ""
+// if the file cannot be read.
+func (s sources) snippet(pos, end token.Position) string {
+	lines, ok := s[pos.Filename]
+	if !ok {
+		src, _ := os.ReadFile(pos.Filename) //nolint:gosec // G304: the file was just analyzed
+		lines = strings.Split(string(src), "\n")
+		s[pos.Filename] = lines
+	}
+
+	lastLine := pos.Line
+	if end.IsValid() && end.Filename == pos.Filename && end.Line > lastLine {
+		lastLine = end.Line
+	}
+
+	if pos.Line < 1 || lastLine > len(lines) {
+		return ""
+	}
+
+	return strings.Join(lines[pos.Line-1:lastLine], "\n")
+}
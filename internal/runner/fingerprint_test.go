@@ -0,0 +1,29 @@
+package runner_test
+
+import (
+	"testing"
+
+	"github.com/mickamy/pointless/internal/runner"
+)
+
+func TestFingerprint(t *testing.T) {
+	t.Parallel()
+
+	base := runner.Fingerprint("return-pointer", "example.com/a", "example.com/a.NewUser", "func NewUser() *User {", 0)
+
+	if got := runner.Fingerprint("return-pointer", "example.com/a", "example.com/a.NewUser", "  func NewUser()\t*User  {", 0); got != base {
+		t.Errorf("reformatting the snippet changed the fingerprint: %s != %s", got, base)
+	}
+
+	for name, got := range map[string]string{
+		"rule":       runner.Fingerprint("slice-pointer", "example.com/a", "example.com/a.NewUser", "func NewUser() *User {", 0),
+		"package":    runner.Fingerprint("return-pointer", "example.com/b", "example.com/a.NewUser", "func NewUser() *User {", 0),
+		"symbol":     runner.Fingerprint("return-pointer", "example.com/a", "example.com/a.NewAdmin", "func NewUser() *User {", 0),
+		"snippet":    runner.Fingerprint("return-pointer", "example.com/a", "example.com/a.NewUser", "func NewUser() *Admin {", 0),
+		"occurrence": runner.Fingerprint("return-pointer", "example.com/a", "example.com/a.NewUser", "func NewUser() *User {", 1),
+	} {
+		if got == base {
+			t.Errorf("changing the %s did not change the fingerprint", name)
+		}
+	}
+}
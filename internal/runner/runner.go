@@ -0,0 +1,331 @@
+// Package runner runs the pointless analyzer over packages loaded with
+// go/packages and returns structured findings instead of printing them.
+package runner
+
+import (
+	"errors"
+	"fmt"
+	"go/ast"
+	"go/token"
+	"sort"
+	"strings"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/analysis/checker"
+	"golang.org/x/tools/go/packages"
+
+	"github.com/mickamy/pointless/internal/analyzer"
+	"github.com/mickamy/pointless/internal/editapply"
+	"github.com/mickamy/pointless/internal/fixsafety"
+	"github.com/mickamy/pointless/internal/severity"
+	"github.com/mickamy/pointless/internal/symbol"
+)
+
+// Finding is a single diagnostic reported by the analyzer.
+type Finding struct {
+	// Position is the resolved source position of the diagnostic.
+	Position token.Position
+	// Message is the diagnostic message, without its severity tag.
+	Message string
+	// Severity is the configured severity of the finding (see internal/severity).
+	Severity string
+	// Rule is the ID of the rule that produced the finding (see internal/rules).
+	Rule string
+	// Package is the import path of the package the finding belongs to.
+	Package string
+	// Symbol is the package-path-qualified enclosing symbol (e.g. "example.com/pkg.(*T).M"),
+	// or "" if the finding is outside of a function or variable declaration.
+	Symbol string
+	// End is the resolved end position of the diagnostic, if known.
+	End token.Position
+	// Related holds resolved related locations.
+	Related []Related
+	// Fingerprint identifies the finding independently of its line and file
+	// name (see Fingerprint).
+	Fingerprint string
+	// Fixes holds the suggested fixes with resolved edits.
+	Fixes []Fix
+	// Diagnostic is the underlying analysis diagnostic.
+	Diagnostic analysis.Diagnostic
+
+	// snippet is the normalized source of the reported lines.
+	snippet string
+}
+
+// Related is a location related to a finding.
+type Related struct {
+	Position token.Position
+	Message  string
+}
+
+// Fix is a suggested fix whose edits are resolved to file offsets.
+type Fix struct {
+	// Message describes the fix, without its safety tag.
+	Message string
+	// Safety is how safe the fix is to apply (see internal/fixsafety), and
+	// Evidence the reasons the check gave for it.
+	Safety   string
+	Evidence []string
+	Edits    []Edit
+}
+
+// Edit replaces the bytes [Offset, End) of Filename with NewText.
+type Edit struct {
+	Filename string
+	Offset   int
+	End      int
+	NewText  []byte
+}
+
+// LoadMode is the go/packages load mode required by Check.
+const LoadMode = packages.LoadAllSyntax
+
+// Load loads the packages matching patterns and their tests with the mode
+// required by Check, as the analysis driver does by default.
+func Load(patterns ...string) ([]*packages.Package, error) {
+	return LoadDir("", patterns...)
+}
+
+// LoadDir is like Load, but resolves patterns in dir ("" for the current directory).
+func LoadDir(dir string, patterns ...string) ([]*packages.Package, error) {
+	return LoadTests(dir, true, patterns...)
+}
+
+// LoadTests is like LoadDir, but only loads the tests of the packages if
+// tests is set, as the -test flag of the analysis driver does.
+func LoadTests(dir string, tests bool, patterns ...string) ([]*packages.Package, error) {
+	pkgs, err := packages.Load(&packages.Config{Mode: LoadMode | packages.NeedForTest, Dir: dir, Tests: tests}, patterns...)
+	if err != nil {
+		return nil, fmt.Errorf("loading packages: %w", err)
+	}
+
+	var errs []error
+
+	packages.Visit(pkgs, nil, func(pkg *packages.Package) {
+		for _, e := range pkg.Errors {
+			errs = append(errs, e)
+		}
+	})
+
+	if len(errs) > 0 {
+		return nil, fmt.Errorf("loading packages: %w", errors.Join(errs...))
+	}
+
+	return withoutTestedVariants(pkgs), nil
+}
+
+// withoutTestedVariants drops the packages whose test variant, which adds the
+// in-package test files to the same files, is in pkgs too, along with the
+// generated test mains, so that every file is analyzed and reported once.
+func withoutTestedVariants(pkgs []*packages.Package) []*packages.Package {
+	tested := make(map[string]bool)
+
+	for _, pkg := range pkgs {
+		if pkg.ForTest != "" && pkg.PkgPath == pkg.ForTest {
+			tested[pkg.PkgPath] = true
+		}
+	}
+
+	result := make([]*packages.Package, 0, len(pkgs))
+
+	for _, pkg := range pkgs {
+		switch {
+		case pkg.ForTest == "" && tested[pkg.PkgPath]:
+		case pkg.Name == "main" && strings.HasSuffix(pkg.ID, ".test"):
+		default:
+			result = append(result, pkg)
+		}
+	}
+
+	return result
+}
+
+// Files returns the Go files the analyzer sees in the packages matching
+// patterns and in their tests, sorted and without duplicates, without
+// type-checking anything.
+func Files(patterns ...string) ([]string, error) {
+	pkgs, err := packages.Load(&packages.Config{Mode: packages.NeedName | packages.NeedFiles | packages.NeedCompiledGoFiles, Tests: true}, patterns...)
+	if err != nil {
+		return nil, fmt.Errorf("loading packages: %w", err)
+	}
+
+	seen := make(map[string]bool)
+
+	var files []string
+
+	for _, pkg := range pkgs {
+		for _, e := range pkg.Errors {
+			return nil, fmt.Errorf("loading packages: %w", e)
+		}
+
+		for _, f := range pkg.CompiledGoFiles {
+			if !seen[f] {
+				seen[f] = true
+				files = append(files, f)
+			}
+		}
+	}
+
+	sort.Strings(files)
+
+	return files, nil
+}
+
+// Check runs a (pointless) analyzer on pkgs and returns the findings sorted by position.
+func Check(a *analysis.Analyzer, pkgs []*packages.Package) ([]Finding, error) {
+	findings, _, err := CheckWithMetrics(a, pkgs)
+
+	return findings, err
+}
+
+// CheckWithMetrics is like Check, but also returns the analyzer's metrics
+// merged across the packages.
+func CheckWithMetrics(a *analysis.Analyzer, pkgs []*packages.Package) ([]Finding, analyzer.Metrics, error) {
+	var metrics analyzer.Metrics
+
+	graph, err := checker.Analyze([]*analysis.Analyzer{a}, pkgs, nil)
+	if err != nil {
+		return nil, metrics, fmt.Errorf("running analyzer: %w", err)
+	}
+
+	var findings []Finding
+
+	src := make(sources)
+
+	for _, act := range graph.Roots {
+		if act.Err != nil {
+			return nil, metrics, fmt.Errorf("analyzing %s: %w", act.Package.PkgPath, act.Err)
+		}
+
+		if m, ok := act.Result.(*analyzer.Metrics); ok {
+			metrics.Add(m)
+		}
+
+		for _, d := range act.Diagnostics {
+			findings = append(findings, newFinding(act.Package, d, src))
+		}
+	}
+
+	sort.SliceStable(findings, func(i, j int) bool {
+		a, b := findings[i].Position, findings[j].Position
+		if a.Filename != b.Filename {
+			return a.Filename < b.Filename
+		}
+
+		if a.Line != b.Line {
+			return a.Line < b.Line
+		}
+
+		return a.Column < b.Column
+	})
+
+	assignFingerprints(findings)
+
+	return findings, metrics, nil
+}
+
+// Verdicts runs the analyzer returned by analyzer.NewVerdict for opts on
+// pkgs and returns the verdicts of their struct types, sorted by type.
+func Verdicts(opts analyzer.Options, pkgs []*packages.Package) ([]analyzer.Verdict, error) {
+	graph, err := checker.Analyze([]*analysis.Analyzer{analyzer.NewVerdict(opts)}, pkgs, nil)
+	if err != nil {
+		return nil, fmt.Errorf("running analyzer: %w", err)
+	}
+
+	var verdicts []analyzer.Verdict
+
+	for _, act := range graph.Roots {
+		if act.Err != nil {
+			return nil, fmt.Errorf("analyzing %s: %w", act.Package.PkgPath, act.Err)
+		}
+
+		if v, ok := act.Result.([]analyzer.Verdict); ok {
+			verdicts = append(verdicts, v...)
+		}
+	}
+
+	sort.SliceStable(verdicts, func(i, j int) bool {
+		return verdicts[i].Type < verdicts[j].Type
+	})
+
+	return verdicts, nil
+}
+
+// Run loads the packages matching patterns and checks them with the analyzer
+// configured by opts.
+func Run(opts analyzer.Options, patterns ...string) ([]Finding, error) {
+	pkgs, err := Load(patterns...)
+	if err != nil {
+		return nil, err
+	}
+
+	return Check(analyzer.New(opts), pkgs)
+}
+
+func newFinding(pkg *packages.Package, d analysis.Diagnostic, src sources) Finding {
+	sev, msg := severity.Parse(d.Message)
+
+	f := Finding{
+		Position:   pkg.Fset.Position(d.Pos),
+		Message:    msg,
+		Severity:   sev,
+		Rule:       d.Category,
+		Package:    pkg.PkgPath,
+		Diagnostic: d,
+	}
+
+	if d.End.IsValid() {
+		f.End = pkg.Fset.Position(d.End)
+	}
+
+	// Snippets come from the compiled file, not the one a //line directive
+	// names: that is a generator input, if it exists at all
+	var end token.Position
+	if d.End.IsValid() {
+		end = pkg.Fset.PositionFor(d.End, false)
+	}
+
+	f.snippet = src.snippet(pkg.Fset.PositionFor(d.Pos, false), end)
+
+	for _, sf := range d.SuggestedFixes {
+		msg, evidence := fixsafety.Parse(sf.Message)
+		fix := Fix{Message: msg, Safety: evidence.Level, Evidence: evidence.Reasons}
+
+		for _, e := range sf.TextEdits {
+			// The compiled file, not the one a //line directive names
+			tf := pkg.Fset.File(e.Pos)
+			if tf == nil {
+				continue
+			}
+
+			for _, fe := range editapply.FromTextEdits(tf, []analysis.TextEdit{e}) {
+				fix.Edits = append(fix.Edits, Edit{Filename: tf.Name(), Offset: fe.Offset, End: fe.End, NewText: fe.NewText})
+			}
+		}
+
+		f.Fixes = append(f.Fixes, fix)
+	}
+
+	for _, r := range d.Related {
+		f.Related = append(f.Related, Related{Position: pkg.Fset.Position(r.Pos), Message: r.Message})
+	}
+
+	if file := fileAt(pkg, d.Pos); file != nil {
+		if name := symbol.Enclosing(file, d.Pos); name != "" {
+			f.Symbol = pkg.PkgPath + "." + name
+		}
+	}
+
+	return f
+}
+
+// fileAt returns the syntax tree of pkg containing pos.
+func fileAt(pkg *packages.Package, pos token.Pos) *ast.File {
+	for _, file := range pkg.Syntax {
+		if file.FileStart <= pos && pos <= file.FileEnd {
+			return file
+		}
+	}
+
+	return nil
+}
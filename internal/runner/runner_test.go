@@ -0,0 +1,127 @@
+package runner_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/mickamy/pointless/internal/analyzer"
+	"github.com/mickamy/pointless/internal/format"
+	"github.com/mickamy/pointless/internal/runner"
+)
+
+func writeFiles(t *testing.T, dir string, files map[string]string) {
+	t.Helper()
+
+	for name, content := range files {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0o750); err != nil {
+			t.Fatal(err)
+		}
+
+		if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestFingerprintLineDirectives(t *testing.T) {
+	t.Parallel()
+
+	const generated = "func NewSmall() *Small { return &Small{} }"
+
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		"go.mod":   "module example.com/m\n\ngo 1.24\n",
+		"small.go": "package m\n\ntype Small struct{ A, B int64 }\n",
+		"gen.go":   "package m\n\n//line small.tmpl:1\n" + generated + "\n",
+		// The generator input, whose lines must not make the fingerprints
+		"small.tmpl": "{{define \"constructor\"}}\n",
+	})
+
+	pkgs, err := runner.LoadDir(dir, "./...")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	findings, err := runner.Check(analyzer.New(analyzer.Options{Threshold: analyzer.DefaultThreshold}), pkgs)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(findings) != 1 {
+		t.Fatalf("got %d findings, want 1: %+v", len(findings), findings)
+	}
+
+	f := findings[0]
+	if filepath.Base(f.Position.Filename) != "small.tmpl" {
+		t.Errorf("Position = %s, want the position in small.tmpl", f.Position)
+	}
+
+	if want := runner.Fingerprint(f.Rule, f.Package, f.Symbol, generated, 0); f.Fingerprint != want {
+		t.Errorf("Fingerprint = %s, want %s, from the line of gen.go", f.Fingerprint, want)
+	}
+}
+
+func TestLoadTests(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		"go.mod":      "module example.com/m\n\ngo 1.24\n",
+		"m.go":        "package m\n\ntype Small struct{ A, B int64 }\n\nfunc NewSmall() *Small { return &Small{} }\n",
+		"m_test.go":   "package m\n\nfunc newFixture() *Small { return &Small{} }\n",
+		"ext_test.go": "package m_test\n\nimport \"example.com/m\"\n\nfunc newExternal() *m.Small { return &m.Small{} }\n",
+	})
+
+	tests := []struct {
+		name  string
+		tests bool
+		want  []string
+	}{
+		{name: "with tests", tests: true, want: []string{"ext_test.go", "m.go", "m_test.go"}},
+		{name: "without tests", tests: false, want: []string{"m.go"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			pkgs, err := runner.LoadTests(dir, tt.tests, "./...")
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			findings, err := runner.Check(analyzer.New(analyzer.Options{Threshold: analyzer.DefaultThreshold}), pkgs)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var buf bytes.Buffer
+			if err := format.Write(&buf, "json", findings, format.Options{}); err != nil {
+				t.Fatal(err)
+			}
+
+			var report []struct {
+				File string `json:"file"`
+			}
+
+			if err := json.Unmarshal(buf.Bytes(), &report); err != nil {
+				t.Fatal(err)
+			}
+
+			// Each finding once, although m.go is in both m and its test variant
+			var files []string
+			for _, f := range report {
+				files = append(files, filepath.Base(f.File))
+			}
+
+			if !slices.Equal(files, tt.want) {
+				t.Errorf("findings in %q, want %q:\n%s", files, tt.want, buf.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,83 @@
+// Package severity defines the severities pointless findings can be reported
+// with, and how they are tagged in diagnostic messages.
+package severity
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Severities, from most to least severe.
+const (
+	Warning = "warning"
+	Info    = "info"
+	Hint    = "hint"
+)
+
+// Default is the severity of rules without a configured one.
+const Default = Warning
+
+// Names returns all severities, from most to least severe.
+func Names() []string {
+	return []string{Warning, Info, Hint}
+}
+
+// Validate returns an error if s is not a severity.
+func Validate(s string) error {
+	for _, name := range Names() {
+		if s == name {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("unknown severity %q (expected one of %s)", s, strings.Join(Names(), ", "))
+}
+
+// Tag prefixes msg with "[sev] " so that editors and other consumers of plain
+// diagnostics can tell severities apart. Warnings, the default, are not tagged.
+func Tag(sev, msg string) string {
+	if sev == "" || sev == Warning {
+		return msg
+	}
+
+	return "[" + sev + "] " + msg
+}
+
+// Parse splits a message produced by Tag into its severity and the untagged
+// message.
+func Parse(msg string) (string, string) {
+	for _, sev := range Names() {
+		if rest, ok := strings.CutPrefix(msg, "["+sev+"] "); ok {
+			return sev, rest
+		}
+	}
+
+	return Warning, msg
+}
+
+// LeastSevere returns the least severe of sevs, or Default if there are none.
+func LeastSevere(sevs ...string) string {
+	names := Names()
+	for i := len(names) - 1; i >= 0; i-- {
+		for _, sev := range sevs {
+			if sev == names[i] {
+				return names[i]
+			}
+		}
+	}
+
+	return Default
+}
+
+// MostSevere returns the most severe of sevs, or Default if there are none.
+func MostSevere(sevs ...string) string {
+	for _, name := range Names() {
+		for _, sev := range sevs {
+			if sev == name {
+				return name
+			}
+		}
+	}
+
+	return Default
+}
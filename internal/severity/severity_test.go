@@ -0,0 +1,48 @@
+package severity_test
+
+import (
+	"testing"
+
+	"github.com/mickamy/pointless/internal/severity"
+)
+
+func TestTagParse(t *testing.T) {
+	t.Parallel()
+
+	for _, sev := range severity.Names() {
+		msg := severity.Tag(sev, "consider using value receiver")
+
+		gotSev, gotMsg := severity.Parse(msg)
+		if gotSev != sev || gotMsg != "consider using value receiver" {
+			t.Errorf("Parse(%q) = %q, %q", msg, gotSev, gotMsg)
+		}
+	}
+
+	if got := severity.Tag(severity.Warning, "msg"); got != "msg" {
+		t.Errorf("warnings must not be tagged, got %q", got)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	t.Parallel()
+
+	if err := severity.Validate(severity.Hint); err != nil {
+		t.Error(err)
+	}
+
+	if err := severity.Validate("error"); err == nil {
+		t.Error("expected an error for an unknown severity")
+	}
+}
+
+func TestLeastSevere(t *testing.T) {
+	t.Parallel()
+
+	if got := severity.LeastSevere(severity.Warning, severity.Hint, severity.Info); got != severity.Hint {
+		t.Errorf("LeastSevere() = %q, want %q", got, severity.Hint)
+	}
+
+	if got := severity.LeastSevere(); got != severity.Default {
+		t.Errorf("LeastSevere() of nothing = %q, want %q", got, severity.Default)
+	}
+}
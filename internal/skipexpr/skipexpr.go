@@ -0,0 +1,327 @@
+// Package skipexpr implements the skip-if expressions of the config, which
+// exempt types from all checks by predicates over the type, as in
+//
+//	type.hasFieldOfType("sync.Mutex") || type.name.matches("DTO$")
+//
+// Predicates are combined with !, &&, ||, and parentheses. They are
+//   - type.name.matches(re): the type name matches the regular expression;
+//   - type.pkg.matches(re): the import path of its package matches it;
+//   - type.hasFieldOfType(t): the struct has a field of type t, spelled with
+//     the package name ("sync.Mutex") or path ("example.com/pkg.T");
+//   - type.hasMethod(name): *T has the method.
+package skipexpr
+
+import (
+	"errors"
+	"fmt"
+	"go/types"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// Expr is a parsed skip-if expression.
+type Expr struct {
+	src  string
+	root node
+}
+
+// Parse parses a skip-if expression.
+func Parse(src string) (*Expr, error) {
+	toks, err := lex(src)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &parser{toks: toks}
+
+	root, err := p.or()
+	if err != nil {
+		return nil, err
+	}
+
+	if p.pos < len(p.toks) {
+		return nil, fmt.Errorf("unexpected %s", p.toks[p.pos])
+	}
+
+	return &Expr{src: src, root: root}, nil
+}
+
+// String returns the source of the expression.
+func (e *Expr) String() string {
+	return e.src
+}
+
+// Match reports whether t, or the type t points to, satisfies the expression.
+func (e *Expr) Match(t types.Type) bool {
+	for {
+		ptr, ok := types.Unalias(t).(*types.Pointer)
+		if !ok {
+			break
+		}
+
+		t = ptr.Elem()
+	}
+
+	return e.root.eval(types.Unalias(t))
+}
+
+type node interface {
+	eval(t types.Type) bool
+}
+
+type not struct{ x node }
+
+func (n not) eval(t types.Type) bool { return !n.x.eval(t) }
+
+type and struct{ x, y node }
+
+func (n and) eval(t types.Type) bool { return n.x.eval(t) && n.y.eval(t) }
+
+type or struct{ x, y node }
+
+func (n or) eval(t types.Type) bool { return n.x.eval(t) || n.y.eval(t) }
+
+// predicate is a call of one of the predicates with its argument.
+type predicate func(t types.Type) bool
+
+func (p predicate) eval(t types.Type) bool { return p(t) }
+
+// predicates maps predicate names to constructors taking their argument.
+var predicates = map[string]func(arg string) (predicate, error){
+	"type.name.matches": func(arg string) (predicate, error) {
+		re, err := regexp.Compile(arg)
+		if err != nil {
+			return nil, fmt.Errorf("type.name.matches: %w", err)
+		}
+
+		return func(t types.Type) bool {
+			named, ok := t.(*types.Named)
+
+			return ok && re.MatchString(named.Obj().Name())
+		}, nil
+	},
+	"type.pkg.matches": func(arg string) (predicate, error) {
+		re, err := regexp.Compile(arg)
+		if err != nil {
+			return nil, fmt.Errorf("type.pkg.matches: %w", err)
+		}
+
+		return func(t types.Type) bool {
+			named, ok := t.(*types.Named)
+
+			return ok && named.Obj().Pkg() != nil && re.MatchString(named.Obj().Pkg().Path())
+		}, nil
+	},
+	"type.hasFieldOfType": func(arg string) (predicate, error) {
+		return func(t types.Type) bool { return hasFieldOfType(t, arg) }, nil
+	},
+	"type.hasMethod": func(arg string) (predicate, error) {
+		return func(t types.Type) bool { return hasMethod(t, arg) }, nil
+	},
+}
+
+func hasFieldOfType(t types.Type, name string) bool {
+	s, ok := t.Underlying().(*types.Struct)
+	if !ok {
+		return false
+	}
+
+	byName := func(p *types.Package) string { return p.Name() }
+
+	for i := range s.NumFields() {
+		ft := s.Field(i).Type()
+		if types.TypeString(ft, byName) == name || types.TypeString(ft, nil) == name {
+			return true
+		}
+	}
+
+	return false
+}
+
+func hasMethod(t types.Type, name string) bool {
+	if _, isNamed := t.(*types.Named); !isNamed {
+		return false
+	}
+
+	mset := types.NewMethodSet(types.NewPointer(t))
+	for i := range mset.Len() {
+		if mset.At(i).Obj().Name() == name {
+			return true
+		}
+	}
+
+	return false
+}
+
+// token is a lexical token: an operator, a parenthesis, a name, or a quoted
+// string (with its quotes).
+type token string
+
+func (t token) String() string {
+	return strconv.Quote(string(t))
+}
+
+func lex(src string) ([]token, error) {
+	var toks []token
+
+	for i := 0; i < len(src); {
+		c := src[i]
+
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			i++
+		case c == '(' || c == ')' || c == '!':
+			toks = append(toks, token(src[i:i+1]))
+			i++
+		case strings.HasPrefix(src[i:], "&&"), strings.HasPrefix(src[i:], "||"):
+			toks = append(toks, token(src[i:i+2]))
+			i += 2
+		case c == '"' || c == '`':
+			end := i + 1
+			for end < len(src) && src[end] != c {
+				if src[end] == '\\' && c == '"' {
+					end++
+				}
+
+				end++
+			}
+
+			if end >= len(src) {
+				return nil, errors.New("unterminated string")
+			}
+
+			toks = append(toks, token(src[i:end+1]))
+			i = end + 1
+		case isNameByte(c):
+			end := i
+			for end < len(src) && isNameByte(src[end]) {
+				end++
+			}
+
+			toks = append(toks, token(src[i:end]))
+			i = end
+		default:
+			return nil, fmt.Errorf("unexpected %q", c)
+		}
+	}
+
+	return toks, nil
+}
+
+func isNameByte(c byte) bool {
+	return c == '.' || c == '_' || unicode.IsLetter(rune(c)) || unicode.IsDigit(rune(c))
+}
+
+type parser struct {
+	toks []token
+	pos  int
+}
+
+func (p *parser) peek() token {
+	if p.pos < len(p.toks) {
+		return p.toks[p.pos]
+	}
+
+	return ""
+}
+
+func (p *parser) next() token {
+	t := p.peek()
+	p.pos++
+
+	return t
+}
+
+func (p *parser) or() (node, error) {
+	x, err := p.and()
+	if err != nil {
+		return nil, err
+	}
+
+	for p.peek() == "||" {
+		p.next()
+
+		y, err := p.and()
+		if err != nil {
+			return nil, err
+		}
+
+		x = or{x, y}
+	}
+
+	return x, nil
+}
+
+func (p *parser) and() (node, error) {
+	x, err := p.unary()
+	if err != nil {
+		return nil, err
+	}
+
+	for p.peek() == "&&" {
+		p.next()
+
+		y, err := p.unary()
+		if err != nil {
+			return nil, err
+		}
+
+		x = and{x, y}
+	}
+
+	return x, nil
+}
+
+func (p *parser) unary() (node, error) {
+	switch tok := p.next(); tok {
+	case "":
+		return nil, errors.New("unexpected end of expression")
+	case "!":
+		x, err := p.unary()
+		if err != nil {
+			return nil, err
+		}
+
+		return not{x}, nil
+	case "(":
+		x, err := p.or()
+		if err != nil {
+			return nil, err
+		}
+
+		if p.next() != ")" {
+			return nil, errors.New("missing )")
+		}
+
+		return x, nil
+	default:
+		return p.call(tok)
+	}
+}
+
+// call parses the argument list of the predicate name.
+func (p *parser) call(name token) (node, error) {
+	newPredicate, ok := predicates[string(name)]
+	if !ok {
+		return nil, fmt.Errorf("unknown predicate %s", name)
+	}
+
+	if p.next() != "(" {
+		return nil, fmt.Errorf("missing ( after %s", name)
+	}
+
+	lit := p.next()
+
+	arg, err := strconv.Unquote(string(lit))
+	if err != nil {
+		return nil, fmt.Errorf("%s takes a quoted string, got %s", name, lit)
+	}
+
+	if p.next() != ")" {
+		return nil, fmt.Errorf("missing ) after the argument of %s", name)
+	}
+
+	return newPredicate(arg)
+}
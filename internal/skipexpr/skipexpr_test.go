@@ -0,0 +1,119 @@
+package skipexpr_test
+
+import (
+	"go/ast"
+	"go/importer"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"testing"
+
+	"github.com/mickamy/pointless/internal/skipexpr"
+)
+
+const src = `package store
+
+import "sync"
+
+type Cache struct {
+	mu    sync.Mutex
+	items map[string]string
+}
+
+type UserDTO struct {
+	Name string
+}
+
+func (*UserDTO) Validate() error { return nil }
+
+type Point struct {
+	X, Y int
+}
+`
+
+func check(t *testing.T) *types.Package {
+	t.Helper()
+
+	fset := token.NewFileSet()
+
+	f, err := parser.ParseFile(fset, "store.go", src, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	conf := types.Config{Importer: importer.ForCompiler(fset, "source", nil)}
+
+	pkg, err := conf.Check("example.com/store", fset, []*ast.File{f}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return pkg
+}
+
+func TestMatch(t *testing.T) {
+	t.Parallel()
+
+	pkg := check(t)
+
+	tests := []struct {
+		expr string
+		want []string
+	}{
+		{`type.hasFieldOfType("sync.Mutex") || type.name.matches("DTO$")`, []string{"Cache", "UserDTO"}},
+		{`type.hasFieldOfType("map[string]string")`, []string{"Cache"}},
+		{`type.hasMethod("Validate")`, []string{"UserDTO"}},
+		{`type.pkg.matches("^example\\.com/") && !type.name.matches("^(Cache|UserDTO)$")`, []string{"Point"}},
+		{"!(type.name.matches(`^P`) || type.name.matches(`^U`))", []string{"Cache"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			t.Parallel()
+
+			e, err := skipexpr.Parse(tt.expr)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var got []string
+
+			for _, name := range []string{"Cache", "Point", "UserDTO"} {
+				// Pointers match like the type they point to
+				if e.Match(types.NewPointer(pkg.Scope().Lookup(name).Type())) {
+					got = append(got, name)
+				}
+			}
+
+			if len(got) != len(tt.want) {
+				t.Fatalf("Match = %v, want %v", got, tt.want)
+			}
+
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Fatalf("Match = %v, want %v", got, tt.want)
+				}
+			}
+		})
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	t.Parallel()
+
+	for _, expr := range []string{
+		``,
+		`type.name.matches("x"`,
+		`type.size > 3`,
+		`type.name.matches(x)`,
+		`type.name.matches("(")`,
+		`type.hasMethod("A") &&`,
+		`(type.hasMethod("A")`,
+		`type.hasMethod("A") type.hasMethod("B")`,
+		`type.hasMethod("A`,
+	} {
+		if _, err := skipexpr.Parse(expr); err == nil {
+			t.Errorf("Parse(%q) succeeded, want an error", expr)
+		}
+	}
+}
@@ -0,0 +1,55 @@
+package startup
+
+import (
+	"log"
+	"log/slog"
+	"os"
+	"strings"
+
+	"github.com/mickamy/pointless/internal/config"
+	"github.com/mickamy/pointless/internal/logging"
+)
+
+// DebugUsage is the usage of -debug.
+const DebugUsage = "log the config resolution, file exclusions, the decision on each pointer, and timing to stderr"
+
+// SetupLogging installs the logger of the run, writing warnings to stderr, and
+// with -debug, the debug records too. It returns args without -debug.
+// The output of package log, used by the driver, still goes to stderr.
+//
+// The driver has a -debug flag of its own, taking letters (-debug=fpstv) to
+// debug the analysis framework; only a bare -debug, or -debug=true or false,
+// is ours, so the driver's keeps working.
+func SetupLogging(args []string) []string {
+	debug := false
+	rest := make([]string, 0, len(args))
+
+	for _, arg := range args {
+		name, value, hasValue := strings.Cut(strings.TrimLeft(arg, "-"), "=")
+		if !strings.HasPrefix(arg, "-") || name != "debug" || hasValue && value != "true" && value != "false" {
+			rest = append(rest, arg)
+
+			continue
+		}
+
+		debug = !hasValue || value == "true"
+	}
+
+	slog.SetDefault(logging.New(os.Stderr, debug))
+
+	// The driver reports its errors with package log, which SetDefault sends
+	// to the logger at the info level: keep them on stderr
+	log.SetOutput(os.Stderr)
+
+	return rest
+}
+
+// LogConfig logs the config resolved for dir, at the debug level.
+func LogConfig(dir string, cfg config.Config) {
+	file := cfg.File
+	if file == "" {
+		file = "none"
+	}
+
+	slog.Debug("config resolved", "dir", dir, "file", file, "threshold", cfg.Threshold, "exclude", len(cfg.Exclude), "include", len(cfg.Include), "presets", cfg.Presets, "enable", cfg.Enable)
+}
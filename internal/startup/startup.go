@@ -0,0 +1,131 @@
+// Package startup is the startup shared by the pointless commands: logging,
+// the platform and build tags packages are loaded for, and the config, all
+// applied before the analysis driver parses the flags.
+package startup
+
+import (
+	"log/slog"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/mickamy/pointless/internal/config"
+)
+
+// LoadConfig loads the config file, warning about (but tolerating) problems
+// with it, unless -strict-config makes them fatal.
+func LoadConfig() config.Config {
+	cfg, err := config.Load()
+	CheckConfigErrors(err)
+
+	if wd, err := os.Getwd(); err == nil {
+		LogConfig(wd, cfg)
+	}
+
+	return cfg
+}
+
+// LoadWorkspace loads the configs of the modules of the workspace rooted at
+// the working directory, if it is one, warning about problems like LoadConfig.
+func LoadWorkspace() (config.Workspace, bool) {
+	wd, err := os.Getwd()
+	if err != nil {
+		return config.Workspace{}, false
+	}
+
+	path := config.WorkFile(wd)
+	if path == "" {
+		return config.Workspace{}, false
+	}
+
+	ws, err := config.LoadWorkspace(path)
+	CheckConfigErrors(err)
+
+	LogConfig(ws.Dir, ws.Config)
+
+	for i, m := range ws.Modules {
+		if m.OwnConfig {
+			LogConfig(m.Dir, m.Config)
+		} else {
+			ws.Modules[i].Config = ws.Config
+			slog.Debug("module uses the workspace config", "dir", m.Dir)
+		}
+	}
+
+	return ws, true
+}
+
+// HasFlag reports whether args contain the flag with the given name.
+func HasFlag(args []string, flagName string) bool {
+	return slices.ContainsFunc(args, func(arg string) bool {
+		name, _, _ := strings.Cut(strings.TrimLeft(arg, "-"), "=")
+
+		return strings.HasPrefix(arg, "-") && name == flagName
+	})
+}
+
+// FlagArg returns the value of the flag with the given name in args, if any.
+func FlagArg(args []string, flagName string) (string, bool) {
+	for i, arg := range args {
+		name, value, hasValue := strings.Cut(strings.TrimLeft(arg, "-"), "=")
+		if !strings.HasPrefix(arg, "-") || name != flagName {
+			continue
+		}
+
+		if hasValue {
+			return value, true
+		}
+
+		if i+1 < len(args) {
+			return args[i+1], true
+		}
+	}
+
+	return "", false
+}
+
+// WorkspacePatterns expands the relative dir/... patterns into one pattern per
+// module of the workspace below dir, so that they match the packages of every
+// module. Other patterns are kept as they are.
+func WorkspacePatterns(modules []config.Module, patterns []string) []string {
+	wd, err := os.Getwd()
+	if err != nil {
+		return patterns
+	}
+
+	result := make([]string, 0, len(patterns))
+
+	for _, pattern := range patterns {
+		dir, ok := strings.CutSuffix(pattern, "/...")
+		if !ok || !strings.HasPrefix(dir, ".") {
+			result = append(result, pattern)
+
+			continue
+		}
+
+		base := filepath.Join(wd, filepath.FromSlash(dir))
+		expanded := false
+
+		for _, m := range modules {
+			rel, err := filepath.Rel(base, m.Dir)
+			if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+				continue
+			}
+
+			p := filepath.ToSlash(filepath.Join(dir, rel))
+			if p != "." && !strings.HasPrefix(p, "../") {
+				p = "./" + p
+			}
+
+			result = append(result, p+"/...")
+			expanded = true
+		}
+
+		if !expanded {
+			result = append(result, pattern)
+		}
+	}
+
+	return result
+}
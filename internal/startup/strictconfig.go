@@ -0,0 +1,75 @@
+package startup
+
+import (
+	"flag"
+	"log/slog"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/mickamy/pointless/internal/config"
+)
+
+// The -strict-config flag is registered with the driver's flags so that it
+// shows up in its usage, but LoadConfig applies it while loading the config,
+// before the flags are parsed.
+var _ = flag.Bool("strict-config", false, StrictConfigUsage)
+
+// StrictConfigUsage is the usage of -strict-config.
+const StrictConfigUsage = "exit instead of running with defaults if the config file cannot be read or parsed, or has invalid settings"
+
+// strictKinds are the kinds of config problems -strict-config makes fatal.
+// Running without a config file, or ignoring one above the module root, is
+// an ordinary setup: NotFound problems remain warnings.
+var strictKinds = []config.ErrorKind{config.Unreadable, config.ParseError, config.InvalidValue}
+
+// CheckConfigErrors logs the problems found loading the config, and exits if
+// -strict-config is set and one of them is fatal.
+func CheckConfigErrors(err error) {
+	problems := config.Errors(err)
+	if err != nil && len(problems) == 0 {
+		slog.Warn("failed to load config", "err", err)
+
+		return
+	}
+
+	strict := StrictConfig(os.Args[1:]) && config.HasKind(err, strictKinds...)
+
+	for _, p := range problems {
+		attrs := []any{"kind", p.Kind, "err", p.Err}
+		if p.Path != "" {
+			attrs = append(attrs, "file", p.Path)
+		}
+
+		if strict {
+			slog.Error("config problem", attrs...)
+		} else {
+			slog.Warn("config problem", attrs...)
+		}
+	}
+
+	if strict {
+		slog.Error("config problems are fatal with -strict-config")
+		os.Exit(2)
+	}
+}
+
+// StrictConfig reports whether args set -strict-config.
+func StrictConfig(args []string) bool {
+	for _, arg := range args {
+		name, value, hasValue := strings.Cut(strings.TrimLeft(arg, "-"), "=")
+		if !strings.HasPrefix(arg, "-") || name != "strict-config" {
+			continue
+		}
+
+		if !hasValue {
+			return true
+		}
+
+		strict, err := strconv.ParseBool(value)
+
+		return err == nil && strict
+	}
+
+	return false
+}
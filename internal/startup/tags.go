@@ -0,0 +1,39 @@
+package startup
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+	"strings"
+)
+
+// The driver registers -tags itself, as a deprecated flag without effect:
+// SelectTags applies it instead, before any package is loaded.
+// TagsUsage is the usage of -tags.
+const TagsUsage = "comma-separated `list` of build tags to consider satisfied when selecting the files of the packages"
+
+// SelectTags applies the -tags flag in args by adding it to GOFLAGS, which
+// the go command go/packages runs reads, so that files excluded by build
+// constraints contribute neither facts (nil uses, mutations) nor findings.
+// It reports false if GOFLAGS cannot be set.
+func SelectTags(args []string) bool {
+	value, ok := FlagArg(args, "tags")
+	if !ok {
+		return true
+	}
+
+	// Accept the space-separated form the go command used to take
+	tags := strings.Join(strings.FieldsFunc(value, func(r rune) bool { return r == ',' || r == ' ' }), ",")
+
+	// A later -tags in GOFLAGS overrides an earlier one
+	goflags := strings.TrimSpace(os.Getenv("GOFLAGS") + " -tags=" + tags)
+	if err := os.Setenv("GOFLAGS", goflags); err != nil {
+		fmt.Fprintf(os.Stderr, "pointless: -tags: %v\n", err)
+
+		return false
+	}
+
+	slog.Debug("build tags selected", "tags", tags, "GOFLAGS", goflags)
+
+	return true
+}
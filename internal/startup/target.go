@@ -0,0 +1,56 @@
+package startup
+
+import (
+	"flag"
+	"fmt"
+	"log/slog"
+	"os"
+
+	"github.com/mickamy/pointless/internal/target"
+)
+
+// The -target flag is registered with the driver's flags so that it shows up in
+// its usage, but it is applied by SelectTarget before any package is loaded.
+var _ = flag.String("target", "", TargetUsage)
+
+// TargetUsage is the usage of -target.
+const TargetUsage = "analyze for the `goos/goarch` platform, whose sizes apply, instead of the one go env reports"
+
+// SelectTarget applies the -target flag in args by setting GOOS and GOARCH for
+// the go command, which go/packages computes struct sizes with, and warns if
+// the packages are analyzed for another platform than the host. It reports
+// false if the flag is invalid.
+func SelectTarget(args []string) bool {
+	if value, ok := FlagArg(args, "target"); ok {
+		p, err := target.Parse(value)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "pointless: -target: %v\n", err)
+
+			return false
+		}
+
+		if err := os.Setenv("GOOS", p.GOOS); err != nil {
+			fmt.Fprintf(os.Stderr, "pointless: -target: %v\n", err)
+
+			return false
+		}
+
+		if err := os.Setenv("GOARCH", p.GOARCH); err != nil {
+			fmt.Fprintf(os.Stderr, "pointless: -target: %v\n", err)
+
+			return false
+		}
+	}
+
+	p, host, err := target.Env()
+	if err != nil {
+		// Without a go command, packages cannot be loaded either, which reports the problem
+		return true
+	}
+
+	if p != host {
+		slog.Warn("analyzing for another platform than the host: struct sizes are those of the target", "target", p, "host", host)
+	}
+
+	return true
+}
@@ -0,0 +1,101 @@
+package survey
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"text/tabwriter"
+
+	"golang.org/x/tools/go/packages"
+
+	"github.com/mickamy/pointless/internal/analyzer"
+	"github.com/mickamy/pointless/internal/config"
+	"github.com/mickamy/pointless/internal/runner"
+)
+
+// DefaultThresholds are the thresholds, in bytes, calibrate compares by default.
+var DefaultThresholds = []int{32, 64, 128, 256, 512, 1024, 2048, 4096}
+
+// Level is the outcome of a threshold.
+type Level struct {
+	// Threshold is in bytes.
+	Threshold int
+	// Structs counts the struct types no larger than the threshold.
+	Structs int
+	// Findings counts the findings the threshold gives.
+	Findings int
+}
+
+// Calibrate checks pkgs (loaded with runner.LoadMode) with cfg at each of the
+// thresholds, in bytes, and returns their levels by ascending threshold.
+func Calibrate(pkgs []*packages.Package, cfg config.Config, thresholds []int) ([]Level, error) {
+	sizes := StructSizes(pkgs)
+
+	thresholds = append([]int(nil), thresholds...)
+	sort.Ints(thresholds)
+
+	levels := make([]Level, 0, len(thresholds))
+
+	for _, threshold := range thresholds {
+		a := analyzer.New(analyzer.Options{Threshold: threshold, ThresholdUnit: config.UnitBytes, Config: cfg})
+
+		findings, err := runner.Check(a, pkgs)
+		if err != nil {
+			return nil, fmt.Errorf("checking with threshold %d: %w", threshold, err)
+		}
+
+		structs := sort.Search(len(sizes), func(i int) bool { return sizes[i] > int64(threshold) })
+		levels = append(levels, Level{Threshold: threshold, Structs: structs, Findings: len(findings)})
+	}
+
+	return levels, nil
+}
+
+// WriteCalibration writes the distribution of sizes (see StructSizes) and the
+// levels as tables to w, marking the level of the current threshold in bytes,
+// if any.
+func WriteCalibration(w io.Writer, sizes []int64, levels []Level, current int) error {
+	s := Survey{Sizes: sizes}
+
+	fmt.Fprintf(w, "Struct sizes (%d types):\n", len(sizes))
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', tabwriter.AlignRight)
+
+	if len(sizes) > 0 {
+		for _, p := range []struct {
+			name     string
+			quantile float64
+		}{{"p50", 0.5}, {"p75", 0.75}, {"p90", 0.9}, {"p99", 0.99}, {"max", 1}} {
+			fmt.Fprintf(tw, "%s\t%d bytes\t\n", p.name, s.Percentile(p.quantile))
+		}
+	}
+
+	if err := tw.Flush(); err != nil {
+		return fmt.Errorf("writing calibration: %w", err)
+	}
+
+	fmt.Fprintf(w, "\n")
+
+	tw = tabwriter.NewWriter(w, 0, 0, 2, ' ', tabwriter.AlignRight)
+	fmt.Fprintf(tw, "threshold\tstructs fitting\tfindings\t\n")
+
+	for _, l := range levels {
+		share := 0
+		if len(sizes) > 0 {
+			share = l.Structs * 100 / len(sizes)
+		}
+
+		mark := ""
+		if l.Threshold == current {
+			mark = " (current)"
+		}
+
+		fmt.Fprintf(tw, "%d bytes%s\t%d (%d%%)\t%d\t\n", l.Threshold, mark, l.Structs, share, l.Findings)
+	}
+
+	if err := tw.Flush(); err != nil {
+		return fmt.Errorf("writing calibration: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,88 @@
+package survey
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/mickamy/pointless/internal/config"
+)
+
+// thresholdQuantile is the fraction of the struct types SuggestedThreshold
+// covers, and minThreshold the smallest threshold it suggests.
+const (
+	thresholdQuantile = 0.75
+	minThreshold      = 64
+)
+
+// SuggestedThreshold returns a threshold for the surveyed packages: the
+// smallest power of two that three quarters of their struct types fit in,
+// between 64 bytes and the default threshold. Structs above it are the
+// module's large ones, which pointers suit.
+func (s Survey) SuggestedThreshold() int {
+	limit := config.DefaultConfig().Threshold
+	if len(s.Sizes) == 0 {
+		return limit
+	}
+
+	size := s.Percentile(thresholdQuantile)
+
+	threshold := minThreshold
+	for int64(threshold) < size && threshold < limit {
+		threshold *= 2
+	}
+
+	return threshold
+}
+
+// WriteStarter writes a commented starter config for the surveyed packages to w.
+func WriteStarter(w io.Writer, s Survey) error {
+	var b bytes.Buffer
+
+	b.WriteString("# pointless configuration, generated by pointless init.\n")
+	b.WriteString("# See https://github.com/mickamy/pointless#configuration for all settings.\n\n")
+
+	if len(s.Sizes) > 0 {
+		fmt.Fprintf(&b, "# %d%% of the %d struct types in %d packages are at most %d bytes.\n",
+			int(thresholdQuantile*100), len(s.Sizes), s.Packages, s.Percentile(thresholdQuantile))
+	}
+
+	b.WriteString("# Pointers to structs up to the threshold (in bytes) are reported.\n")
+	fmt.Fprintf(&b, "threshold: %d\n\n", s.SuggestedThreshold())
+
+	if len(s.Generated) > 0 {
+		b.WriteString("# Generated code, relative to this file.\n")
+		b.WriteString("exclude:\n")
+
+		for _, p := range s.Generated {
+			fmt.Fprintf(&b, "  - %s\n", strconv.Quote(p))
+		}
+
+		b.WriteString("  # - \"*_test.go\"\n\n")
+	} else {
+		b.WriteString("# Files not to report on, relative to this file (** matches any directories).\n")
+		b.WriteString("# exclude:\n")
+		b.WriteString("#   - \"*_test.go\"\n\n")
+	}
+
+	if len(s.Presets) > 0 {
+		b.WriteString("# Types managed by the frameworks the module imports.\n")
+		fmt.Fprintf(&b, "presets: [%s]\n", strings.Join(s.Presets, ", "))
+	} else {
+		b.WriteString("# Types managed by frameworks (gorm, protobuf, ...) can be exempted.\n")
+		b.WriteString("# presets: []\n")
+	}
+
+	if s.Vendor {
+		b.WriteString("\n# vendor/ is not analyzed; set include-vendor: true to analyze it.\n")
+		b.WriteString("# include-vendor: false\n")
+	}
+
+	if _, err := w.Write(b.Bytes()); err != nil {
+		return fmt.Errorf("writing config: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,209 @@
+// Package survey inspects the packages of a module for pointless init and
+// calibrate: whether dependencies are vendored, which files are generated,
+// which frameworks with a preset are imported, how large the struct types are,
+// and how many findings thresholds would give.
+package survey
+
+import (
+	"fmt"
+	"go/ast"
+	"go/types"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+
+	"golang.org/x/tools/go/packages"
+
+	"github.com/mickamy/pointless/internal/config"
+	"github.com/mickamy/pointless/internal/preset"
+)
+
+// Survey is what Run found out about the packages of a module.
+type Survey struct {
+	// Dir is the module root, which the exclude patterns are relative to.
+	Dir string
+	// Packages is the number of packages surveyed.
+	Packages int
+	// Vendor reports whether the module vendors its dependencies.
+	Vendor bool
+	// Generated holds exclude patterns covering the generated files: dir/*.go
+	// for directories with generated files only, and the file otherwise.
+	Generated []string
+	// Presets holds the built-in presets of the frameworks the packages import.
+	Presets []string
+	// Sizes holds the sizes in bytes of the non-empty struct types the
+	// packages declare, ascending.
+	Sizes []int64
+}
+
+// loadMode loads syntax for the generated-code headers, and types for sizes.
+const loadMode = packages.NeedName | packages.NeedFiles | packages.NeedImports | packages.NeedDeps |
+	packages.NeedSyntax | packages.NeedTypes | packages.NeedTypesSizes | packages.NeedModule
+
+// Run surveys the packages matching patterns in dir ("" for the current
+// directory).
+func Run(dir string, patterns ...string) (Survey, error) {
+	pkgs, err := packages.Load(&packages.Config{Mode: loadMode, Dir: dir}, patterns...)
+	if err != nil {
+		return Survey{}, fmt.Errorf("loading packages: %w", err)
+	}
+
+	var s Survey
+
+	for _, pkg := range pkgs {
+		for _, e := range pkg.Errors {
+			return Survey{}, fmt.Errorf("loading packages: %w", e)
+		}
+
+		if s.Dir == "" && pkg.Module != nil {
+			s.Dir = pkg.Module.Dir
+		}
+	}
+
+	if s.Dir == "" {
+		abs, err := filepath.Abs(dir)
+		if err != nil {
+			return Survey{}, fmt.Errorf("resolving directory: %w", err)
+		}
+
+		s.Dir = config.ModuleRoot(abs)
+	}
+
+	if _, err := os.Stat(filepath.Join(s.Dir, "vendor", "modules.txt")); err == nil {
+		s.Vendor = true
+	}
+
+	s.Packages = len(pkgs)
+	s.Generated = generatedPatterns(s.Dir, pkgs)
+	s.Presets = importedPresets(pkgs)
+	s.Sizes = StructSizes(pkgs)
+
+	return s, nil
+}
+
+// Percentile returns the size that the given fraction (0 to 1) of the struct
+// types is no larger than.
+func (s Survey) Percentile(p float64) int64 {
+	if len(s.Sizes) == 0 {
+		return 0
+	}
+
+	i := int(p*float64(len(s.Sizes))+0.5) - 1
+
+	return s.Sizes[min(max(i, 0), len(s.Sizes)-1)]
+}
+
+// generatedPatterns returns the exclude patterns for the generated files of
+// pkgs, relative to the module root dir.
+func generatedPatterns(dir string, pkgs []*packages.Package) []string {
+	// generated and total count the files per slash-separated directory
+	generated := make(map[string][]string)
+	total := make(map[string]int)
+
+	for _, pkg := range pkgs {
+		for _, f := range pkg.Syntax {
+			rel, err := filepath.Rel(dir, pkg.Fset.File(f.Pos()).Name())
+			if err != nil || strings.HasPrefix(rel, "..") {
+				continue
+			}
+
+			rel = filepath.ToSlash(rel)
+			d := path.Dir(rel)
+			total[d]++
+
+			if ast.IsGenerated(f) {
+				generated[d] = append(generated[d], rel)
+			}
+		}
+	}
+
+	var patterns []string
+
+	for d, files := range generated {
+		switch {
+		// Patterns without a slash match base names in every directory
+		case len(files) == total[d] && d != ".":
+			patterns = append(patterns, d+"/*.go")
+		default:
+			for _, f := range files {
+				if !strings.Contains(f, "/") {
+					f = "./" + f
+				}
+
+				patterns = append(patterns, f)
+			}
+		}
+	}
+
+	sort.Strings(patterns)
+
+	return patterns
+}
+
+// importedPresets returns the presets whose frameworks pkgs import.
+func importedPresets(pkgs []*packages.Package) []string {
+	var names []string
+
+	for _, name := range preset.Names() {
+		p, _ := preset.Lookup(name)
+
+		if slices.ContainsFunc(pkgs, func(pkg *packages.Package) bool { return importsAny(pkg, p.Imports) }) {
+			names = append(names, name)
+		}
+	}
+
+	return names
+}
+
+// importsAny reports whether pkg imports a package of one of the modules.
+func importsAny(pkg *packages.Package, modules []string) bool {
+	for imp := range pkg.Imports {
+		for _, m := range modules {
+			if imp == m || strings.HasPrefix(imp, m+"/") {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// StructSizes returns the sizes of the non-empty struct types declared at
+// package level by pkgs, ascending.
+func StructSizes(pkgs []*packages.Package) []int64 {
+	var sizes []int64
+
+	for _, pkg := range pkgs {
+		if pkg.Types == nil || pkg.TypesSizes == nil {
+			continue
+		}
+
+		scope := pkg.Types.Scope()
+		for _, name := range scope.Names() {
+			tn, ok := scope.Lookup(name).(*types.TypeName)
+			if !ok || tn.IsAlias() {
+				continue
+			}
+
+			// Generic types have no size until instantiated
+			if named, ok := tn.Type().(*types.Named); ok && named.TypeParams().Len() > 0 {
+				continue
+			}
+
+			if _, ok := tn.Type().Underlying().(*types.Struct); !ok {
+				continue
+			}
+
+			if size := pkg.TypesSizes.Sizeof(tn.Type()); size > 0 {
+				sizes = append(sizes, size)
+			}
+		}
+	}
+
+	slices.Sort(sizes)
+
+	return sizes
+}
@@ -0,0 +1,243 @@
+package survey_test
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/mickamy/pointless/internal/analyzer"
+	"github.com/mickamy/pointless/internal/config"
+	"github.com/mickamy/pointless/internal/runner"
+	"github.com/mickamy/pointless/internal/survey"
+)
+
+const generated = "// Code generated by gen. DO NOT EDIT.\n\n"
+
+// writeFiles creates the files under dir, with their parent directories.
+func writeFiles(t *testing.T, dir string, files map[string]string) {
+	t.Helper()
+
+	for name, content := range files {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0o750); err != nil {
+			t.Fatal(err)
+		}
+
+		if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestRun(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		"go.mod":              "module example.com/m\n\ngo 1.24\n",
+		"vendor/modules.txt":  "",
+		"m.go":                "package m\n\ntype Point struct{ X, Y int }\n\ntype Empty struct{}\n",
+		"m_string.go":         generated + "package m\n\ntype Line struct{ A, B Point }\n",
+		"gen/types.go":        generated + "package gen\n\ntype Big struct{ Buf [4096]byte }\n",
+		"gen/more.go":         generated + "package gen\n\ntype List[T any] struct{ Items []T }\n",
+		"internal/x/x.go":     "package x\n\ntype Pair struct{ A, B int32 }\n",
+		"internal/x/x_gen.go": generated + "package x\n",
+	})
+
+	s, err := survey.Run(dir, "./...")
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+
+	if !s.Vendor || s.Packages != 3 {
+		t.Errorf("Run() = vendor %v, %d packages, want true and 3", s.Vendor, s.Packages)
+	}
+
+	if want := []string{"./m_string.go", "gen/*.go", "internal/x/x_gen.go"}; !slices.Equal(s.Generated, want) {
+		t.Errorf("Run() = generated %q, want %q", s.Generated, want)
+	}
+
+	// Empty and generic structs have no size to compare
+	if want := []int64{8, 16, 32, 4096}; !slices.Equal(s.Sizes, want) {
+		t.Errorf("Run() = sizes %v, want %v", s.Sizes, want)
+	}
+
+	if got := s.SuggestedThreshold(); got != 64 {
+		t.Errorf("SuggestedThreshold() = %d, want 64", got)
+	}
+
+	var buf bytes.Buffer
+	if err := survey.WriteStarter(&buf, s); err != nil {
+		t.Fatal(err)
+	}
+
+	// The starter config loads, with its patterns excluding the generated files
+	cfg := config.DefaultConfig()
+	if err := yaml.Unmarshal(buf.Bytes(), &cfg); err != nil {
+		t.Fatalf("starter config does not parse: %v\n%s", err, buf.String())
+	}
+
+	cfg.Dir = dir
+
+	if cfg.Threshold != 64 || !cfg.ShouldExclude(filepath.Join(dir, "gen", "types.go")) || cfg.ShouldExclude(filepath.Join(dir, "m.go")) {
+		t.Errorf("starter config = threshold %d, exclude %q", cfg.Threshold, cfg.Exclude)
+	}
+
+	if !strings.Contains(buf.String(), "include-vendor") {
+		t.Errorf("starter config does not mention vendor/:\n%s", buf.String())
+	}
+}
+
+func TestSuggestedThreshold(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		sizes []int64
+		want  int
+	}{
+		{nil, 1024},
+		{[]int64{8, 8, 8, 8}, 64},
+		{[]int64{8, 100, 200, 300}, 256},
+		{[]int64{2000, 3000, 4000, 5000}, 1024},
+	}
+
+	for _, tt := range tests {
+		if got := (survey.Survey{Sizes: tt.sizes}).SuggestedThreshold(); got != tt.want {
+			t.Errorf("SuggestedThreshold() with sizes %v = %d, want %d", tt.sizes, got, tt.want)
+		}
+	}
+}
+
+func TestCalibrate(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		"go.mod": "module example.com/m\n\ngo 1.24\n",
+		"m.go": `package m
+
+type Small struct{ A, B int64 }
+
+type Large struct{ Buf [200]byte }
+
+func NewSmall() *Small { return &Small{} }
+
+func NewLarge() *Large { return &Large{} }
+`,
+	})
+
+	pkgs, err := runner.LoadDir(dir, "./...")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	levels, err := survey.Calibrate(pkgs, config.DefaultConfig(), []int{256, 8, 64})
+	if err != nil {
+		t.Fatalf("Calibrate() error = %v", err)
+	}
+
+	want := []survey.Level{{Threshold: 8, Structs: 0, Findings: 0}, {Threshold: 64, Structs: 1, Findings: 1}, {Threshold: 256, Structs: 2, Findings: 2}}
+	if !slices.Equal(levels, want) {
+		t.Errorf("Calibrate() = %+v, want %+v", levels, want)
+	}
+
+	var buf bytes.Buffer
+	if err := survey.WriteCalibration(&buf, survey.StructSizes(pkgs), levels, 64); err != nil {
+		t.Fatal(err)
+	}
+
+	if !strings.Contains(buf.String(), "64 bytes (current)          1 (50%)         1") {
+		t.Errorf("WriteCalibration() =\n%s", buf.String())
+	}
+}
+
+func TestWriteVerdicts(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		"go.mod": "module example.com/m\n\ngo 1.24\n",
+		"m.go": `package m
+
+import "sync"
+
+type Point struct{ X, Y int }
+
+type Cache struct {
+	mu sync.Mutex
+	n  int
+}
+
+type Counter struct{ n int }
+
+func (c *Counter) Inc() { c.n++ }
+
+type User struct{ ID int }
+
+func Find(id int) *User {
+	if id == 0 {
+		return nil
+	}
+
+	return &User{ID: id}
+}
+
+type Large struct{ Buf [2048]byte }
+`,
+	})
+
+	pkgs, err := runner.LoadDir(dir, "./...")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cfg := config.DefaultConfig()
+
+	verdicts, err := runner.Verdicts(analyzer.Options{Threshold: cfg.Threshold, Config: cfg}, pkgs)
+	if err != nil {
+		t.Fatalf("Verdicts() error = %v", err)
+	}
+
+	var buf bytes.Buffer
+	if err := survey.WriteVerdicts(&buf, verdicts, dir, 1); err != nil {
+		t.Fatal(err)
+	}
+
+	want := `example.com/m.Cache: pointer-required (16 bytes)
+    sync field: field mu of type sync.Mutex must not be copied (m.go:8)
+example.com/m.Counter: pointer-required (8 bytes)
+    mutated receiver: (*Counter).Inc mutates the receiver (m.go:14)
+example.com/m.Large: too large (2048 bytes)
+    size: 2048 bytes > threshold 1024 bytes
+example.com/m.Point: value-friendly (16 bytes)
+    size: 16 bytes <= threshold 1024 bytes, with no mutated receiver, nil use, or sync field
+example.com/m.User: pointer-required (8 bytes)
+    nil: Find returns a nil *User (m.go:20)
+`
+	if got := buf.String(); got != want {
+		t.Errorf("WriteVerdicts() =\n%s\nwant\n%s", got, want)
+	}
+
+	// The evidence is described in the configured language
+	cfg.Lang = "ja"
+
+	verdicts, err = runner.Verdicts(analyzer.Options{Threshold: cfg.Threshold, Config: cfg}, pkgs)
+	if err != nil {
+		t.Fatalf("Verdicts() error = %v", err)
+	}
+
+	buf.Reset()
+
+	if err := survey.WriteVerdicts(&buf, verdicts, dir, 1); err != nil {
+		t.Fatal(err)
+	}
+
+	if got := buf.String(); !strings.Contains(got, "(*Counter).Inc はレシーバを変更します") {
+		t.Errorf("WriteVerdicts() with lang ja =\n%s", got)
+	}
+}
@@ -0,0 +1,57 @@
+package survey
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"path/filepath"
+
+	"github.com/mickamy/pointless/internal/analyzer"
+)
+
+// WriteVerdicts writes a line per verdict to w, with the type, verdict, and
+// size, followed by at most maxEvidence lines of evidence (all if negative).
+// Positions are shown relative to dir if they are below it.
+func WriteVerdicts(w io.Writer, verdicts []analyzer.Verdict, dir string, maxEvidence int) error {
+	var buf bytes.Buffer
+
+	for _, v := range verdicts {
+		fmt.Fprintf(&buf, "%s: %s (%d bytes)\n", v.Type, v.Verdict, v.Size)
+
+		shown := v.Evidence
+		if maxEvidence >= 0 && len(shown) > maxEvidence {
+			shown = shown[:maxEvidence]
+		}
+
+		for _, e := range shown {
+			fmt.Fprintf(&buf, "    %s: %s", e.Reason, e.Message)
+
+			if e.Position.IsValid() {
+				fmt.Fprintf(&buf, " (%s:%d)", relativeTo(dir, e.Position.Filename), e.Position.Line)
+			}
+
+			buf.WriteByte('\n')
+		}
+
+		if more := len(v.Evidence) - len(shown); more > 0 {
+			fmt.Fprintf(&buf, "    ... and %d more\n", more)
+		}
+	}
+
+	if _, err := w.Write(buf.Bytes()); err != nil {
+		return fmt.Errorf("writing verdicts: %w", err)
+	}
+
+	return nil
+}
+
+// relativeTo returns the path of name relative to dir, or name if it is not
+// below dir.
+func relativeTo(dir, name string) string {
+	rel, err := filepath.Rel(dir, name)
+	if err != nil || !filepath.IsLocal(rel) {
+		return name
+	}
+
+	return filepath.ToSlash(rel)
+}
@@ -0,0 +1,91 @@
+// Package symbol names the top-level declarations findings belong to, in the
+// form used by the ignore-symbols config and suppression files.
+package symbol
+
+import (
+	"go/ast"
+	"go/token"
+)
+
+// FuncNames returns the unqualified identifiers a function or method may be
+// referred to by: "Name" for functions, and "(*T).Name" or "(T).Name" plus the
+// receiver-agnostic "T.Name" for methods. The first name is the canonical one.
+func FuncNames(fn *ast.FuncDecl) []string {
+	if fn.Recv == nil || len(fn.Recv.List) == 0 {
+		return []string{fn.Name.Name}
+	}
+
+	typ := fn.Recv.List[0].Type
+	pointer := false
+
+	if star, ok := typ.(*ast.StarExpr); ok {
+		typ = star.X
+		pointer = true
+	}
+
+	// Strip type parameters from generic receivers
+	switch t := typ.(type) {
+	case *ast.IndexExpr:
+		typ = t.X
+	case *ast.IndexListExpr:
+		typ = t.X
+	}
+
+	ident, ok := typ.(*ast.Ident)
+	if !ok {
+		return nil
+	}
+
+	recv := "(" + ident.Name + ")"
+	if pointer {
+		recv = "(*" + ident.Name + ")"
+	}
+
+	return []string{recv + "." + fn.Name.Name, ident.Name + "." + fn.Name.Name}
+}
+
+// Enclosing returns the canonical unqualified name of the top-level function,
+// method, or variable declaration in file that contains pos, or "" if pos is
+// outside of any of them.
+func Enclosing(file *ast.File, pos token.Pos) string {
+	if names := EnclosingNames(file, pos); len(names) > 0 {
+		return names[0]
+	}
+
+	return ""
+}
+
+// EnclosingNames is like Enclosing, but returns all the unqualified names the
+// declaration may be referred to by (see FuncNames), canonical first.
+func EnclosingNames(file *ast.File, pos token.Pos) []string {
+	for _, decl := range file.Decls {
+		if pos < decl.Pos() || pos >= decl.End() {
+			continue
+		}
+
+		switch d := decl.(type) {
+		case *ast.FuncDecl:
+			return FuncNames(d)
+		case *ast.GenDecl:
+			if d.Tok != token.VAR {
+				return nil
+			}
+
+			for _, spec := range d.Specs {
+				vs, ok := spec.(*ast.ValueSpec)
+				if !ok || len(vs.Names) == 0 {
+					continue
+				}
+
+				// Ungrouped declarations cover the var keyword too
+				if len(d.Specs) == 1 || (pos >= vs.Pos() && pos < vs.End()) {
+					return []string{vs.Names[0].Name}
+				}
+			}
+		}
+
+		return nil
+	}
+
+	return nil
+}
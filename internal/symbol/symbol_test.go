@@ -0,0 +1,88 @@
+package symbol_test
+
+import (
+	"go/parser"
+	"go/token"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/mickamy/pointless/internal/symbol"
+)
+
+const src = `package p
+
+type T struct {
+	Name string
+}
+
+func New() *T { return &T{} }
+
+func (t *T) Ptr() string { return t.Name }
+
+func (t T) Value() string { return t.Name }
+
+func (l *List[E]) Push(e E) {}
+
+var single *T
+
+var (
+	first  *T
+	second []*T
+)
+`
+
+func TestEnclosing(t *testing.T) {
+	t.Parallel()
+
+	fset := token.NewFileSet()
+
+	f, err := parser.ParseFile(fset, "p.go", src, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name, at string
+		want     []string
+	}{
+		{"function", "&T{} }", []string{"New"}},
+		{"function signature", "*T { return", []string{"New"}},
+		{"pointer method", "t.Name }\n\nfunc (t T)", []string{"(*T).Ptr", "T.Ptr"}},
+		{"value method", "t.Name }\n\nfunc (l", []string{"(T).Value", "T.Value"}},
+		{"generic method", "e E", []string{"(*List).Push", "List.Push"}},
+		{"ungrouped var keyword", "var single", []string{"single"}},
+		{"ungrouped var", "*T\n\nvar (", []string{"single"}},
+		{"grouped var", "[]*T", []string{"second"}},
+		{"grouped var keyword", "var (", nil},
+		{"struct field", "string\n}", nil},
+		{"type", "struct {", nil},
+		{"package clause", "package p", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			off := strings.Index(src, tt.at)
+			if off < 0 {
+				t.Fatalf("%q not found", tt.at)
+			}
+
+			pos := f.FileStart + token.Pos(off)
+
+			if got := symbol.EnclosingNames(f, pos); !slices.Equal(got, tt.want) {
+				t.Errorf("EnclosingNames = %q, want %q", got, tt.want)
+			}
+
+			want := ""
+			if len(tt.want) > 0 {
+				want = tt.want[0]
+			}
+
+			if got := symbol.Enclosing(f, pos); got != want {
+				t.Errorf("Enclosing = %q, want %q", got, want)
+			}
+		})
+	}
+}
@@ -0,0 +1,62 @@
+// Package target determines the platform the analyzed packages are built for.
+// Struct sizes, and so findings, depend on it: go/packages computes the sizes
+// of the GOARCH the go command builds for, which need not be the host's.
+package target
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"go/types"
+	"os/exec"
+	"strings"
+)
+
+// Platform is a GOOS/GOARCH pair.
+type Platform struct {
+	GOOS   string
+	GOARCH string
+}
+
+func (p Platform) String() string {
+	return p.GOOS + "/" + p.GOARCH
+}
+
+// Parse parses a platform spelled "goos/goarch", like "linux/386". The
+// architecture must be one the gc compiler has sizes for.
+func Parse(s string) (Platform, error) {
+	goos, goarch, ok := strings.Cut(s, "/")
+	if !ok || goos == "" || goarch == "" || strings.Contains(goarch, "/") {
+		return Platform{}, fmt.Errorf("invalid platform %q, want goos/goarch", s)
+	}
+
+	if types.SizesFor("gc", goarch) == nil {
+		return Platform{}, fmt.Errorf("unknown architecture %q", goarch)
+	}
+
+	return Platform{GOOS: goos, GOARCH: goarch}, nil
+}
+
+// Env returns the platform the go command builds for, as set by the GOOS and
+// GOARCH environment variables or go env -w, and the host platform.
+func Env() (target, host Platform, err error) {
+	var stdout, stderr bytes.Buffer
+
+	cmd := exec.Command("go", "env", "-json", "GOOS", "GOARCH", "GOHOSTOS", "GOHOSTARCH")
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		return Platform{}, Platform{}, fmt.Errorf("running go env: %w: %s", err, bytes.TrimSpace(stderr.Bytes()))
+	}
+
+	var env map[string]string
+	if err := json.Unmarshal(stdout.Bytes(), &env); err != nil {
+		return Platform{}, Platform{}, fmt.Errorf("parsing go env: %w", err)
+	}
+
+	target = Platform{GOOS: env["GOOS"], GOARCH: env["GOARCH"]}
+	host = Platform{GOOS: env["GOHOSTOS"], GOARCH: env["GOHOSTARCH"]}
+
+	return target, host, nil
+}
@@ -0,0 +1,45 @@
+package target_test
+
+import (
+	"testing"
+
+	"github.com/mickamy/pointless/internal/target"
+)
+
+func TestParse(t *testing.T) {
+	t.Parallel()
+
+	p, err := target.Parse("linux/386")
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+
+	if p != (target.Platform{GOOS: "linux", GOARCH: "386"}) || p.String() != "linux/386" {
+		t.Errorf("Parse() = %v, want linux/386", p)
+	}
+
+	for _, s := range []string{"", "linux", "linux/", "/386", "linux/386/x", "linux/z80"} {
+		if _, err := target.Parse(s); err == nil {
+			t.Errorf("Parse(%q) succeeded, want an error", s)
+		}
+	}
+}
+
+//nolint:paralleltest // sets GOOS and GOARCH
+func TestEnv(t *testing.T) {
+	t.Setenv("GOOS", "windows")
+	t.Setenv("GOARCH", "386")
+
+	got, host, err := target.Env()
+	if err != nil {
+		t.Fatalf("Env() error = %v", err)
+	}
+
+	if want := (target.Platform{GOOS: "windows", GOARCH: "386"}); got != want {
+		t.Errorf("Env() = %v, want %v", got, want)
+	}
+
+	if host.GOOS == "" || host.GOARCH == "" {
+		t.Errorf("Env() = host %v, want a host platform", host)
+	}
+}
@@ -0,0 +1,97 @@
+// Package version reports the version pointless was built from, as recorded
+// by the go command in the binary.
+package version
+
+import (
+	"fmt"
+	"runtime"
+	"runtime/debug"
+	"strings"
+)
+
+// Info describes a build of pointless.
+type Info struct {
+	// Version is the module version, like v1.2.3, or "(devel)" for builds from
+	// a checkout.
+	Version string
+	// Revision is the VCS revision the binary was built from, if known.
+	Revision string
+	// Time is the commit time of Revision, in RFC 3339 format, if known.
+	Time string
+	// Modified reports whether the working tree had uncommitted changes.
+	Modified bool
+	// GoVersion is the version of the Go toolchain that built the binary.
+	GoVersion string
+}
+
+// devel is the module version of builds without one.
+const devel = "(devel)"
+
+// Get returns the build information of the running binary.
+func Get() Info {
+	info := Info{Version: devel, GoVersion: runtime.Version()}
+
+	bi, ok := debug.ReadBuildInfo()
+	if !ok {
+		return info
+	}
+
+	if v := bi.Main.Version; v != "" {
+		info.Version = v
+	}
+
+	info.GoVersion = bi.GoVersion
+
+	for _, s := range bi.Settings {
+		switch s.Key {
+		case "vcs.revision":
+			info.Revision = s.Value
+		case "vcs.time":
+			info.Time = s.Value
+		case "vcs.modified":
+			info.Modified = s.Value == "true"
+		}
+	}
+
+	return info
+}
+
+// Short returns the version, with the abbreviated revision for builds from a
+// checkout, as in "(devel) 1a2b3c4d5e6f-dirty".
+func (i Info) Short() string {
+	if i.Version != devel || i.Revision == "" {
+		return i.Version
+	}
+
+	rev := i.Revision[:min(len(i.Revision), 12)]
+	if i.Modified {
+		rev += "-dirty"
+	}
+
+	return i.Version + " " + rev
+}
+
+// String returns the full description of the build, as printed by pointless
+// version.
+func (i Info) String() string {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "pointless %s\n", i.Version)
+
+	if i.Revision != "" {
+		rev := i.Revision
+		if i.Modified {
+			rev += " (modified)"
+		}
+
+		fmt.Fprintf(&b, "revision: %s\n", rev)
+	}
+
+	if i.Time != "" {
+		fmt.Fprintf(&b, "time: %s\n", i.Time)
+	}
+
+	fmt.Fprintf(&b, "go: %s %s/%s\n", i.GoVersion, runtime.GOOS, runtime.GOARCH)
+
+	return b.String()
+}
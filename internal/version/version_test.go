@@ -0,0 +1,43 @@
+package version_test
+
+import (
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/mickamy/pointless/internal/version"
+)
+
+func TestGet(t *testing.T) {
+	t.Parallel()
+
+	info := version.Get()
+	if info.Version == "" {
+		t.Error("empty version")
+	}
+
+	if info.GoVersion != runtime.Version() {
+		t.Errorf("GoVersion = %q, want %q", info.GoVersion, runtime.Version())
+	}
+}
+
+func TestString(t *testing.T) {
+	t.Parallel()
+
+	info := version.Info{Version: "(devel)", Revision: "0123456789abcdef", Modified: true, Time: "2026-01-02T03:04:05Z", GoVersion: "go1.24.0"}
+
+	got := info.String()
+	for _, want := range []string{"pointless (devel)\n", "revision: 0123456789abcdef (modified)\n", "time: 2026-01-02T03:04:05Z\n", "go: go1.24.0 "} {
+		if !strings.Contains(got, want) {
+			t.Errorf("String() = %q, missing %q", got, want)
+		}
+	}
+
+	if got, want := info.Short(), "(devel) 0123456789ab-dirty"; got != want {
+		t.Errorf("Short() = %q, want %q", got, want)
+	}
+
+	if got, want := (version.Info{Version: "v1.2.3", Revision: "0123456789abcdef"}).Short(), "v1.2.3"; got != want {
+		t.Errorf("Short() = %q, want %q", got, want)
+	}
+}
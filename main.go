@@ -11,28 +11,80 @@ import (
 
 	"github.com/mickamy/pointless/internal/analyzer"
 	"github.com/mickamy/pointless/internal/config"
+	"github.com/mickamy/pointless/internal/messages"
+	"github.com/mickamy/pointless/internal/preset"
+	"github.com/mickamy/pointless/internal/severity"
+	"github.com/mickamy/pointless/internal/startup"
 )
 
+// commands maps subcommand names to their entry points. Anything else is
+// handled by singlechecker.
+var commands = map[string]func(args []string) int{
+	"suppress":      runSuppress,
+	"explain":       runExplain,
+	"selftest":      runSelftest,
+	"config":        runConfig,
+	"init":          runInit,
+	"calibrate":     runCalibrate,
+	"diff-findings": runDiffFindings,
+	"verdict":       runVerdict,
+	"version":       runVersion,
+}
+
 func main() {
-	// Load config file before flag parsing
-	cfg, err := config.Load()
-	if err != nil {
-		fmt.Fprintf(os.Stderr, "pointless: warning: failed to load config: %v\n", err)
+	os.Args = append(os.Args[:1], startup.SetupLogging(os.Args[1:])...)
+
+	if len(os.Args) > 1 {
+		if cmd, ok := commands[os.Args[1]]; ok {
+			os.Exit(cmd(os.Args[2:]))
+		}
 	}
 
-	// Set default from config file if not overridden by flags
-	if cfg.Threshold > 0 {
-		// Check if -threshold flag is explicitly set
-		thresholdSet := false
+	if startup.HasFlag(os.Args[1:], "version") {
+		os.Exit(runVersion(nil))
+	}
+
+	// Select the platform whose sizes apply before any package is loaded
+	if !startup.SelectTarget(os.Args[1:]) {
+		os.Exit(2)
+	}
+
+	// Select the files of the packages by build tags, for facts and findings alike
+	if !startup.SelectTags(os.Args[1:]) {
+		os.Exit(2)
+	}
+
+	// Load config file before flag parsing. At a workspace root, each module
+	// gets its own config.
+	ws, inWorkspace := startup.LoadWorkspace()
+
+	cfg := ws.Config
+	if !inWorkspace {
+		cfg = startup.LoadConfig()
+	}
+
+	// Check if -threshold flag is explicitly set
+	thresholdSet := false
+
+	for _, arg := range os.Args[1:] {
+		if arg == "-threshold" || (len(arg) > 10 && arg[:11] == "-threshold=") {
+			thresholdSet = true
 
-		for _, arg := range os.Args[1:] {
-			if arg == "-threshold" || (len(arg) > 10 && arg[:11] == "-threshold=") {
-				thresholdSet = true
+			break
+		}
+	}
 
-				break
-			}
+	// The analyzer picks each module's threshold unless the flag overrides them
+	if inWorkspace && thresholdSet {
+		for i := range ws.Modules {
+			ws.Modules[i].Config.Threshold = 0
 		}
 
+		ws.Config.Threshold = 0
+	}
+
+	// Set default from config file if not overridden by flags
+	if cfg.Threshold > 0 && !inWorkspace {
 		if !thresholdSet {
 			// Inject the config value as a flag (insert after program name, before other args)
 			newArgs := make([]string, 0, len(os.Args)+1)
@@ -42,24 +94,77 @@ func main() {
 		}
 	}
 
-	// Store config in analyzer for exclude pattern support
-	analyzer.SetConfig(cfg.Exclude)
+	// Store config in analyzer for exclude pattern and preset support
+	analyzer.SetConfig(cfg)
+
+	if inWorkspace {
+		analyzer.SetWorkspace(ws)
+	}
+
+	// Related locations, such as the definitions of types, are one more line
+	// per finding in text output
+	analyzer.SetTypeDefinitions(startup.HasFlag(os.Args[1:], "json"))
+
+	// The driver cannot expand ./... at a workspace root, so the runner does
+	if needsRunner(os.Args[1:]) || inWorkspace && !startup.HasFlag(os.Args[1:], "diff") {
+		os.Exit(runFormatted(os.Args[1:], cfg, ws.Modules))
+	}
 
 	singlechecker.Main(analyzer.Analyzer)
 }
 
+// singlechecker.Main replaces flag.Usage with its own, but -h and invalid
+// flags print the usage of the command line flag set, which stays ours.
 func init() {
-	// Add version flag.
-	flag.Usage = func() {
+	flag.CommandLine.Usage = func() {
 		fmt.Fprintf(os.Stderr, "pointless: suggests using value types instead of pointers for small structs\n\n")
-		fmt.Fprintf(os.Stderr, "Usage: pointless [flags] [packages]\n\n")
+		fmt.Fprintf(os.Stderr, "Usage: pointless [flags] [packages]\n")
+		fmt.Fprintf(os.Stderr, "       pointless init [-o file] [-force] [packages]\n")
+		fmt.Fprintf(os.Stderr, "       pointless calibrate [-thresholds list] [packages]\n")
+		fmt.Fprintf(os.Stderr, "       pointless verdict [-evidence n] [packages]\n")
+		fmt.Fprintf(os.Stderr, "       pointless suppress [-o file] [packages]\n")
+		fmt.Fprintf(os.Stderr, "       pointless selftest [-corpus file] [-update]\n")
+		fmt.Fprintf(os.Stderr, "       pointless diff-findings old.json new.json\n")
+		fmt.Fprintf(os.Stderr, "       pointless explain <rule>\n")
+		fmt.Fprintf(os.Stderr, "       pointless config lint [packages]\n")
+		fmt.Fprintf(os.Stderr, "       pointless config schema\n")
+		fmt.Fprintf(os.Stderr, "       pointless version\n\n")
 		fmt.Fprintf(os.Stderr, "Flags:\n")
+
+		// The driver registers both as deprecated no-ops
+		if f := flag.Lookup("tags"); f != nil {
+			f.Usage = startup.TagsUsage
+		}
+
+		if f := flag.Lookup("v"); f != nil {
+			f.Usage = "shorthand for -verbose"
+		}
+
 		flag.PrintDefaults()
+		fmt.Fprintf(os.Stderr, "  -debug\n    \t%s\n", startup.DebugUsage)
 		fmt.Fprintf(os.Stderr, "\nConfiguration:\n")
 		fmt.Fprintf(os.Stderr, "  Create .pointless.yaml in your project root:\n")
-		fmt.Fprintf(os.Stderr, "    threshold: 1024  # bytes\n")
+		fmt.Fprintf(os.Stderr, "    threshold: 1024\n")
+		fmt.Fprintf(os.Stderr, "    threshold-unit: bytes  # or words, cachelines\n")
+		fmt.Fprintf(os.Stderr, "    min-size: 16  # leave smaller structs alone\n")
 		fmt.Fprintf(os.Stderr, "    exclude:\n")
 		fmt.Fprintf(os.Stderr, "      - \"*_test.go\"\n")
-		fmt.Fprintf(os.Stderr, "      - \"vendor/**\"\n")
+		fmt.Fprintf(os.Stderr, "    include: [\"services/billing/**\"]  # only report on matching files\n")
+		fmt.Fprintf(os.Stderr, "    include-vendor: false  # vendor/ and the module cache are skipped by default\n")
+		fmt.Fprintf(os.Stderr, "    presets: [gorm, protobuf]  # available: %v\n", preset.Names())
+		fmt.Fprintf(os.Stderr, "    ignore-symbols:\n")
+		fmt.Fprintf(os.Stderr, "      - \"(*Server).Handler\"\n")
+		fmt.Fprintf(os.Stderr, "    include-symbols: '^(pkg\\.)?Get'  # only report findings in matching symbols\n")
+		fmt.Fprintf(os.Stderr, "    ignore-implements: [driver.Valuer]  # exempt types implementing these interfaces\n")
+		fmt.Fprintf(os.Stderr, "    ignore-if-contains-field-type: [sync.Mutex]  # exempt structs with fields of these types\n")
+		fmt.Fprintf(os.Stderr, "    respect-unsafe: false  # also check types used with unsafe or reflect\n")
+		fmt.Fprintf(os.Stderr, "    skip-if: 'type.name.matches(\"DTO$\")'  # exempt types matching an expression\n")
+		fmt.Fprintf(os.Stderr, "    enable: [context-value]  # opt-in rules\n")
+		fmt.Fprintf(os.Stderr, "    lang: ja  # message language: %v\n", messages.Languages())
+		fmt.Fprintf(os.Stderr, "    default-severity: warning  # %v\n", severity.Names())
+		fmt.Fprintf(os.Stderr, "    severity: {value-receiver: hint}\n")
+		fmt.Fprintf(os.Stderr, "    tests: {threshold: 4096, rules: [slice-pointer]}  # relaxed checks in _test.go files, reported as info\n")
+		fmt.Fprintf(os.Stderr, "    package-budget: 5s  # warn about packages taking longer to analyze\n")
+		fmt.Fprintf(os.Stderr, "    format: gofumpt  # formatter for files changed by -fix: %v\n", config.Formatters())
 	}
 }
@@ -0,0 +1,98 @@
+package main_test
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestCommand(t *testing.T) {
+	t.Parallel()
+
+	bin := filepath.Join(t.TempDir(), "pointless")
+
+	build := exec.Command("go", "build", "-o", bin, ".")
+	if out, err := build.CombinedOutput(); err != nil {
+		t.Fatalf("go build: %v\n%s", err, out)
+	}
+
+	tests := []struct {
+		name     string
+		dir      string
+		args     []string
+		exitCode int
+		want     []string
+		notWant  []string
+	}{
+		{
+			name:     "usage",
+			dir:      "tests",
+			args:     []string{"-h"},
+			exitCode: 0,
+			want:     []string{"pointless init", "pointless config lint", "-v\tshorthand for -verbose", "-tags list\n    \tcomma-separated list of build tags", "-debug", "Configuration:"},
+			notWant:  []string{"-v\tno effect", "-tags string"},
+		},
+		{
+			name:     "text",
+			dir:      "tests",
+			args:     []string{"./..."},
+			exitCode: 3,
+			want:     []string{"tests.go:7", "tests_test.go:3"},
+		},
+		{
+			name:     "json",
+			dir:      "tests",
+			args:     []string{"-format=json", "./..."},
+			exitCode: 3,
+			want:     []string{`"file": "tests.go"`, `"file": "tests_test.go"`},
+		},
+		{
+			name:     "json without tests",
+			dir:      "tests",
+			args:     []string{"-format=json", "-test=false", "./..."},
+			exitCode: 3,
+			want:     []string{`"file": "tests.go"`},
+			notWant:  []string{"tests_test.go"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			cmd := exec.Command(bin, tt.args...) //nolint:gosec // G204: the binary built above
+			cmd.Dir = filepath.Join("testdata", tt.dir)
+			cmd.Env = append(os.Environ(), "GOFLAGS=")
+
+			out, err := cmd.CombinedOutput()
+
+			exitCode := 0
+
+			var exitErr *exec.ExitError
+			if errors.As(err, &exitErr) {
+				exitCode = exitErr.ExitCode()
+			} else if err != nil {
+				t.Fatal(err)
+			}
+
+			if exitCode != tt.exitCode {
+				t.Errorf("exit code = %d, want %d\n%s", exitCode, tt.exitCode, out)
+			}
+
+			for _, s := range tt.want {
+				if !strings.Contains(string(out), s) {
+					t.Errorf("output does not contain %q:\n%s", s, out)
+				}
+			}
+
+			for _, s := range tt.notWant {
+				if strings.Contains(string(out), s) {
+					t.Errorf("output contains %q:\n%s", s, out)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,149 @@
+// Package fixtest verifies the suggested fixes of an analyzer end to end: the
+// fixed sources must match golden files, as with
+// analysistest.RunWithSuggestedFixes, and must also still compile. Forks adding
+// rules with fixes can test them with the same harness as the built-in ones.
+package fixtest
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/analysis/analysistest"
+	"golang.org/x/tools/go/packages"
+	"golang.org/x/tools/txtar"
+)
+
+// Run applies the suggested fixes of a to the packages matching patterns in
+// the GOPATH-style tree dir (usually analysistest.TestData()), and compares
+// each fixed file with the golden file next to it (example.go.golden for
+// example.go), in either form analysistest.RunWithSuggestedFixes accepts.
+// Each package is then type-checked with the golden files in place of the
+// sources, once per section of txtar golden files, and type errors fail t.
+// t is usually a *testing.T.
+func Run(t analysistest.Testing, dir string, a *analysis.Analyzer, patterns ...string) []*analysistest.Result {
+	if h, ok := t.(interface{ Helper() }); ok {
+		h.Helper()
+	}
+
+	results := analysistest.RunWithSuggestedFixes(t, dir, a, patterns...)
+
+	checked := make(map[string]bool)
+
+	for _, r := range results {
+		if r.Pass == nil || checked[r.Pass.Pkg.Path()] {
+			continue
+		}
+
+		checked[r.Pass.Pkg.Path()] = true
+
+		var files []string
+		for _, f := range r.Pass.Files {
+			files = append(files, r.Pass.Fset.File(f.Pos()).Name())
+		}
+
+		variants, err := goldenVariants(files)
+		if err != nil {
+			t.Errorf("%v", err)
+
+			continue
+		}
+
+		for _, v := range variants {
+			if err := typeCheck(dir, r.Pass.Pkg.Path(), v.overlay); err != nil {
+				t.Errorf("fixed %s%s does not compile: %v", r.Pass.Pkg.Path(), v.name, err)
+			}
+		}
+	}
+
+	return results
+}
+
+// variant is a set of fixed files to type-check together.
+type variant struct {
+	// name describes the variant in errors: empty, or the txtar section.
+	name    string
+	overlay map[string][]byte
+}
+
+// goldenVariants returns the fixed contents of files according to their
+// golden files. Plain golden files apply to every variant; each section of a
+// txtar golden file, named after a fix message, makes a variant of its own.
+func goldenVariants(files []string) ([]variant, error) {
+	base := make(map[string][]byte)
+
+	var sections []variant
+
+	for _, name := range files {
+		data, err := os.ReadFile(name + ".golden")
+		if os.IsNotExist(err) {
+			continue
+		}
+
+		if err != nil {
+			return nil, fmt.Errorf("reading golden file: %w", err)
+		}
+
+		ar := txtar.Parse(data)
+		if len(ar.Files) == 0 {
+			base[name] = data
+
+			continue
+		}
+
+		for _, f := range ar.Files {
+			sections = append(sections, variant{name: fmt.Sprintf(" (%s: %s)", filepath.Base(name), f.Name), overlay: map[string][]byte{name: f.Data}})
+		}
+	}
+
+	if len(sections) == 0 {
+		if len(base) == 0 {
+			return nil, nil
+		}
+
+		return []variant{{overlay: base}}, nil
+	}
+
+	for _, v := range sections {
+		for name, data := range base {
+			v.overlay[name] = data
+		}
+	}
+
+	return sections, nil
+}
+
+// typeCheck loads the package path from the GOPATH-style tree dir with
+// overlay in place of its files, and returns its errors.
+func typeCheck(dir, path string, overlay map[string][]byte) error {
+	cfg := &packages.Config{
+		Mode:    packages.NeedName | packages.NeedFiles | packages.NeedImports | packages.NeedDeps | packages.NeedTypes | packages.NeedSyntax | packages.NeedTypesInfo,
+		Dir:     dir,
+		Env:     append(os.Environ(), "GOPATH="+dir, "GO111MODULE=off", "GOWORK=off"),
+		Overlay: overlay,
+	}
+
+	pkgs, err := packages.Load(cfg, path)
+	if err != nil {
+		return fmt.Errorf("loading: %w", err)
+	}
+
+	var errs []string
+
+	for _, pkg := range pkgs {
+		for _, e := range pkg.Errors {
+			errs = append(errs, e.Error())
+		}
+	}
+
+	if len(errs) == 0 {
+		return nil
+	}
+
+	sort.Strings(errs)
+
+	return fmt.Errorf("%s", strings.Join(errs, "; "))
+}
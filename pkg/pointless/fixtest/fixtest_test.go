@@ -0,0 +1,76 @@
+package fixtest_test
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"strings"
+	"testing"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/analysis/analysistest"
+
+	"github.com/mickamy/pointless/pkg/pointless/fixtest"
+)
+
+// addZero reports x + 0. Its fix drops "+ 0", or replaces the sum with an
+// undefined name in package broken, which then no longer compiles.
+var addZero = &analysis.Analyzer{
+	Name: "addzero",
+	Doc:  "reports adding zero",
+	Run: func(pass *analysis.Pass) (any, error) {
+		for _, f := range pass.Files {
+			ast.Inspect(f, func(n ast.Node) bool {
+				bin, ok := n.(*ast.BinaryExpr)
+				if !ok || bin.Op != token.ADD {
+					return true
+				}
+
+				if lit, ok := bin.Y.(*ast.BasicLit); !ok || lit.Value != "0" {
+					return true
+				}
+
+				edit := analysis.TextEdit{Pos: bin.X.End(), End: bin.End()}
+				if pass.Pkg.Name() == "broken" {
+					edit = analysis.TextEdit{Pos: bin.Pos(), End: bin.End(), NewText: []byte("y")}
+				}
+
+				pass.Report(analysis.Diagnostic{
+					Pos:            bin.Pos(),
+					Message:        "adding zero",
+					SuggestedFixes: []analysis.SuggestedFix{{Message: "Drop the operand", TextEdits: []analysis.TextEdit{edit}}},
+				})
+
+				return true
+			})
+		}
+
+		return nil, nil
+	},
+}
+
+// recorder collects the errors of a test run.
+type recorder struct {
+	errs []string
+}
+
+func (r *recorder) Errorf(format string, args ...any) {
+	r.errs = append(r.errs, fmt.Sprintf(format, args...))
+}
+
+func TestRun(t *testing.T) {
+	t.Parallel()
+
+	fixtest.Run(t, analysistest.TestData(), addZero, "ok")
+}
+
+func TestRunDoesNotCompile(t *testing.T) {
+	t.Parallel()
+
+	var r recorder
+	fixtest.Run(&r, analysistest.TestData(), addZero, "broken")
+
+	if len(r.errs) != 1 || !strings.Contains(r.errs[0], "fixed broken (broken.go: Drop the operand) does not compile") {
+		t.Errorf("Run() errors = %q, want one about broken not compiling", r.errs)
+	}
+}
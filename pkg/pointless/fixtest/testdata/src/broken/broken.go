@@ -0,0 +1,6 @@
+package broken
+
+func F() int {
+	x := 1
+	return x + 0 // want "adding zero"
+}
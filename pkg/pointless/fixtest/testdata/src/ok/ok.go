@@ -0,0 +1,6 @@
+package ok
+
+func F() int {
+	x := 1
+	return x + 0 // want "adding zero"
+}
@@ -0,0 +1,238 @@
+// Package pointless exposes the pointless analyzer as a library, so tools such
+// as bots and code-review services can consume structured findings without
+// shelling out to the command and parsing its output.
+package pointless
+
+import (
+	"fmt"
+	"go/token"
+	"go/types"
+
+	"golang.org/x/tools/go/packages"
+
+	"github.com/mickamy/pointless/internal/analyzer"
+	"github.com/mickamy/pointless/internal/config"
+	"github.com/mickamy/pointless/internal/runner"
+)
+
+// DefaultThreshold is the default size threshold in bytes.
+const DefaultThreshold = analyzer.DefaultThreshold
+
+// LoadMode is the go/packages load mode packages passed to Checker.Check must be loaded with.
+const LoadMode = runner.LoadMode
+
+// Checker runs the analyzer programmatically. The zero value uses the default
+// threshold and no config. Checkers with different settings may run concurrently.
+type Checker struct {
+	// Threshold is the size threshold (DefaultThreshold bytes if zero).
+	Threshold int
+	// ThresholdUnit is the unit of Threshold: "bytes" (the default if empty),
+	// "words", or "cachelines".
+	ThresholdUnit string
+	// MinSize is the smallest struct size, in ThresholdUnit, worth
+	// reporting, as in the min-size config (none if zero).
+	MinSize int
+	// Exclude lists file patterns to skip, as in the exclude config.
+	Exclude []string
+	// ExcludeDir is the directory Exclude patterns with a slash are relative
+	// to, like the directory of the config file (each file's module root if
+	// empty).
+	ExcludeDir string
+	// Include, if set, limits findings to the files matching its patterns,
+	// as in the include config. They are relative to ExcludeDir too.
+	Include []string
+	// Presets lists built-in framework presets to enable.
+	Presets []string
+	// IgnoreSymbols lists functions, methods, and variables never to report.
+	IgnoreSymbols []string
+	// IncludeSymbols, if set, is a regular expression limiting findings to
+	// the symbols it matches, as in the include-symbols config.
+	IncludeSymbols string
+	// IgnoreSymbolsRegex is a regular expression dropping the findings in the
+	// symbols it matches, as in the ignore-symbols-regex config.
+	IgnoreSymbolsRegex string
+	// SkipIf is an expression exempting the types it matches, as in the
+	// skip-if config, e.g. `type.name.matches("DTO$")`.
+	SkipIf string
+	// IgnoreImplements lists interfaces ("error" or "database/sql/driver.Valuer")
+	// whose implementations are never reported, as in the ignore-implements
+	// config.
+	IgnoreImplements []string
+	// IgnoreIfContainsFieldType lists field types ("sync.Mutex" or "*sql.DB")
+	// whose presence exempts a struct, as in the
+	// ignore-if-contains-field-type config.
+	IgnoreIfContainsFieldType []string
+	// RespectUnsafe, true unless set to false, exempts types converted with
+	// unsafe.Pointer or inspected with unsafe.Sizeof and the like, as in the
+	// respect-unsafe config.
+	RespectUnsafe *bool
+	// SkipType, if set, exempts the types it returns true for. It is called
+	// with the named type, or pointer to it, each finding is about before the
+	// finding is reported, and must be safe for concurrent use.
+	SkipType func(t types.Type) bool
+	// IncludeVendor analyzes vendored and module cache packages, which are
+	// skipped by default.
+	IncludeVendor bool
+	// Enable lists opt-in rules to run (e.g. "context-value").
+	Enable []string
+	// Lang selects the language of finding messages ("en" if empty, or "ja").
+	Lang string
+	// DefaultSeverity is the severity of findings ("warning" if empty,
+	// "info", or "hint").
+	DefaultSeverity string
+	// Severity overrides the severity per rule ID.
+	Severity map[string]string
+	// Tests relaxes the findings in _test.go files, as in the tests config:
+	// they are reported as "info" by default.
+	Tests TestFiles
+	// ExcludeRules drops the findings matching any of the rules, as in the
+	// exclude-rules config.
+	ExcludeRules []ExcludeRule
+	// DocsBaseURL is where Finding.URL points to the documentation of the
+	// rules instead of the published one, as in the docs-base-url config.
+	DocsBaseURL string
+	// AllowBreaking keeps suggested fixes that would break the public API of
+	// a package, which are withheld by default, as the -allow-breaking flag.
+	AllowBreaking bool
+}
+
+// TestFiles relaxes the findings in _test.go files.
+type TestFiles struct {
+	// Severity caps the severity of their findings ("info" if empty).
+	Severity string
+	// Threshold, if set, replaces Checker.Threshold in test files, in the
+	// same unit.
+	Threshold int
+	// Rules, if set, lists the only rules reported in test files.
+	Rules []string
+}
+
+// ExcludeRule drops the findings matching all of its conditions, with the
+// semantics of an exclude-rules entry of golangci-lint. Regular expressions
+// match anywhere in their input unless anchored.
+type ExcludeRule struct {
+	// Path matches the slash-separated path of the file, relative to
+	// Checker.ExcludeDir.
+	Path string
+	// PathExcept matches the paths of the files the rule does not apply to.
+	PathExcept string
+	// Rules lists the rule IDs the rule applies to, all if empty.
+	Rules []string
+	// Linters lists linters as golangci-lint does: the rule only applies if
+	// it is empty or lists pointless.
+	Linters []string
+	// Text matches the message of the finding.
+	Text string
+	// Source matches the source line the finding starts on.
+	Source string
+}
+
+// Finding is a single diagnostic.
+type Finding struct {
+	// Position is where the finding was reported.
+	Position token.Position
+	// End is the end of the reported range, if known.
+	End token.Position
+	// Message is the human-readable diagnostic.
+	Message string
+	// Severity is the configured severity: "warning", "info", or "hint".
+	Severity string
+	// Rule is the stable ID of the check that produced the finding
+	// (e.g. "return-pointer").
+	Rule string
+	// URL links to the documentation of the rule.
+	URL string
+	// Package is the import path of the package containing the finding.
+	Package string
+	// Symbol is the package-path-qualified enclosing function, method, or
+	// variable (e.g. "example.com/pkg.(*T).M"), or "" if there is none.
+	Symbol string
+	// Related holds locations related to the finding, such as the definition
+	// of the type involved.
+	Related []Related
+	// Fingerprint is a hash of the rule, package, symbol, and normalized
+	// source snippet. It identifies the finding across line-number drift and
+	// file renames.
+	Fingerprint string
+}
+
+// Related is a location related to a finding.
+type Related struct {
+	Position token.Position
+	Message  string
+}
+
+// Load loads the packages matching patterns and their tests in LoadMode. The
+// findings in _test.go files follow the Tests settings of the Checker.
+func Load(patterns ...string) ([]*packages.Package, error) {
+	pkgs, err := runner.Load(patterns...)
+	if err != nil {
+		return nil, fmt.Errorf("pointless: %w", err)
+	}
+
+	return pkgs, nil
+}
+
+// Check analyzes pkgs, which must be loaded in LoadMode, and returns the
+// findings sorted by position. Nothing is printed.
+func (c Checker) Check(pkgs []*packages.Package) ([]Finding, error) {
+	cfg := config.DefaultConfig()
+	cfg.Exclude = c.Exclude
+	cfg.Include = c.Include
+	cfg.Dir = c.ExcludeDir
+	cfg.Presets = c.Presets
+	cfg.IgnoreSymbols = c.IgnoreSymbols
+	cfg.IncludeSymbols = c.IncludeSymbols
+	cfg.IgnoreSymbolsRegex = c.IgnoreSymbolsRegex
+	cfg.SkipIf = c.SkipIf
+	cfg.Enable = c.Enable
+	cfg.IncludeVendor = c.IncludeVendor
+	cfg.Lang = c.Lang
+	cfg.DefaultSeverity = c.DefaultSeverity
+	cfg.Severity = c.Severity
+	cfg.DocsBaseURL = c.DocsBaseURL
+	cfg.MinSize = c.MinSize
+	cfg.IgnoreImplements = c.IgnoreImplements
+	cfg.IgnoreIfContainsFieldType = c.IgnoreIfContainsFieldType
+	cfg.RespectUnsafe = c.RespectUnsafe
+	cfg.Tests = config.TestFiles(c.Tests)
+
+	for _, r := range c.ExcludeRules {
+		cfg.ExcludeRules = append(cfg.ExcludeRules, config.ExcludeRule(r))
+	}
+
+	threshold, unit := c.Threshold, c.ThresholdUnit
+	if threshold <= 0 {
+		threshold, unit = DefaultThreshold, config.UnitBytes
+	}
+
+	opts := analyzer.Options{Threshold: threshold, ThresholdUnit: unit, Config: cfg, SkipIf: c.SkipType, AllowBreaking: c.AllowBreaking}
+
+	results, err := runner.Check(analyzer.New(opts), pkgs)
+	if err != nil {
+		return nil, fmt.Errorf("pointless: %w", err)
+	}
+
+	findings := make([]Finding, 0, len(results))
+	for _, r := range results {
+		f := Finding{
+			Position:    r.Position,
+			End:         r.End,
+			Message:     r.Message,
+			Severity:    r.Severity,
+			Rule:        r.Rule,
+			URL:         r.Diagnostic.URL,
+			Package:     r.Package,
+			Symbol:      r.Symbol,
+			Fingerprint: r.Fingerprint,
+		}
+
+		for _, rel := range r.Related {
+			f.Related = append(f.Related, Related{Position: rel.Position, Message: rel.Message})
+		}
+
+		findings = append(findings, f)
+	}
+
+	return findings, nil
+}
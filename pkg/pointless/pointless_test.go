@@ -0,0 +1,238 @@
+package pointless_test
+
+import (
+	"go/types"
+	"maps"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+
+	"golang.org/x/tools/go/packages"
+
+	"github.com/mickamy/pointless/internal/config"
+	"github.com/mickamy/pointless/pkg/pointless"
+)
+
+func TestCheckerCheck(t *testing.T) {
+	t.Parallel()
+
+	pkgs, err := packages.Load(&packages.Config{Mode: pointless.LoadMode, Dir: filepath.Join("testdata", "example")}, "./...")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	findings, err := pointless.Checker{}.Check(pkgs)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []struct {
+		line   int
+		symbol string
+		rule   string
+		msg    string
+	}{
+		{line: 7, symbol: "example.com/example.NewPoint", rule: "return-pointer", msg: "consider returning value instead of pointer"},
+		{line: 11, symbol: "example.com/example.(*Point).Sum", rule: "value-receiver", msg: "consider using value receiver"},
+	}
+
+	if len(findings) != len(want) {
+		t.Fatalf("got %d findings, want %d: %+v", len(findings), len(want), findings)
+	}
+
+	for i, w := range want {
+		f := findings[i]
+		if f.Position.Line != w.line || f.Symbol != w.symbol || f.Rule != w.rule || !strings.HasPrefix(f.Message, w.msg) {
+			t.Errorf("finding %d = %d %s %s %q, want %d %s %s %q", i, f.Position.Line, f.Symbol, f.Rule, f.Message, w.line, w.symbol, w.rule, w.msg)
+		}
+
+		if !strings.HasSuffix(f.URL, "/"+w.rule+".md") {
+			t.Errorf("finding %d URL = %q, want the %s documentation", i, f.URL, w.rule)
+		}
+	}
+
+	if findings[0].Fingerprint == "" || findings[0].Fingerprint == findings[1].Fingerprint {
+		t.Errorf("fingerprints = %q, %q, want distinct non-empty values", findings[0].Fingerprint, findings[1].Fingerprint)
+	}
+}
+
+func TestCheckerIgnoreSymbols(t *testing.T) {
+	t.Parallel()
+
+	pkgs, err := packages.Load(&packages.Config{Mode: pointless.LoadMode, Dir: filepath.Join("testdata", "example")}, "./...")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	findings, err := pointless.Checker{IgnoreSymbols: []string{"NewPoint", "(*Point).Sum"}}.Check(pkgs)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(findings) != 0 {
+		t.Errorf("got findings %+v, want none", findings)
+	}
+}
+
+func TestCheckerDocsBaseURL(t *testing.T) {
+	t.Parallel()
+
+	pkgs, err := packages.Load(&packages.Config{Mode: pointless.LoadMode, Dir: filepath.Join("testdata", "example")}, "./...")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	findings, err := pointless.Checker{DocsBaseURL: "https://wiki.example.com/go/{rule}"}.Check(pkgs)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, f := range findings {
+		if want := "https://wiki.example.com/go/" + f.Rule; f.URL != want {
+			t.Errorf("finding URL = %q, want %q", f.URL, want)
+		}
+	}
+}
+
+func TestCheckerSkip(t *testing.T) {
+	t.Parallel()
+
+	pkgs, err := packages.Load(&packages.Config{Mode: pointless.LoadMode, Dir: filepath.Join("testdata", "example")}, "./...")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	findings, err := pointless.Checker{SkipIf: `type.name.matches("^Point$")`}.Check(pkgs)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(findings) != 0 {
+		t.Errorf("SkipIf: got findings %+v, want none", findings)
+	}
+
+	skipped := 0
+	skipType := func(t types.Type) bool {
+		skipped++
+
+		return strings.HasSuffix(t.String(), "example.Point")
+	}
+
+	findings, err = pointless.Checker{SkipType: skipType}.Check(pkgs)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(findings) != 0 || skipped == 0 {
+		t.Errorf("SkipType: got findings %+v after %d calls, want none", findings, skipped)
+	}
+
+	if _, err := (pointless.Checker{SkipIf: "type.size > 8"}).Check(pkgs); err == nil {
+		t.Error("Check with an invalid SkipIf succeeded, want an error")
+	}
+}
+
+func TestCheckerOptions(t *testing.T) {
+	t.Parallel()
+
+	pkgs, err := packages.Load(&packages.Config{Mode: pointless.LoadMode, Dir: filepath.Join("testdata", "options"), Tests: true}, "./...")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defaults := []string{"NewConn", "NewTiny", "newFixture", "(*Code).Error"}
+	respectUnsafe := false
+
+	tests := []struct {
+		name    string
+		checker pointless.Checker
+		want    []string
+	}{
+		{"defaults", pointless.Checker{}, defaults},
+		{"min-size", pointless.Checker{MinSize: 8}, []string{"NewConn", "newFixture", "(*Code).Error"}},
+		{"ignore-implements", pointless.Checker{IgnoreImplements: []string{"error"}}, []string{"NewConn", "NewTiny", "newFixture"}},
+		{"ignore-if-contains-field-type", pointless.Checker{IgnoreIfContainsFieldType: []string{"options.Handle"}}, []string{"NewTiny", "newFixture", "(*Code).Error"}},
+		{"respect-unsafe", pointless.Checker{RespectUnsafe: &respectUnsafe}, append(slices.Clone(defaults), "NewRaw")},
+		{"exclude-rules", pointless.Checker{ExcludeRules: []pointless.ExcludeRule{{Rules: []string{"return-pointer"}}}}, []string{"(*Code).Error"}},
+		{"tests", pointless.Checker{Tests: pointless.TestFiles{Rules: []string{"value-receiver"}}}, []string{"NewConn", "NewTiny", "(*Code).Error"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			findings, err := tt.checker.Check(pkgs)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			got := map[string]bool{}
+			for _, f := range findings {
+				got[strings.TrimPrefix(f.Symbol, "example.com/options.")] = true
+			}
+
+			want := map[string]bool{}
+			for _, s := range tt.want {
+				want[s] = true
+			}
+
+			if !maps.Equal(got, want) {
+				t.Errorf("findings in %v, want %v", slices.Sorted(maps.Keys(got)), slices.Sorted(maps.Keys(want)))
+			}
+		})
+	}
+}
+
+func TestCheckerTestSeverity(t *testing.T) {
+	t.Parallel()
+
+	pkgs, err := packages.Load(&packages.Config{Mode: pointless.LoadMode, Dir: filepath.Join("testdata", "options"), Tests: true}, "./...")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, tt := range []struct{ severity, want string }{{"", "info"}, {"hint", "hint"}} {
+		findings, err := pointless.Checker{Tests: pointless.TestFiles{Severity: tt.severity}}.Check(pkgs)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		for _, f := range findings {
+			if strings.HasSuffix(f.Position.Filename, "_test.go") && f.Severity != tt.want {
+				t.Errorf("tests.severity %q: finding in %s has severity %s, want %s", tt.severity, f.Position.Filename, f.Severity, tt.want)
+			}
+		}
+	}
+}
+
+// TestCheckerCoversConfig fails when a setting is added to the config file
+// without a Checker field.
+func TestCheckerCoversConfig(t *testing.T) {
+	t.Parallel()
+
+	// Settings that do not apply to a library call
+	notApplicable := map[string]bool{
+		"Format":        true, // only formats the files changed by -fix
+		"PathMatching":  true, // where config files are looked up
+		"PackageBudget": true, // warns on the command line
+		"Dir":           true, // Checker.ExcludeDir
+		"File":          true,
+		"Suppressed":    true, // read from the suppressions file by the command
+	}
+
+	checker := reflect.TypeFor[pointless.Checker]()
+	cfg := reflect.TypeFor[config.Config]()
+
+	for i := range cfg.NumField() {
+		field := cfg.Field(i)
+		if notApplicable[field.Name] {
+			continue
+		}
+
+		if _, ok := checker.FieldByName(field.Name); !ok {
+			t.Errorf("config field %s has no Checker counterpart", field.Name)
+		}
+	}
+}
@@ -0,0 +1,13 @@
+package example
+
+type Point struct {
+	X, Y int
+}
+
+func NewPoint() *Point {
+	return &Point{}
+}
+
+func (p *Point) Sum() int {
+	return p.X + p.Y
+}
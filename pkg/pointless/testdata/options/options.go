@@ -0,0 +1,47 @@
+package options
+
+import "unsafe"
+
+// Tiny is smaller than a min-size of 8 bytes.
+type Tiny struct {
+	ok bool
+}
+
+func NewTiny() *Tiny {
+	return &Tiny{}
+}
+
+// Handle is a field type exempting the structs containing it.
+type Handle struct {
+	fd int
+}
+
+// Conn contains a Handle.
+type Conn struct {
+	h Handle
+	n int
+}
+
+func NewConn() *Conn {
+	return &Conn{}
+}
+
+// Code implements error.
+type Code struct {
+	n int
+}
+
+func (c *Code) Error() string {
+	return "code"
+}
+
+// Raw has its layout inspected with unsafe.Sizeof.
+type Raw struct {
+	a, b int
+}
+
+var rawSize = unsafe.Sizeof(Raw{})
+
+func NewRaw() *Raw {
+	return &Raw{}
+}
@@ -0,0 +1,10 @@
+package options
+
+// Fixture is only used by tests.
+type Fixture struct {
+	n int
+}
+
+func newFixture() *Fixture {
+	return &Fixture{}
+}
@@ -0,0 +1,246 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/mickamy/pointless/internal/analyzer"
+	"github.com/mickamy/pointless/internal/changed"
+	"github.com/mickamy/pointless/internal/config"
+	"github.com/mickamy/pointless/internal/fix"
+	"github.com/mickamy/pointless/internal/fixsafety"
+	"github.com/mickamy/pointless/internal/format"
+	"github.com/mickamy/pointless/internal/heapprof"
+	"github.com/mickamy/pointless/internal/metrics"
+	"github.com/mickamy/pointless/internal/runner"
+	"github.com/mickamy/pointless/internal/startup"
+	"github.com/mickamy/pointless/internal/version"
+)
+
+// The CLI-level flags are registered with the driver's flags so that they show
+// up in its usage and -format=text is accepted there. Everything else is handled
+// by runFormatted (see needsRunner).
+var (
+	formatFlag        = flag.String("format", format.Text, formatUsage)
+	junitTestCaseFlag = flag.String("junit-testcase", format.JUnitPerFile, junitTestCaseUsage)
+	_                 = flag.Bool("changed", false, changedUsage)
+	_                 = flag.Bool("changed-rdeps", false, changedRdepsUsage)
+	fixSafetyFlag     = flag.String("fix-safety", fixsafety.Default, fixSafetyUsage)
+	_                 = flag.String("metrics-out", "", metricsOutUsage)
+	_                 = flag.String("pprof", "", pprofUsage)
+)
+
+var (
+	formatUsage    = "output format: " + strings.Join(format.Names(), ", ")
+	fixSafetyUsage = "with -fix, the least safe fixes to apply: " + strings.Join(fixsafety.Names(), ", ")
+)
+
+const (
+	junitTestCaseUsage = "what a -format=junit test case stands for: " + format.JUnitPerFile + " or " + format.JUnitPerRule
+	changedUsage       = "only analyze packages with uncommitted changes (git status) below the current directory"
+	changedRdepsUsage  = "with -changed, also analyze the packages importing them"
+	fixUsage           = "apply all suggested fixes and format the changed files"
+	metricsOutUsage    = "write a JSON summary of the run (packages, time per phase, findings per rule) to `file`"
+	pprofUsage         = "rank findings by the allocations of their types in the heap profile `file`, largest first"
+	testUsage          = "indicates whether test files should be analyzed, too"
+)
+
+// needsRunner reports whether args use flags that the analysis driver does not
+// support, so they must be handled by runFormatted. -fix is handled there too,
+// so that fixed files are formatted, except with -diff, which only the driver
+// supports. So are -verbose, which also prints the time spent per package,
+// and -v, which the driver reserves as a deprecated no-op, as shorthand for
+// -verbose.
+func needsRunner(args []string) bool {
+	if name, ok := startup.FlagArg(args, "format"); ok && name != format.Text {
+		return true
+	}
+
+	if startup.HasFlag(args, "fix") && !startup.HasFlag(args, "diff") {
+		return true
+	}
+
+	return startup.HasFlag(args, "changed") || startup.HasFlag(args, "changed-rdeps") || startup.HasFlag(args, "metrics-out") || startup.HasFlag(args, "pprof") || startup.HasFlag(args, "verbose") || startup.HasFlag(args, "v")
+}
+
+// runFormatted analyzes the packages named in args, or the changed ones with
+// -changed, with the analyzer's flags and writes the findings to stdout in the
+// format selected by -format. With -fix, it applies the suggested fixes up to
+// -fix-safety and formats the changed files with the formatter of cfg, and
+// only writes the findings left. With -metrics-out, it also writes a summary of
+// the run. With -verbose, it prints the time spent per package to stderr,
+// slowest first. With -pprof, findings are ranked by the allocations of their types
+// in the heap profile. Like the analysis driver, it exits with 3 if there are findings.
+//
+// At the root of a workspace with modules, the packages of all modules are
+// analyzed by default, and ./... patterns are expanded to the modules below
+// them, which the go command does not do.
+func runFormatted(args []string, cfg config.Config, modules []config.Module) int {
+	started := time.Now()
+
+	fs := flag.NewFlagSet("pointless", flag.ContinueOnError)
+	name := fs.String("format", *formatFlag, formatUsage)
+	junitTestCase := fs.String("junit-testcase", *junitTestCaseFlag, junitTestCaseUsage)
+	onlyChanged := fs.Bool("changed", false, changedUsage)
+	changedRdeps := fs.Bool("changed-rdeps", false, changedRdepsUsage)
+	applyFixes := fs.Bool("fix", false, fixUsage)
+	fixSafety := fs.String("fix-safety", *fixSafetyFlag, fixSafetyUsage)
+	metricsOut := fs.String("metrics-out", "", metricsOutUsage)
+	pprof := fs.String("pprof", "", pprofUsage)
+	tests := fs.Bool("test", true, testUsage)
+	_ = fs.String("target", "", startup.TargetUsage)               // applied by startup.SelectTarget
+	_ = fs.Bool("strict-config", false, startup.StrictConfigUsage) // applied by startup.LoadConfig
+	_ = fs.String("tags", "", startup.TagsUsage)                   // applied by startup.SelectTags
+
+	analyzer.Analyzer.Flags.VisitAll(func(f *flag.Flag) {
+		fs.Var(f.Value, f.Name, f.Usage)
+	})
+
+	if f := analyzer.Analyzer.Flags.Lookup("verbose"); f != nil {
+		fs.Var(f.Value, "v", "shorthand for -verbose")
+	}
+
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+
+	if _, ok := format.Lookup(*name); !ok {
+		fmt.Fprintf(os.Stderr, "pointless: unknown format %q (available: %s)\n", *name, strings.Join(format.Names(), ", "))
+
+		return 2
+	}
+
+	if *name == "json" || *name == "sarif" {
+		analyzer.SetTypeDefinitions(true)
+	}
+
+	patterns := fs.Args()
+
+	switch {
+	case len(modules) > 0:
+		if len(patterns) == 0 {
+			patterns = []string{"./..."}
+		}
+
+		patterns = startup.WorkspacePatterns(modules, patterns)
+	case len(patterns) == 0:
+		patterns = []string{"."}
+	}
+
+	if *onlyChanged || *changedRdeps {
+		if len(fs.Args()) > 0 {
+			fmt.Fprintf(os.Stderr, "pointless: -changed does not take package patterns\n")
+
+			return 2
+		}
+
+		pkgs, err := changed.Packages(".", *changedRdeps)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "pointless: %v\n", err)
+
+			return 1
+		}
+
+		if len(pkgs) == 0 {
+			fmt.Fprintf(os.Stderr, "pointless: no changed packages\n")
+
+			return 0
+		}
+
+		patterns = pkgs
+	}
+
+	var profile *heapprof.Profile
+
+	if *pprof != "" {
+		p, err := heapprof.ReadFile(*pprof)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "pointless: -pprof: %v\n", err)
+
+			return 2
+		}
+
+		profile = p
+	}
+
+	loadStarted := time.Now()
+
+	pkgs, err := runner.LoadTests("", *tests, patterns...)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "pointless: %v\n", err)
+
+		return 1
+	}
+
+	loaded := time.Since(loadStarted)
+
+	findings, phases, err := runner.CheckWithMetrics(analyzer.Analyzer, pkgs)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "pointless: %v\n", err)
+
+		return 1
+	}
+
+	if f := analyzer.Analyzer.Flags.Lookup("verbose"); f != nil && f.Value.String() == "true" {
+		for _, t := range metrics.Timings(phases) {
+			fmt.Fprintf(os.Stderr, "pointless: timing: %s\n", t)
+		}
+	}
+
+	if *metricsOut != "" {
+		if err := writeMetrics(*metricsOut, metrics.New(time.Since(started), loaded, phases, findings)); err != nil {
+			fmt.Fprintf(os.Stderr, "pointless: %v\n", err)
+
+			return 1
+		}
+	}
+
+	if *applyFixes {
+		result, err := fix.Apply(findings, cfg.Format, *fixSafety)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "pointless: %v\n", err)
+
+			return 1
+		}
+
+		findings = result.Unfixed
+	}
+
+	if profile != nil {
+		findings = heapprof.Rank(findings, pkgs, heapprof.Attribute(profile, pkgs))
+	}
+
+	if err := format.Write(os.Stdout, *name, findings, format.Options{JUnitTestCase: *junitTestCase, ToolVersion: version.Get().Short(), DocsBaseURL: cfg.DocsBaseURL}); err != nil {
+		fmt.Fprintf(os.Stderr, "pointless: %v\n", err)
+
+		return 1
+	}
+
+	if len(findings) > 0 {
+		return 3
+	}
+
+	return 0
+}
+
+func writeMetrics(path string, s metrics.Summary) error {
+	f, err := os.Create(path) //nolint:gosec // G304: path is the user-provided output file
+	if err != nil {
+		return fmt.Errorf("creating metrics file: %w", err)
+	}
+
+	if err := metrics.Write(f, s); err != nil {
+		_ = f.Close()
+
+		return fmt.Errorf("writing metrics file: %w", err)
+	}
+
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("closing metrics file: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,139 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/mickamy/pointless/internal/analyzer"
+	"github.com/mickamy/pointless/internal/config"
+	"github.com/mickamy/pointless/internal/corpus"
+	"github.com/mickamy/pointless/internal/runner"
+)
+
+// defaultCorpus is the corpus file maintained in this repository.
+const defaultCorpus = "corpus/corpus.yaml"
+
+// runSelftest implements `pointless selftest`, which analyzes the snapshots of a
+// corpus file and compares the finding counts per rule with the recorded ones.
+func runSelftest(args []string) int {
+	fs := flag.NewFlagSet("pointless selftest", flag.ContinueOnError)
+	path := fs.String("corpus", defaultCorpus, "corpus file")
+	update := fs.Bool("update", false, "record the current counts in the corpus file")
+	fs.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage: pointless selftest [-corpus file] [-update]\n\n")
+		fmt.Fprintf(os.Stderr, "Analyzes pinned code snapshots and compares finding counts per rule.\n\n")
+		fs.PrintDefaults()
+	}
+
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+
+	f, err := corpus.Load(*path)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "pointless: %v\n", err)
+
+		return 1
+	}
+
+	// Counts are recorded with the default settings, independent of any local config.
+	// Module snapshots live in the module cache, which is skipped by default.
+	cfg := config.DefaultConfig()
+	cfg.IncludeVendor = true
+	opts := analyzer.Options{Threshold: analyzer.DefaultThreshold, Config: cfg}
+	base := filepath.Dir(*path)
+	changed := 0
+
+	for i, e := range f.Entries {
+		counts, err := countFindings(opts, base, e)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "pointless: %s: %v\n", e.Name, err)
+
+			return 1
+		}
+
+		diffs := corpus.Compare(e.Counts, counts)
+		if len(diffs) == 0 {
+			fmt.Printf("ok    %s\n", e.Name)
+
+			continue
+		}
+
+		changed++
+
+		fmt.Printf("FAIL  %s\n", e.Name)
+
+		for _, d := range diffs {
+			fmt.Printf("      %-18s %5d -> %d\n", d.Rule, d.Want, d.Got)
+		}
+
+		f.Entries[i].Counts = counts
+	}
+
+	if *update {
+		if err := writeCorpus(*path, f); err != nil {
+			fmt.Fprintf(os.Stderr, "pointless: %v\n", err)
+
+			return 1
+		}
+
+		fmt.Fprintf(os.Stderr, "pointless: updated %d entries in %s\n", changed, *path)
+
+		return 0
+	}
+
+	if changed > 0 {
+		fmt.Fprintf(os.Stderr, "pointless: finding counts changed for %d entries (rerun with -update to record them)\n", changed)
+
+		return 1
+	}
+
+	return 0
+}
+
+// countFindings analyzes one corpus entry and counts its findings per rule.
+func countFindings(opts analyzer.Options, base string, e corpus.Entry) (map[string]int, error) {
+	dir, err := e.Resolve(base)
+	if err != nil {
+		return nil, err
+	}
+
+	// The counts of the corpus leave out the tests of its modules
+	pkgs, err := runner.LoadTests(dir, false, e.PatternsOrDefault()...)
+	if err != nil {
+		return nil, err
+	}
+
+	findings, err := runner.Check(analyzer.New(opts), pkgs)
+	if err != nil {
+		return nil, err
+	}
+
+	counts := make(map[string]int)
+	for _, f := range findings {
+		counts[f.Rule]++
+	}
+
+	return counts, nil
+}
+
+func writeCorpus(path string, f corpus.File) error {
+	out, err := os.Create(path) //nolint:gosec // G304: path is the user-provided corpus file
+	if err != nil {
+		return fmt.Errorf("creating corpus file: %w", err)
+	}
+
+	if err := corpus.Write(out, f); err != nil {
+		_ = out.Close()
+
+		return fmt.Errorf("writing corpus file: %w", err)
+	}
+
+	if err := out.Close(); err != nil {
+		return fmt.Errorf("closing corpus file: %w", err)
+	}
+
+	return nil
+}
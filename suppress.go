@@ -0,0 +1,102 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/mickamy/pointless/internal/analyzer"
+	"github.com/mickamy/pointless/internal/config"
+	"github.com/mickamy/pointless/internal/runner"
+	"github.com/mickamy/pointless/internal/startup"
+)
+
+// runSuppress implements `pointless suppress`, which writes the current findings
+// as symbol-level suppressions.
+func runSuppress(args []string) int {
+	cfg := startup.LoadConfig()
+
+	fs := flag.NewFlagSet("pointless suppress", flag.ContinueOnError)
+	out := fs.String("o", config.SuppressionsFile, "output file")
+	threshold := fs.Int("threshold", cfg.Threshold, "size threshold, in -threshold-unit")
+	unit := fs.String("threshold-unit", cfg.ThresholdUnit, "unit of -threshold: bytes, words, or cachelines")
+	fs.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage: pointless suppress [-o file] [packages]\n\n")
+		fmt.Fprintf(os.Stderr, "Writes the current findings as symbol-level suppressions.\n\n")
+		fs.PrintDefaults()
+	}
+
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+
+	patterns := fs.Args()
+	if len(patterns) == 0 {
+		patterns = []string{"."}
+	}
+
+	// Regenerate from scratch: existing suppressions must not hide current findings
+	cfg.Suppressed = nil
+
+	findings, err := runner.Run(analyzer.Options{Threshold: *threshold, ThresholdUnit: *unit, Config: cfg}, patterns...)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "pointless: %v\n", err)
+
+		return 1
+	}
+
+	var s config.Suppressions
+
+	index := make(map[string]int)
+	skipped := 0
+
+	for _, f := range findings {
+		if f.Symbol == "" {
+			skipped++
+
+			continue
+		}
+
+		i, ok := index[f.Symbol]
+		if !ok {
+			i = len(s.Suppressions)
+			index[f.Symbol] = i
+			s.Suppressions = append(s.Suppressions, config.Suppression{Symbol: f.Symbol, Reason: f.Message})
+		}
+
+		s.Suppressions[i].Fingerprints = append(s.Suppressions[i].Fingerprints, f.Fingerprint)
+	}
+
+	if err := writeSuppressions(*out, s); err != nil {
+		fmt.Fprintf(os.Stderr, "pointless: %v\n", err)
+
+		return 1
+	}
+
+	fmt.Fprintf(os.Stderr, "pointless: wrote %d suppressions to %s\n", len(s.Suppressions), *out)
+
+	if skipped > 0 {
+		fmt.Fprintf(os.Stderr, "pointless: warning: %d findings outside of functions and variables cannot be suppressed by symbol\n", skipped)
+	}
+
+	return 0
+}
+
+func writeSuppressions(path string, s config.Suppressions) error {
+	f, err := os.Create(path) //nolint:gosec // G304: path is the user-provided output file
+	if err != nil {
+		return fmt.Errorf("creating suppressions file: %w", err)
+	}
+
+	if err := config.WriteSuppressions(f, s); err != nil {
+		_ = f.Close()
+
+		return fmt.Errorf("writing suppressions file: %w", err)
+	}
+
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("closing suppressions file: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,9 @@
+package tests
+
+type Point struct {
+	X, Y int
+}
+
+func NewPoint() *Point {
+	return &Point{}
+}
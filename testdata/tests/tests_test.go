@@ -0,0 +1,5 @@
+package tests
+
+func newFixture() *Point {
+	return &Point{X: 1}
+}
@@ -0,0 +1,65 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/mickamy/pointless/internal/analyzer"
+	"github.com/mickamy/pointless/internal/runner"
+	"github.com/mickamy/pointless/internal/startup"
+	"github.com/mickamy/pointless/internal/survey"
+)
+
+// runVerdict implements `pointless verdict`, which prints a verdict per struct
+// type of the packages: value-friendly, pointer-required, or too large.
+func runVerdict(args []string) int {
+	fs := flag.NewFlagSet("pointless verdict", flag.ContinueOnError)
+	evidence := fs.Int("evidence", 3, "the most evidence lines to print per type, all if negative")
+	fs.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage: pointless verdict [-evidence n] [packages]\n\n")
+		fmt.Fprintf(os.Stderr, "Prints, for each struct type of the packages (default ./...), whether it is\n")
+		fmt.Fprintf(os.Stderr, "value-friendly, pointer-required, or too large, with the key evidence.\n\n")
+		fs.PrintDefaults()
+	}
+
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+
+	cfg := startup.LoadConfig()
+
+	patterns := fs.Args()
+	if len(patterns) == 0 {
+		patterns = []string{"./..."}
+	}
+
+	pkgs, err := runner.Load(patterns...)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "pointless: %v\n", err)
+
+		return 1
+	}
+
+	verdicts, err := runner.Verdicts(analyzer.Options{Threshold: cfg.Threshold, ThresholdUnit: cfg.ThresholdUnit, Config: cfg}, pkgs)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "pointless: %v\n", err)
+
+		return 1
+	}
+
+	wd, err := os.Getwd()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "pointless: %v\n", err)
+
+		return 1
+	}
+
+	if err := survey.WriteVerdicts(os.Stdout, verdicts, wd, *evidence); err != nil {
+		fmt.Fprintf(os.Stderr, "pointless: %v\n", err)
+
+		return 1
+	}
+
+	return 0
+}
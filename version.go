@@ -0,0 +1,32 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/mickamy/pointless/internal/version"
+)
+
+// The -version flag is registered with the driver's flags so that it shows up
+// in its usage, but main handles it before any package is loaded.
+var _ = flag.Bool("version", false, versionUsage)
+
+const versionUsage = "print the version, VCS revision, and Go version of pointless and exit"
+
+// runVersion implements pointless version (and -version), printing the build
+// information of the binary.
+func runVersion(args []string) int {
+	fs := flag.NewFlagSet("pointless version", flag.ContinueOnError)
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: pointless version\n\n%s\n", versionUsage)
+	}
+
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+
+	fmt.Fprint(os.Stdout, version.Get())
+
+	return 0
+}